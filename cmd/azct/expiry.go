@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"azure-control-tower/internal/auth"
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/export"
+	"azure-control-tower/internal/models"
+)
+
+// expiryReport implements the expiry-report subcommand, which lists the Key Vault secrets, keys and
+// certificates that have expired or expire soon as CSV or JSON without starting the UI
+func expiryReport(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("expiry-report", flag.ExitOnError)
+	subscription := flags.String("subscription", "", "subscription ID or name to scan (default: all subscriptions)")
+	days := flags.Int("days", 30, "report items expiring within this many days")
+	format := flags.String("format", export.FormatCSV, "output format: "+strings.Join(export.ReportFormats, ", "))
+	output := flags.String("output", "", "output file, or - for stdout (default: keyvault-expiry.csv or keyvault-expiry.json)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: azct expiry-report [flags]\n\nLists Key Vault secrets, keys and certificates that have expired or expire within --days.\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if *days < 0 {
+		fmt.Fprintln(os.Stderr, "Invalid options: --days must not be negative")
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = export.DefaultReportFileName(*format)
+	}
+	if _, err := export.RenderExpiryReport(&models.ExpiryReport{}, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid options: %v\n", err)
+		os.Exit(2)
+	}
+
+	cred, err := auth.NewAzureAuth()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Authentication error: %v\n", err)
+		os.Exit(1)
+	}
+	azureClient, err := azure.NewClient(cred)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Azure client: %v\n", err)
+		os.Exit(1)
+	}
+
+	subscriptions, err := azureClient.ListSubscriptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list subscriptions: %v\n", err)
+		os.Exit(1)
+	}
+	if *subscription != "" {
+		var selected []*models.Subscription
+		for _, sub := range subscriptions {
+			if strings.EqualFold(sub.ID, *subscription) || strings.EqualFold(sub.DisplayName, *subscription) || strings.EqualFold(sub.Name, *subscription) {
+				selected = append(selected, sub)
+			}
+		}
+		if len(selected) == 0 {
+			fmt.Fprintf(os.Stderr, "Subscription %q not found\n", *subscription)
+			os.Exit(1)
+		}
+		subscriptions = selected
+	}
+
+	report, err := azureClient.BuildExpiryReport(ctx, subscriptions, *days, func(scanned, total int, vault string) {
+		fmt.Fprintf(os.Stderr, "Scanning %s (%d/%d)\n", vault, scanned+1, total)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Report failed: %v\n", err)
+		os.Exit(1)
+	}
+	for _, msg := range report.Errors {
+		fmt.Fprintf(os.Stderr, "Skipped %s\n", msg)
+	}
+
+	data, err := export.RenderExpiryReport(report, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Report failed: %v\n", err)
+		os.Exit(1)
+	}
+	target := *output
+	if target == "-" {
+		target = "stdout"
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Report failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d item(s) from %d vault(s) to %s\n", len(report.Items), report.VaultsScanned, target)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"azure-control-tower/internal/auth"
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/export"
+)
+
+// exportSecrets implements the export-secrets subcommand, which writes Key Vault secrets to a
+// dotenv, JSON or Kubernetes Secret file without starting the UI
+func exportSecrets(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("export-secrets", flag.ExitOnError)
+	vault := flags.String("vault", "", "Key Vault name or URL (required)")
+	secrets := flags.String("secrets", "", "comma-separated secret names (default: all enabled secrets)")
+	format := flags.String("format", export.FormatDotenv, "output format: "+strings.Join(export.Formats, ", "))
+	mapping := flags.String("mapping", export.MappingUpperSnake, "secret name mapping: "+strings.Join(export.Mappings, ", "))
+	prefix := flags.String("prefix", "", "prefix added to every key")
+	output := flags.String("output", "", "output file, or - for stdout (default: .env, secrets.json or secret.yaml)")
+	secretName := flags.String("secret-name", "", "Kubernetes Secret name (default: the vault name)")
+	namespace := flags.String("namespace", "", "Kubernetes Secret namespace")
+	yes := flags.Bool("yes", false, "skip the confirmation prompt")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: azct export-secrets --vault NAME [flags]\n\nWrites Key Vault secret values in plain text to a file readable only by you.\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	vaultURL, err := azure.ResolveVaultURL(*vault)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid vault: %v\n", err)
+		os.Exit(2)
+	}
+	if *secretName == "" {
+		*secretName = strings.SplitN(strings.TrimPrefix(vaultURL, "https://"), ".", 2)[0]
+	}
+	if *output == "" {
+		*output = export.DefaultFileName(*format)
+	}
+	opts := export.Options{
+		Format:     *format,
+		Mapping:    *mapping,
+		Prefix:     *prefix,
+		SecretName: *secretName,
+		Namespace:  *namespace,
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid options: %v\n", err)
+		os.Exit(2)
+	}
+
+	cred, err := auth.NewAzureAuth()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Authentication error: %v\n", err)
+		os.Exit(1)
+	}
+	azureClient, err := azure.NewClient(cred)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create Azure client: %v\n", err)
+		os.Exit(1)
+	}
+
+	var names []string
+	for _, name := range strings.Split(*secrets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		all, err := azureClient.ListSecrets(ctx, vaultURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list secrets: %v\n", err)
+			os.Exit(1)
+		}
+		for _, secret := range all {
+			// Values of disabled secrets cannot be read
+			if secret.Enabled {
+				names = append(names, secret.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, "No secrets to export")
+		os.Exit(1)
+	}
+
+	target := *output
+	if target == "-" {
+		target = "stdout"
+	}
+	if !*yes && !confirmExport(fmt.Sprintf("Write the values of %d secret(s) from %s in plain text to %s?", len(names), vaultURL, target)) {
+		fmt.Fprintln(os.Stderr, "Export cancelled")
+		os.Exit(1)
+	}
+
+	entries, err := export.Fetch(ctx, names, opts, func(ctx context.Context, name string) (string, error) {
+		return azureClient.GetSecretValue(ctx, vaultURL, name, "")
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := export.Render(entries, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = export.WriteFile(*output, data)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d secret(s) to %s\n", len(entries), target)
+}
+
+// confirmExport asks a yes/no question on the terminal; anything but y or yes declines
+func confirmExport(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
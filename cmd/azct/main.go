@@ -2,18 +2,72 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"azure-control-tower/internal/auth"
 	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
 	"azure-control-tower/internal/ui"
 	"azure-control-tower/pkg/resource"
+
+	"golang.org/x/term"
 )
 
 func main() {
 	ctx := context.Background()
 
+	// Subcommands run without the UI
+	if len(os.Args) > 1 && os.Args[1] == "export-secrets" {
+		exportSecrets(ctx, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "expiry-report" {
+		expiryReport(ctx, os.Args[2:])
+		return
+	}
+
+	// Direct storage endpoint flags (e.g. Azurite); these bypass ARM entirely
+	connectionString := flag.String("connection-string", "", "open the storage explorer on a storage connection string (use \"UseDevelopmentStorage=true\" for Azurite); set "+connectionStringEnv+" instead for connection strings with an AccountKey")
+	storageEndpoint := flag.String("storage-endpoint", "", "open the storage explorer on a blob service URL")
+	accountName := flag.String("account-name", "", "storage account name for --storage-endpoint (inferred from the URL when omitted)")
+	accountKey := flag.String("account-key", "", "deprecated: visible in the process list and shell history; set AZURE_STORAGE_KEY or enter the key when asked instead")
+	sasToken := flag.String("sas-token", "", "SAS token for --storage-endpoint")
+	clipboardTimeout := flag.Duration("clipboard-timeout", ui.DefaultClipboardTimeout, "clear copied secret values from the clipboard after this long (0 keeps them)")
+	handlersDir := flag.String("handlers-dir", defaultHandlersDir(), "directory of YAML resource handler definitions")
+	flag.Parse()
+
+	connection := storageConnectionString(*connectionString)
+	if connection != "" || *storageEndpoint != "" {
+		var endpoint *models.StorageEndpoint
+		var err error
+		if connection != "" {
+			endpoint, err = azure.ParseStorageConnectionString(connection)
+		} else {
+			key := storageAccountKey(*accountKey, *storageEndpoint, *sasToken)
+			endpoint, err = azure.NewStorageEndpoint(*storageEndpoint, *accountName, key, *sasToken)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid storage endpoint: %v\n", err)
+			os.Exit(1)
+		}
+
+		app, storageHandler, err := newApp(azure.NewStorageEndpointClient(endpoint), *handlersDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load resource handlers: %v\n", err)
+			os.Exit(1)
+		}
+		app.SetClipboardTimeout(*clipboardTimeout)
+		if err := app.StartWithStorageEndpoint(ctx, storageHandler); err != nil {
+			fmt.Fprintf(os.Stderr, "Application error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Authenticate with Azure
 	cred, err := auth.NewAzureAuth()
 	if err != nil {
@@ -28,25 +82,91 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize resource registry and register handlers
+	// Create and start UI application
+	app, _, err := newApp(azureClient, *handlersDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load resource handlers: %v\n", err)
+		os.Exit(1)
+	}
+	app.SetClipboardTimeout(*clipboardTimeout)
+	if err := app.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Application error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newApp creates the UI application with the resource handlers, and returns the storage handler that
+// opens direct storage endpoints. The storage and Key Vault handlers load their resources with the
+// client and open them in the explorer views of the application.
+func newApp(client *azure.Client, handlersDir string) (*ui.App, *resource.StorageHandler, error) {
 	registry := resource.NewRegistry()
-	
+	app := ui.NewApp(client, registry)
+
 	// Register default handler (for generic resources)
-	defaultHandler := resource.NewDefaultHandler()
-	registry.RegisterHandler(defaultHandler)
-	
-	// Register storage account handler
-	storageHandler := resource.NewStorageHandler()
+	registry.RegisterHandler(resource.NewDefaultHandler())
+
+	// Register storage account and Key Vault handlers, with their fetchers
+	storageHandler := resource.NewStorageHandler(client, app.StorageExplorerView())
 	registry.RegisterHandler(storageHandler)
+	registry.RegisterHandler(resource.NewKeyVaultHandler(client, app.KeyVaultExplorerView()))
 
-	// Register Key Vault handler
-	keyVaultHandler := resource.NewKeyVaultHandler()
-	registry.RegisterHandler(keyVaultHandler)
+	// Register YAML handlers for other resource types
+	if _, err := registry.LoadHandlers(handlersDir); err != nil {
+		return nil, nil, err
+	}
 
-	// Create and start UI application
-	app := ui.NewApp(azureClient, registry)
-	if err := app.Start(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Application error: %v\n", err)
-		os.Exit(1)
+	// Resources are listed from Azure Resource Manager unless a type registers its own fetcher
+	registry.Register("", client)
+	return app, storageHandler, nil
+}
+
+// connectionStringEnv is the environment variable a storage connection string is read from when
+// --connection-string is not given. It is specific to azct, so that the AZURE_STORAGE_CONNECTION_STRING
+// other tools read does not switch azct to the storage endpoint mode.
+const connectionStringEnv = "AZCT_STORAGE_CONNECTION_STRING"
+
+// storageConnectionString returns the storage connection string from --connection-string or from
+// AZCT_STORAGE_CONNECTION_STRING. An AccountKey in the flag prints the same warning as --account-key.
+func storageConnectionString(flagValue string) string {
+	if flagValue == "" {
+		return os.Getenv(connectionStringEnv)
+	}
+	if strings.Contains(strings.ToLower(flagValue), "accountkey=") {
+		fmt.Fprintf(os.Stderr, "Warning: an AccountKey in --connection-string is deprecated because the key is visible in the process list and shell history; set %s instead\n", connectionStringEnv)
+	}
+	return flagValue
+}
+
+// storageAccountKey returns the shared key for --storage-endpoint: from the deprecated --account-key
+// flag, from AZURE_STORAGE_KEY, or typed without echo when the terminal is interactive and no SAS token
+// is given. An empty key, such as one left blank at the prompt, opens the endpoint anonymously.
+func storageAccountKey(flagKey, serviceURL, sasToken string) string {
+	if flagKey != "" {
+		fmt.Fprintln(os.Stderr, "Warning: --account-key is deprecated because the key is visible in the process list and shell history; set AZURE_STORAGE_KEY instead")
+		return flagKey
+	}
+	if key := os.Getenv("AZURE_STORAGE_KEY"); key != "" {
+		return key
+	}
+	if sasToken != "" || strings.Contains(serviceURL, "?") || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return ""
+	}
+
+	fmt.Fprint(os.Stderr, "Storage account key (leave empty for anonymous access): ")
+	key, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(key))
+}
+
+// defaultHandlersDir returns the directory YAML resource handlers are loaded from by default,
+// such as ~/.config/azct/handlers on Linux
+func defaultHandlersDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
 	}
+	return filepath.Join(dir, "azct", "handlers")
 }
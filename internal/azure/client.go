@@ -1,6 +1,10 @@
 package azure
 
 import (
+	"sync"
+
+	"azure-control-tower/internal/models"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 )
@@ -9,6 +13,8 @@ import (
 type Client struct {
 	SubscriptionsClient *armsubscriptions.Client
 	credential          azcore.TokenCredential
+	storageEndpoint     *models.StorageEndpoint // Set when blob access bypasses ARM
+	apiVersions         sync.Map                // Resolved API versions keyed by lower-case resource type
 }
 
 // NewClient creates a new Azure client wrapper
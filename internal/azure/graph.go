@@ -0,0 +1,100 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	graphEndpoint = "https://graph.microsoft.com/v1.0"
+	graphScope    = "https://graph.microsoft.com/.default"
+
+	// graphMaxIDs is the most object IDs getByIds accepts in one request
+	graphMaxIDs = 1000
+)
+
+// DirectoryObject is a user, group or service principal returned by Microsoft Graph
+type DirectoryObject struct {
+	ID          string
+	Type        string // user, group or servicePrincipal
+	DisplayName string
+}
+
+// graphPipeline returns a pipeline that authenticates requests to Microsoft Graph
+func (c *Client) graphPipeline() runtime.Pipeline {
+	return runtime.NewPipeline("azure-control-tower", "", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(c.credential, []string{graphScope}, nil)},
+	}, nil)
+}
+
+// graphPost posts a JSON body to a Microsoft Graph endpoint and decodes the JSON response
+func (c *Client) graphPost(ctx context.Context, path string, body, result interface{}) error {
+	req, err := runtime.NewRequest(ctx, http.MethodPost, graphEndpoint+path)
+	if err != nil {
+		return err
+	}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return err
+	}
+	resp, err := c.graphPipeline().Do(req)
+	if err != nil {
+		return err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return runtime.NewResponseError(resp)
+	}
+	return runtime.UnmarshalAsJSON(resp, result)
+}
+
+// ResolveDirectoryObjects looks up the users, groups and service principals with the given object IDs.
+// IDs that do not exist in the directory are missing from the result.
+func (c *Client) ResolveDirectoryObjects(ctx context.Context, ids []string) (map[string]DirectoryObject, error) {
+	objects := make(map[string]DirectoryObject)
+	for start := 0; start < len(ids); start += graphMaxIDs {
+		end := start + graphMaxIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var result struct {
+			Value []struct {
+				ODataType   string `json:"@odata.type"`
+				ID          string `json:"id"`
+				DisplayName string `json:"displayName"`
+			} `json:"value"`
+		}
+		body := map[string]interface{}{
+			"ids":   ids[start:end],
+			"types": []string{"user", "group", "servicePrincipal"},
+		}
+		if err := c.graphPost(ctx, "/directoryObjects/getByIds", body, &result); err != nil {
+			return nil, fmt.Errorf("failed to resolve directory objects: %w", err)
+		}
+		for _, obj := range result.Value {
+			objects[strings.ToLower(obj.ID)] = DirectoryObject{
+				ID:          obj.ID,
+				Type:        strings.TrimPrefix(obj.ODataType, "#microsoft.graph."),
+				DisplayName: obj.DisplayName,
+			}
+		}
+	}
+	return objects, nil
+}
+
+// GetMemberGroups returns the IDs of all groups a user or service principal is a member of,
+// directly or through nested groups
+func (c *Client) GetMemberGroups(ctx context.Context, objectID string) ([]string, error) {
+	var result struct {
+		Value []string `json:"value"`
+	}
+	body := map[string]interface{}{"securityEnabledOnly": false}
+	if err := c.graphPost(ctx, "/directoryObjects/"+objectID+"/getMemberGroups", body, &result); err != nil {
+		return nil, fmt.Errorf("failed to get group memberships: %w", err)
+	}
+	return result.Value, nil
+}
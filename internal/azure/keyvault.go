@@ -13,6 +13,9 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 )
 
+// defaultSoftDeleteRetentionDays is the retention Key Vault applies when none is configured
+const defaultSoftDeleteRetentionDays = 90
+
 // ListKeyVaults lists all Key Vaults in a resource group
 func (c *Client) ListKeyVaults(ctx context.Context, subscriptionID, resourceGroupName string) ([]*models.KeyVault, error) {
 	client, err := armkeyvault.NewVaultsClient(subscriptionID, c.credential, nil)
@@ -33,75 +36,114 @@ func (c *Client) ListKeyVaults(ctx context.Context, subscriptionID, resourceGrou
 			if vault == nil {
 				continue
 			}
+			keyVaults = append(keyVaults, convertKeyVault(vault))
+		}
+	}
 
-			kv := &models.KeyVault{
-				Tags:       make(map[string]*string),
-				Properties: make(map[string]interface{}),
-			}
+	return keyVaults, nil
+}
 
-			if vault.ID != nil {
-				kv.ID = *vault.ID
-			}
-			if vault.Name != nil {
-				kv.Name = *vault.Name
-			}
-			if vault.Location != nil {
-				kv.Location = *vault.Location
-			}
+// convertKeyVault converts an ARM vault into the Key Vault model
+func convertKeyVault(vault *armkeyvault.Vault) *models.KeyVault {
+	kv := &models.KeyVault{
+		Tags:       make(map[string]*string),
+		Properties: make(map[string]interface{}),
+	}
 
-			// Extract resource group from ID
-			if kv.ID != "" {
-				parts := strings.Split(kv.ID, "/")
-				for i, part := range parts {
-					if strings.EqualFold(part, "resourceGroups") && i+1 < len(parts) {
-						kv.ResourceGroup = parts[i+1]
-						break
-					}
-				}
+	if vault.ID != nil {
+		kv.ID = *vault.ID
+	}
+	if vault.Name != nil {
+		kv.Name = *vault.Name
+	}
+	if vault.Location != nil {
+		kv.Location = *vault.Location
+	}
+
+	// Extract resource group from ID
+	if kv.ID != "" {
+		parts := strings.Split(kv.ID, "/")
+		for i, part := range parts {
+			if strings.EqualFold(part, "resourceGroups") && i+1 < len(parts) {
+				kv.ResourceGroup = parts[i+1]
+				break
 			}
+		}
+	}
 
-			if vault.Properties != nil {
-				if vault.Properties.VaultURI != nil {
-					kv.VaultURI = *vault.Properties.VaultURI
-				}
-				if vault.Properties.TenantID != nil {
-					kv.TenantID = *vault.Properties.TenantID
-				}
-				if vault.Properties.EnabledForDeployment != nil {
-					kv.EnabledForDeploy = *vault.Properties.EnabledForDeployment
-				}
-				if vault.Properties.EnabledForDiskEncryption != nil {
-					kv.EnabledForDisk = *vault.Properties.EnabledForDiskEncryption
-				}
-				if vault.Properties.EnabledForTemplateDeployment != nil {
-					kv.EnabledForTemplate = *vault.Properties.EnabledForTemplateDeployment
-				}
+	if vault.Properties != nil {
+		if vault.Properties.VaultURI != nil {
+			kv.VaultURI = *vault.Properties.VaultURI
+		}
+		if vault.Properties.TenantID != nil {
+			kv.TenantID = *vault.Properties.TenantID
+		}
+		if vault.Properties.EnabledForDeployment != nil {
+			kv.EnabledForDeploy = *vault.Properties.EnabledForDeployment
+		}
+		if vault.Properties.EnabledForDiskEncryption != nil {
+			kv.EnabledForDisk = *vault.Properties.EnabledForDiskEncryption
+		}
+		if vault.Properties.EnabledForTemplateDeployment != nil {
+			kv.EnabledForTemplate = *vault.Properties.EnabledForTemplateDeployment
+		}
 
-				// Add SKU info
-				if vault.Properties.SKU != nil && vault.Properties.SKU.Name != nil {
-					kv.SKU = string(*vault.Properties.SKU.Name)
-					kv.Properties["sku"] = kv.SKU
-				}
+		// Soft delete is on unless explicitly disabled; vaults created since 2020 cannot turn it off
+		kv.SoftDeleteEnabled = vault.Properties.EnableSoftDelete == nil || *vault.Properties.EnableSoftDelete
+		kv.SoftDeleteRetentionDays = defaultSoftDeleteRetentionDays
+		if vault.Properties.SoftDeleteRetentionInDays != nil {
+			kv.SoftDeleteRetentionDays = *vault.Properties.SoftDeleteRetentionInDays
+		}
+		if vault.Properties.EnablePurgeProtection != nil {
+			kv.PurgeProtectionEnabled = *vault.Properties.EnablePurgeProtection
+		}
+		if vault.Properties.EnableRbacAuthorization != nil {
+			kv.RBACAuthorization = *vault.Properties.EnableRbacAuthorization
+		}
+		kv.AccessPolicies = convertAccessPolicies(vault.Properties.AccessPolicies)
 
-				// Add network rules info
-				if vault.Properties.NetworkACLs != nil {
-					if vault.Properties.NetworkACLs.DefaultAction != nil {
-						kv.Properties["networkDefaultAction"] = string(*vault.Properties.NetworkACLs.DefaultAction)
-					}
+		// Add SKU info
+		if vault.Properties.SKU != nil && vault.Properties.SKU.Name != nil {
+			kv.SKU = string(*vault.Properties.SKU.Name)
+			kv.Properties["sku"] = kv.SKU
+		}
+
+		// Add network rules info
+		kv.PublicNetworkAccess = publicNetworkAccessEnabled
+		if vault.Properties.PublicNetworkAccess != nil {
+			kv.PublicNetworkAccess = *vault.Properties.PublicNetworkAccess
+		}
+		kv.NetworkDefaultAction = string(armkeyvault.NetworkRuleActionAllow)
+		kv.NetworkBypass = string(armkeyvault.NetworkRuleBypassOptionsAzureServices)
+		if acls := vault.Properties.NetworkACLs; acls != nil {
+			if acls.DefaultAction != nil {
+				kv.NetworkDefaultAction = string(*acls.DefaultAction)
+				kv.Properties["networkDefaultAction"] = kv.NetworkDefaultAction
+			}
+			if acls.Bypass != nil {
+				kv.NetworkBypass = string(*acls.Bypass)
+			}
+			for _, rule := range acls.IPRules {
+				if rule != nil && rule.Value != nil {
+					kv.IPRules = append(kv.IPRules, *rule.Value)
 				}
 			}
-
-			if vault.Tags != nil {
-				for k, v := range vault.Tags {
-					kv.Tags[k] = v
+			for _, rule := range acls.VirtualNetworkRules {
+				if rule != nil && rule.ID != nil {
+					kv.VirtualNetworkRules = append(kv.VirtualNetworkRules, *rule.ID)
 				}
 			}
+		}
+		kv.PrivateEndpointConnections = convertPrivateEndpointConnections(vault.Properties.PrivateEndpointConnections)
+	}
 
-			keyVaults = append(keyVaults, kv)
+	if vault.Tags != nil {
+		for k, v := range vault.Tags {
+			kv.Tags[k] = v
 		}
 	}
 
-	return keyVaults, nil
+	return kv
 }
 
 // ListSecrets lists all secrets in a Key Vault
@@ -166,14 +208,14 @@ func (c *Client) ListSecrets(ctx context.Context, vaultURL string) ([]*models.Se
 	return secrets, nil
 }
 
-// GetSecretValue retrieves the actual value of a secret
-func (c *Client) GetSecretValue(ctx context.Context, vaultURL, secretName string) (string, error) {
+// GetSecretValue retrieves the actual value of a secret; an empty version means the latest
+func (c *Client) GetSecretValue(ctx context.Context, vaultURL, secretName, version string) (string, error) {
 	client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create secrets client: %w", err)
 	}
 
-	resp, err := client.GetSecret(ctx, secretName, "", nil)
+	resp, err := client.GetSecret(ctx, secretName, version, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get secret: %w", err)
 	}
@@ -272,6 +314,17 @@ func (c *Client) GetKeyDetails(ctx context.Context, vaultURL, keyName string) (*
 	if resp.Key.Kty != nil {
 		key.KeyType = string(*resp.Key.Kty)
 	}
+	if resp.Key.Crv != nil {
+		key.Curve = string(*resp.Key.Crv)
+	}
+	if len(resp.Key.N) > 0 {
+		key.KeySize = rsaModulusBits(resp.Key.N)
+	}
+	for _, op := range resp.Key.KeyOps {
+		if op != nil {
+			key.KeyOps = append(key.KeyOps, string(*op))
+		}
+	}
 
 	if resp.Attributes != nil {
 		if resp.Attributes.Enabled != nil {
@@ -0,0 +1,377 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+)
+
+// accessPolicyAll is the access policy permission that grants every permission of an item type
+const accessPolicyAll = "all"
+
+// vaultCapability maps an operation to the RBAC data action and the access policy permission it needs
+type vaultCapability struct {
+	name       string
+	dataAction string
+	itemType   string
+	permission string
+}
+
+// vaultCapabilities are the operations checked for the current identity, in display order
+var vaultCapabilities = []vaultCapability{
+	{"List secrets", "Microsoft.KeyVault/vaults/secrets/readMetadata/action", models.VaultItemSecret, "list"},
+	{"Read secret values", "Microsoft.KeyVault/vaults/secrets/getSecret/action", models.VaultItemSecret, "get"},
+	{"Create and update secrets", "Microsoft.KeyVault/vaults/secrets/setSecret/action", models.VaultItemSecret, "set"},
+	{"Delete secrets", "Microsoft.KeyVault/vaults/secrets/delete", models.VaultItemSecret, "delete"},
+	{"Back up secrets", "Microsoft.KeyVault/vaults/secrets/backup/action", models.VaultItemSecret, "backup"},
+	{"Purge deleted secrets", "Microsoft.KeyVault/vaults/deletedSecrets/delete", models.VaultItemSecret, "purge"},
+	{"List keys", "Microsoft.KeyVault/vaults/keys/read", models.VaultItemKey, "list"},
+	{"Create keys", "Microsoft.KeyVault/vaults/keys/create/action", models.VaultItemKey, "create"},
+	{"Encrypt with keys", "Microsoft.KeyVault/vaults/keys/encrypt/action", models.VaultItemKey, "encrypt"},
+	{"Decrypt with keys", "Microsoft.KeyVault/vaults/keys/decrypt/action", models.VaultItemKey, "decrypt"},
+	{"Sign with keys", "Microsoft.KeyVault/vaults/keys/sign/action", models.VaultItemKey, "sign"},
+	{"Rotate keys", "Microsoft.KeyVault/vaults/keys/rotate/action", models.VaultItemKey, "rotate"},
+	{"Delete keys", "Microsoft.KeyVault/vaults/keys/delete", models.VaultItemKey, "delete"},
+	{"List certificates", "Microsoft.KeyVault/vaults/certificates/read", models.VaultItemCertificate, "list"},
+	{"Create certificates", "Microsoft.KeyVault/vaults/certificates/create/action", models.VaultItemCertificate, "create"},
+	{"Import certificates", "Microsoft.KeyVault/vaults/certificates/import/action", models.VaultItemCertificate, "import"},
+	{"Delete certificates", "Microsoft.KeyVault/vaults/certificates/delete", models.VaultItemCertificate, "delete"},
+}
+
+// IsForbidden reports whether an Azure request failed because the caller lacks permission
+func IsForbidden(err error) bool {
+	var respErr *azcore.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden
+}
+
+// convertAccessPolicies converts the access policies of a vault
+func convertAccessPolicies(entries []*armkeyvault.AccessPolicyEntry) []*models.AccessPolicy {
+	var policies []*models.AccessPolicy
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		policy := &models.AccessPolicy{
+			TenantID:      stringValue(entry.TenantID),
+			ObjectID:      stringValue(entry.ObjectID),
+			ApplicationID: stringValue(entry.ApplicationID),
+		}
+		if entry.Permissions != nil {
+			for _, p := range entry.Permissions.Secrets {
+				if p != nil {
+					policy.Secrets = append(policy.Secrets, strings.ToLower(string(*p)))
+				}
+			}
+			for _, p := range entry.Permissions.Keys {
+				if p != nil {
+					policy.Keys = append(policy.Keys, strings.ToLower(string(*p)))
+				}
+			}
+			for _, p := range entry.Permissions.Certificates {
+				if p != nil {
+					policy.Certificates = append(policy.Certificates, strings.ToLower(string(*p)))
+				}
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// GetVaultAccess reads the access model of a vault: its access policies or the role assignments
+// that apply to it, with principal names when Microsoft Graph is reachable, and works out what the
+// signed-in identity can do there
+func (c *Client) GetVaultAccess(ctx context.Context, subscriptionID, resourceGroupName, vaultName string) (*models.VaultAccess, error) {
+	vault, err := c.GetKeyVault(ctx, subscriptionID, resourceGroupName, vaultName)
+	if err != nil {
+		return nil, err
+	}
+	access := &models.VaultAccess{Vault: vault, RBAC: vault.RBACAuthorization, AccessPolicies: vault.AccessPolicies}
+
+	identity, err := c.GetUserInfo(ctx)
+	if err != nil {
+		access.Notes = append(access.Notes, fmt.Sprintf("The signed-in identity is unknown: %v", err))
+	} else {
+		access.Identity = identity
+	}
+
+	// Role assignments apply to the management plane of every vault, and to the data plane with RBAC
+	assignments, err := c.listVaultRoleAssignments(ctx, subscriptionID, vault.ID)
+	if err != nil {
+		access.Notes = append(access.Notes, fmt.Sprintf("Role assignments could not be read: %v", err))
+	}
+	access.RoleAssignments = assignments
+
+	var ids []string
+	for _, policy := range access.AccessPolicies {
+		ids = append(ids, policy.ObjectID)
+	}
+	for _, assignment := range access.RoleAssignments {
+		ids = append(ids, assignment.PrincipalID)
+	}
+	if len(ids) > 0 {
+		objects, err := c.ResolveDirectoryObjects(ctx, uniqueStrings(ids))
+		if err != nil {
+			access.Notes = append(access.Notes, fmt.Sprintf("Principal names could not be resolved: %v", err))
+		}
+		applyPrincipalNames(access, objects)
+	}
+
+	if access.RBAC {
+		c.resolveRBACCapabilities(ctx, subscriptionID, resourceGroupName, vaultName, access)
+	} else if access.Identity != nil && access.Identity.ObjectID != "" {
+		c.resolvePolicyCapabilities(ctx, access)
+	}
+	return access, nil
+}
+
+// listVaultRoleAssignments lists the role assignments at the vault scope and inherited from above it
+func (c *Client) listVaultRoleAssignments(ctx context.Context, subscriptionID, vaultID string) ([]*models.RoleAssignment, error) {
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role assignments client: %w", err)
+	}
+	definitions, err := armauthorization.NewRoleDefinitionsClient(c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role definitions client: %w", err)
+	}
+
+	pager := client.NewListForScopePager(vaultID, &armauthorization.RoleAssignmentsClientListForScopeOptions{
+		Filter: to.Ptr("atScope()"),
+	})
+	roleNames := make(map[string]string)
+	var assignments []*models.RoleAssignment
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page: %w", err)
+		}
+		for _, ra := range page.Value {
+			if ra == nil || ra.Properties == nil {
+				continue
+			}
+			assignment := convertRoleAssignment(ra, vaultID)
+			if _, ok := roleNames[assignment.RoleDefinitionID]; !ok {
+				// Custom roles can be unreadable; fall back to the definition ID
+				roleNames[assignment.RoleDefinitionID] = ""
+				if def, err := definitions.GetByID(ctx, assignment.RoleDefinitionID, nil); err == nil && def.Properties != nil {
+					roleNames[assignment.RoleDefinitionID] = stringValue(def.Properties.RoleName)
+				}
+			}
+			assignment.RoleName = roleNames[assignment.RoleDefinitionID]
+			if assignment.RoleName == "" {
+				assignment.RoleName = lastSegment(assignment.RoleDefinitionID)
+			}
+			assignments = append(assignments, assignment)
+		}
+	}
+	sortRoleAssignments(assignments)
+	return assignments, nil
+}
+
+// convertRoleAssignment converts a role assignment; it is inherited when assigned above the vault
+func convertRoleAssignment(ra *armauthorization.RoleAssignment, vaultID string) *models.RoleAssignment {
+	assignment := &models.RoleAssignment{
+		ID:               stringValue(ra.ID),
+		PrincipalID:      stringValue(ra.Properties.PrincipalID),
+		RoleDefinitionID: stringValue(ra.Properties.RoleDefinitionID),
+		Scope:            stringValue(ra.Properties.Scope),
+	}
+	if ra.Properties.PrincipalType != nil {
+		assignment.PrincipalType = string(*ra.Properties.PrincipalType)
+	}
+	assignment.Inherited = !strings.EqualFold(strings.TrimSuffix(assignment.Scope, "/"), strings.TrimSuffix(vaultID, "/"))
+	return assignment
+}
+
+// sortRoleAssignments lists the assignments on the vault itself first, then by role and principal
+func sortRoleAssignments(assignments []*models.RoleAssignment) {
+	sort.SliceStable(assignments, func(i, j int) bool {
+		if assignments[i].Inherited != assignments[j].Inherited {
+			return !assignments[i].Inherited
+		}
+		if assignments[i].RoleName != assignments[j].RoleName {
+			return assignments[i].RoleName < assignments[j].RoleName
+		}
+		return assignments[i].PrincipalID < assignments[j].PrincipalID
+	})
+}
+
+// applyPrincipalNames fills in the display names and types of the resolved principals
+func applyPrincipalNames(access *models.VaultAccess, objects map[string]DirectoryObject) {
+	for _, policy := range access.AccessPolicies {
+		if obj, ok := objects[strings.ToLower(policy.ObjectID)]; ok {
+			policy.DisplayName = obj.DisplayName
+			policy.PrincipalType = obj.Type
+		}
+	}
+	for _, assignment := range access.RoleAssignments {
+		if obj, ok := objects[strings.ToLower(assignment.PrincipalID)]; ok {
+			assignment.DisplayName = obj.DisplayName
+		}
+	}
+}
+
+// resolveRBACCapabilities works out the capabilities of the signed-in identity from its effective
+// data actions on the vault, which include roles assigned through groups
+func (c *Client) resolveRBACCapabilities(ctx context.Context, subscriptionID, resourceGroupName, vaultName string, access *models.VaultAccess) {
+	client, err := armauthorization.NewPermissionsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		access.Notes = append(access.Notes, fmt.Sprintf("Effective permissions could not be read: %v", err))
+		return
+	}
+
+	pager := client.NewListForResourcePager(resourceGroupName, "Microsoft.KeyVault", "", "vaults", vaultName, nil)
+	var permissions []*armauthorization.Permission
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			access.Notes = append(access.Notes, fmt.Sprintf("Effective permissions could not be read: %v", err))
+			return
+		}
+		permissions = append(permissions, page.Value...)
+	}
+	access.Capabilities = RBACCapabilities(permissions)
+}
+
+// RBACCapabilities evaluates each capability against the effective permissions of an identity.
+// An operation is allowed when one permission grants its data action without excluding it.
+func RBACCapabilities(permissions []*armauthorization.Permission) []models.VaultCapability {
+	capabilities := make([]models.VaultCapability, len(vaultCapabilities))
+	for i, capability := range vaultCapabilities {
+		capabilities[i].Name = capability.name
+		for _, permission := range permissions {
+			if permission != nil && matchesAnyAction(permission.DataActions, capability.dataAction) &&
+				!matchesAnyAction(permission.NotDataActions, capability.dataAction) {
+				capabilities[i].Allowed = true
+				break
+			}
+		}
+	}
+	return capabilities
+}
+
+// matchesAnyAction reports whether one of the action patterns matches the action
+func matchesAnyAction(patterns []*string, action string) bool {
+	for _, pattern := range patterns {
+		if pattern != nil && matchAction(*pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAction matches an action against a role definition pattern, where * matches any text.
+// Actions are case-insensitive.
+func matchAction(pattern, action string) bool {
+	pattern = strings.ToLower(pattern)
+	action = strings.ToLower(action)
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == action
+	}
+	if !strings.HasPrefix(action, parts[0]) {
+		return false
+	}
+	action = action[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(action, part)
+		if idx < 0 {
+			return false
+		}
+		action = action[idx+len(part):]
+	}
+	return strings.HasSuffix(action, parts[len(parts)-1])
+}
+
+// resolvePolicyCapabilities works out the capabilities of the signed-in identity from the access
+// policies granted to it directly or to its groups
+func (c *Client) resolvePolicyCapabilities(ctx context.Context, access *models.VaultAccess) {
+	objectIDs := []string{access.Identity.ObjectID}
+	groups, err := c.GetMemberGroups(ctx, access.Identity.ObjectID)
+	if err != nil {
+		access.Notes = append(access.Notes, fmt.Sprintf("Group memberships could not be read, so only policies granted directly are counted: %v", err))
+	}
+	objectIDs = append(objectIDs, groups...)
+	access.Capabilities = PolicyCapabilities(access.AccessPolicies, objectIDs, access.Identity.AppID)
+}
+
+// PolicyCapabilities evaluates each capability against the access policies that apply to any of the
+// object IDs. A compound identity policy only applies when the caller is signed in as its application.
+func PolicyCapabilities(policies []*models.AccessPolicy, objectIDs []string, appID string) []models.VaultCapability {
+	ids := make(map[string]bool)
+	for _, id := range objectIDs {
+		ids[strings.ToLower(id)] = true
+	}
+
+	granted := map[string]map[string]bool{
+		models.VaultItemSecret:      {},
+		models.VaultItemKey:         {},
+		models.VaultItemCertificate: {},
+	}
+	for _, policy := range policies {
+		if !ids[strings.ToLower(policy.ObjectID)] {
+			continue
+		}
+		if policy.ApplicationID != "" && !strings.EqualFold(policy.ApplicationID, appID) {
+			continue
+		}
+		for _, p := range policy.Secrets {
+			granted[models.VaultItemSecret][p] = true
+		}
+		for _, p := range policy.Keys {
+			granted[models.VaultItemKey][p] = true
+		}
+		for _, p := range policy.Certificates {
+			granted[models.VaultItemCertificate][p] = true
+		}
+	}
+
+	capabilities := make([]models.VaultCapability, len(vaultCapabilities))
+	for i, capability := range vaultCapabilities {
+		perms := granted[capability.itemType]
+		capabilities[i] = models.VaultCapability{
+			Name:    capability.name,
+			Allowed: perms[capability.permission] || perms[accessPolicyAll],
+		}
+	}
+	return capabilities
+}
+
+// uniqueStrings returns the distinct non-empty values, compared case-insensitively, in their original order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
+
+// lastSegment returns the last segment of a resource ID
+func lastSegment(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
+
+// stringValue dereferences an optional string
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,242 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVaultID = "/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.KeyVault/vaults/kv-1"
+
+// capabilityAllowed returns whether the named capability is allowed
+func capabilityAllowed(t *testing.T, capabilities []models.VaultCapability, name string) bool {
+	t.Helper()
+	for _, capability := range capabilities {
+		if capability.Name == name {
+			return capability.Allowed
+		}
+	}
+	t.Fatalf("capability %q not found", name)
+	return false
+}
+
+func TestMatchAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		action  string
+		want    bool
+	}{
+		{name: "Exact", pattern: "Microsoft.KeyVault/vaults/keys/read", action: "Microsoft.KeyVault/vaults/keys/read", want: true},
+		{name: "Case insensitive", pattern: "microsoft.keyvault/vaults/KEYS/read", action: "Microsoft.KeyVault/vaults/keys/read", want: true},
+		{name: "Different action", pattern: "Microsoft.KeyVault/vaults/keys/read", action: "Microsoft.KeyVault/vaults/keys/delete", want: false},
+		{name: "Trailing wildcard", pattern: "Microsoft.KeyVault/vaults/secrets/*", action: "Microsoft.KeyVault/vaults/secrets/getSecret/action", want: true},
+		{name: "Wildcard other type", pattern: "Microsoft.KeyVault/vaults/secrets/*", action: "Microsoft.KeyVault/vaults/keys/read", want: false},
+		{name: "Everything", pattern: "*", action: "Microsoft.KeyVault/vaults/keys/read", want: true},
+		{name: "Inner wildcard", pattern: "Microsoft.KeyVault/vaults/*/read", action: "Microsoft.KeyVault/vaults/certificates/read", want: true},
+		{name: "Inner wildcard mismatch", pattern: "Microsoft.KeyVault/vaults/*/read", action: "Microsoft.KeyVault/vaults/certificates/delete", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchAction(tt.pattern, tt.action))
+		})
+	}
+}
+
+func TestRBACCapabilities(t *testing.T) {
+	// Key Vault Secrets User and a role that grants all keys actions except delete
+	permissions := []*armauthorization.Permission{
+		{DataActions: []*string{to.Ptr("Microsoft.KeyVault/vaults/secrets/getSecret/action"), to.Ptr("Microsoft.KeyVault/vaults/secrets/readMetadata/action")}},
+		{
+			DataActions:    []*string{to.Ptr("Microsoft.KeyVault/vaults/keys/*")},
+			NotDataActions: []*string{to.Ptr("Microsoft.KeyVault/vaults/keys/delete")},
+		},
+	}
+
+	capabilities := RBACCapabilities(permissions)
+
+	assert.Len(t, capabilities, len(vaultCapabilities))
+	assert.True(t, capabilityAllowed(t, capabilities, "List secrets"))
+	assert.True(t, capabilityAllowed(t, capabilities, "Read secret values"))
+	assert.False(t, capabilityAllowed(t, capabilities, "Create and update secrets"))
+	assert.True(t, capabilityAllowed(t, capabilities, "Encrypt with keys"))
+	assert.False(t, capabilityAllowed(t, capabilities, "Delete keys"))
+	assert.False(t, capabilityAllowed(t, capabilities, "List certificates"))
+}
+
+func TestRBACCapabilitiesNoPermissions(t *testing.T) {
+	for _, capability := range RBACCapabilities(nil) {
+		assert.False(t, capability.Allowed, capability.Name)
+	}
+}
+
+func TestPolicyCapabilities(t *testing.T) {
+	policies := []*models.AccessPolicy{
+		{ObjectID: "USER-1", Secrets: []string{"get", "list"}},
+		{ObjectID: "group-1", Keys: []string{"all"}},
+		{ObjectID: "user-1", ApplicationID: "app-1", Certificates: []string{"list", "create"}},
+		{ObjectID: "other", Secrets: []string{"set", "delete"}},
+	}
+
+	tests := []struct {
+		name      string
+		objectIDs []string
+		appID     string
+		allowed   []string
+		denied    []string
+	}{
+		{
+			name:      "Direct policy",
+			objectIDs: []string{"user-1"},
+			allowed:   []string{"List secrets", "Read secret values"},
+			denied:    []string{"Create and update secrets", "List keys", "List certificates"},
+		},
+		{
+			name:      "Group policy with all",
+			objectIDs: []string{"user-1", "group-1"},
+			allowed:   []string{"List keys", "Rotate keys", "Delete keys"},
+			denied:    []string{"Delete secrets"},
+		},
+		{
+			name:      "Compound identity",
+			objectIDs: []string{"user-1"},
+			appID:     "APP-1",
+			allowed:   []string{"List certificates", "Create certificates"},
+			denied:    []string{"Import certificates"},
+		},
+		{
+			name:      "No matching policy",
+			objectIDs: []string{"nobody"},
+			denied:    []string{"List secrets", "List keys", "List certificates"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities := PolicyCapabilities(policies, tt.objectIDs, tt.appID)
+			for _, name := range tt.allowed {
+				assert.True(t, capabilityAllowed(t, capabilities, name), name)
+			}
+			for _, name := range tt.denied {
+				assert.False(t, capabilityAllowed(t, capabilities, name), name)
+			}
+		})
+	}
+}
+
+func TestConvertAccessPolicies(t *testing.T) {
+	entries := []*armkeyvault.AccessPolicyEntry{
+		nil,
+		{
+			TenantID:      to.Ptr("tenant-1"),
+			ObjectID:      to.Ptr("object-1"),
+			ApplicationID: to.Ptr("app-1"),
+			Permissions: &armkeyvault.Permissions{
+				Secrets:      []*armkeyvault.SecretPermissions{to.Ptr(armkeyvault.SecretPermissionsGet), to.Ptr(armkeyvault.SecretPermissions("List"))},
+				Keys:         []*armkeyvault.KeyPermissions{to.Ptr(armkeyvault.KeyPermissionsAll)},
+				Certificates: []*armkeyvault.CertificatePermissions{nil},
+			},
+		},
+		{ObjectID: to.Ptr("object-2")},
+	}
+
+	policies := convertAccessPolicies(entries)
+
+	require.Len(t, policies, 2)
+	assert.Equal(t, "tenant-1", policies[0].TenantID)
+	assert.Equal(t, "object-1", policies[0].ObjectID)
+	assert.Equal(t, "app-1", policies[0].ApplicationID)
+	assert.Equal(t, []string{"get", "list"}, policies[0].Secrets)
+	assert.Equal(t, []string{"all"}, policies[0].Keys)
+	assert.Empty(t, policies[0].Certificates)
+	assert.Equal(t, "object-2", policies[1].ObjectID)
+	assert.Empty(t, policies[1].Secrets)
+}
+
+func TestConvertRoleAssignment(t *testing.T) {
+	assignment := func(scope string) *armauthorization.RoleAssignment {
+		return &armauthorization.RoleAssignment{
+			ID: to.Ptr(scope + "/providers/Microsoft.Authorization/roleAssignments/ra-1"),
+			Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      to.Ptr("principal-1"),
+				PrincipalType:    to.Ptr(armauthorization.PrincipalTypeGroup),
+				RoleDefinitionID: to.Ptr("/subscriptions/sub-1/providers/Microsoft.Authorization/roleDefinitions/def-1"),
+				Scope:            to.Ptr(scope),
+			},
+		}
+	}
+
+	atVault := convertRoleAssignment(assignment(testVaultID), testVaultID)
+	assert.Equal(t, "principal-1", atVault.PrincipalID)
+	assert.Equal(t, "Group", atVault.PrincipalType)
+	assert.Equal(t, testVaultID, atVault.Scope)
+	assert.False(t, atVault.Inherited)
+
+	differentCase := convertRoleAssignment(assignment("/subscriptions/sub-1/resourcegroups/rg-1/providers/microsoft.keyvault/vaults/KV-1"), testVaultID)
+	assert.False(t, differentCase.Inherited)
+
+	inherited := convertRoleAssignment(assignment("/subscriptions/sub-1"), testVaultID)
+	assert.True(t, inherited.Inherited)
+}
+
+func TestSortRoleAssignments(t *testing.T) {
+	assignments := []*models.RoleAssignment{
+		{RoleName: "Reader", PrincipalID: "b", Inherited: true},
+		{RoleName: "Key Vault Secrets User", PrincipalID: "b"},
+		{RoleName: "Key Vault Administrator", PrincipalID: "a", Inherited: true},
+		{RoleName: "Key Vault Secrets User", PrincipalID: "a"},
+	}
+
+	sortRoleAssignments(assignments)
+
+	var order []string
+	for _, a := range assignments {
+		order = append(order, a.RoleName+"/"+a.PrincipalID)
+	}
+	assert.Equal(t, []string{
+		"Key Vault Secrets User/a",
+		"Key Vault Secrets User/b",
+		"Key Vault Administrator/a",
+		"Reader/b",
+	}, order)
+}
+
+func TestApplyPrincipalNames(t *testing.T) {
+	access := &models.VaultAccess{
+		AccessPolicies:  []*models.AccessPolicy{{ObjectID: "OBJECT-1"}, {ObjectID: "object-2"}},
+		RoleAssignments: []*models.RoleAssignment{{PrincipalID: "object-1"}},
+	}
+	objects := map[string]DirectoryObject{
+		"object-1": {ID: "object-1", Type: "user", DisplayName: "Alex Doe"},
+	}
+
+	applyPrincipalNames(access, objects)
+
+	assert.Equal(t, "Alex Doe", access.AccessPolicies[0].DisplayName)
+	assert.Equal(t, "user", access.AccessPolicies[0].PrincipalType)
+	assert.Empty(t, access.AccessPolicies[1].DisplayName)
+	assert.Equal(t, "Alex Doe", access.RoleAssignments[0].DisplayName)
+}
+
+func TestUniqueStrings(t *testing.T) {
+	assert.Equal(t, []string{"a", "B"}, uniqueStrings([]string{"a", "", "B", "A", "b"}))
+	assert.Nil(t, uniqueStrings(nil))
+}
+
+func TestIsForbidden(t *testing.T) {
+	assert.True(t, IsForbidden(&azcore.ResponseError{StatusCode: http.StatusForbidden}))
+	assert.True(t, IsForbidden(fmt.Errorf("failed to get next page: %w", &azcore.ResponseError{StatusCode: http.StatusForbidden})))
+	assert.False(t, IsForbidden(&azcore.ResponseError{StatusCode: http.StatusNotFound}))
+	assert.False(t, IsForbidden(errors.New("forbidden")))
+}
@@ -0,0 +1,336 @@
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+const (
+	// backupFormatVersion is the version of the backup directory layout written by BackupVaultItems
+	backupFormatVersion = 1
+	// BackupManifestName is the name of the manifest file in a backup directory
+	BackupManifestName = "manifest.json"
+)
+
+// BackupVaultItem returns the opaque, service-encrypted backup blob of a secret, key or certificate
+func (c *Client) BackupVaultItem(ctx context.Context, vaultURL string, item models.VaultItemRef) ([]byte, error) {
+	switch item.Type {
+	case models.VaultItemSecret:
+		client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secrets client: %w", err)
+		}
+		resp, err := client.BackupSecret(ctx, item.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up secret: %w", err)
+		}
+		return resp.Value, nil
+	case models.VaultItemKey:
+		client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create keys client: %w", err)
+		}
+		resp, err := client.BackupKey(ctx, item.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up key: %w", err)
+		}
+		return resp.Value, nil
+	case models.VaultItemCertificate:
+		client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create certificates client: %w", err)
+		}
+		resp, err := client.BackupCertificate(ctx, item.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up certificate: %w", err)
+		}
+		return resp.Value, nil
+	default:
+		return nil, fmt.Errorf("unsupported Key Vault item type %q", item.Type)
+	}
+}
+
+// RestoreVaultItem restores a backup blob into a vault. The item must not exist in the vault,
+// not even as a deleted item, and the vault must be in the same subscription and geography as the source.
+func (c *Client) RestoreVaultItem(ctx context.Context, vaultURL, itemType string, backup []byte) error {
+	switch itemType {
+	case models.VaultItemSecret:
+		client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create secrets client: %w", err)
+		}
+		if _, err := client.RestoreSecret(ctx, azsecrets.RestoreSecretParameters{SecretBackup: backup}, nil); err != nil {
+			return fmt.Errorf("failed to restore secret: %w", err)
+		}
+	case models.VaultItemKey:
+		client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create keys client: %w", err)
+		}
+		if _, err := client.RestoreKey(ctx, azkeys.RestoreKeyParameters{KeyBackup: backup}, nil); err != nil {
+			return fmt.Errorf("failed to restore key: %w", err)
+		}
+	case models.VaultItemCertificate:
+		client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create certificates client: %w", err)
+		}
+		if _, err := client.RestoreCertificate(ctx, azcertificates.RestoreCertificateParameters{CertificateBackup: backup}, nil); err != nil {
+			return fmt.Errorf("failed to restore certificate: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported Key Vault item type %q", itemType)
+	}
+	return nil
+}
+
+// BackupVaultItems backs up items into a new or empty local directory: one file per item plus a
+// manifest. Files are only readable by the current user and are not encrypted locally beyond the
+// service encryption of the backup blobs. onProgress is called after every item; the final
+// progress is also returned. Items already written are kept in the manifest if the backup is stopped.
+func (c *Client) BackupVaultItems(ctx context.Context, vaultName, vaultURL string, items []models.VaultItemRef, dir string, onProgress func(models.ItemProgress)) (models.ItemProgress, error) {
+	backup := func(ctx context.Context, item models.VaultItemRef) ([]byte, error) {
+		return c.BackupVaultItem(ctx, vaultURL, item)
+	}
+	return backupItems(ctx, vaultName, vaultURL, items, dir, backup, onProgress)
+}
+
+// backupItems writes a backup directory using the given function to fetch each backup blob
+func backupItems(ctx context.Context, vaultName, vaultURL string, items []models.VaultItemRef, dir string, backup func(context.Context, models.VaultItemRef) ([]byte, error), onProgress func(models.ItemProgress)) (models.ItemProgress, error) {
+	progress := models.ItemProgress{Total: len(items)}
+
+	// Files of an earlier or partial backup would be mixed with this one, so only an empty directory is used
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return progress, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return progress, fmt.Errorf("%s is not empty; choose a new or empty directory", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return progress, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	manifestPath := filepath.Join(dir, BackupManifestName)
+	manifest := models.BackupManifest{
+		FormatVersion: backupFormatVersion,
+		Vault:         vaultName,
+		VaultURL:      vaultURL,
+		Created:       time.Now().UTC(),
+		Encryption:    models.BackupEncryptionService,
+	}
+
+	var runErr error
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			runErr = err
+			break
+		}
+
+		entry, err := backupItem(ctx, item, dir, backup)
+		result := models.ItemResult{Item: item}
+		if err != nil {
+			result.Error = err.Error()
+			progress.Failed++
+		} else {
+			manifest.Items = append(manifest.Items, entry)
+			progress.Succeeded++
+		}
+		progress.Results = append(progress.Results, result)
+		onProgress(progress)
+	}
+
+	if len(manifest.Items) > 0 {
+		if err := writeBackupManifest(manifestPath, &manifest); err != nil {
+			return progress, err
+		}
+	}
+	return progress, runErr
+}
+
+// backupItem fetches one backup blob and writes it to its file in the backup directory
+func backupItem(ctx context.Context, item models.VaultItemRef, dir string, backup func(context.Context, models.VaultItemRef) ([]byte, error)) (models.BackupManifestItem, error) {
+	file, err := backupFileName(item)
+	if err != nil {
+		return models.BackupManifestItem{}, err
+	}
+
+	data, err := backup(ctx, item)
+	if err != nil {
+		return models.BackupManifestItem{}, err
+	}
+
+	path := filepath.Join(dir, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return models.BackupManifestItem{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := writeNewFile(path, data); err != nil {
+		return models.BackupManifestItem{}, fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return models.BackupManifestItem{
+		Type:   item.Type,
+		Name:   item.Name,
+		File:   filepath.ToSlash(file),
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// backupFileName returns the path of an item's backup file relative to the backup directory
+func backupFileName(item models.VaultItemRef) (string, error) {
+	switch item.Type {
+	case models.VaultItemSecret, models.VaultItemKey, models.VaultItemCertificate:
+	default:
+		return "", fmt.Errorf("unsupported Key Vault item type %q", item.Type)
+	}
+	// Key Vault names are limited to letters, digits and dashes, so they are safe as file names
+	if err := ValidateSecretName(item.Name); err != nil {
+		return "", fmt.Errorf("invalid %s name %q: %w", item.Type, item.Name, err)
+	}
+	return filepath.Join(item.Type+"s", item.Name+".kvbackup"), nil
+}
+
+// writeBackupManifest writes a manifest file readable only by the current user
+func writeBackupManifest(path string, manifest *models.BackupManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadBackupManifest reads and validates the manifest of a backup directory
+func ReadBackupManifest(dir string) (*models.BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BackupManifestName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s does not contain a Key Vault backup (%s not found)", dir, BackupManifestName)
+		}
+		return nil, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+
+	var manifest models.BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %w", err)
+	}
+	if manifest.FormatVersion != backupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", manifest.FormatVersion)
+	}
+	for _, item := range manifest.Items {
+		expected, err := backupFileName(models.VaultItemRef{Type: item.Type, Name: item.Name})
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest entry: %w", err)
+		}
+		if filepath.FromSlash(item.File) != expected {
+			return nil, fmt.Errorf("invalid manifest entry: unexpected file %q for %s %q", item.File, item.Type, item.Name)
+		}
+	}
+	return &manifest, nil
+}
+
+// RestoreVaultItems restores items of a backup directory into a vault. Each file is checked against
+// the size and SHA-256 in the manifest before it is uploaded. onProgress is called after every item;
+// the final progress is also returned.
+func (c *Client) RestoreVaultItems(ctx context.Context, vaultURL, dir string, items []models.BackupManifestItem, onProgress func(models.ItemProgress)) (models.ItemProgress, error) {
+	restore := func(ctx context.Context, itemType string, data []byte) error {
+		return c.RestoreVaultItem(ctx, vaultURL, itemType, data)
+	}
+	return restoreItems(ctx, dir, items, restore, onProgress)
+}
+
+// restoreItems restores backup files using the given function to upload each backup blob
+func restoreItems(ctx context.Context, dir string, items []models.BackupManifestItem, restore func(context.Context, string, []byte) error, onProgress func(models.ItemProgress)) (models.ItemProgress, error) {
+	progress := models.ItemProgress{Total: len(items)}
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		result := models.ItemResult{Item: models.VaultItemRef{Type: item.Type, Name: item.Name}}
+		data, err := readBackupFile(dir, item)
+		if err == nil {
+			err = restore(ctx, item.Type, data)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			progress.Failed++
+		} else {
+			progress.Succeeded++
+		}
+		progress.Results = append(progress.Results, result)
+		onProgress(progress)
+	}
+	return progress, nil
+}
+
+// readBackupFile reads an item's backup file and verifies it against the manifest
+func readBackupFile(dir string, item models.BackupManifestItem) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(item.File)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file: %w", err)
+	}
+	if int64(len(data)) != item.Size {
+		return nil, fmt.Errorf("backup file %s is %d bytes, manifest says %d", item.File, len(data), item.Size)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != item.SHA256 {
+		return nil, fmt.Errorf("backup file %s does not match its checksum", item.File)
+	}
+	return data, nil
+}
+
+// VaultBackupItems lists the items to back up for a whole vault. Secrets and keys that share their
+// name with a certificate back that certificate and are included in its backup, so they are left out.
+func VaultBackupItems(secrets, keys, certificates []string) []models.VaultItemRef {
+	certificateNames := make(map[string]bool)
+	for _, name := range certificates {
+		certificateNames[name] = true
+	}
+
+	var items []models.VaultItemRef
+	for _, name := range secrets {
+		if !certificateNames[name] {
+			items = append(items, models.VaultItemRef{Type: models.VaultItemSecret, Name: name})
+		}
+	}
+	for _, name := range keys {
+		if !certificateNames[name] {
+			items = append(items, models.VaultItemRef{Type: models.VaultItemKey, Name: name})
+		}
+	}
+	for _, name := range certificates {
+		items = append(items, models.VaultItemRef{Type: models.VaultItemCertificate, Name: name})
+	}
+	return items
+}
+
+// writeNewFile writes data to a file readable only by the current user, failing if the file exists
+func writeNewFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
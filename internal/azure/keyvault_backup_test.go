@@ -0,0 +1,143 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupFileName(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      models.VaultItemRef
+		expected  string
+		expectErr bool
+	}{
+		{name: "Secret", item: models.VaultItemRef{Type: models.VaultItemSecret, Name: "db-password"}, expected: filepath.Join("secrets", "db-password.kvbackup")},
+		{name: "Certificate", item: models.VaultItemRef{Type: models.VaultItemCertificate, Name: "tls"}, expected: filepath.Join("certificates", "tls.kvbackup")},
+		{name: "Path traversal", item: models.VaultItemRef{Type: models.VaultItemKey, Name: "../etc"}, expectErr: true},
+		{name: "Unknown type", item: models.VaultItemRef{Type: "blob", Name: "a"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := backupFileName(tt.item)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestBackupAndRestoreItems(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backup")
+	items := []models.VaultItemRef{
+		{Type: models.VaultItemSecret, Name: "db-password"},
+		{Type: models.VaultItemKey, Name: "broken"},
+		{Type: models.VaultItemCertificate, Name: "tls"},
+	}
+	backup := func(ctx context.Context, item models.VaultItemRef) ([]byte, error) {
+		if item.Name == "broken" {
+			return nil, errors.New("forbidden")
+		}
+		return []byte("blob-" + item.Name), nil
+	}
+
+	var updates int
+	progress, err := backupItems(context.Background(), "vault", "https://vault.vault.azure.net/", items, dir, backup, func(models.ItemProgress) { updates++ })
+	require.NoError(t, err)
+	assert.Equal(t, 3, updates)
+	assert.Equal(t, 2, progress.Succeeded)
+	assert.Equal(t, 1, progress.Failed)
+	assert.Equal(t, "forbidden", progress.Results[1].Error)
+
+	info, err := os.Stat(filepath.Join(dir, "secrets", "db-password.kvbackup"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	manifest, err := ReadBackupManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "vault", manifest.Vault)
+	assert.Equal(t, models.BackupEncryptionService, manifest.Encryption)
+	require.Len(t, manifest.Items, 2, "Failed items are not in the manifest")
+	assert.Equal(t, "secrets/db-password.kvbackup", manifest.Items[0].File)
+
+	// A second backup into the same directory is refused
+	_, err = backupItems(context.Background(), "vault", "", items, dir, backup, func(models.ItemProgress) {})
+	assert.Error(t, err)
+
+	// So is a directory holding the files of a partial backup without a manifest
+	partial := filepath.Join(t.TempDir(), "partial")
+	require.NoError(t, os.MkdirAll(filepath.Join(partial, "secrets"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(partial, "secrets", "db-password.kvbackup"), []byte("old"), 0600))
+	_, err = backupItems(context.Background(), "vault", "", items, partial, backup, func(models.ItemProgress) {})
+	assert.ErrorContains(t, err, "not empty")
+	data, err := os.ReadFile(filepath.Join(partial, "secrets", "db-password.kvbackup"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data), "Existing files are not overwritten")
+
+	// An empty existing directory can be used
+	empty := t.TempDir()
+	progress, err = backupItems(context.Background(), "vault", "", items, empty, backup, func(models.ItemProgress) {})
+	require.NoError(t, err)
+	assert.Equal(t, 2, progress.Succeeded)
+
+	restored := make(map[string]string)
+	restore := func(ctx context.Context, itemType string, data []byte) error {
+		restored[itemType] = string(data)
+		return nil
+	}
+	progress, err = restoreItems(context.Background(), dir, manifest.Items, restore, func(models.ItemProgress) {})
+	require.NoError(t, err)
+	assert.Equal(t, 2, progress.Succeeded)
+	assert.Equal(t, map[string]string{models.VaultItemSecret: "blob-db-password", models.VaultItemCertificate: "blob-tls"}, restored)
+
+	// A tampered file fails its checksum and is not uploaded
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "certificates", "tls.kvbackup"), []byte("blob-xyz"), 0600))
+	progress, err = restoreItems(context.Background(), dir, manifest.Items, restore, func(models.ItemProgress) {})
+	require.NoError(t, err)
+	assert.Equal(t, 1, progress.Failed)
+	assert.Contains(t, progress.Results[1].Error, "checksum")
+}
+
+func TestReadBackupManifest_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ReadBackupManifest(dir)
+	assert.Error(t, err, "Missing manifest")
+
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, BackupManifestName), []byte(content), 0600))
+	}
+
+	write(`{"formatVersion": 2, "items": []}`)
+	_, err = ReadBackupManifest(dir)
+	assert.Error(t, err, "Unknown format version")
+
+	write(`{"formatVersion": 1, "items": [{"type": "secret", "name": "a", "file": "../a.kvbackup"}]}`)
+	_, err = ReadBackupManifest(dir)
+	assert.Error(t, err, "File outside the backup directory")
+
+	write(`{"formatVersion": 1, "items": [{"type": "secret", "name": "a", "file": "secrets/a.kvbackup"}]}`)
+	_, err = ReadBackupManifest(dir)
+	assert.NoError(t, err)
+}
+
+func TestVaultBackupItems(t *testing.T) {
+	items := VaultBackupItems([]string{"db", "tls"}, []string{"signing", "tls"}, []string{"tls"})
+
+	assert.Equal(t, []models.VaultItemRef{
+		{Type: models.VaultItemSecret, Name: "db"},
+		{Type: models.VaultItemKey, Name: "signing"},
+		{Type: models.VaultItemCertificate, Name: "tls"},
+	}, items)
+}
@@ -0,0 +1,375 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// Certificate issuers with a special meaning in a certificate policy
+const (
+	CertificateIssuerSelf    = "Self"
+	CertificateIssuerUnknown = "Unknown" // The CSR is signed outside Key Vault and merged afterwards
+)
+
+// Content types of the secret backing a certificate
+const (
+	CertificateContentTypePKCS12 = "application/x-pkcs12"
+	CertificateContentTypePEM    = "application/x-pem-file"
+)
+
+// Certificate download formats
+const (
+	CertificateDownloadCER  = "CER"
+	CertificateDownloadPEM  = "PEM"
+	CertificateDownloadFull = "Full (with private key)"
+	CertificateDownloadCSR  = "CSR"
+)
+
+// CertificateDownloadFormats lists the certificate download formats in display order
+var CertificateDownloadFormats = []string{CertificateDownloadCER, CertificateDownloadPEM, CertificateDownloadFull, CertificateDownloadCSR}
+
+// ValidateCertificateName checks a certificate name against the Key Vault naming rules
+func ValidateCertificateName(name string) error {
+	return validateVaultItemName(models.VaultItemCertificate, name)
+}
+
+// ParseSubjectAlternativeNames splits comma or line separated subject alternative names into DNS names
+// and email addresses
+func ParseSubjectAlternativeNames(text string) (dnsNames, emails []string, err error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ' '
+	})
+	for _, field := range fields {
+		switch {
+		case strings.Contains(field, "@"):
+			emails = append(emails, field)
+		case strings.Trim(field, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-.*") != "":
+			return nil, nil, fmt.Errorf("invalid DNS name %q", field)
+		default:
+			dnsNames = append(dnsNames, field)
+		}
+	}
+	return dnsNames, emails, nil
+}
+
+// buildCertificatePolicy validates a new certificate and converts it to a certificate policy
+func buildCertificatePolicy(cert *models.NewCertificate) (*azcertificates.CertificatePolicy, error) {
+	if !strings.Contains(cert.Subject, "=") {
+		return nil, fmt.Errorf("subject must be a distinguished name such as CN=www.contoso.com")
+	}
+	if cert.Issuer == "" {
+		return nil, fmt.Errorf("enter an issuer: Self, Unknown or the name of a configured issuer")
+	}
+	if cert.ValidityMonths < 1 || cert.ValidityMonths > 1200 {
+		return nil, fmt.Errorf("validity must be 1-1200 months")
+	}
+	if cert.RenewPercentage < 1 || cert.RenewPercentage > 99 {
+		return nil, fmt.Errorf("renewal must be at 1-99%% of the lifetime")
+	}
+	if cert.ContentType != CertificateContentTypePKCS12 && cert.ContentType != CertificateContentTypePEM {
+		return nil, fmt.Errorf("unknown certificate content type %q", cert.ContentType)
+	}
+
+	keyProperties := &azcertificates.KeyProperties{
+		KeyType:    to.Ptr(azcertificates.KeyType(cert.KeyType)),
+		Exportable: to.Ptr(cert.Exportable),
+		ReuseKey:   to.Ptr(false),
+	}
+	if baseKeyType(cert.KeyType) == "RSA" {
+		keyProperties.KeySize = to.Ptr(int32(cert.KeySize))
+	} else {
+		keyProperties.Curve = to.Ptr(azcertificates.CurveName(cert.Curve))
+	}
+
+	x509Properties := &azcertificates.X509CertificateProperties{
+		Subject:          to.Ptr(cert.Subject),
+		ValidityInMonths: to.Ptr(int32(cert.ValidityMonths)),
+	}
+	if len(cert.DNSNames) > 0 || len(cert.Emails) > 0 {
+		x509Properties.SubjectAlternativeNames = &azcertificates.SubjectAlternativeNames{}
+		for _, name := range cert.DNSNames {
+			x509Properties.SubjectAlternativeNames.DNSNames = append(x509Properties.SubjectAlternativeNames.DNSNames, to.Ptr(name))
+		}
+		for _, email := range cert.Emails {
+			x509Properties.SubjectAlternativeNames.Emails = append(x509Properties.SubjectAlternativeNames.Emails, to.Ptr(email))
+		}
+	}
+
+	action := azcertificates.CertificatePolicyActionEmailContacts
+	if cert.AutoRenew {
+		action = azcertificates.CertificatePolicyActionAutoRenew
+	}
+
+	return &azcertificates.CertificatePolicy{
+		IssuerParameters:          &azcertificates.IssuerParameters{Name: to.Ptr(cert.Issuer)},
+		KeyProperties:             keyProperties,
+		SecretProperties:          &azcertificates.SecretProperties{ContentType: to.Ptr(cert.ContentType)},
+		X509CertificateProperties: x509Properties,
+		LifetimeActions: []*azcertificates.LifetimeAction{{
+			Action:  &azcertificates.LifetimeActionType{ActionType: to.Ptr(action)},
+			Trigger: &azcertificates.LifetimeActionTrigger{LifetimePercentage: to.Ptr(int32(cert.RenewPercentage))},
+		}},
+	}, nil
+}
+
+// CreateCertificate starts issuing a certificate. Issuance is asynchronous; the returned operation
+// holds the CSR when the issuer is Unknown.
+func (c *Client) CreateCertificate(ctx context.Context, vaultURL string, cert *models.NewCertificate) (*models.CertificateOperation, error) {
+	certPolicy, err := buildCertificatePolicy(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificates client: %w", err)
+	}
+	resp, err := client.CreateCertificate(ctx, cert.Name, azcertificates.CreateCertificateParameters{
+		CertificatePolicy:     certPolicy,
+		CertificateAttributes: &azcertificates.CertificateAttributes{Enabled: to.Ptr(true)},
+		Tags:                  toMetadataPointers(cert.Tags),
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return convertCertificateOperation(resp.CertificateOperation), nil
+}
+
+// GetCertificateOperation returns the state of the latest issuance of a certificate
+func (c *Client) GetCertificateOperation(ctx context.Context, vaultURL, certName string) (*models.CertificateOperation, error) {
+	client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificates client: %w", err)
+	}
+	resp, err := client.GetCertificateOperation(ctx, certName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate operation: %w", err)
+	}
+	return convertCertificateOperation(resp.CertificateOperation), nil
+}
+
+// convertCertificateOperation converts an SDK certificate operation
+func convertCertificateOperation(op azcertificates.CertificateOperation) *models.CertificateOperation {
+	operation := &models.CertificateOperation{CSR: op.CSR}
+	if op.Status != nil {
+		operation.Status = *op.Status
+	}
+	if op.StatusDetails != nil {
+		operation.StatusDetails = *op.StatusDetails
+	}
+	if op.IssuerParameters != nil && op.IssuerParameters.Name != nil {
+		operation.Issuer = *op.IssuerParameters.Name
+	}
+	if op.RequestID != nil {
+		operation.RequestID = *op.RequestID
+	}
+	if op.Target != nil {
+		operation.Target = *op.Target
+	}
+	if op.Error != nil {
+		operation.Error = op.Error.Error()
+	}
+	if op.CancellationRequested != nil {
+		operation.CancellationRequested = *op.CancellationRequested
+	}
+	return operation
+}
+
+// ParseCertificateChain reads certificates from PEM, DER or base64 DER data and returns them DER encoded
+func ParseCertificateChain(data []byte) ([][]byte, error) {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		var chain [][]byte
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, fmt.Errorf("invalid certificate: %w", err)
+			}
+			chain = append(chain, block.Bytes)
+		}
+		if len(chain) == 0 {
+			return nil, fmt.Errorf("no certificate found in the PEM data")
+		}
+		return chain, nil
+	}
+
+	certs, err := x509.ParseCertificates(data)
+	if err != nil {
+		if decoded, decodeErr := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil))); decodeErr == nil {
+			certs, err = x509.ParseCertificates(decoded)
+		}
+	}
+	if err != nil || len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found; expected PEM, DER or base64 DER")
+	}
+	chain := make([][]byte, len(certs))
+	for i, cert := range certs {
+		chain[i] = cert.Raw
+	}
+	return chain, nil
+}
+
+// MergeCertificate completes a pending certificate with the certificate, and optionally its chain,
+// signed by the CA from the CSR
+func (c *Client) MergeCertificate(ctx context.Context, vaultURL, certName string, signed []byte) error {
+	chain, err := ParseCertificateChain(signed)
+	if err != nil {
+		return err
+	}
+
+	client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create certificates client: %w", err)
+	}
+	if _, err := client.MergeCertificate(ctx, certName, azcertificates.MergeCertificateParameters{X509Certificates: chain}, nil); err != nil {
+		return fmt.Errorf("failed to merge certificate: %w", err)
+	}
+	return nil
+}
+
+// certificateImportValue validates a PFX or PEM file for import and returns the content type and the value
+// Key Vault expects: the PEM text, or the base64 encoded PFX
+func certificateImportValue(data []byte) (string, string, error) {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		var hasCert, hasKey bool
+		for rest := data; ; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch {
+			case block.Type == "CERTIFICATE":
+				hasCert = true
+			case block.Type == "ENCRYPTED PRIVATE KEY":
+				return "", "", fmt.Errorf("encrypted PEM private keys are not supported; use a PFX file with a password instead")
+			case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+				hasKey = true
+			}
+		}
+		if !hasCert || !hasKey {
+			return "", "", fmt.Errorf("a PEM file must hold the certificate and its private key")
+		}
+		return CertificateContentTypePEM, string(data), nil
+	}
+
+	// A PFX file is a DER encoded PKCS #12 structure, which starts with a SEQUENCE tag
+	if len(data) == 0 || data[0] != 0x30 {
+		return "", "", fmt.Errorf("not a PFX or PEM certificate file")
+	}
+	return CertificateContentTypePKCS12, base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ImportCertificate imports a certificate with its private key from a PFX or PEM file. The password
+// only applies to PFX files.
+func (c *Client) ImportCertificate(ctx context.Context, vaultURL, certName string, data []byte, password string) error {
+	contentType, value, err := certificateImportValue(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create certificates client: %w", err)
+	}
+	params := azcertificates.ImportCertificateParameters{
+		Base64EncodedCertificate: to.Ptr(value),
+		CertificatePolicy: &azcertificates.CertificatePolicy{
+			SecretProperties: &azcertificates.SecretProperties{ContentType: to.Ptr(contentType)},
+		},
+	}
+	if password != "" {
+		params.Password = to.Ptr(password)
+	}
+	if _, err := client.ImportCertificate(ctx, certName, params, nil); err != nil {
+		return fmt.Errorf("failed to import certificate: %w", err)
+	}
+	return nil
+}
+
+// DownloadCertificate returns a certificate in one of the CertificateDownload formats, with the file
+// extension that suits it. The full format is read from the backing secret and holds the private key.
+func (c *Client) DownloadCertificate(ctx context.Context, vaultURL, certName, format string) ([]byte, string, error) {
+	switch format {
+	case CertificateDownloadCER, CertificateDownloadPEM:
+		client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create certificates client: %w", err)
+		}
+		resp, err := client.GetCertificate(ctx, certName, "", nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get certificate: %w", err)
+		}
+		if len(resp.CER) == 0 {
+			return nil, "", fmt.Errorf("the certificate has not been issued yet")
+		}
+		if format == CertificateDownloadPEM {
+			return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: resp.CER}), ".pem", nil
+		}
+		return resp.CER, ".cer", nil
+
+	case CertificateDownloadFull:
+		client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create secrets client: %w", err)
+		}
+		resp, err := client.GetSecret(ctx, certName, "", nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get certificate secret: %w", err)
+		}
+		if resp.Value == nil {
+			return nil, "", fmt.Errorf("certificate secret value is nil")
+		}
+		var contentType string
+		if resp.ContentType != nil {
+			contentType = *resp.ContentType
+		}
+		return certificateSecretBytes(contentType, *resp.Value)
+
+	case CertificateDownloadCSR:
+		op, err := c.GetCertificateOperation(ctx, vaultURL, certName)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(op.CSR) == 0 {
+			return nil, "", fmt.Errorf("the certificate has no pending signing request")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: op.CSR}), ".csr", nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown certificate format %q", format)
+	}
+}
+
+// certificateSecretBytes decodes the value of the secret backing a certificate: base64 PFX or PEM text
+func certificateSecretBytes(contentType, value string) ([]byte, string, error) {
+	switch contentType {
+	case CertificateContentTypePKCS12:
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid PFX in the certificate secret: %w", err)
+		}
+		return data, ".pfx", nil
+	case CertificateContentTypePEM:
+		return []byte(value), ".pem", nil
+	default:
+		return nil, "", fmt.Errorf("unknown certificate secret content type %q", contentType)
+	}
+}
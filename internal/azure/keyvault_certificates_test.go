@@ -0,0 +1,192 @@
+package azure
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCertificate creates a self-signed certificate and returns it DER encoded with its PKCS #8 private key
+func testCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return der, pkcs8
+}
+
+func TestParseSubjectAlternativeNames(t *testing.T) {
+	dnsNames, emails, err := ParseSubjectAlternativeNames("www.contoso.com, *.contoso.com\nadmin@contoso.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"www.contoso.com", "*.contoso.com"}, dnsNames)
+	assert.Equal(t, []string{"admin@contoso.com"}, emails)
+
+	dnsNames, emails, err = ParseSubjectAlternativeNames("")
+	require.NoError(t, err)
+	assert.Empty(t, dnsNames)
+	assert.Empty(t, emails)
+
+	_, _, err = ParseSubjectAlternativeNames("contoso.com, https://contoso.com")
+	assert.Error(t, err)
+}
+
+func TestBuildCertificatePolicy(t *testing.T) {
+	valid := func() *models.NewCertificate {
+		return &models.NewCertificate{
+			Name:            "web",
+			Subject:         "CN=www.contoso.com",
+			DNSNames:        []string{"www.contoso.com"},
+			Issuer:          CertificateIssuerSelf,
+			KeyType:         "RSA",
+			KeySize:         2048,
+			Exportable:      true,
+			ContentType:     CertificateContentTypePKCS12,
+			ValidityMonths:  12,
+			AutoRenew:       true,
+			RenewPercentage: 80,
+		}
+	}
+
+	policy, err := buildCertificatePolicy(valid())
+	require.NoError(t, err)
+	assert.Equal(t, "Self", *policy.IssuerParameters.Name)
+	assert.Equal(t, int32(2048), *policy.KeyProperties.KeySize)
+	assert.Nil(t, policy.KeyProperties.Curve)
+	assert.Equal(t, "www.contoso.com", *policy.X509CertificateProperties.SubjectAlternativeNames.DNSNames[0])
+	assert.Equal(t, azcertificates.CertificatePolicyActionAutoRenew, *policy.LifetimeActions[0].Action.ActionType)
+	assert.Equal(t, int32(80), *policy.LifetimeActions[0].Trigger.LifetimePercentage)
+
+	ec := valid()
+	ec.KeyType, ec.Curve, ec.AutoRenew, ec.DNSNames = "EC", "P-384", false, nil
+	policy, err = buildCertificatePolicy(ec)
+	require.NoError(t, err)
+	assert.Equal(t, azcertificates.CurveNameP384, *policy.KeyProperties.Curve)
+	assert.Nil(t, policy.KeyProperties.KeySize)
+	assert.Nil(t, policy.X509CertificateProperties.SubjectAlternativeNames)
+	assert.Equal(t, azcertificates.CertificatePolicyActionEmailContacts, *policy.LifetimeActions[0].Action.ActionType)
+
+	invalid := map[string]func(*models.NewCertificate){
+		"Subject":      func(c *models.NewCertificate) { c.Subject = "www.contoso.com" },
+		"Issuer":       func(c *models.NewCertificate) { c.Issuer = "" },
+		"Validity":     func(c *models.NewCertificate) { c.ValidityMonths = 0 },
+		"Renewal":      func(c *models.NewCertificate) { c.RenewPercentage = 100 },
+		"Content type": func(c *models.NewCertificate) { c.ContentType = "text/plain" },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			cert := valid()
+			modify(cert)
+			_, err := buildCertificatePolicy(cert)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConvertCertificateOperation(t *testing.T) {
+	op := convertCertificateOperation(azcertificates.CertificateOperation{
+		Status:           to.Ptr("inProgress"),
+		StatusDetails:    to.Ptr("Pending certificate created."),
+		IssuerParameters: &azcertificates.IssuerParameters{Name: to.Ptr("Unknown")},
+		CSR:              []byte{0x30, 0x01},
+	})
+	assert.Equal(t, &models.CertificateOperation{
+		Status:        "inProgress",
+		StatusDetails: "Pending certificate created.",
+		Issuer:        "Unknown",
+		CSR:           []byte{0x30, 0x01},
+	}, op)
+}
+
+func TestParseCertificateChain(t *testing.T) {
+	leaf, _ := testCertificate(t, "leaf")
+	ca, _ := testCertificate(t, "ca")
+	pemChain := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca})...)
+
+	tests := []struct {
+		name      string
+		data      []byte
+		expected  [][]byte
+		expectErr bool
+	}{
+		{name: "PEM chain", data: pemChain, expected: [][]byte{leaf, ca}},
+		{name: "DER", data: leaf, expected: [][]byte{leaf}},
+		{name: "Base64 DER", data: []byte(base64.StdEncoding.EncodeToString(leaf) + "\n"), expected: [][]byte{leaf}},
+		{name: "PEM without certificates", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: []byte("x")}), expectErr: true},
+		{name: "Garbage", data: []byte("not a certificate"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseCertificateChain(tt.data)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, chain)
+		})
+	}
+}
+
+func TestCertificateImportValue(t *testing.T) {
+	der, pkcs8 := testCertificate(t, "import")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+
+	contentType, value, err := certificateImportValue(append(keyPEM, certPEM...))
+	require.NoError(t, err)
+	assert.Equal(t, CertificateContentTypePEM, contentType)
+	assert.Equal(t, string(keyPEM)+string(certPEM), value)
+
+	pfx := []byte{0x30, 0x82, 0x01, 0x02}
+	contentType, value, err = certificateImportValue(pfx)
+	require.NoError(t, err)
+	assert.Equal(t, CertificateContentTypePKCS12, contentType)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(pfx), value)
+
+	_, _, err = certificateImportValue(certPEM)
+	assert.Error(t, err, "PEM without a private key")
+	_, _, err = certificateImportValue(append(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("x")}), certPEM...))
+	assert.Error(t, err, "encrypted PEM")
+	_, _, err = certificateImportValue([]byte("hello"))
+	assert.Error(t, err, "not a certificate")
+}
+
+func TestCertificateSecretBytes(t *testing.T) {
+	data, ext, err := certificateSecretBytes(CertificateContentTypePKCS12, base64.StdEncoding.EncodeToString([]byte{0x30, 0x01}))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x30, 0x01}, data)
+	assert.Equal(t, ".pfx", ext)
+
+	data, ext, err = certificateSecretBytes(CertificateContentTypePEM, "-----BEGIN CERTIFICATE-----")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("-----BEGIN CERTIFICATE-----"), data)
+	assert.Equal(t, ".pem", ext)
+
+	_, _, err = certificateSecretBytes(CertificateContentTypePKCS12, "not base64!")
+	assert.Error(t, err)
+	_, _, err = certificateSecretBytes("text/plain", "x")
+	assert.Error(t, err)
+}
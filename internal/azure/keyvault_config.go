@@ -0,0 +1,308 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+)
+
+// Public network access values of a vault
+const (
+	publicNetworkAccessEnabled  = "Enabled"
+	publicNetworkAccessDisabled = "Disabled"
+)
+
+// Private endpoint connection actions
+const (
+	PrivateEndpointApprove = "Approve"
+	PrivateEndpointReject  = "Reject"
+	PrivateEndpointRemove  = "Remove"
+)
+
+var (
+	// PublicNetworkAccessOptions are the accepted public network access values
+	PublicNetworkAccessOptions = []string{publicNetworkAccessEnabled, publicNetworkAccessDisabled}
+	// NetworkDefaultActions are the firewall actions for traffic not matched by a rule
+	NetworkDefaultActions = []string{string(armkeyvault.NetworkRuleActionAllow), string(armkeyvault.NetworkRuleActionDeny)}
+	// NetworkBypassOptions are the accepted firewall bypass values
+	NetworkBypassOptions = []string{string(armkeyvault.NetworkRuleBypassOptionsAzureServices), string(armkeyvault.NetworkRuleBypassOptionsNone)}
+	// PrivateEndpointActions are the actions on a private endpoint connection
+	PrivateEndpointActions = []string{PrivateEndpointApprove, PrivateEndpointReject, PrivateEndpointRemove}
+)
+
+// VaultConfigOf returns the editable configuration of a vault
+func VaultConfigOf(vault *models.KeyVault) models.VaultConfig {
+	return models.VaultConfig{
+		PublicNetworkAccess:          vault.PublicNetworkAccess,
+		NetworkDefaultAction:         vault.NetworkDefaultAction,
+		NetworkBypass:                vault.NetworkBypass,
+		IPRules:                      slices.Clone(vault.IPRules),
+		VirtualNetworkRules:          slices.Clone(vault.VirtualNetworkRules),
+		PurgeProtection:              vault.PurgeProtectionEnabled,
+		EnabledForDeployment:         vault.EnabledForDeploy,
+		EnabledForDiskEncryption:     vault.EnabledForDisk,
+		EnabledForTemplateDeployment: vault.EnabledForTemplate,
+	}
+}
+
+// ParseIPRules parses IPv4 addresses and CIDR ranges separated by newlines or commas
+func ParseIPRules(text string) ([]string, error) {
+	var rules []string
+	for _, value := range splitRuleList(text) {
+		if strings.Contains(value, "/") {
+			ip, _, err := net.ParseCIDR(value)
+			if err != nil || ip.To4() == nil {
+				return nil, fmt.Errorf("invalid IP rule %q: expected an IPv4 address or CIDR range", value)
+			}
+		} else if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("invalid IP rule %q: expected an IPv4 address or CIDR range", value)
+		}
+		if !containsFold(rules, value) {
+			rules = append(rules, value)
+		}
+	}
+	return rules, nil
+}
+
+// ParseVirtualNetworkRules parses subnet resource IDs separated by newlines or commas
+func ParseVirtualNetworkRules(text string) ([]string, error) {
+	var rules []string
+	for _, value := range splitRuleList(text) {
+		parts := strings.Split(strings.Trim(value, "/"), "/")
+		if len(parts) != 10 || !strings.EqualFold(parts[0], "subscriptions") || !strings.EqualFold(parts[2], "resourceGroups") ||
+			!strings.EqualFold(parts[4], "providers") || !strings.EqualFold(parts[5], "Microsoft.Network") ||
+			!strings.EqualFold(parts[6], "virtualNetworks") || !strings.EqualFold(parts[8], "subnets") {
+			return nil, fmt.Errorf("invalid virtual network rule %q: expected a subnet resource ID", value)
+		}
+		if !containsFold(rules, value) {
+			rules = append(rules, value)
+		}
+	}
+	return rules, nil
+}
+
+// splitRuleList splits a rule list on newlines and commas, dropping empty entries
+func splitRuleList(text string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ',' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ValidateVaultConfig checks an updated configuration against the current one for changes Key Vault rejects
+func ValidateVaultConfig(current, updated models.VaultConfig) error {
+	if !slices.Contains(PublicNetworkAccessOptions, updated.PublicNetworkAccess) {
+		return fmt.Errorf("invalid public network access %q", updated.PublicNetworkAccess)
+	}
+	if !slices.Contains(NetworkDefaultActions, updated.NetworkDefaultAction) {
+		return fmt.Errorf("invalid network default action %q", updated.NetworkDefaultAction)
+	}
+	if !slices.Contains(NetworkBypassOptions, updated.NetworkBypass) {
+		return fmt.Errorf("invalid network bypass %q", updated.NetworkBypass)
+	}
+	if current.PurgeProtection && !updated.PurgeProtection {
+		return fmt.Errorf("purge protection cannot be disabled once enabled")
+	}
+	return nil
+}
+
+// DiffVaultConfig lists the changes from the current to the updated configuration. Rules are compared
+// case-insensitively because Key Vault may change the case of subnet IDs.
+func DiffVaultConfig(current, updated models.VaultConfig) []models.VaultConfigChange {
+	var changes []models.VaultConfigChange
+	add := func(setting, from, to string) {
+		if from != to {
+			changes = append(changes, models.VaultConfigChange{Setting: setting, From: from, To: to})
+		}
+	}
+	addRules := func(setting string, from, to []string) {
+		for _, rule := range from {
+			if !containsFold(to, rule) {
+				changes = append(changes, models.VaultConfigChange{Setting: setting, From: rule})
+			}
+		}
+		for _, rule := range to {
+			if !containsFold(from, rule) {
+				changes = append(changes, models.VaultConfigChange{Setting: setting, To: rule})
+			}
+		}
+	}
+
+	add("Public Network Access", current.PublicNetworkAccess, updated.PublicNetworkAccess)
+	add("Default Action", current.NetworkDefaultAction, updated.NetworkDefaultAction)
+	add("Bypass", current.NetworkBypass, updated.NetworkBypass)
+	addRules("IP Rule", current.IPRules, updated.IPRules)
+	addRules("Virtual Network Rule", current.VirtualNetworkRules, updated.VirtualNetworkRules)
+	add("Purge Protection", strconv.FormatBool(current.PurgeProtection), strconv.FormatBool(updated.PurgeProtection))
+	add("Enabled For Deployment", strconv.FormatBool(current.EnabledForDeployment), strconv.FormatBool(updated.EnabledForDeployment))
+	add("Enabled For Disk Encryption", strconv.FormatBool(current.EnabledForDiskEncryption), strconv.FormatBool(updated.EnabledForDiskEncryption))
+	add("Enabled For Template Deployment", strconv.FormatBool(current.EnabledForTemplateDeployment), strconv.FormatBool(updated.EnabledForTemplateDeployment))
+	return changes
+}
+
+// buildVaultPatch builds a patch with only the changed settings. The network rule set is replaced as a
+// whole, so it is sent in full when any of its settings changed.
+func buildVaultPatch(current, updated models.VaultConfig) armkeyvault.VaultPatchParameters {
+	props := &armkeyvault.VaultPatchProperties{}
+	if current.PublicNetworkAccess != updated.PublicNetworkAccess {
+		props.PublicNetworkAccess = to.Ptr(updated.PublicNetworkAccess)
+	}
+	if current.NetworkDefaultAction != updated.NetworkDefaultAction || current.NetworkBypass != updated.NetworkBypass ||
+		!equalFold(current.IPRules, updated.IPRules) || !equalFold(current.VirtualNetworkRules, updated.VirtualNetworkRules) {
+		acls := &armkeyvault.NetworkRuleSet{
+			DefaultAction:       to.Ptr(armkeyvault.NetworkRuleAction(updated.NetworkDefaultAction)),
+			Bypass:              to.Ptr(armkeyvault.NetworkRuleBypassOptions(updated.NetworkBypass)),
+			IPRules:             []*armkeyvault.IPRule{},
+			VirtualNetworkRules: []*armkeyvault.VirtualNetworkRule{},
+		}
+		for _, rule := range updated.IPRules {
+			acls.IPRules = append(acls.IPRules, &armkeyvault.IPRule{Value: to.Ptr(rule)})
+		}
+		for _, rule := range updated.VirtualNetworkRules {
+			acls.VirtualNetworkRules = append(acls.VirtualNetworkRules, &armkeyvault.VirtualNetworkRule{ID: to.Ptr(rule)})
+		}
+		props.NetworkACLs = acls
+	}
+	// Purge protection can only be turned on; sending false is rejected
+	if !current.PurgeProtection && updated.PurgeProtection {
+		props.EnablePurgeProtection = to.Ptr(true)
+	}
+	if current.EnabledForDeployment != updated.EnabledForDeployment {
+		props.EnabledForDeployment = to.Ptr(updated.EnabledForDeployment)
+	}
+	if current.EnabledForDiskEncryption != updated.EnabledForDiskEncryption {
+		props.EnabledForDiskEncryption = to.Ptr(updated.EnabledForDiskEncryption)
+	}
+	if current.EnabledForTemplateDeployment != updated.EnabledForTemplateDeployment {
+		props.EnabledForTemplateDeployment = to.Ptr(updated.EnabledForTemplateDeployment)
+	}
+	return armkeyvault.VaultPatchParameters{Properties: props}
+}
+
+// UpdateVaultConfig applies the changes from the current to the updated configuration of a vault
+func (c *Client) UpdateVaultConfig(ctx context.Context, subscriptionID, resourceGroupName, vaultName string, current, updated models.VaultConfig) (*models.KeyVault, error) {
+	if err := ValidateVaultConfig(current, updated); err != nil {
+		return nil, err
+	}
+
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
+	}
+
+	resp, err := client.Update(ctx, resourceGroupName, vaultName, buildVaultPatch(current, updated), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Key Vault: %w", err)
+	}
+	return convertKeyVault(&resp.Vault), nil
+}
+
+// SetPrivateEndpointConnectionStatus approves or rejects a private endpoint connection
+func (c *Client) SetPrivateEndpointConnectionStatus(ctx context.Context, subscriptionID, resourceGroupName, vaultName, connectionName, action, description string) error {
+	var status armkeyvault.PrivateEndpointServiceConnectionStatus
+	switch action {
+	case PrivateEndpointApprove:
+		status = armkeyvault.PrivateEndpointServiceConnectionStatusApproved
+	case PrivateEndpointReject:
+		status = armkeyvault.PrivateEndpointServiceConnectionStatusRejected
+	default:
+		return fmt.Errorf("unsupported private endpoint connection action %q", action)
+	}
+
+	client, err := armkeyvault.NewPrivateEndpointConnectionsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create private endpoint connections client: %w", err)
+	}
+
+	existing, err := client.Get(ctx, resourceGroupName, vaultName, connectionName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get private endpoint connection: %w", err)
+	}
+
+	connection := armkeyvault.PrivateEndpointConnection{
+		Etag: existing.Etag,
+		Properties: &armkeyvault.PrivateEndpointConnectionProperties{
+			PrivateLinkServiceConnectionState: &armkeyvault.PrivateLinkServiceConnectionState{
+				Status:      to.Ptr(status),
+				Description: to.Ptr(description),
+			},
+		},
+	}
+	if _, err := client.Put(ctx, resourceGroupName, vaultName, connectionName, connection, nil); err != nil {
+		return fmt.Errorf("failed to update private endpoint connection: %w", err)
+	}
+	return nil
+}
+
+// DeletePrivateEndpointConnection removes a private endpoint connection from a vault
+func (c *Client) DeletePrivateEndpointConnection(ctx context.Context, subscriptionID, resourceGroupName, vaultName, connectionName string) error {
+	client, err := armkeyvault.NewPrivateEndpointConnectionsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create private endpoint connections client: %w", err)
+	}
+
+	poller, err := client.BeginDelete(ctx, resourceGroupName, vaultName, connectionName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete private endpoint connection: %w", err)
+	}
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("failed to delete private endpoint connection: %w", err)
+	}
+	return nil
+}
+
+// convertPrivateEndpointConnections converts the private endpoint connections of a vault
+func convertPrivateEndpointConnections(items []*armkeyvault.PrivateEndpointConnectionItem) []*models.PrivateEndpointConnection {
+	var connections []*models.PrivateEndpointConnection
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		connection := &models.PrivateEndpointConnection{Name: lastSegment(stringValue(item.ID))}
+		if props := item.Properties; props != nil {
+			if props.PrivateEndpoint != nil {
+				connection.PrivateEndpointID = stringValue(props.PrivateEndpoint.ID)
+			}
+			if state := props.PrivateLinkServiceConnectionState; state != nil {
+				if state.Status != nil {
+					connection.Status = string(*state.Status)
+				}
+				connection.Description = stringValue(state.Description)
+			}
+			if props.ProvisioningState != nil {
+				connection.ProvisioningState = string(*props.ProvisioningState)
+			}
+		}
+		connections = append(connections, connection)
+	}
+	return connections
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+// equalFold reports whether two rule lists hold the same entries, ignoring case and order
+func equalFold(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, value := range a {
+		if !containsFold(b, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package azure
+
+import (
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSubnetID = "/subscriptions/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/virtualNetworks/vnet-1/subnets/default"
+
+// testVaultConfig returns a valid configuration with a firewall
+func testVaultConfig() models.VaultConfig {
+	return models.VaultConfig{
+		PublicNetworkAccess:  "Enabled",
+		NetworkDefaultAction: "Deny",
+		NetworkBypass:        "AzureServices",
+		IPRules:              []string{"203.0.113.10/32"},
+		VirtualNetworkRules:  []string{testSubnetID},
+	}
+}
+
+func TestParseIPRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Empty", text: " \n ", want: nil},
+		{name: "Address and range", text: "203.0.113.10\n198.51.100.0/24", want: []string{"203.0.113.10", "198.51.100.0/24"}},
+		{name: "Comma separated", text: "203.0.113.10, 198.51.100.0/24,", want: []string{"203.0.113.10", "198.51.100.0/24"}},
+		{name: "Duplicates removed", text: "203.0.113.10\n203.0.113.10", want: []string{"203.0.113.10"}},
+		{name: "IPv6 rejected", text: "2001:db8::1", wantErr: true},
+		{name: "Invalid range", text: "203.0.113.0/33", wantErr: true},
+		{name: "Not an address", text: "example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseIPRules(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rules)
+		})
+	}
+}
+
+func TestParseVirtualNetworkRules(t *testing.T) {
+	rules, err := ParseVirtualNetworkRules(testSubnetID + "\n" + testSubnetID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testSubnetID}, rules)
+
+	_, err = ParseVirtualNetworkRules("/subscriptions/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/virtualNetworks/vnet-1")
+	assert.Error(t, err)
+	_, err = ParseVirtualNetworkRules("default")
+	assert.Error(t, err)
+}
+
+func TestValidateVaultConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(current, updated *models.VaultConfig)
+		wantErr string
+	}{
+		{name: "Valid", modify: func(current, updated *models.VaultConfig) {}},
+		{name: "Enable purge protection", modify: func(current, updated *models.VaultConfig) { updated.PurgeProtection = true }},
+		{name: "Disable purge protection", modify: func(current, updated *models.VaultConfig) {
+			current.PurgeProtection = true
+		}, wantErr: "cannot be disabled"},
+		{name: "Invalid public network access", modify: func(current, updated *models.VaultConfig) { updated.PublicNetworkAccess = "Open" }, wantErr: "public network access"},
+		{name: "Invalid default action", modify: func(current, updated *models.VaultConfig) { updated.NetworkDefaultAction = "Block" }, wantErr: "default action"},
+		{name: "Invalid bypass", modify: func(current, updated *models.VaultConfig) { updated.NetworkBypass = "All" }, wantErr: "bypass"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, updated := testVaultConfig(), testVaultConfig()
+			tt.modify(&current, &updated)
+			err := ValidateVaultConfig(current, updated)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDiffVaultConfig(t *testing.T) {
+	current := testVaultConfig()
+	assert.Empty(t, DiffVaultConfig(current, testVaultConfig()))
+
+	updated := testVaultConfig()
+	updated.NetworkDefaultAction = "Allow"
+	updated.IPRules = []string{"198.51.100.0/24"}
+	updated.VirtualNetworkRules = []string{"/SUBSCRIPTIONS/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/virtualNetworks/vnet-1/subnets/default"}
+	updated.EnabledForDeployment = true
+
+	assert.Equal(t, []models.VaultConfigChange{
+		{Setting: "Default Action", From: "Deny", To: "Allow"},
+		{Setting: "IP Rule", From: "203.0.113.10/32"},
+		{Setting: "IP Rule", To: "198.51.100.0/24"},
+		{Setting: "Enabled For Deployment", From: "false", To: "true"},
+	}, DiffVaultConfig(current, updated))
+}
+
+func TestBuildVaultPatch(t *testing.T) {
+	t.Run("Only changed settings", func(t *testing.T) {
+		current, updated := testVaultConfig(), testVaultConfig()
+		updated.EnabledForDiskEncryption = true
+		updated.PurgeProtection = true
+
+		props := buildVaultPatch(current, updated).Properties
+		assert.Nil(t, props.NetworkACLs)
+		assert.Nil(t, props.PublicNetworkAccess)
+		assert.Nil(t, props.SoftDeleteRetentionInDays)
+		assert.Nil(t, props.EnabledForDeployment)
+		assert.Equal(t, to.Ptr(true), props.EnabledForDiskEncryption)
+		assert.Equal(t, to.Ptr(true), props.EnablePurgeProtection)
+	})
+
+	t.Run("Network rules sent in full", func(t *testing.T) {
+		current, updated := testVaultConfig(), testVaultConfig()
+		updated.IPRules = append(updated.IPRules, "198.51.100.0/24")
+
+		acls := buildVaultPatch(current, updated).Properties.NetworkACLs
+		require.NotNil(t, acls)
+		assert.Equal(t, armkeyvault.NetworkRuleActionDeny, *acls.DefaultAction)
+		assert.Equal(t, armkeyvault.NetworkRuleBypassOptionsAzureServices, *acls.Bypass)
+		require.Len(t, acls.IPRules, 2)
+		assert.Equal(t, "198.51.100.0/24", *acls.IPRules[1].Value)
+		require.Len(t, acls.VirtualNetworkRules, 1)
+		assert.Equal(t, testSubnetID, *acls.VirtualNetworkRules[0].ID)
+	})
+
+	t.Run("Removing all rules sends empty lists", func(t *testing.T) {
+		current, updated := testVaultConfig(), testVaultConfig()
+		updated.IPRules = nil
+		updated.VirtualNetworkRules = nil
+
+		acls := buildVaultPatch(current, updated).Properties.NetworkACLs
+		require.NotNil(t, acls)
+		assert.NotNil(t, acls.IPRules)
+		assert.Empty(t, acls.IPRules)
+		assert.NotNil(t, acls.VirtualNetworkRules)
+		assert.Empty(t, acls.VirtualNetworkRules)
+	})
+}
+
+func TestConvertKeyVaultNetwork(t *testing.T) {
+	vault := &armkeyvault.Vault{
+		ID:   to.Ptr(testVaultID),
+		Name: to.Ptr("kv-1"),
+		Properties: &armkeyvault.VaultProperties{
+			PublicNetworkAccess: to.Ptr("Disabled"),
+			NetworkACLs: &armkeyvault.NetworkRuleSet{
+				DefaultAction:       to.Ptr(armkeyvault.NetworkRuleActionDeny),
+				Bypass:              to.Ptr(armkeyvault.NetworkRuleBypassOptionsNone),
+				IPRules:             []*armkeyvault.IPRule{{Value: to.Ptr("203.0.113.10/32")}, nil},
+				VirtualNetworkRules: []*armkeyvault.VirtualNetworkRule{{ID: to.Ptr(testSubnetID)}},
+			},
+			PrivateEndpointConnections: []*armkeyvault.PrivateEndpointConnectionItem{
+				{
+					ID: to.Ptr(testVaultID + "/privateEndpointConnections/pe-1"),
+					Properties: &armkeyvault.PrivateEndpointConnectionProperties{
+						PrivateEndpoint: &armkeyvault.PrivateEndpoint{ID: to.Ptr("/subscriptions/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/privateEndpoints/pe-1")},
+						PrivateLinkServiceConnectionState: &armkeyvault.PrivateLinkServiceConnectionState{
+							Status:      to.Ptr(armkeyvault.PrivateEndpointServiceConnectionStatusPending),
+							Description: to.Ptr("please approve"),
+						},
+						ProvisioningState: to.Ptr(armkeyvault.PrivateEndpointConnectionProvisioningStateSucceeded),
+					},
+				},
+				nil,
+			},
+		},
+	}
+
+	kv := convertKeyVault(vault)
+	assert.Equal(t, "Disabled", kv.PublicNetworkAccess)
+	assert.Equal(t, "Deny", kv.NetworkDefaultAction)
+	assert.Equal(t, "None", kv.NetworkBypass)
+	assert.Equal(t, []string{"203.0.113.10/32"}, kv.IPRules)
+	assert.Equal(t, []string{testSubnetID}, kv.VirtualNetworkRules)
+	require.Len(t, kv.PrivateEndpointConnections, 1)
+	assert.Equal(t, &models.PrivateEndpointConnection{
+		Name:              "pe-1",
+		PrivateEndpointID: "/subscriptions/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/privateEndpoints/pe-1",
+		Status:            "Pending",
+		Description:       "please approve",
+		ProvisioningState: "Succeeded",
+	}, kv.PrivateEndpointConnections[0])
+
+	defaults := convertKeyVault(&armkeyvault.Vault{Properties: &armkeyvault.VaultProperties{}})
+	assert.Equal(t, "Enabled", defaults.PublicNetworkAccess)
+	assert.Equal(t, "Allow", defaults.NetworkDefaultAction)
+	assert.Equal(t, "AzureServices", defaults.NetworkBypass)
+}
@@ -0,0 +1,536 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+// Key types, sizes and curves offered when creating keys
+var (
+	KeyTypes  = []string{"RSA", "RSA-HSM", "EC", "EC-HSM"}
+	KeySizes  = []string{"2048", "3072", "4096"}
+	KeyCurves = []string{"P-256", "P-384", "P-521", "P-256K"}
+)
+
+// Encodings for crypto console input and output
+const (
+	EncodingText   = "text"
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+)
+
+// CryptoEncodings lists the crypto console encodings in display order
+var CryptoEncodings = []string{EncodingText, EncodingBase64, EncodingHex}
+
+var (
+	rsaEncryptionAlgorithms = []string{"RSA-OAEP-256", "RSA-OAEP", "RSA1_5"}
+	rsaSignatureAlgorithms  = []string{"PS256", "PS384", "PS512", "RS256", "RS384", "RS512"}
+	octWrapAlgorithms       = []string{"A256KW", "A192KW", "A128KW"}
+	ecSignatureAlgorithms   = map[string]string{"P-256": "ES256", "P-384": "ES384", "P-521": "ES512", "P-256K": "ES256K"}
+
+	isoDurationPattern = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+D)?$`)
+)
+
+// AllKeyOperations lists the crypto operations a key can permit
+var AllKeyOperations = []string{models.KeyOperationEncrypt, models.KeyOperationDecrypt, models.KeyOperationSign,
+	models.KeyOperationVerify, models.KeyOperationWrapKey, models.KeyOperationUnwrapKey}
+
+// ValidateKeyName checks a key name against the Key Vault naming rules
+func ValidateKeyName(name string) error {
+	return validateVaultItemName(models.VaultItemKey, name)
+}
+
+// ParseKeyOps parses a comma separated list of key operations; an empty list permits every operation
+func ParseKeyOps(text string) ([]string, error) {
+	var ops []string
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		known := false
+		for _, op := range AllKeyOperations {
+			if strings.EqualFold(field, op) {
+				ops = append(ops, op)
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown key operation %q; use %s", field, strings.Join(AllKeyOperations, ", "))
+		}
+	}
+	return ops, nil
+}
+
+// KeyOperations lists the crypto operations a key supports: those of its key type, limited to the
+// operations permitted on the key when it has any
+func KeyOperations(key *models.Key) []string {
+	var supported []string
+	switch baseKeyType(key.KeyType) {
+	case "RSA":
+		supported = AllKeyOperations
+	case "EC":
+		supported = []string{models.KeyOperationSign, models.KeyOperationVerify}
+	case "oct":
+		supported = []string{models.KeyOperationWrapKey, models.KeyOperationUnwrapKey}
+	}
+	if len(key.KeyOps) == 0 {
+		return supported
+	}
+
+	var operations []string
+	for _, op := range supported {
+		for _, permitted := range key.KeyOps {
+			if op == permitted {
+				operations = append(operations, op)
+				break
+			}
+		}
+	}
+	return operations
+}
+
+// CryptoAlgorithms lists the algorithms for an operation with a key, most recommended first
+func CryptoAlgorithms(key *models.Key, operation string) []string {
+	signing := operation == models.KeyOperationSign || operation == models.KeyOperationVerify
+	switch baseKeyType(key.KeyType) {
+	case "RSA":
+		if signing {
+			return rsaSignatureAlgorithms
+		}
+		return rsaEncryptionAlgorithms
+	case "EC":
+		if !signing {
+			return nil
+		}
+		if algorithm, ok := ecSignatureAlgorithms[key.Curve]; ok {
+			return []string{algorithm}
+		}
+		return []string{"ES256", "ES384", "ES512", "ES256K"}
+	case "oct":
+		if operation == models.KeyOperationWrapKey || operation == models.KeyOperationUnwrapKey {
+			return octWrapAlgorithms
+		}
+	}
+	return nil
+}
+
+// baseKeyType strips the -HSM suffix from a key type
+func baseKeyType(keyType string) string {
+	return strings.TrimSuffix(keyType, "-HSM")
+}
+
+// MessageDigest hashes a message with the hash function of a signature algorithm; Key Vault signs digests, not messages
+func MessageDigest(algorithm string, message []byte) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(algorithm, "256"), algorithm == "ES256K":
+		sum := sha256.Sum256(message)
+		return sum[:], nil
+	case strings.HasSuffix(algorithm, "384"):
+		sum := sha512.Sum384(message)
+		return sum[:], nil
+	case strings.HasSuffix(algorithm, "512"):
+		sum := sha512.Sum512(message)
+		return sum[:], nil
+	default:
+		return nil, fmt.Errorf("unknown signature algorithm %q", algorithm)
+	}
+}
+
+// DecodeCryptoInput decodes console input. Whitespace is ignored in base64 and hex input.
+func DecodeCryptoInput(input, encoding string) ([]byte, error) {
+	switch encoding {
+	case EncodingText:
+		return []byte(input), nil
+	case EncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(input), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 input: %w", err)
+		}
+		return data, nil
+	case EncodingHex:
+		data, err := hex.DecodeString(strings.Join(strings.Fields(input), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex input: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+// EncodeCryptoOutput encodes console output; text output has to be valid UTF-8
+func EncodeCryptoOutput(data []byte, encoding string) (string, error) {
+	switch encoding {
+	case EncodingText:
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("the output is binary, choose base64 or hex")
+		}
+		return string(data), nil
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case EncodingHex:
+		return hex.EncodeToString(data), nil
+	default:
+		return "", fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+// rsaModulusBits returns the size of an RSA key from its modulus
+func rsaModulusBits(n []byte) int {
+	return new(big.Int).SetBytes(n).BitLen()
+}
+
+// ValidateISODuration checks a Key Vault rotation policy duration such as P90D, P1Y or P1Y6M
+func ValidateISODuration(duration string) error {
+	if duration == "P" || !isoDurationPattern.MatchString(duration) {
+		return fmt.Errorf("%q is not a duration such as P30D, P6M or P1Y", duration)
+	}
+	return nil
+}
+
+// KeyCryptoOperation performs an encrypt, decrypt, sign, verify, wrap or unwrap operation with the latest
+// version of a key, or the given version. Sign and verify hash the input with the algorithm's hash function.
+func (c *Client) KeyCryptoOperation(ctx context.Context, vaultURL, keyName, version string, req models.KeyCryptoRequest) (*models.KeyCryptoResult, error) {
+	client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keys client: %w", err)
+	}
+
+	var result models.KeyCryptoResult
+	var kid *azkeys.ID
+	params := azkeys.KeyOperationParameters{
+		Algorithm: to.Ptr(azkeys.EncryptionAlgorithm(req.Algorithm)),
+		Value:     req.Input,
+	}
+
+	switch req.Operation {
+	case models.KeyOperationEncrypt:
+		resp, err := client.Encrypt(ctx, keyName, version, params, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt: %w", err)
+		}
+		kid, result.Output = resp.KID, resp.Result
+	case models.KeyOperationDecrypt:
+		resp, err := client.Decrypt(ctx, keyName, version, params, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt: %w", err)
+		}
+		kid, result.Output = resp.KID, resp.Result
+	case models.KeyOperationWrapKey:
+		resp, err := client.WrapKey(ctx, keyName, version, params, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap key: %w", err)
+		}
+		kid, result.Output = resp.KID, resp.Result
+	case models.KeyOperationUnwrapKey:
+		resp, err := client.UnwrapKey(ctx, keyName, version, params, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap key: %w", err)
+		}
+		kid, result.Output = resp.KID, resp.Result
+	case models.KeyOperationSign, models.KeyOperationVerify:
+		digest, err := MessageDigest(req.Algorithm, req.Input)
+		if err != nil {
+			return nil, err
+		}
+		algorithm := to.Ptr(azkeys.SignatureAlgorithm(req.Algorithm))
+		if req.Operation == models.KeyOperationSign {
+			resp, err := client.Sign(ctx, keyName, version, azkeys.SignParameters{Algorithm: algorithm, Value: digest}, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign: %w", err)
+			}
+			kid, result.Output = resp.KID, resp.Result
+		} else {
+			resp, err := client.Verify(ctx, keyName, version, azkeys.VerifyParameters{Algorithm: algorithm, Digest: digest, Signature: req.Signature}, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify: %w", err)
+			}
+			result.Verified = resp.Value != nil && *resp.Value
+		}
+	default:
+		return nil, fmt.Errorf("unknown key operation %q", req.Operation)
+	}
+
+	if kid != nil {
+		result.KeyID = string(*kid)
+	}
+	return &result, nil
+}
+
+// CreateKey creates a key, or a new version of an existing key
+func (c *Client) CreateKey(ctx context.Context, vaultURL string, key *models.NewKey) error {
+	client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create keys client: %w", err)
+	}
+
+	params := azkeys.CreateKeyParameters{
+		Kty:           to.Ptr(azkeys.KeyType(key.KeyType)),
+		KeyAttributes: &azkeys.KeyAttributes{Enabled: to.Ptr(true), Expires: key.Expires},
+		Tags:          toMetadataPointers(key.Tags),
+	}
+	if baseKeyType(key.KeyType) == "RSA" {
+		params.KeySize = to.Ptr(int32(key.KeySize))
+	} else {
+		params.Curve = to.Ptr(azkeys.CurveName(key.Curve))
+	}
+	for _, op := range key.KeyOps {
+		params.KeyOps = append(params.KeyOps, to.Ptr(azkeys.KeyOperation(op)))
+	}
+
+	if _, err := client.CreateKey(ctx, key.Name, params, nil); err != nil {
+		return fmt.Errorf("failed to create key: %w", err)
+	}
+	return nil
+}
+
+// ImportKey imports a private key from PEM or JWK material, optionally protected by an HSM
+func (c *Client) ImportKey(ctx context.Context, vaultURL, keyName string, material []byte, hsm bool) error {
+	jwk, err := ParseKeyMaterial(material)
+	if err != nil {
+		return err
+	}
+
+	client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create keys client: %w", err)
+	}
+	params := azkeys.ImportKeyParameters{
+		Key:           jwk,
+		HSM:           to.Ptr(hsm),
+		KeyAttributes: &azkeys.KeyAttributes{Enabled: to.Ptr(true)},
+	}
+	if _, err := client.ImportKey(ctx, keyName, params, nil); err != nil {
+		return fmt.Errorf("failed to import key: %w", err)
+	}
+	return nil
+}
+
+// ParseKeyMaterial reads a private key from a JSON Web Key or a PEM block (PKCS #1, PKCS #8 or SEC 1)
+func ParseKeyMaterial(material []byte) (*azkeys.JSONWebKey, error) {
+	material = bytes.TrimSpace(material)
+	if bytes.HasPrefix(material, []byte("{")) {
+		var jwk azkeys.JSONWebKey
+		if err := json.Unmarshal(material, &jwk); err != nil {
+			return nil, fmt.Errorf("invalid JSON Web Key: %w", err)
+		}
+		if jwk.Kty == nil {
+			return nil, fmt.Errorf("the JSON Web Key has no kty")
+		}
+		if len(jwk.D) == 0 && len(jwk.K) == 0 {
+			return nil, fmt.Errorf("the JSON Web Key has no private key; Key Vault can only import private keys")
+		}
+		return &jwk, nil
+	}
+
+	for {
+		var block *pem.Block
+		block, material = pem.Decode(material)
+		if block == nil {
+			return nil, fmt.Errorf("no private key found; expected a PEM private key or a JSON Web Key")
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf("encrypted private keys are not supported; decrypt the key first, for example with openssl pkcs8")
+		default:
+			// Skip certificates, public keys and EC parameters
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.ToLower(block.Type), err)
+		}
+		return privateKeyToJWK(key)
+	}
+}
+
+// privateKeyToJWK converts a parsed RSA or EC private key to a JSON Web Key
+func privateKeyToJWK(key interface{}) (*azkeys.JSONWebKey, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, fmt.Errorf("multi-prime RSA keys are not supported")
+		}
+		k.Precompute()
+		return &azkeys.JSONWebKey{
+			Kty: to.Ptr(azkeys.KeyTypeRSA),
+			N:   k.N.Bytes(),
+			E:   big.NewInt(int64(k.E)).Bytes(),
+			D:   k.D.Bytes(),
+			P:   k.Primes[0].Bytes(),
+			Q:   k.Primes[1].Bytes(),
+			DP:  k.Precomputed.Dp.Bytes(),
+			DQ:  k.Precomputed.Dq.Bytes(),
+			QI:  k.Precomputed.Qinv.Bytes(),
+		}, nil
+	case *ecdsa.PrivateKey:
+		curve := k.Curve.Params().Name
+		ecdhKey, err := k.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("unsupported EC key: %w", err)
+		}
+		// The uncompressed public point is 0x04 || X || Y
+		point := ecdhKey.PublicKey().Bytes()
+		size := (len(point) - 1) / 2
+		return &azkeys.JSONWebKey{
+			Kty: to.Ptr(azkeys.KeyTypeEC),
+			Crv: to.Ptr(azkeys.CurveName(curve)),
+			X:   point[1 : 1+size],
+			Y:   point[1+size:],
+			D:   ecdhKey.Bytes(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// RotateKey creates a new version of a key according to its rotation policy and returns the new version
+func (c *Client) RotateKey(ctx context.Context, vaultURL, keyName string) (string, error) {
+	client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create keys client: %w", err)
+	}
+	resp, err := client.RotateKey(ctx, keyName, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to rotate key: %w", err)
+	}
+	if resp.Key == nil || resp.Key.KID == nil {
+		return "", nil
+	}
+	return resp.Key.KID.Version(), nil
+}
+
+// GetKeyRotationPolicy returns the rotation policy of a key
+func (c *Client) GetKeyRotationPolicy(ctx context.Context, vaultURL, keyName string) (*models.KeyRotationPolicy, error) {
+	client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keys client: %w", err)
+	}
+	resp, err := client.GetKeyRotationPolicy(ctx, keyName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rotation policy: %w", err)
+	}
+	return convertRotationPolicy(resp.KeyRotationPolicy), nil
+}
+
+// UpdateKeyRotationPolicy replaces the rotation policy of a key
+func (c *Client) UpdateKeyRotationPolicy(ctx context.Context, vaultURL, keyName string, policy *models.KeyRotationPolicy) error {
+	azPolicy, err := buildRotationPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create keys client: %w", err)
+	}
+	if _, err := client.UpdateKeyRotationPolicy(ctx, keyName, azPolicy, nil); err != nil {
+		return fmt.Errorf("failed to update rotation policy: %w", err)
+	}
+	return nil
+}
+
+// convertRotationPolicy converts an SDK rotation policy
+func convertRotationPolicy(azPolicy azkeys.KeyRotationPolicy) *models.KeyRotationPolicy {
+	policy := &models.KeyRotationPolicy{}
+	if azPolicy.Attributes != nil {
+		if azPolicy.Attributes.ExpiryTime != nil {
+			policy.ExpiryTime = *azPolicy.Attributes.ExpiryTime
+		}
+		policy.Updated = azPolicy.Attributes.Updated
+	}
+
+	for _, action := range azPolicy.LifetimeActions {
+		if action == nil || action.Action == nil || action.Action.Type == nil || action.Trigger == nil {
+			continue
+		}
+		var after, before string
+		if action.Trigger.TimeAfterCreate != nil {
+			after = *action.Trigger.TimeAfterCreate
+		}
+		if action.Trigger.TimeBeforeExpiry != nil {
+			before = *action.Trigger.TimeBeforeExpiry
+		}
+		// Action types are compared case-insensitively by the service
+		switch {
+		case strings.EqualFold(string(*action.Action.Type), string(azkeys.KeyRotationPolicyActionRotate)):
+			policy.RotateAfterCreate = after
+			policy.RotateBeforeExpiry = before
+		case strings.EqualFold(string(*action.Action.Type), string(azkeys.KeyRotationPolicyActionNotify)):
+			policy.NotifyBeforeExpiry = before
+		}
+	}
+	return policy
+}
+
+// buildRotationPolicy validates a rotation policy and converts it for the SDK
+func buildRotationPolicy(policy *models.KeyRotationPolicy) (azkeys.KeyRotationPolicy, error) {
+	for _, duration := range []string{policy.ExpiryTime, policy.RotateAfterCreate, policy.RotateBeforeExpiry, policy.NotifyBeforeExpiry} {
+		if duration == "" {
+			continue
+		}
+		if err := ValidateISODuration(duration); err != nil {
+			return azkeys.KeyRotationPolicy{}, err
+		}
+	}
+	if policy.RotateAfterCreate != "" && policy.RotateBeforeExpiry != "" {
+		return azkeys.KeyRotationPolicy{}, fmt.Errorf("rotate either a time after creation or a time before expiry, not both")
+	}
+
+	azPolicy := azkeys.KeyRotationPolicy{
+		Attributes: &azkeys.KeyRotationPolicyAttributes{},
+	}
+	if policy.ExpiryTime != "" {
+		azPolicy.Attributes.ExpiryTime = to.Ptr(policy.ExpiryTime)
+	}
+
+	rotate := &azkeys.LifetimeActionTrigger{}
+	switch {
+	case policy.RotateAfterCreate != "":
+		rotate.TimeAfterCreate = to.Ptr(policy.RotateAfterCreate)
+	case policy.RotateBeforeExpiry != "":
+		rotate.TimeBeforeExpiry = to.Ptr(policy.RotateBeforeExpiry)
+	}
+	if rotate.TimeAfterCreate != nil || rotate.TimeBeforeExpiry != nil {
+		azPolicy.LifetimeActions = append(azPolicy.LifetimeActions, &azkeys.LifetimeAction{
+			Action:  &azkeys.LifetimeActionType{Type: to.Ptr(azkeys.KeyRotationPolicyActionRotate)},
+			Trigger: rotate,
+		})
+	}
+	if policy.NotifyBeforeExpiry != "" {
+		azPolicy.LifetimeActions = append(azPolicy.LifetimeActions, &azkeys.LifetimeAction{
+			Action:  &azkeys.LifetimeActionType{Type: to.Ptr(azkeys.KeyRotationPolicyActionNotify)},
+			Trigger: &azkeys.LifetimeActionTrigger{TimeBeforeExpiry: to.Ptr(policy.NotifyBeforeExpiry)},
+		})
+	}
+	return azPolicy, nil
+}
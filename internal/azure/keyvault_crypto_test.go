@@ -0,0 +1,253 @@
+package azure
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      models.Key
+		expected []string
+	}{
+		{name: "RSA", key: models.Key{KeyType: "RSA"}, expected: []string{"encrypt", "decrypt", "sign", "verify", "wrapKey", "unwrapKey"}},
+		{name: "RSA limited by key ops", key: models.Key{KeyType: "RSA-HSM", KeyOps: []string{"sign", "verify", "import"}}, expected: []string{"sign", "verify"}},
+		{name: "EC", key: models.Key{KeyType: "EC"}, expected: []string{"sign", "verify"}},
+		{name: "EC without permitted operations", key: models.Key{KeyType: "EC", KeyOps: []string{"wrapKey"}}, expected: nil},
+		{name: "Symmetric", key: models.Key{KeyType: "oct-HSM"}, expected: []string{"wrapKey", "unwrapKey"}},
+		{name: "Unknown", key: models.Key{KeyType: "OKP"}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, KeyOperations(&tt.key))
+		})
+	}
+}
+
+func TestParseKeyOps(t *testing.T) {
+	ops, err := ParseKeyOps(" sign, Verify ,,wrapkey")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sign", "verify", "wrapKey"}, ops)
+
+	ops, err = ParseKeyOps("")
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+
+	_, err = ParseKeyOps("sign, import")
+	assert.Error(t, err)
+}
+
+func TestValidateKeyName(t *testing.T) {
+	assert.NoError(t, ValidateKeyName("signing-key-1"))
+	assert.Error(t, ValidateKeyName(""))
+	assert.Error(t, ValidateKeyName("signing_key"))
+}
+
+func TestCryptoAlgorithms(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       models.Key
+		operation string
+		expected  []string
+	}{
+		{name: "RSA encrypt", key: models.Key{KeyType: "RSA"}, operation: "encrypt", expected: []string{"RSA-OAEP-256", "RSA-OAEP", "RSA1_5"}},
+		{name: "RSA wrap", key: models.Key{KeyType: "RSA-HSM"}, operation: "wrapKey", expected: []string{"RSA-OAEP-256", "RSA-OAEP", "RSA1_5"}},
+		{name: "RSA sign", key: models.Key{KeyType: "RSA"}, operation: "sign", expected: []string{"PS256", "PS384", "PS512", "RS256", "RS384", "RS512"}},
+		{name: "EC P-384 verify", key: models.Key{KeyType: "EC", Curve: "P-384"}, operation: "verify", expected: []string{"ES384"}},
+		{name: "EC P-256K sign", key: models.Key{KeyType: "EC-HSM", Curve: "P-256K"}, operation: "sign", expected: []string{"ES256K"}},
+		{name: "EC unknown curve", key: models.Key{KeyType: "EC"}, operation: "sign", expected: []string{"ES256", "ES384", "ES512", "ES256K"}},
+		{name: "EC encrypt", key: models.Key{KeyType: "EC", Curve: "P-256"}, operation: "encrypt", expected: nil},
+		{name: "Symmetric unwrap", key: models.Key{KeyType: "oct-HSM"}, operation: "unwrapKey", expected: []string{"A256KW", "A192KW", "A128KW"}},
+		{name: "Symmetric sign", key: models.Key{KeyType: "oct-HSM"}, operation: "sign", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CryptoAlgorithms(&tt.key, tt.operation))
+		})
+	}
+}
+
+func TestMessageDigest(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		size      int
+		expectErr bool
+	}{
+		{algorithm: "RS256", size: 32},
+		{algorithm: "PS384", size: 48},
+		{algorithm: "ES512", size: 64},
+		{algorithm: "ES256K", size: 32},
+		{algorithm: "RSA-OAEP", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			digest, err := MessageDigest(tt.algorithm, []byte("message"))
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, digest, tt.size)
+		})
+	}
+}
+
+func TestCryptoEncoding(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		encoding  string
+		expected  []byte
+		expectErr bool
+	}{
+		{name: "Text", input: "hello ", encoding: EncodingText, expected: []byte("hello ")},
+		{name: "Base64 with line breaks", input: "aGVs\nbG8=", encoding: EncodingBase64, expected: []byte("hello")},
+		{name: "Hex with spaces", input: "68 65 6c 6c 6f", encoding: EncodingHex, expected: []byte("hello")},
+		{name: "Invalid base64", input: "a$b", encoding: EncodingBase64, expectErr: true},
+		{name: "Invalid hex", input: "6", encoding: EncodingHex, expectErr: true},
+		{name: "Unknown encoding", input: "x", encoding: "base32", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DecodeCryptoInput(tt.input, tt.encoding)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, data)
+		})
+	}
+
+	encoded, err := EncodeCryptoOutput([]byte("hello"), EncodingBase64)
+	require.NoError(t, err)
+	assert.Equal(t, "aGVsbG8=", encoded)
+	encoded, err = EncodeCryptoOutput([]byte("hello"), EncodingHex)
+	require.NoError(t, err)
+	assert.Equal(t, "68656c6c6f", encoded)
+	_, err = EncodeCryptoOutput([]byte{0xff, 0xfe}, EncodingText)
+	assert.Error(t, err)
+}
+
+func TestRSAModulusBits(t *testing.T) {
+	assert.Equal(t, 2048, rsaModulusBits(append([]byte{0x00, 0x80}, make([]byte, 255)...)))
+	assert.Equal(t, 0, rsaModulusBits(nil))
+}
+
+func TestParseKeyMaterial(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	jwk, err := json.Marshal(azkeys.JSONWebKey{Kty: to.Ptr(azkeys.KeyTypeOct), K: []byte("0123456789abcdef")})
+	require.NoError(t, err)
+	publicJWK, err := json.Marshal(azkeys.JSONWebKey{Kty: to.Ptr(azkeys.KeyTypeRSA), N: rsaKey.N.Bytes(), E: []byte{1, 0, 1}})
+	require.NoError(t, err)
+
+	encode := func(blockType string, der []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	}
+
+	t.Run("PKCS1 RSA", func(t *testing.T) {
+		key, err := ParseKeyMaterial(encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)))
+		require.NoError(t, err)
+		assert.Equal(t, azkeys.KeyTypeRSA, *key.Kty)
+		assert.Equal(t, rsaKey.N.Bytes(), key.N)
+		assert.Equal(t, []byte{1, 0, 1}, key.E)
+		assert.Equal(t, rsaKey.D.Bytes(), key.D)
+		assert.NotEmpty(t, key.QI)
+	})
+
+	t.Run("PKCS8 EC after a certificate block", func(t *testing.T) {
+		material := append(encode("CERTIFICATE", []byte("not parsed")), encode("PRIVATE KEY", pkcs8)...)
+		key, err := ParseKeyMaterial(material)
+		require.NoError(t, err)
+		assert.Equal(t, azkeys.KeyTypeEC, *key.Kty)
+		assert.Equal(t, azkeys.CurveNameP384, *key.Crv)
+		assert.Len(t, key.X, 48)
+		assert.Len(t, key.Y, 48)
+		assert.Len(t, key.D, 48)
+	})
+
+	t.Run("SEC1 EC", func(t *testing.T) {
+		key, err := ParseKeyMaterial(encode("EC PRIVATE KEY", sec1))
+		require.NoError(t, err)
+		assert.Equal(t, azkeys.CurveNameP384, *key.Crv)
+	})
+
+	t.Run("JWK", func(t *testing.T) {
+		key, err := ParseKeyMaterial(jwk)
+		require.NoError(t, err)
+		assert.Equal(t, azkeys.KeyTypeOct, *key.Kty)
+		assert.Equal(t, []byte("0123456789abcdef"), key.K)
+	})
+
+	for name, material := range map[string][]byte{
+		"Public JWK":       publicJWK,
+		"Public PEM":       encode("PUBLIC KEY", public),
+		"Encrypted PEM":    encode("ENCRYPTED PRIVATE KEY", []byte("x")),
+		"Invalid PEM body": encode("RSA PRIVATE KEY", []byte("x")),
+		"Not a key":        []byte("hello"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseKeyMaterial(material)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateISODuration(t *testing.T) {
+	for _, valid := range []string{"P30D", "P6M", "P1Y", "P1Y6M"} {
+		assert.NoError(t, ValidateISODuration(valid), valid)
+	}
+	for _, invalid := range []string{"", "P", "30D", "P1W", "PT1H", "P1D1Y"} {
+		assert.Error(t, ValidateISODuration(invalid), invalid)
+	}
+}
+
+func TestRotationPolicyRoundTrip(t *testing.T) {
+	policy := &models.KeyRotationPolicy{ExpiryTime: "P1Y", RotateBeforeExpiry: "P30D", NotifyBeforeExpiry: "P7D"}
+	azPolicy, err := buildRotationPolicy(policy)
+	require.NoError(t, err)
+	require.Len(t, azPolicy.LifetimeActions, 2)
+	assert.Equal(t, policy, convertRotationPolicy(azPolicy))
+
+	empty, err := buildRotationPolicy(&models.KeyRotationPolicy{})
+	require.NoError(t, err)
+	assert.Empty(t, empty.LifetimeActions)
+
+	_, err = buildRotationPolicy(&models.KeyRotationPolicy{RotateAfterCreate: "P90D", RotateBeforeExpiry: "P30D"})
+	assert.Error(t, err)
+	_, err = buildRotationPolicy(&models.KeyRotationPolicy{ExpiryTime: "90 days"})
+	assert.Error(t, err)
+
+	lowercase := azkeys.KeyRotationPolicy{LifetimeActions: []*azkeys.LifetimeAction{{
+		Action:  &azkeys.LifetimeActionType{Type: to.Ptr(azkeys.KeyRotationPolicyAction("rotate"))},
+		Trigger: &azkeys.LifetimeActionTrigger{TimeAfterCreate: to.Ptr("P90D")},
+	}}}
+	assert.Equal(t, "P90D", convertRotationPolicy(lowercase).RotateAfterCreate)
+}
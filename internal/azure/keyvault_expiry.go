@@ -0,0 +1,208 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+)
+
+// Days before expiry at which an item becomes critical or a warning
+const (
+	expiryCriticalDays = 7
+	expiryWarningDays  = 30
+)
+
+// ListSubscriptionKeyVaults lists all Key Vaults in a subscription
+func (c *Client) ListSubscriptionKeyVaults(ctx context.Context, subscriptionID string) ([]*models.KeyVault, error) {
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
+	}
+
+	pager := client.NewListBySubscriptionPager(nil)
+	var keyVaults []*models.KeyVault
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page: %w", err)
+		}
+		for _, vault := range page.Value {
+			if vault != nil {
+				keyVaults = append(keyVaults, convertKeyVault(vault))
+			}
+		}
+	}
+	return keyVaults, nil
+}
+
+// ExpiryStatus classifies an expiry date and returns the whole days left, negative once expired
+func ExpiryStatus(expires, now time.Time) (string, int) {
+	remaining := expires.Sub(now)
+	days := int(remaining / (24 * time.Hour))
+	switch {
+	case remaining <= 0:
+		if remaining%(24*time.Hour) != 0 {
+			days--
+		}
+		return models.ExpiryStatusExpired, days
+	case days < expiryCriticalDays:
+		return models.ExpiryStatusCritical, days
+	case days < expiryWarningDays:
+		return models.ExpiryStatusWarning, days
+	default:
+		return models.ExpiryStatusUpcoming, days
+	}
+}
+
+// BuildExpiryReport scans every Key Vault of the given subscriptions for secrets, keys and certificates
+// that have expired or expire within withinDays. Subscriptions, vaults and item types of a vault that
+// cannot be read are recorded in the report errors. onProgress is called before each vault is scanned.
+func (c *Client) BuildExpiryReport(ctx context.Context, subscriptions []*models.Subscription, withinDays int, onProgress func(scanned, total int, vault string)) (*models.ExpiryReport, error) {
+	report := &models.ExpiryReport{Generated: time.Now(), WithinDays: withinDays, Items: []*models.ExpiryItem{}}
+
+	type subscriptionVault struct {
+		subscription *models.Subscription
+		vault        *models.KeyVault
+	}
+	var vaults []subscriptionVault
+	for _, sub := range subscriptions {
+		subVaults, err := c.ListSubscriptionKeyVaults(ctx, sub.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", SubscriptionLabel(sub), err))
+			continue
+		}
+		for _, vault := range subVaults {
+			vaults = append(vaults, subscriptionVault{subscription: sub, vault: vault})
+		}
+	}
+
+	for i, sv := range vaults {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		onProgress(i, len(vaults), sv.vault.Name)
+
+		items, errs := c.scanVaultExpiry(ctx, sv.vault)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for _, err := range errs {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s/%s: %v", SubscriptionLabel(sv.subscription), sv.vault.Name, err))
+		}
+		if len(errs) == len(vaultItemTypes) {
+			continue
+		}
+		report.VaultsScanned++
+		for _, item := range selectExpiring(items, report.Generated, withinDays) {
+			item.SubscriptionID = sv.subscription.ID
+			item.SubscriptionName = SubscriptionLabel(sv.subscription)
+			report.Items = append(report.Items, item)
+		}
+	}
+
+	SortExpiryItems(report.Items)
+	return report, nil
+}
+
+// SubscriptionLabel names a subscription by its display name, falling back to its ID
+func SubscriptionLabel(sub *models.Subscription) string {
+	if sub.DisplayName != "" {
+		return sub.DisplayName
+	}
+	if sub.Name != "" {
+		return sub.Name
+	}
+	return sub.ID
+}
+
+// vaultItemTypes are the item types an expiry scan lists in each vault
+var vaultItemTypes = []string{"certificates", "secrets", "keys"}
+
+// scanVaultExpiry lists the secrets, keys and certificates of a vault that have an expiry date. Each
+// item type is listed on its own, so that a type the caller may not read, such as secrets under a
+// split RBAC assignment, still leaves the others in the report; one error is returned per failed type.
+func (c *Client) scanVaultExpiry(ctx context.Context, vault *models.KeyVault) ([]*models.ExpiryItem, []error) {
+	var errs []error
+	certificates, err := c.ListCertificates(ctx, vault.VaultURI)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", vaultItemTypes[0], err))
+	}
+	secrets, err := c.ListSecrets(ctx, vault.VaultURI)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", vaultItemTypes[1], err))
+	}
+	keys, err := c.ListKeys(ctx, vault.VaultURI)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", vaultItemTypes[2], err))
+	}
+	return vaultExpiryItems(vault, certificates, secrets, keys), errs
+}
+
+// vaultExpiryItems returns the items of a vault that have an expiry date. The secret and key backing a
+// certificate share its name and are reported as the certificate only; when the certificates could not
+// be listed, they are reported as a secret and a key.
+func vaultExpiryItems(vault *models.KeyVault, certificates []*models.Certificate, secrets []*models.Secret, keys []*models.Key) []*models.ExpiryItem {
+	var items []*models.ExpiryItem
+	add := func(itemType, name string, enabled bool, expires *time.Time) {
+		if expires != nil {
+			items = append(items, &models.ExpiryItem{
+				ResourceGroup: vault.ResourceGroup,
+				Vault:         vault.Name,
+				Type:          itemType,
+				Name:          name,
+				Enabled:       enabled,
+				Expires:       *expires,
+			})
+		}
+	}
+
+	certificateNames := make(map[string]bool)
+	for _, cert := range certificates {
+		certificateNames[cert.Name] = true
+		add(models.VaultItemCertificate, cert.Name, cert.Enabled, cert.Expires)
+	}
+	for _, secret := range secrets {
+		if !certificateNames[secret.Name] {
+			add(models.VaultItemSecret, secret.Name, secret.Enabled, secret.Expires)
+		}
+	}
+	for _, key := range keys {
+		if !certificateNames[key.Name] {
+			add(models.VaultItemKey, key.Name, key.Enabled, key.Expires)
+		}
+	}
+	return items
+}
+
+// selectExpiring keeps the items that have expired or expire within withinDays, and sets their status
+func selectExpiring(items []*models.ExpiryItem, now time.Time, withinDays int) []*models.ExpiryItem {
+	var selected []*models.ExpiryItem
+	for _, item := range items {
+		item.Status, item.DaysLeft = ExpiryStatus(item.Expires, now)
+		if item.Expires.Before(now.AddDate(0, 0, withinDays)) {
+			selected = append(selected, item)
+		}
+	}
+	return selected
+}
+
+// SortExpiryItems sorts items by urgency: the earliest expiry first, then by vault and name
+func SortExpiryItems(items []*models.ExpiryItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Expires.Equal(items[j].Expires) {
+			return items[i].Expires.Before(items[j].Expires)
+		}
+		if items[i].Vault != items[j].Vault {
+			return items[i].Vault < items[j].Vault
+		}
+		return items[i].Name < items[j].Name
+	})
+}
@@ -0,0 +1,104 @@
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryStatus(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		expires  time.Time
+		status   string
+		daysLeft int
+	}{
+		{name: "Expired days ago", expires: now.Add(-36 * time.Hour), status: models.ExpiryStatusExpired, daysLeft: -2},
+		{name: "Expired an hour ago", expires: now.Add(-time.Hour), status: models.ExpiryStatusExpired, daysLeft: -1},
+		{name: "Expires now", expires: now, status: models.ExpiryStatusExpired, daysLeft: 0},
+		{name: "Expires today", expires: now.Add(time.Hour), status: models.ExpiryStatusCritical, daysLeft: 0},
+		{name: "Expires in six days", expires: now.AddDate(0, 0, 6), status: models.ExpiryStatusCritical, daysLeft: 6},
+		{name: "Expires in a week", expires: now.AddDate(0, 0, 7), status: models.ExpiryStatusWarning, daysLeft: 7},
+		{name: "Expires in 30 days", expires: now.AddDate(0, 0, 30), status: models.ExpiryStatusUpcoming, daysLeft: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, daysLeft := ExpiryStatus(tt.expires, now)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.daysLeft, daysLeft)
+		})
+	}
+}
+
+func TestSelectExpiring(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := []*models.ExpiryItem{
+		{Name: "expired", Expires: now.AddDate(-1, 0, 0)},
+		{Name: "soon", Expires: now.AddDate(0, 0, 10)},
+		{Name: "later", Expires: now.AddDate(0, 0, 45)},
+	}
+
+	selected := selectExpiring(items, now, 30)
+
+	if assert.Len(t, selected, 2) {
+		assert.Equal(t, "expired", selected[0].Name)
+		assert.Equal(t, models.ExpiryStatusExpired, selected[0].Status)
+		assert.Equal(t, "soon", selected[1].Name)
+		assert.Equal(t, models.ExpiryStatusWarning, selected[1].Status)
+		assert.Equal(t, 10, selected[1].DaysLeft)
+	}
+}
+
+func TestVaultExpiryItems(t *testing.T) {
+	expires := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	vault := &models.KeyVault{Name: "kv-app", ResourceGroup: "rg-app"}
+	certificates := []*models.Certificate{{Name: "tls", Enabled: true, Expires: &expires}}
+	secrets := []*models.Secret{{Name: "tls", Expires: &expires}, {Name: "db-password", Enabled: true, Expires: &expires}, {Name: "no-expiry"}}
+	keys := []*models.Key{{Name: "tls", Expires: &expires}, {Name: "signing", Expires: &expires}}
+
+	items := vaultExpiryItems(vault, certificates, secrets, keys)
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Type+":"+item.Name)
+		assert.Equal(t, "kv-app", item.Vault)
+		assert.Equal(t, "rg-app", item.ResourceGroup)
+	}
+	assert.Equal(t, []string{
+		models.VaultItemCertificate + ":tls",
+		models.VaultItemSecret + ":db-password",
+		models.VaultItemKey + ":signing",
+	}, names)
+
+	// Secrets that could not be listed leave the certificates and keys in the report
+	items = vaultExpiryItems(vault, certificates, nil, keys)
+	assert.Len(t, items, 2)
+}
+
+func TestSortExpiryItems(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := []*models.ExpiryItem{
+		{Vault: "b", Name: "x", Expires: day.AddDate(0, 0, 2)},
+		{Vault: "b", Name: "y", Expires: day},
+		{Vault: "a", Name: "z", Expires: day},
+		{Vault: "a", Name: "w", Expires: day.AddDate(0, 0, -5)},
+	}
+
+	SortExpiryItems(items)
+
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	assert.Equal(t, []string{"w", "z", "y", "x"}, names)
+}
+
+func TestSubscriptionLabel(t *testing.T) {
+	assert.Equal(t, "Production", SubscriptionLabel(&models.Subscription{ID: "sub-1", Name: "prod", DisplayName: "Production"}))
+	assert.Equal(t, "prod", SubscriptionLabel(&models.Subscription{ID: "sub-1", Name: "prod"}))
+	assert.Equal(t, "sub-1", SubscriptionLabel(&models.Subscription{ID: "sub-1"}))
+}
@@ -0,0 +1,445 @@
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+const (
+	logAnalyticsEndpoint = "https://api.loganalytics.io/v1"
+	logAnalyticsScope    = "https://api.loganalytics.io/.default"
+
+	// auditHistoryDays is how far back audit events are read; the Activity Log keeps 90 days
+	auditHistoryDays = 90
+	// maxAuditEvents is the most diagnostic log events read for one secret
+	maxAuditEvents = 500
+	// auditMatchWindow is how far apart a version update and its audit event may be to be matched
+	auditMatchWindow = 5 * time.Minute
+	// maxDiffCells bounds the line diff table; larger values are shown as fully changed
+	maxDiffCells = 1000000
+)
+
+// secretAuditQuery reads the secret operations of a vault from its diagnostic logs, in the legacy
+// AzureDiagnostics table or the resource-specific AZKVAuditLogs table, whichever the vault sends to
+const secretAuditQuery = `union isfuzzy=true
+(AzureDiagnostics
+ | where ResourceProvider == "MICROSOFT.KEYVAULT" and OperationName startswith "Secret"
+ | extend ItemId = tostring(column_ifexists("id_s", "")),
+   Caller = coalesce(tostring(column_ifexists("identity_claim_http_schemas_xmlsoap_org_ws_2005_05_identity_claims_upn_s", "")),
+     tostring(column_ifexists("identity_claim_upn_s", "")), tostring(column_ifexists("identity_claim_appid_g", "")),
+     tostring(column_ifexists("identity_claim_oid_g", ""))),
+   CallerIP = tostring(column_ifexists("CallerIPAddress", "")), Result = tostring(column_ifexists("ResultSignature", ""))),
+(AZKVAuditLogs
+ | where OperationName startswith "Secret"
+ | extend ItemId = tostring(Id),
+   Caller = coalesce(tostring(Identity.claim["http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn"]),
+     tostring(Identity.claim.appid), tostring(Identity.claim.oid)),
+   CallerIP = tostring(CallerIpAddress), Result = tostring(ResultSignature))
+| where ItemId startswith "%s"
+| project TimeGenerated, OperationName, ItemId, Caller, CallerIP, Result
+| order by TimeGenerated desc
+| take %d`
+
+// logAnalyticsTable is one result table of a Log Analytics query
+type logAnalyticsTable struct {
+	Columns []struct {
+		Name string `json:"name"`
+	} `json:"columns"`
+	Rows [][]interface{} `json:"rows"`
+}
+
+// LineDiff is one row of a side-by-side diff. Left is empty for an added line and Right for a removed one.
+type LineDiff struct {
+	Left    string
+	Right   string
+	Changed bool
+}
+
+// GetSecretHistory builds the timeline of a secret from its versions, the Activity Log and the diagnostic
+// logs of the vault. Audit sources that cannot be read are explained in the history notes.
+func (c *Client) GetSecretHistory(ctx context.Context, subscriptionID, resourceGroupName, vaultName, vaultURL, secretName string) (*models.SecretHistory, error) {
+	versions, err := c.ListSecretVersions(ctx, vaultURL, secretName)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.SecretHistory{Name: secretName, Versions: versions}
+
+	if resourceGroupName == "" {
+		history.Notes = append(history.Notes, "The resource group of the vault is unknown, so audit logs were not read.")
+		history.Timeline = BuildSecretTimeline(versions, nil)
+		return history, nil
+	}
+	vaultID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s", subscriptionID, resourceGroupName, vaultName)
+
+	var events []*models.SecretAuditEvent
+	activity, err := c.listActivityLogSecretEvents(ctx, subscriptionID, vaultID, secretName)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		history.Notes = append(history.Notes, fmt.Sprintf("Activity Log: %v", err))
+	}
+	events = append(events, activity...)
+
+	enabled, err := c.vaultSendsAuditLogs(ctx, vaultID)
+	switch {
+	case err != nil:
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		history.Notes = append(history.Notes, fmt.Sprintf("Diagnostic settings: %v", err))
+	case !enabled:
+		history.Notes = append(history.Notes, "The vault does not send audit logs to a Log Analytics workspace, so new versions and reads cannot be attributed. Add a diagnostic setting with the audit category to record them.")
+	default:
+		diagnostic, err := c.queryDiagnosticSecretEvents(ctx, vaultID, vaultURL, secretName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			history.Notes = append(history.Notes, fmt.Sprintf("Diagnostic logs: %v", err))
+		}
+		events = append(events, diagnostic...)
+	}
+
+	history.Timeline = BuildSecretTimeline(versions, events)
+	return history, nil
+}
+
+// listActivityLogSecretEvents reads the completed management operations on a secret, such as ARM
+// template deployments, from the Activity Log
+func (c *Client) listActivityLogSecretEvents(ctx context.Context, subscriptionID, vaultID, secretName string) ([]*models.SecretAuditEvent, error) {
+	client, err := armmonitor.NewActivityLogsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create activity log client: %w", err)
+	}
+
+	now := time.Now().UTC()
+	filter := fmt.Sprintf("eventTimestamp ge '%s' and eventTimestamp le '%s' and resourceUri eq '%s/secrets/%s'",
+		now.AddDate(0, 0, -auditHistoryDays).Format(time.RFC3339), now.Format(time.RFC3339), vaultID, secretName)
+	pager := client.NewListPager(filter, nil)
+
+	var events []*models.SecretAuditEvent
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list activity log events: %w", err)
+		}
+		for _, data := range page.Value {
+			if event := convertActivityLogEvent(data); event != nil {
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
+
+// convertActivityLogEvent converts a completed Activity Log event; started and accepted events are skipped
+func convertActivityLogEvent(data *armmonitor.EventData) *models.SecretAuditEvent {
+	if data == nil || data.EventTimestamp == nil {
+		return nil
+	}
+	status := localizable(data.Status)
+	if status == "Started" || status == "Accepted" {
+		return nil
+	}
+
+	event := &models.SecretAuditEvent{
+		Time:      *data.EventTimestamp,
+		Operation: localizable(data.OperationName),
+		Caller:    stringValue(data.Caller),
+		Result:    status,
+		Source:    models.AuditSourceActivityLog,
+	}
+	if data.HTTPRequest != nil {
+		event.CallerIP = stringValue(data.HTTPRequest.ClientIPAddress)
+	}
+	return event
+}
+
+// localizable returns the display value of an Activity Log string, falling back to its raw value
+func localizable(s *armmonitor.LocalizableString) string {
+	if s == nil {
+		return ""
+	}
+	if value := stringValue(s.LocalizedValue); value != "" {
+		return value
+	}
+	return stringValue(s.Value)
+}
+
+// vaultSendsAuditLogs reports whether a diagnostic setting sends the audit logs of a vault to a Log Analytics workspace
+func (c *Client) vaultSendsAuditLogs(ctx context.Context, vaultID string) (bool, error) {
+	client, err := armmonitor.NewDiagnosticSettingsClient(c.credential, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create diagnostic settings client: %w", err)
+	}
+
+	pager := client.NewListPager(vaultID, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to list diagnostic settings: %w", err)
+		}
+		for _, setting := range page.Value {
+			if setting != nil && sendsAuditLogs(setting.Properties) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// sendsAuditLogs reports whether a diagnostic setting sends the AuditEvent category to a workspace
+func sendsAuditLogs(settings *armmonitor.DiagnosticSettings) bool {
+	if settings == nil || stringValue(settings.WorkspaceID) == "" {
+		return false
+	}
+	for _, log := range settings.Logs {
+		if log == nil || log.Enabled == nil || !*log.Enabled {
+			continue
+		}
+		if strings.EqualFold(stringValue(log.Category), "AuditEvent") ||
+			strings.EqualFold(stringValue(log.CategoryGroup), "audit") || strings.EqualFold(stringValue(log.CategoryGroup), "allLogs") {
+			return true
+		}
+	}
+	return false
+}
+
+// queryDiagnosticSecretEvents reads the operations on a secret from the vault's diagnostic logs, through
+// a resource-centric Log Analytics query that finds the workspaces the vault sends to
+func (c *Client) queryDiagnosticSecretEvents(ctx context.Context, vaultID, vaultURL, secretName string) ([]*models.SecretAuditEvent, error) {
+	secretID := strings.TrimSuffix(vaultURL, "/") + "/secrets/" + secretName
+	body := map[string]interface{}{
+		"query":    fmt.Sprintf(secretAuditQuery, secretID, maxAuditEvents),
+		"timespan": fmt.Sprintf("P%dD", auditHistoryDays),
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, logAnalyticsEndpoint+vaultID+"/query")
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return nil, err
+	}
+	pipeline := runtime.NewPipeline("azure-control-tower", "", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(c.credential, []string{logAnalyticsScope}, nil)},
+	}, nil)
+	resp, err := pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query diagnostic logs: %w", err)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, fmt.Errorf("failed to query diagnostic logs: %w", runtime.NewResponseError(resp))
+	}
+
+	var result struct {
+		Tables []logAnalyticsTable `json:"tables"`
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to read diagnostic logs: %w", err)
+	}
+	var events []*models.SecretAuditEvent
+	for _, table := range result.Tables {
+		events = append(events, convertDiagnosticEvents(table, secretName)...)
+	}
+	return events, nil
+}
+
+// convertDiagnosticEvents converts the rows of the audit query for the given secret. The query matches
+// item IDs by prefix, so rows of other secrets whose name starts with the same text are dropped here.
+func convertDiagnosticEvents(table logAnalyticsTable, secretName string) []*models.SecretAuditEvent {
+	columns := make(map[string]int)
+	for i, column := range table.Columns {
+		columns[column.Name] = i
+	}
+	cell := func(row []interface{}, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) || row[i] == nil {
+			return ""
+		}
+		return fmt.Sprint(row[i])
+	}
+
+	var events []*models.SecretAuditEvent
+	for _, row := range table.Rows {
+		id := azsecrets.ID(cell(row, "ItemId"))
+		if !strings.EqualFold(id.Name(), secretName) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, cell(row, "TimeGenerated"))
+		if err != nil {
+			continue
+		}
+		events = append(events, &models.SecretAuditEvent{
+			Time:      t,
+			Operation: cell(row, "OperationName"),
+			Version:   id.Version(),
+			Caller:    cell(row, "Caller"),
+			CallerIP:  cell(row, "CallerIP"),
+			Result:    cell(row, "Result"),
+			Source:    models.AuditSourceDiagnosticLogs,
+		})
+	}
+	return events
+}
+
+// BuildSecretTimeline merges the versions of a secret with its audit events, newest first. Each version
+// adds a creation entry, attributed to the SecretSet event of that version, and an update entry when its
+// properties changed later, attributed to the closest SecretUpdate event of that version. Audit events
+// that were not matched to a version are listed as they are.
+func BuildSecretTimeline(versions []*models.Secret, events []*models.SecretAuditEvent) []*models.SecretAuditEvent {
+	used := make(map[*models.SecretAuditEvent]bool)
+	match := func(operation, version string, at time.Time, exact bool) *models.SecretAuditEvent {
+		var best *models.SecretAuditEvent
+		for _, event := range events {
+			if used[event] || event.Operation != operation || !strings.EqualFold(event.Version, version) {
+				continue
+			}
+			distance := event.Time.Sub(at).Abs()
+			if !exact && distance > auditMatchWindow {
+				continue
+			}
+			if best == nil || distance < best.Time.Sub(at).Abs() {
+				best = event
+			}
+		}
+		if best != nil {
+			used[best] = true
+		}
+		return best
+	}
+	entry := func(operation, version string, at time.Time, audit *models.SecretAuditEvent) *models.SecretAuditEvent {
+		e := &models.SecretAuditEvent{Time: at, Operation: operation, Version: version, Source: models.AuditSourceVault}
+		if audit != nil {
+			e.Caller, e.CallerIP, e.Result, e.Source = audit.Caller, audit.CallerIP, audit.Result, audit.Source
+		}
+		return e
+	}
+
+	var timeline []*models.SecretAuditEvent
+	for _, version := range versions {
+		if version.Created == nil {
+			continue
+		}
+		timeline = append(timeline, entry("Version created", version.Version, *version.Created, match("SecretSet", version.Version, *version.Created, true)))
+		if version.Updated != nil && version.Updated.Sub(*version.Created) > time.Second {
+			timeline = append(timeline, entry("Properties updated", version.Version, *version.Updated, match("SecretUpdate", version.Version, *version.Updated, false)))
+		}
+	}
+	for _, event := range events {
+		if !used[event] {
+			timeline = append(timeline, event)
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.After(timeline[j].Time)
+	})
+	return timeline
+}
+
+// CompareSecretVersions tells whether two versions of a secret hold the same value by comparing the
+// SHA-256 hashes of their values; the values are not returned
+func (c *Client) CompareSecretVersions(ctx context.Context, vaultURL, secretName, versionA, versionB string) (*models.SecretVersionComparison, error) {
+	hash := func(version string) ([32]byte, error) {
+		value, err := c.GetSecretValue(ctx, vaultURL, secretName, version)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("failed to read version %s: %w", version, err)
+		}
+		return sha256.Sum256([]byte(value)), nil
+	}
+
+	hashA, err := hash(versionA)
+	if err != nil {
+		return nil, err
+	}
+	hashB, err := hash(versionB)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SecretVersionComparison{
+		Name:     secretName,
+		VersionA: versionA,
+		VersionB: versionB,
+		Changed:  hashA != hashB,
+	}, nil
+}
+
+// DiffLines compares two texts line by line for a side-by-side view. Unchanged lines are paired,
+// and each run of removed and added lines is paired up as changed rows.
+func DiffLines(a, b string) []LineDiff {
+	left, right := strings.Split(a, "\n"), strings.Split(b, "\n")
+	n, m := len(left), len(right)
+	if n*m > maxDiffCells {
+		return pairChanged(left, right)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of left[i:] and right[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var rows []LineDiff
+	var removed, added []string
+	flush := func() {
+		rows = append(rows, pairChanged(removed, added)...)
+		removed, added = nil, nil
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && left[i] == right[j]:
+			flush()
+			rows = append(rows, LineDiff{Left: left[i], Right: right[j]})
+			i++
+			j++
+		case j >= m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, left[i])
+			i++
+		default:
+			added = append(added, right[j])
+			j++
+		}
+	}
+	flush()
+	return rows
+}
+
+// pairChanged pairs removed lines with added lines as changed rows
+func pairChanged(removed, added []string) []LineDiff {
+	var rows []LineDiff
+	for k := 0; k < max(len(removed), len(added)); k++ {
+		row := LineDiff{Changed: true}
+		if k < len(removed) {
+			row.Left = removed[k]
+		}
+		if k < len(added) {
+			row.Right = added[k]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
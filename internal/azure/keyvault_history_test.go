@@ -0,0 +1,170 @@
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSecretTimeline(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		t := base.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+
+	versions := []*models.Secret{
+		{Version: "v2", Created: at(60), Updated: at(120)},
+		{Version: "v1", Created: at(0), Updated: at(0)},
+		{Version: "v0"},
+	}
+	set := &models.SecretAuditEvent{Time: *at(60), Operation: "SecretSet", Version: "V2", Caller: "alice@example.com", CallerIP: "203.0.113.10", Result: "OK", Source: models.AuditSourceDiagnosticLogs}
+	update := &models.SecretAuditEvent{Time: *at(122), Operation: "SecretUpdate", Version: "v2", Caller: "bob@example.com", Source: models.AuditSourceDiagnosticLogs}
+	lateUpdate := &models.SecretAuditEvent{Time: *at(200), Operation: "SecretUpdate", Version: "v2", Caller: "carol@example.com", Source: models.AuditSourceDiagnosticLogs}
+	get := &models.SecretAuditEvent{Time: *at(90), Operation: "SecretGet", Version: "v2", Caller: "app", Source: models.AuditSourceDiagnosticLogs}
+
+	timeline := BuildSecretTimeline(versions, []*models.SecretAuditEvent{get, lateUpdate, update, set})
+
+	require.Len(t, timeline, 5)
+	assert.Same(t, lateUpdate, timeline[0])
+
+	assert.Equal(t, "Properties updated", timeline[1].Operation)
+	assert.Equal(t, "v2", timeline[1].Version)
+	assert.Equal(t, *at(120), timeline[1].Time)
+	assert.Equal(t, "bob@example.com", timeline[1].Caller)
+
+	assert.Same(t, get, timeline[2])
+
+	assert.Equal(t, "Version created", timeline[3].Operation)
+	assert.Equal(t, "alice@example.com", timeline[3].Caller)
+	assert.Equal(t, "203.0.113.10", timeline[3].CallerIP)
+	assert.Equal(t, models.AuditSourceDiagnosticLogs, timeline[3].Source)
+
+	assert.Equal(t, "Version created", timeline[4].Operation)
+	assert.Equal(t, "v1", timeline[4].Version)
+	assert.Empty(t, timeline[4].Caller)
+	assert.Equal(t, models.AuditSourceVault, timeline[4].Source)
+}
+
+func TestConvertDiagnosticEvents(t *testing.T) {
+	table := logAnalyticsTable{
+		Columns: []struct {
+			Name string `json:"name"`
+		}{{Name: "TimeGenerated"}, {Name: "OperationName"}, {Name: "ItemId"}, {Name: "Caller"}, {Name: "CallerIP"}, {Name: "Result"}},
+		Rows: [][]interface{}{
+			{"2026-03-01T12:00:00.123Z", "SecretSet", "https://kv-1.vault.azure.net/secrets/db-password/abc123", "alice@example.com", "203.0.113.10", "OK"},
+			{"2026-03-01T13:00:00Z", "SecretGet", "https://kv-1.vault.azure.net/secrets/db-password-old/def456", "bob@example.com", "", "OK"},
+			{"2026-03-01T14:00:00Z", "SecretUpdate", "https://kv-1.vault.azure.net/secrets/DB-PASSWORD", nil, nil, "Forbidden"},
+			{"not a time", "SecretGet", "https://kv-1.vault.azure.net/secrets/db-password/abc123", "", "", ""},
+		},
+	}
+
+	events := convertDiagnosticEvents(table, "db-password")
+
+	require.Len(t, events, 2)
+	assert.Equal(t, &models.SecretAuditEvent{
+		Time:      time.Date(2026, 3, 1, 12, 0, 0, 123000000, time.UTC),
+		Operation: "SecretSet",
+		Version:   "abc123",
+		Caller:    "alice@example.com",
+		CallerIP:  "203.0.113.10",
+		Result:    "OK",
+		Source:    models.AuditSourceDiagnosticLogs,
+	}, events[0])
+	assert.Equal(t, "SecretUpdate", events[1].Operation)
+	assert.Empty(t, events[1].Version)
+	assert.Empty(t, events[1].Caller)
+	assert.Equal(t, "Forbidden", events[1].Result)
+}
+
+func TestConvertActivityLogEvent(t *testing.T) {
+	timestamp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	data := &armmonitor.EventData{
+		EventTimestamp: to.Ptr(timestamp),
+		OperationName:  &armmonitor.LocalizableString{Value: to.Ptr("Microsoft.KeyVault/vaults/secrets/write"), LocalizedValue: to.Ptr("Write Secret")},
+		Status:         &armmonitor.LocalizableString{Value: to.Ptr("Succeeded")},
+		Caller:         to.Ptr("deployer@example.com"),
+		HTTPRequest:    &armmonitor.HTTPRequestInfo{ClientIPAddress: to.Ptr("198.51.100.7")},
+	}
+
+	assert.Equal(t, &models.SecretAuditEvent{
+		Time:      timestamp,
+		Operation: "Write Secret",
+		Caller:    "deployer@example.com",
+		CallerIP:  "198.51.100.7",
+		Result:    "Succeeded",
+		Source:    models.AuditSourceActivityLog,
+	}, convertActivityLogEvent(data))
+
+	data.Status = &armmonitor.LocalizableString{Value: to.Ptr("Started")}
+	assert.Nil(t, convertActivityLogEvent(data))
+	assert.Nil(t, convertActivityLogEvent(&armmonitor.EventData{}))
+}
+
+func TestSendsAuditLogs(t *testing.T) {
+	workspace := to.Ptr("/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.OperationalInsights/workspaces/ws-1")
+	tests := []struct {
+		name     string
+		settings *armmonitor.DiagnosticSettings
+		want     bool
+	}{
+		{name: "Nil", settings: nil, want: false},
+		{name: "Audit category", settings: &armmonitor.DiagnosticSettings{WorkspaceID: workspace, Logs: []*armmonitor.LogSettings{{Category: to.Ptr("AuditEvent"), Enabled: to.Ptr(true)}}}, want: true},
+		{name: "Audit group", settings: &armmonitor.DiagnosticSettings{WorkspaceID: workspace, Logs: []*armmonitor.LogSettings{{CategoryGroup: to.Ptr("audit"), Enabled: to.Ptr(true)}}}, want: true},
+		{name: "All logs", settings: &armmonitor.DiagnosticSettings{WorkspaceID: workspace, Logs: []*armmonitor.LogSettings{{CategoryGroup: to.Ptr("allLogs"), Enabled: to.Ptr(true)}}}, want: true},
+		{name: "Disabled", settings: &armmonitor.DiagnosticSettings{WorkspaceID: workspace, Logs: []*armmonitor.LogSettings{{Category: to.Ptr("AuditEvent"), Enabled: to.Ptr(false)}}}, want: false},
+		{name: "Other category", settings: &armmonitor.DiagnosticSettings{WorkspaceID: workspace, Logs: []*armmonitor.LogSettings{{Category: to.Ptr("AzurePolicyEvaluationDetails"), Enabled: to.Ptr(true)}}}, want: false},
+		{name: "Storage only", settings: &armmonitor.DiagnosticSettings{StorageAccountID: to.Ptr("sa"), Logs: []*armmonitor.LogSettings{{Category: to.Ptr("AuditEvent"), Enabled: to.Ptr(true)}}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sendsAuditLogs(tt.settings))
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []LineDiff
+	}{
+		{
+			name: "Identical",
+			a:    "one\ntwo",
+			b:    "one\ntwo",
+			want: []LineDiff{{Left: "one", Right: "one"}, {Left: "two", Right: "two"}},
+		},
+		{
+			name: "Changed line",
+			a:    "host=db1\nuser=app\nport=5432",
+			b:    "host=db2\nuser=app\nport=5432",
+			want: []LineDiff{{Left: "host=db1", Right: "host=db2", Changed: true}, {Left: "user=app", Right: "user=app"}, {Left: "port=5432", Right: "port=5432"}},
+		},
+		{
+			name: "Added and removed lines",
+			a:    "a\nb\nc",
+			b:    "a\nc\nd",
+			want: []LineDiff{{Left: "a", Right: "a"}, {Left: "b", Changed: true}, {Left: "c", Right: "c"}, {Right: "d", Changed: true}},
+		},
+		{
+			name: "Single value",
+			a:    "old-password",
+			b:    "new-password",
+			want: []LineDiff{{Left: "old-password", Right: "new-password", Changed: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffLines(tt.a, tt.b))
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// GetKeyVault gets a single Key Vault, including its soft delete, purge protection and network settings
+func (c *Client) GetKeyVault(ctx context.Context, subscriptionID, resourceGroupName, vaultName string) (*models.KeyVault, error) {
+	client, err := armkeyvault.NewVaultsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
+	}
+
+	resp, err := client.Get(ctx, resourceGroupName, vaultName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Key Vault: %w", err)
+	}
+	return convertKeyVault(&resp.Vault), nil
+}
+
+// DeleteVaultItem deletes a secret, key or certificate. With soft delete enabled the item
+// moves to the deleted items of the vault, where it can be recovered until it is purged.
+func (c *Client) DeleteVaultItem(ctx context.Context, vaultURL, itemType, name string) error {
+	switch itemType {
+	case models.VaultItemSecret:
+		client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create secrets client: %w", err)
+		}
+		_, err = client.DeleteSecret(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to delete secret: %w", err)
+		}
+	case models.VaultItemKey:
+		client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create keys client: %w", err)
+		}
+		_, err = client.DeleteKey(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to delete key: %w", err)
+		}
+	case models.VaultItemCertificate:
+		client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create certificates client: %w", err)
+		}
+		_, err = client.DeleteCertificate(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to delete certificate: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported Key Vault item type %q", itemType)
+	}
+	return nil
+}
+
+// RecoverDeletedItem recovers a soft-deleted secret, key or certificate to its latest version.
+// The item can take a few seconds to become available again after the call returns.
+func (c *Client) RecoverDeletedItem(ctx context.Context, vaultURL, itemType, name string) error {
+	switch itemType {
+	case models.VaultItemSecret:
+		client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create secrets client: %w", err)
+		}
+		_, err = client.RecoverDeletedSecret(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to recover secret: %w", err)
+		}
+	case models.VaultItemKey:
+		client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create keys client: %w", err)
+		}
+		_, err = client.RecoverDeletedKey(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to recover key: %w", err)
+		}
+	case models.VaultItemCertificate:
+		client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create certificates client: %w", err)
+		}
+		_, err = client.RecoverDeletedCertificate(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to recover certificate: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported Key Vault item type %q", itemType)
+	}
+	return nil
+}
+
+// PurgeDeletedItem permanently deletes a soft-deleted secret, key or certificate.
+// Vaults with purge protection reject this until the scheduled purge date.
+func (c *Client) PurgeDeletedItem(ctx context.Context, vaultURL, itemType, name string) error {
+	switch itemType {
+	case models.VaultItemSecret:
+		client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create secrets client: %w", err)
+		}
+		_, err = client.PurgeDeletedSecret(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to purge secret: %w", err)
+		}
+	case models.VaultItemKey:
+		client, err := azkeys.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create keys client: %w", err)
+		}
+		_, err = client.PurgeDeletedKey(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to purge key: %w", err)
+		}
+	case models.VaultItemCertificate:
+		client, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create certificates client: %w", err)
+		}
+		_, err = client.PurgeDeletedCertificate(ctx, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to purge certificate: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported Key Vault item type %q", itemType)
+	}
+	return nil
+}
+
+// ListDeletedItems lists the soft-deleted secrets, keys and certificates of a vault, most recently deleted first.
+// Secrets and keys managed by a certificate are left out; they are recovered and purged with the certificate.
+func (c *Client) ListDeletedItems(ctx context.Context, vaultURL string) ([]*models.DeletedItem, error) {
+	var items []*models.DeletedItem
+
+	secretsClient, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets client: %w", err)
+	}
+	secretsPager := secretsClient.NewListDeletedSecretPropertiesPager(nil)
+	for secretsPager.More() {
+		page, err := secretsPager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deleted secrets: %w", err)
+		}
+		for _, secret := range page.Value {
+			if secret == nil || secret.ID == nil || (secret.Managed != nil && *secret.Managed) {
+				continue
+			}
+			item := newDeletedItem(models.VaultItemSecret, secret.ID.Name(), secret.RecoveryID, secret.DeletedDate, secret.ScheduledPurgeDate, secret.Tags)
+			if secret.Attributes != nil && secret.Attributes.Enabled != nil {
+				item.Enabled = *secret.Attributes.Enabled
+			}
+			items = append(items, item)
+		}
+	}
+
+	keysClient, err := azkeys.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keys client: %w", err)
+	}
+	keysPager := keysClient.NewListDeletedKeyPropertiesPager(nil)
+	for keysPager.More() {
+		page, err := keysPager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deleted keys: %w", err)
+		}
+		for _, key := range page.Value {
+			if key == nil || key.KID == nil || (key.Managed != nil && *key.Managed) {
+				continue
+			}
+			item := newDeletedItem(models.VaultItemKey, key.KID.Name(), key.RecoveryID, key.DeletedDate, key.ScheduledPurgeDate, key.Tags)
+			if key.Attributes != nil && key.Attributes.Enabled != nil {
+				item.Enabled = *key.Attributes.Enabled
+			}
+			items = append(items, item)
+		}
+	}
+
+	certsClient, err := azcertificates.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificates client: %w", err)
+	}
+	certsPager := certsClient.NewListDeletedCertificatePropertiesPager(nil)
+	for certsPager.More() {
+		page, err := certsPager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deleted certificates: %w", err)
+		}
+		for _, cert := range page.Value {
+			if cert == nil || cert.ID == nil {
+				continue
+			}
+			item := newDeletedItem(models.VaultItemCertificate, cert.ID.Name(), cert.RecoveryID, cert.DeletedDate, cert.ScheduledPurgeDate, cert.Tags)
+			if cert.Attributes != nil && cert.Attributes.Enabled != nil {
+				item.Enabled = *cert.Attributes.Enabled
+			}
+			items = append(items, item)
+		}
+	}
+
+	sortDeletedItems(items)
+	return items, nil
+}
+
+// newDeletedItem builds a deleted item from the fields the three Key Vault clients have in common
+func newDeletedItem(itemType, name string, recoveryID *string, deletedDate, scheduledPurgeDate *time.Time, tags map[string]*string) *models.DeletedItem {
+	item := &models.DeletedItem{
+		Type:               itemType,
+		Name:               name,
+		DeletedDate:        deletedDate,
+		ScheduledPurgeDate: scheduledPurgeDate,
+		Tags:               make(map[string]string),
+	}
+	if recoveryID != nil {
+		item.RecoveryID = *recoveryID
+	}
+	for k, v := range tags {
+		if v != nil {
+			item.Tags[k] = *v
+		}
+	}
+	return item
+}
+
+// sortDeletedItems orders deleted items most recently deleted first, then by type and name
+func sortDeletedItems(items []*models.DeletedItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].DeletedDate, items[j].DeletedDate
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.After(*b)
+		}
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// DeleteWarning describes what deleting an item means for a vault, based on its soft delete settings.
+// A nil vault means the settings could not be read.
+func DeleteWarning(vault *models.KeyVault, itemType, name string) string {
+	switch {
+	case vault == nil:
+		return fmt.Sprintf("Delete %s '%s'? The soft delete settings of this vault could not be read; the %s may not be recoverable.", itemType, name, itemType)
+	case !vault.SoftDeleteEnabled:
+		return fmt.Sprintf("Delete %s '%s'? Soft delete is disabled on this vault, so the %s is deleted permanently and cannot be recovered.", itemType, name, itemType)
+	case vault.PurgeProtectionEnabled:
+		return fmt.Sprintf("Delete %s '%s'? It can be recovered for %d days. Purge protection is enabled, so the name stays reserved until then.", itemType, name, vault.SoftDeleteRetentionDays)
+	default:
+		return fmt.Sprintf("Delete %s '%s'? It can be recovered for %d days, or purged from Deleted Items.", itemType, name, vault.SoftDeleteRetentionDays)
+	}
+}
+
+// DeleteItemsWarning is DeleteWarning for deleting several items of one type at once
+func DeleteItemsWarning(vault *models.KeyVault, itemType string, count int) string {
+	switch {
+	case vault == nil:
+		return fmt.Sprintf("Delete %d %ss? The soft delete settings of this vault could not be read; they may not be recoverable.", count, itemType)
+	case !vault.SoftDeleteEnabled:
+		return fmt.Sprintf("Delete %d %ss? Soft delete is disabled on this vault, so they are deleted permanently and cannot be recovered.", count, itemType)
+	case vault.PurgeProtectionEnabled:
+		return fmt.Sprintf("Delete %d %ss? They can be recovered for %d days. Purge protection is enabled, so their names stay reserved until then.", count, itemType, vault.SoftDeleteRetentionDays)
+	default:
+		return fmt.Sprintf("Delete %d %ss? They can be recovered for %d days, or purged from Deleted Items.", count, itemType, vault.SoftDeleteRetentionDays)
+	}
+}
+
+// CheckPurgeAllowed reports why a deleted item cannot be purged, or nil if it can.
+// With purge protection, items can only be purged by the service on their scheduled purge date.
+func CheckPurgeAllowed(vault *models.KeyVault, item *models.DeletedItem) error {
+	if vault == nil || !vault.PurgeProtectionEnabled {
+		return nil
+	}
+	if item.ScheduledPurgeDate != nil {
+		return fmt.Errorf("purge protection is enabled on this vault; %s '%s' will be purged automatically on %s",
+			item.Type, item.Name, item.ScheduledPurgeDate.Local().Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Errorf("purge protection is enabled on this vault; %s '%s' will be purged automatically when its retention period ends", item.Type, item.Name)
+}
@@ -0,0 +1,143 @@
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertKeyVault_SoftDeleteSettings(t *testing.T) {
+	enabled := true
+	disabled := false
+	retention := int32(30)
+
+	tests := []struct {
+		name               string
+		properties         *armkeyvault.VaultProperties
+		expectSoftDelete   bool
+		expectRetention    int32
+		expectPurgeProtect bool
+	}{
+		{
+			name:               "Defaults when not set",
+			properties:         &armkeyvault.VaultProperties{},
+			expectSoftDelete:   true,
+			expectRetention:    90,
+			expectPurgeProtect: false,
+		},
+		{
+			name:               "Configured retention and purge protection",
+			properties:         &armkeyvault.VaultProperties{EnableSoftDelete: &enabled, SoftDeleteRetentionInDays: &retention, EnablePurgeProtection: &enabled},
+			expectSoftDelete:   true,
+			expectRetention:    30,
+			expectPurgeProtect: true,
+		},
+		{
+			name:               "Soft delete disabled",
+			properties:         &armkeyvault.VaultProperties{EnableSoftDelete: &disabled},
+			expectSoftDelete:   false,
+			expectRetention:    90,
+			expectPurgeProtect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kv := convertKeyVault(&armkeyvault.Vault{Properties: tt.properties})
+			assert.Equal(t, tt.expectSoftDelete, kv.SoftDeleteEnabled)
+			assert.Equal(t, tt.expectRetention, kv.SoftDeleteRetentionDays)
+			assert.Equal(t, tt.expectPurgeProtect, kv.PurgeProtectionEnabled)
+		})
+	}
+}
+
+func TestSortDeletedItems(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	items := []*models.DeletedItem{
+		{Type: models.VaultItemSecret, Name: "unknown-date"},
+		{Type: models.VaultItemSecret, Name: "old", DeletedDate: &older},
+		{Type: models.VaultItemSecret, Name: "b", DeletedDate: &newer},
+		{Type: models.VaultItemKey, Name: "a", DeletedDate: &newer},
+	}
+
+	sortDeletedItems(items)
+
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "old", "unknown-date"}, names)
+}
+
+func TestNewDeletedItem(t *testing.T) {
+	recoveryID := "https://vault.vault.azure.net/deletedsecrets/db"
+	value := "prod"
+	item := newDeletedItem(models.VaultItemSecret, "db", &recoveryID, nil, nil, map[string]*string{"env": &value, "empty": nil})
+
+	assert.Equal(t, models.VaultItemSecret, item.Type)
+	assert.Equal(t, "db", item.Name)
+	assert.Equal(t, recoveryID, item.RecoveryID)
+	assert.Equal(t, map[string]string{"env": "prod"}, item.Tags)
+}
+
+func TestDeleteWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		vault    *models.KeyVault
+		contains string
+	}{
+		{name: "Unknown settings", vault: nil, contains: "could not be read"},
+		{name: "Soft delete disabled", vault: &models.KeyVault{}, contains: "deleted permanently"},
+		{name: "Soft delete", vault: &models.KeyVault{SoftDeleteEnabled: true, SoftDeleteRetentionDays: 7}, contains: "recovered for 7 days"},
+		{name: "Purge protection", vault: &models.KeyVault{SoftDeleteEnabled: true, SoftDeleteRetentionDays: 90, PurgeProtectionEnabled: true}, contains: "Purge protection is enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := DeleteWarning(tt.vault, models.VaultItemSecret, "db")
+			assert.Contains(t, warning, "secret 'db'")
+			assert.Contains(t, warning, tt.contains)
+		})
+	}
+}
+
+func TestDeleteItemsWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		vault    *models.KeyVault
+		contains string
+	}{
+		{name: "Unknown settings", vault: nil, contains: "could not be read"},
+		{name: "Soft delete disabled", vault: &models.KeyVault{}, contains: "deleted permanently"},
+		{name: "Soft delete", vault: &models.KeyVault{SoftDeleteEnabled: true, SoftDeleteRetentionDays: 7}, contains: "recovered for 7 days"},
+		{name: "Purge protection", vault: &models.KeyVault{SoftDeleteEnabled: true, SoftDeleteRetentionDays: 90, PurgeProtectionEnabled: true}, contains: "Purge protection is enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := DeleteItemsWarning(tt.vault, models.VaultItemKey, 3)
+			assert.Contains(t, warning, "Delete 3 keys?")
+			assert.Contains(t, warning, tt.contains)
+		})
+	}
+}
+
+func TestCheckPurgeAllowed(t *testing.T) {
+	purgeDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	item := &models.DeletedItem{Type: models.VaultItemKey, Name: "signing", ScheduledPurgeDate: &purgeDate}
+
+	assert.NoError(t, CheckPurgeAllowed(nil, item), "Unknown settings leave the decision to the service")
+	assert.NoError(t, CheckPurgeAllowed(&models.KeyVault{SoftDeleteEnabled: true}, item))
+
+	protected := &models.KeyVault{SoftDeleteEnabled: true, PurgeProtectionEnabled: true}
+	err := CheckPurgeAllowed(protected, item)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), purgeDate.Local().Format("2006-01-02"))
+
+	assert.Error(t, CheckPurgeAllowed(protected, &models.DeletedItem{Type: models.VaultItemKey, Name: "signing"}))
+}
@@ -0,0 +1,239 @@
+package azure
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+const (
+	// maxSecretValueBytes is the largest secret value Key Vault accepts
+	maxSecretValueBytes = 25 * 1024
+	// maxSecretTags is the number of tags Key Vault allows on a secret
+	maxSecretTags = 15
+	// secretValueAlphabet is used for generated secret values
+	secretValueAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.~!@#%^*+="
+)
+
+// SetSecret creates a secret, or a new version of an existing secret, with the given value and properties
+func (c *Client) SetSecret(ctx context.Context, vaultURL string, secret *models.Secret) (*models.Secret, error) {
+	client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets client: %w", err)
+	}
+
+	parameters := azsecrets.SetSecretParameters{
+		Value: &secret.Value,
+		SecretAttributes: &azsecrets.SecretAttributes{
+			Enabled:   &secret.Enabled,
+			Expires:   secret.Expires,
+			NotBefore: secret.NotBefore,
+		},
+		Tags: toMetadataPointers(secret.Tags),
+	}
+	if secret.ContentType != "" {
+		parameters.ContentType = &secret.ContentType
+	}
+
+	resp, err := client.SetSecret(ctx, secret.Name, parameters, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set secret: %w", err)
+	}
+
+	result := &models.Secret{
+		Name:        secret.Name,
+		Enabled:     secret.Enabled,
+		ContentType: secret.ContentType,
+		Tags:        secret.Tags,
+	}
+	if resp.ID != nil {
+		result.Version = resp.ID.Version()
+	}
+	if resp.Attributes != nil {
+		result.Created = resp.Attributes.Created
+		result.Updated = resp.Attributes.Updated
+		result.Expires = resp.Attributes.Expires
+		result.NotBefore = resp.Attributes.NotBefore
+	}
+	return result, nil
+}
+
+// SetSecretEnabled enables or disables one version of a secret; an empty version means the latest
+func (c *Client) SetSecretEnabled(ctx context.Context, vaultURL, secretName, version string, enabled bool) error {
+	client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create secrets client: %w", err)
+	}
+
+	parameters := azsecrets.UpdateSecretPropertiesParameters{
+		SecretAttributes: &azsecrets.SecretAttributes{Enabled: &enabled},
+	}
+	if _, err := client.UpdateSecretProperties(ctx, secretName, version, parameters, nil); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	return nil
+}
+
+// ListSecretVersions lists every version of a secret, newest first
+func (c *Client) ListSecretVersions(ctx context.Context, vaultURL, secretName string) ([]*models.Secret, error) {
+	client, err := azsecrets.NewClient(vaultURL, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets client: %w", err)
+	}
+
+	pager := client.NewListSecretPropertiesVersionsPager(secretName, nil)
+	var versions []*models.Secret
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page: %w", err)
+		}
+
+		for _, secretProps := range page.Value {
+			if secretProps == nil || secretProps.ID == nil {
+				continue
+			}
+
+			secret := &models.Secret{
+				Name:    secretName,
+				Version: secretProps.ID.Version(),
+				Tags:    make(map[string]string),
+			}
+
+			if secretProps.Attributes != nil {
+				if secretProps.Attributes.Enabled != nil {
+					secret.Enabled = *secretProps.Attributes.Enabled
+				}
+				secret.Created = secretProps.Attributes.Created
+				secret.Updated = secretProps.Attributes.Updated
+				secret.Expires = secretProps.Attributes.Expires
+				secret.NotBefore = secretProps.Attributes.NotBefore
+			}
+
+			if secretProps.ContentType != nil {
+				secret.ContentType = *secretProps.ContentType
+			}
+
+			for k, v := range secretProps.Tags {
+				if v != nil {
+					secret.Tags[k] = *v
+				}
+			}
+
+			versions = append(versions, secret)
+		}
+	}
+
+	sortSecretVersions(versions)
+	return versions, nil
+}
+
+// sortSecretVersions orders versions newest first; versions without a creation time go last
+func sortSecretVersions(versions []*models.Secret) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, b := versions[i].Created, versions[j].Created
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.After(*b)
+	})
+}
+
+// ValidateSecretName checks the Key Vault object naming rules: 1-127 alphanumerics and dashes
+func ValidateSecretName(name string) error {
+	return validateVaultItemName(models.VaultItemSecret, name)
+}
+
+// validateVaultItemName checks a secret, key or certificate name; they share the same naming rules
+func validateVaultItemName(itemType, name string) error {
+	if len(name) < 1 || len(name) > 127 {
+		return fmt.Errorf("%s name must be 1-127 characters long", itemType)
+	}
+	for _, r := range name {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-') {
+			return fmt.Errorf("%s name may only contain letters, digits and dashes", itemType)
+		}
+	}
+	return nil
+}
+
+// ParseTags parses "key=value" lines into secret tags. Unlike metadata, keys keep their case.
+func ParseTags(text string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("line %d: expected key=value", i+1)
+		}
+		key := strings.TrimSpace(kv[0])
+		value := strings.TrimSpace(kv[1])
+		if key == "" || len(key) > 512 {
+			return nil, fmt.Errorf("line %d: tag name must be 1-512 characters long", i+1)
+		}
+		if len(value) > 256 {
+			return nil, fmt.Errorf("line %d: tag value must be at most 256 characters long", i+1)
+		}
+		if _, exists := tags[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate tag %q", i+1, key)
+		}
+		tags[key] = value
+	}
+	if len(tags) > maxSecretTags {
+		return nil, fmt.Errorf("at most %d tags are allowed, got %d", maxSecretTags, len(tags))
+	}
+	return tags, nil
+}
+
+// GenerateSecretValue returns a cryptographically random value of the given length
+func GenerateSecretValue(length int) (string, error) {
+	if length < 8 || length > 1024 {
+		return "", fmt.Errorf("generated length must be between 8 and 1024, got %d", length)
+	}
+
+	alphabetSize := big.NewInt(int64(len(secretValueAlphabet)))
+	value := make([]byte, length)
+	for i := range value {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate secret value: %w", err)
+		}
+		value[i] = secretValueAlphabet[n.Int64()]
+	}
+	return string(value), nil
+}
+
+// ReadSecretFile reads a secret value from a local file. The content is used as is, including any trailing newline.
+func ReadSecretFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > maxSecretValueBytes {
+		return "", fmt.Errorf("%s is %d bytes; secret values are limited to %d bytes", path, info.Size(), maxSecretValueBytes)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	if len(content) == 0 {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,139 @@
+package azure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSecretName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+	}{
+		{name: "Valid name", input: "db-password", expectErr: false},
+		{name: "Digits and capitals", input: "Api2Key", expectErr: false},
+		{name: "Max length", input: strings.Repeat("a", 127), expectErr: false},
+		{name: "Empty", input: "", expectErr: true},
+		{name: "Too long", input: strings.Repeat("a", 128), expectErr: true},
+		{name: "Underscore", input: "db_password", expectErr: true},
+		{name: "Dot", input: "db.password", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecretName(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{name: "Empty", input: "", expected: map[string]string{}},
+		{name: "Keeps key case", input: "Environment=Prod\nowner = team-a", expected: map[string]string{"Environment": "Prod", "owner": "team-a"}},
+		{name: "Value with equals sign", input: "conn=a=b", expected: map[string]string{"conn": "a=b"}},
+		{name: "Missing equals", input: "env", expectErr: true},
+		{name: "Empty key", input: "=value", expectErr: true},
+		{name: "Duplicate key", input: "env=a\nenv=b", expectErr: true},
+		{name: "Value too long", input: "env=" + strings.Repeat("x", 257), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseTags(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParseTags_TooMany(t *testing.T) {
+	var lines []string
+	for i := 0; i <= maxSecretTags; i++ {
+		lines = append(lines, strings.Repeat("k", i+1)+"=v")
+	}
+	_, err := ParseTags(strings.Join(lines, "\n"))
+	assert.Error(t, err)
+}
+
+func TestGenerateSecretValue(t *testing.T) {
+	value, err := GenerateSecretValue(32)
+	require.NoError(t, err)
+	assert.Len(t, value, 32)
+	for _, r := range value {
+		assert.Contains(t, secretValueAlphabet, string(r))
+	}
+
+	other, err := GenerateSecretValue(32)
+	require.NoError(t, err)
+	assert.NotEqual(t, value, other, "Generated values should differ")
+
+	_, err = GenerateSecretValue(4)
+	assert.Error(t, err)
+	_, err = GenerateSecretValue(2048)
+	assert.Error(t, err)
+}
+
+func TestReadSecretFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(valid, []byte("line1\nline2\n"), 0600))
+	value, err := ReadSecretFile(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "line1\nline2\n", value, "Content is used as is")
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, nil, 0600))
+	_, err = ReadSecretFile(empty)
+	assert.Error(t, err)
+
+	large := filepath.Join(dir, "large")
+	require.NoError(t, os.WriteFile(large, make([]byte, maxSecretValueBytes+1), 0600))
+	_, err = ReadSecretFile(large)
+	assert.Error(t, err)
+
+	_, err = ReadSecretFile(dir)
+	assert.Error(t, err)
+
+	_, err = ReadSecretFile(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestSortSecretVersions(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	versions := []*models.Secret{
+		{Version: "unknown"},
+		{Version: "v1", Created: &older},
+		{Version: "v2", Created: &newer},
+	}
+
+	sortSecretVersions(versions)
+
+	assert.Equal(t, "v2", versions[0].Version)
+	assert.Equal(t, "v1", versions[1].Version)
+	assert.Equal(t, "unknown", versions[2].Version)
+}
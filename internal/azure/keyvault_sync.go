@@ -0,0 +1,278 @@
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// ResolveVaultURL turns a vault name or URL into a vault URL with a trailing slash.
+// A bare name is assumed to be in the Azure public cloud.
+func ResolveVaultURL(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("enter a Key Vault name or URL")
+	}
+
+	if strings.Contains(input, "://") {
+		parsed, err := url.Parse(input)
+		if err != nil || parsed.Host == "" {
+			return "", fmt.Errorf("invalid Key Vault URL %q", input)
+		}
+		if parsed.Scheme != "https" {
+			return "", fmt.Errorf("Key Vault URL must use https")
+		}
+		return fmt.Sprintf("https://%s/", strings.ToLower(parsed.Host)), nil
+	}
+
+	if len(input) < 3 || len(input) > 24 {
+		return "", fmt.Errorf("Key Vault name must be 3-24 characters long")
+	}
+	for i, r := range input {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		switch {
+		case i == 0 && !isLetter:
+			return "", fmt.Errorf("Key Vault name must start with a letter")
+		case !isLetter && !isDigit && r != '-':
+			return "", fmt.Errorf("Key Vault name may only contain letters, digits and dashes")
+		}
+	}
+	if strings.HasSuffix(input, "-") || strings.Contains(input, "--") {
+		return "", fmt.Errorf("Key Vault name cannot end with a dash or contain consecutive dashes")
+	}
+	return fmt.Sprintf("https://%s.vault.azure.net/", strings.ToLower(input)), nil
+}
+
+// CompareVaultSecrets compares the secrets of two vaults by name, content type, tags, enabled state and
+// validity dates. Version IDs are vault specific and are not compared. When compareValues is set, the
+// values of secrets present and enabled in both vaults are read and compared by hash; values are never returned.
+func (c *Client) CompareVaultSecrets(ctx context.Context, sourceURL, targetURL string, compareValues bool) ([]*models.SecretDiff, error) {
+	sources, err := c.ListSecrets(ctx, sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source secrets: %w", err)
+	}
+	targets, err := c.ListSecrets(ctx, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target secrets: %w", err)
+	}
+
+	var sourceHashes, targetHashes map[string]string
+	if compareValues {
+		targetsByName := make(map[string]*models.Secret)
+		for _, secret := range targets {
+			targetsByName[secret.Name] = secret
+		}
+
+		sourceHashes = make(map[string]string)
+		targetHashes = make(map[string]string)
+		for _, source := range sources {
+			target, ok := targetsByName[source.Name]
+			// Values of disabled secrets cannot be read
+			if !ok || !source.Enabled || !target.Enabled {
+				continue
+			}
+			if sourceHashes[source.Name], err = c.secretValueHash(ctx, sourceURL, source.Name); err != nil {
+				return nil, err
+			}
+			if targetHashes[source.Name], err = c.secretValueHash(ctx, targetURL, source.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return diffSecrets(sources, targets, sourceHashes, targetHashes), nil
+}
+
+// secretValueHash reads the latest value of a secret and returns its SHA-256 hash
+func (c *Client) secretValueHash(ctx context.Context, vaultURL, secretName string) (string, error) {
+	value, err := c.GetSecretValue(ctx, vaultURL, secretName, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", secretName, err)
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// diffSecrets builds the sync diff of two secret lists, sorted by name.
+// Value hashes are optional; a value is only compared when both hashes are known.
+func diffSecrets(sources, targets []*models.Secret, sourceHashes, targetHashes map[string]string) []*models.SecretDiff {
+	targetsByName := make(map[string]*models.Secret)
+	for _, secret := range targets {
+		targetsByName[secret.Name] = secret
+	}
+
+	var diffs []*models.SecretDiff
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		seen[source.Name] = true
+		diff := &models.SecretDiff{Name: source.Name, Source: source}
+
+		target, ok := targetsByName[source.Name]
+		if !ok {
+			diff.Status = models.SyncStatusMissing
+			diffs = append(diffs, diff)
+			continue
+		}
+
+		diff.Target = target
+		diff.Differences = secretDifferences(source, target)
+		sourceHash, sourceKnown := sourceHashes[source.Name]
+		targetHash, targetKnown := targetHashes[source.Name]
+		if sourceKnown && targetKnown && sourceHash != targetHash {
+			diff.Differences = append(diff.Differences, "value")
+		}
+
+		diff.Status = models.SyncStatusIdentical
+		if len(diff.Differences) > 0 {
+			diff.Status = models.SyncStatusDifferent
+		}
+		diffs = append(diffs, diff)
+	}
+
+	for _, target := range targets {
+		if !seen[target.Name] {
+			diffs = append(diffs, &models.SecretDiff{Name: target.Name, Status: models.SyncStatusTargetOnly, Target: target})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// secretDifferences lists the properties that differ between two versions of a secret
+func secretDifferences(a, b *models.Secret) []string {
+	var differences []string
+	if a.ContentType != b.ContentType {
+		differences = append(differences, "content type")
+	}
+	if !equalTags(a.Tags, b.Tags) {
+		differences = append(differences, "tags")
+	}
+	if a.Enabled != b.Enabled {
+		differences = append(differences, "enabled")
+	}
+	if !equalTimes(a.Expires, b.Expires) {
+		differences = append(differences, "expires")
+	}
+	if !equalTimes(a.NotBefore, b.NotBefore) {
+		differences = append(differences, "not before")
+	}
+	return differences
+}
+
+// equalTags compares two tag maps, treating nil and empty as equal
+func equalTags(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+	return true
+}
+
+// equalTimes compares two optional times
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// CopySecrets copies the latest version of secrets, with their value and properties, from one vault to another.
+// Secrets that exist in the target get a new version when overwrite is set and are skipped otherwise.
+// onProgress is called after every secret; the final progress is also returned.
+func (c *Client) CopySecrets(ctx context.Context, sourceURL, targetURL string, names []string, overwrite bool, onProgress func(models.ItemProgress)) (models.ItemProgress, error) {
+	progress := models.ItemProgress{Total: len(names)}
+
+	existing := make(map[string]bool)
+	if !overwrite {
+		targets, err := c.ListSecrets(ctx, targetURL)
+		if err != nil {
+			return progress, fmt.Errorf("failed to list target secrets: %w", err)
+		}
+		for _, secret := range targets {
+			existing[secret.Name] = true
+		}
+	}
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		result := models.ItemResult{Item: models.VaultItemRef{Type: models.VaultItemSecret, Name: name}}
+		var err error
+		if existing[name] {
+			err = fmt.Errorf("already exists in the target vault")
+		} else {
+			err = c.copySecret(ctx, sourceURL, targetURL, name)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			progress.Failed++
+		} else {
+			progress.Succeeded++
+		}
+		progress.Results = append(progress.Results, result)
+		onProgress(progress)
+	}
+	return progress, nil
+}
+
+// copySecret reads the latest version of a secret and sets it in the target vault
+func (c *Client) copySecret(ctx context.Context, sourceURL, targetURL, name string) error {
+	client, err := azsecrets.NewClient(sourceURL, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create secrets client: %w", err)
+	}
+	resp, err := client.GetSecret(ctx, name, "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+	if resp.Managed != nil && *resp.Managed {
+		return fmt.Errorf("secret backs a certificate and cannot be copied")
+	}
+	if resp.Value == nil {
+		return fmt.Errorf("secret value is nil")
+	}
+
+	secret := &models.Secret{
+		Name:    name,
+		Value:   *resp.Value,
+		Enabled: true,
+		Tags:    make(map[string]string),
+	}
+	if resp.ContentType != nil {
+		secret.ContentType = *resp.ContentType
+	}
+	if resp.Attributes != nil {
+		if resp.Attributes.Enabled != nil {
+			secret.Enabled = *resp.Attributes.Enabled
+		}
+		secret.Expires = resp.Attributes.Expires
+		secret.NotBefore = resp.Attributes.NotBefore
+	}
+	for k, v := range resp.Tags {
+		if v != nil {
+			secret.Tags[k] = *v
+		}
+	}
+
+	_, err = c.SetSecret(ctx, targetURL, secret)
+	return err
+}
@@ -0,0 +1,90 @@
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveVaultURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{name: "Name", input: "My-Vault", expected: "https://my-vault.vault.azure.net/"},
+		{name: "URL without slash", input: "https://my-vault.vault.azure.net", expected: "https://my-vault.vault.azure.net/"},
+		{name: "URL with path", input: " https://my-vault.vault.azure.net/secrets/a ", expected: "https://my-vault.vault.azure.net/"},
+		{name: "Empty", input: "  ", expectErr: true},
+		{name: "HTTP URL", input: "http://my-vault.vault.azure.net", expectErr: true},
+		{name: "Too short", input: "ab", expectErr: true},
+		{name: "Starts with digit", input: "1vault", expectErr: true},
+		{name: "Invalid character", input: "my_vault", expectErr: true},
+		{name: "Consecutive dashes", input: "my--vault", expectErr: true},
+		{name: "Trailing dash", input: "my-vault-", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ResolveVaultURL(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestDiffSecrets(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	sources := []*models.Secret{
+		{Name: "db", Version: "v1", Enabled: true, ContentType: "text/plain", Tags: map[string]string{"env": "prod"}},
+		{Name: "api", Version: "v1", Enabled: true},
+		{Name: "cert", Version: "v1", Enabled: true, Expires: &expires},
+		{Name: "token", Version: "v1", Enabled: true},
+	}
+	targets := []*models.Secret{
+		{Name: "db", Version: "v9", Enabled: true, ContentType: "text/plain", Tags: map[string]string{"env": "prod"}},
+		{Name: "cert", Version: "v2", Enabled: false, ContentType: "application/json"},
+		{Name: "token", Version: "v3", Enabled: true},
+		{Name: "legacy", Version: "v1", Enabled: true},
+	}
+	sourceHashes := map[string]string{"db": "a", "token": "b"}
+	targetHashes := map[string]string{"db": "a", "token": "c"}
+
+	diffs := diffSecrets(sources, targets, sourceHashes, targetHashes)
+	require.Len(t, diffs, 5)
+
+	byName := make(map[string]*models.SecretDiff)
+	var names []string
+	for _, diff := range diffs {
+		byName[diff.Name] = diff
+		names = append(names, diff.Name)
+	}
+	assert.Equal(t, []string{"api", "cert", "db", "legacy", "token"}, names)
+
+	assert.Equal(t, models.SyncStatusMissing, byName["api"].Status)
+	assert.Nil(t, byName["api"].Target)
+	assert.Equal(t, models.SyncStatusIdentical, byName["db"].Status, "Versions are not compared")
+	assert.Equal(t, models.SyncStatusDifferent, byName["cert"].Status)
+	assert.Equal(t, []string{"content type", "enabled", "expires"}, byName["cert"].Differences)
+	assert.Equal(t, models.SyncStatusTargetOnly, byName["legacy"].Status)
+	assert.Nil(t, byName["legacy"].Source)
+	assert.Equal(t, []string{"value"}, byName["token"].Differences)
+}
+
+func TestDiffSecrets_WithoutValues(t *testing.T) {
+	sources := []*models.Secret{{Name: "a", Enabled: true, Tags: map[string]string{}}}
+	targets := []*models.Secret{{Name: "a", Enabled: true}}
+
+	diffs := diffSecrets(sources, targets, nil, nil)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, models.SyncStatusIdentical, diffs[0].Status, "Empty and nil tags are equal")
+}
@@ -0,0 +1,244 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+)
+
+// graphPageSize is the number of rows Resource Graph returns per page, its maximum
+const graphPageSize = 1000
+
+// graphResourceColumns are the columns of resource listings, with the ARM field names
+const graphResourceColumns = "id, name, type, location, resourceGroup, kind, sku, tags, properties"
+
+// QueryResourceGraph runs a KQL query with Azure Resource Graph and returns a page of its result as a
+// table. Without subscriptions the query spans every subscription the caller can read. An empty skip
+// token reads the first page.
+func (c *Client) QueryResourceGraph(ctx context.Context, query string, subscriptionIDs []string, skipToken string) (*models.GraphQueryResult, error) {
+	response, err := c.queryResourceGraph(ctx, query, subscriptionIDs, skipToken, armresourcegraph.ResultFormatTable)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := parseGraphTable(response.Data)
+	if err != nil {
+		return nil, err
+	}
+	if response.TotalRecords != nil {
+		result.TotalRecords = *response.TotalRecords
+	}
+	result.SkipToken = stringValue(response.SkipToken)
+	result.Truncated = response.ResultTruncated != nil && *response.ResultTruncated == armresourcegraph.ResultTruncatedTrue
+	return result, nil
+}
+
+// ListResourcesGraph lists the resources of a subscription, or of every subscription the caller can read
+// when subscriptionID is empty, optionally filtered by resource type. Every page of the result is read.
+func (c *Client) ListResourcesGraph(ctx context.Context, subscriptionID, resourceType string) ([]*models.Resource, error) {
+	query := "Resources"
+	if resourceType != "" {
+		query += " | where type =~ " + kqlString(resourceType)
+	}
+	query += " | project " + graphResourceColumns
+
+	objects, err := c.queryResourceGraphObjects(ctx, query, subscriptionScope(subscriptionID))
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*models.Resource, 0, len(objects))
+	for _, object := range objects {
+		resources = append(resources, convertGraphResource(object))
+	}
+	return resources, nil
+}
+
+// GetResourceTypeCounts returns resource type summaries with counts for a resource group, counted by
+// Resource Graph
+func (c *Client) GetResourceTypeCounts(ctx context.Context, subscriptionID, resourceGroupName string) ([]*models.ResourceTypeSummary, error) {
+	// Resource Graph lowercases types, so a resource ID of each type is kept to restore their casing
+	query := fmt.Sprintf("Resources | where resourceGroup =~ %s | summarize resources = count(), sampleId = any(id) by type", kqlString(resourceGroupName))
+	objects, err := c.queryResourceGraphObjects(ctx, query, subscriptionScope(subscriptionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count resources: %w", err)
+	}
+	return convertTypeCounts(objects), nil
+}
+
+// queryResourceGraph runs a query and returns a page of its result in the given format
+func (c *Client) queryResourceGraph(ctx context.Context, query string, subscriptionIDs []string, skipToken string, format armresourcegraph.ResultFormat) (armresourcegraph.QueryResponse, error) {
+	client, err := armresourcegraph.NewClient(c.credential, nil)
+	if err != nil {
+		return armresourcegraph.QueryResponse{}, fmt.Errorf("failed to create Resource Graph client: %w", err)
+	}
+
+	request := armresourcegraph.QueryRequest{
+		Query: to.Ptr(query),
+		Options: &armresourcegraph.QueryRequestOptions{
+			ResultFormat: to.Ptr(format),
+			Top:          to.Ptr(int32(graphPageSize)),
+		},
+	}
+	if skipToken != "" {
+		request.Options.SkipToken = to.Ptr(skipToken)
+	}
+	for _, subscriptionID := range subscriptionIDs {
+		request.Subscriptions = append(request.Subscriptions, to.Ptr(subscriptionID))
+	}
+
+	response, err := client.Resources(ctx, request, nil)
+	if err != nil {
+		return armresourcegraph.QueryResponse{}, fmt.Errorf("failed to query Resource Graph: %w", err)
+	}
+	return response.QueryResponse, nil
+}
+
+// queryResourceGraphObjects runs a query and returns the rows of every page as objects
+func (c *Client) queryResourceGraphObjects(ctx context.Context, query string, subscriptionIDs []string) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+	skipToken := ""
+	for {
+		response, err := c.queryResourceGraph(ctx, query, subscriptionIDs, skipToken, armresourcegraph.ResultFormatObjectArray)
+		if err != nil {
+			return nil, err
+		}
+		rows, ok := response.Data.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected Resource Graph result of type %T", response.Data)
+		}
+		for _, row := range rows {
+			if object, ok := row.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+
+		skipToken = stringValue(response.SkipToken)
+		if skipToken == "" {
+			return objects, nil
+		}
+	}
+}
+
+// subscriptionScope returns the subscriptions of a query: the given one, or none for every subscription
+func subscriptionScope(subscriptionID string) []string {
+	if subscriptionID == "" {
+		return nil
+	}
+	return []string{subscriptionID}
+}
+
+// parseGraphTable reads a result in table format: its columns and rows of values
+func parseGraphTable(data interface{}) (*models.GraphQueryResult, error) {
+	table, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected Resource Graph result of type %T", data)
+	}
+
+	result := &models.GraphQueryResult{}
+	columns, _ := table["columns"].([]interface{})
+	for _, column := range columns {
+		fields, _ := column.(map[string]interface{})
+		name, _ := fields["name"].(string)
+		columnType, _ := fields["type"].(string)
+		result.Columns = append(result.Columns, models.GraphColumn{Name: name, Type: columnType})
+	}
+	rows, _ := table["rows"].([]interface{})
+	for _, row := range rows {
+		values, _ := row.([]interface{})
+		result.Rows = append(result.Rows, values)
+	}
+	return result, nil
+}
+
+// convertGraphResource converts a resource listed by Resource Graph into the resource model
+func convertGraphResource(object map[string]interface{}) *models.Resource {
+	id, _ := object["id"].(string)
+	graphType, _ := object["type"].(string)
+	name, _ := object["name"].(string)
+	location, _ := object["location"].(string)
+	kind, _ := object["kind"].(string)
+
+	res := &models.Resource{
+		ID:            id,
+		Name:          name,
+		Type:          canonicalResourceType(id, graphType),
+		Location:      location,
+		ResourceGroup: extractResourceGroupFromID(id),
+		Kind:          kind,
+		Properties:    make(map[string]interface{}),
+	}
+	if res.ResourceGroup == "" {
+		// Resource Graph lowercases the resource group, so it is only used when the ID has none
+		res.ResourceGroup, _ = object["resourceGroup"].(string)
+	}
+	if properties, ok := object["properties"].(map[string]interface{}); ok {
+		res.Properties = properties
+	}
+	if sku, ok := object["sku"].(map[string]interface{}); ok && len(sku) > 0 {
+		res.SKU = sku
+	}
+	if tags, ok := object["tags"].(map[string]interface{}); ok && len(tags) > 0 {
+		res.Tags = make(map[string]*string, len(tags))
+		for key, value := range tags {
+			res.Tags[key] = to.Ptr(fmt.Sprint(value))
+		}
+	}
+	return res
+}
+
+// convertTypeCounts converts the type counts of Resource Graph into summaries, ordered by type
+func convertTypeCounts(objects []map[string]interface{}) []*models.ResourceTypeSummary {
+	summaries := make([]*models.ResourceTypeSummary, 0, len(objects))
+	for _, object := range objects {
+		graphType, _ := object["type"].(string)
+		if graphType == "" {
+			continue
+		}
+		sampleID, _ := object["sampleId"].(string)
+		count, _ := object["resources"].(float64)
+		summaries = append(summaries, &models.ResourceTypeSummary{
+			Type:  canonicalResourceType(sampleID, graphType),
+			Count: int(count),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return strings.ToLower(summaries[i].Type) < strings.ToLower(summaries[j].Type)
+	})
+	return summaries
+}
+
+// canonicalResourceType restores the casing of a type lowercased by Resource Graph from the ID of a
+// resource of that type, such as Microsoft.Storage/storageAccounts for microsoft.storage/storageaccounts
+func canonicalResourceType(resourceID, graphType string) string {
+	parts := splitResourceID(resourceID)
+	provider := -1
+	for i, part := range parts {
+		if strings.EqualFold(part, "providers") {
+			provider = i
+		}
+	}
+	if provider < 0 || provider+2 >= len(parts) {
+		return graphType
+	}
+
+	// The namespace is followed by type and name pairs, as in Microsoft.Sql/servers/{name}/databases/{name}
+	segments := []string{parts[provider+1]}
+	for i := provider + 2; i < len(parts); i += 2 {
+		segments = append(segments, parts[i])
+	}
+	if resourceType := strings.Join(segments, "/"); strings.EqualFold(resourceType, graphType) {
+		return resourceType
+	}
+	return graphType
+}
+
+// kqlString quotes a value as a KQL string literal
+func kqlString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,163 @@
+package azure
+
+import (
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalResourceType(t *testing.T) {
+	tests := []struct {
+		name       string
+		resourceID string
+		graphType  string
+		expected   string
+	}{
+		{
+			name:       "Restores casing from the ID",
+			resourceID: "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/myaccount",
+			graphType:  "microsoft.storage/storageaccounts",
+			expected:   "Microsoft.Storage/storageAccounts",
+		},
+		{
+			name:       "Nested type",
+			resourceID: "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Sql/servers/srv/databases/db",
+			graphType:  "microsoft.sql/servers/databases",
+			expected:   "Microsoft.Sql/servers/databases",
+		},
+		{
+			name:       "Extension resource uses the last provider",
+			resourceID: "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vm/providers/Microsoft.Insights/diagnosticSettings/ds",
+			graphType:  "microsoft.insights/diagnosticsettings",
+			expected:   "Microsoft.Insights/diagnosticSettings",
+		},
+		{
+			name:       "Type not matching the ID is kept",
+			resourceID: "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Web/sites/app",
+			graphType:  "microsoft.storage/storageaccounts",
+			expected:   "microsoft.storage/storageaccounts",
+		},
+		{
+			name:       "Empty ID",
+			resourceID: "",
+			graphType:  "microsoft.web/sites",
+			expected:   "microsoft.web/sites",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, canonicalResourceType(tt.resourceID, tt.graphType))
+		})
+	}
+}
+
+func TestKQLString(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "Plain value", value: "my-rg", expected: "'my-rg'"},
+		{name: "Quote", value: "it's", expected: `'it\'s'`},
+		{name: "Backslash", value: `a\b`, expected: `'a\\b'`},
+		{name: "Empty", value: "", expected: "''"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, kqlString(tt.value))
+		})
+	}
+}
+
+func TestParseGraphTable(t *testing.T) {
+	data := map[string]interface{}{
+		"columns": []interface{}{
+			map[string]interface{}{"name": "name", "type": "string"},
+			map[string]interface{}{"name": "count_", "type": "integer"},
+		},
+		"rows": []interface{}{
+			[]interface{}{"vm1", float64(3)},
+			[]interface{}{"vm2", float64(5)},
+		},
+	}
+
+	result, err := parseGraphTable(data)
+	require.NoError(t, err)
+	assert.Equal(t, []models.GraphColumn{{Name: "name", Type: "string"}, {Name: "count_", Type: "integer"}}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"vm1", float64(3)}, {"vm2", float64(5)}}, result.Rows)
+
+	_, err = parseGraphTable([]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestConvertGraphResource(t *testing.T) {
+	object := map[string]interface{}{
+		"id":            "/subscriptions/sub-123/resourceGroups/My-RG/providers/Microsoft.Storage/storageAccounts/myaccount",
+		"name":          "myaccount",
+		"type":          "microsoft.storage/storageaccounts",
+		"location":      "westeurope",
+		"resourceGroup": "my-rg",
+		"kind":          "StorageV2",
+		"sku":           map[string]interface{}{"name": "Standard_LRS"},
+		"tags":          map[string]interface{}{"env": "prod"},
+		"properties":    map[string]interface{}{"accessTier": "Hot"},
+	}
+
+	res := convertGraphResource(object)
+	assert.Equal(t, "myaccount", res.Name)
+	assert.Equal(t, "Microsoft.Storage/storageAccounts", res.Type)
+	assert.Equal(t, "My-RG", res.ResourceGroup)
+	assert.Equal(t, "westeurope", res.Location)
+	assert.Equal(t, "StorageV2", res.Kind)
+	assert.Equal(t, map[string]interface{}{"name": "Standard_LRS"}, res.SKU)
+	assert.Equal(t, "Hot", res.Properties["accessTier"])
+	require.Contains(t, res.Tags, "env")
+	assert.Equal(t, "prod", *res.Tags["env"])
+}
+
+func TestConvertGraphResourceWithoutOptionalFields(t *testing.T) {
+	res := convertGraphResource(map[string]interface{}{
+		"id":            "/subscriptions/sub-123/providers/Microsoft.Network/networkWatchers/nw",
+		"name":          "nw",
+		"type":          "microsoft.network/networkwatchers",
+		"resourceGroup": "",
+		"sku":           nil,
+		"tags":          map[string]interface{}{},
+	})
+	assert.Equal(t, "Microsoft.Network/networkWatchers", res.Type)
+	assert.Empty(t, res.ResourceGroup)
+	assert.Nil(t, res.SKU)
+	assert.Nil(t, res.Tags)
+	assert.NotNil(t, res.Properties)
+}
+
+func TestConvertTypeCounts(t *testing.T) {
+	objects := []map[string]interface{}{
+		{
+			"type":      "microsoft.web/sites",
+			"resources": float64(2),
+			"sampleId":  "/subscriptions/sub-123/resourceGroups/rg/providers/Microsoft.Web/sites/app",
+		},
+		{
+			"type":      "microsoft.storage/storageaccounts",
+			"resources": float64(3),
+			"sampleId":  "/subscriptions/sub-123/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa",
+		},
+		{
+			"type":      "",
+			"resources": float64(1),
+		},
+	}
+
+	summaries := convertTypeCounts(objects)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "Microsoft.Storage/storageAccounts", summaries[0].Type)
+	assert.Equal(t, 3, summaries[0].Count)
+	assert.Equal(t, "Microsoft.Web/sites", summaries[1].Type)
+	assert.Equal(t, 2, summaries[1].Count)
+}
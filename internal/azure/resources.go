@@ -5,10 +5,24 @@ import (
 	"fmt"
 
 	"azure-control-tower/internal/models"
+	"azure-control-tower/pkg/resource"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 )
 
+// listExpand asks resource lists for the provisioning state, which they omit by default
+const listExpand = "provisioningState"
+
+// FetchResources lists the resources of a scope: a resource group from Azure Resource Manager, and a
+// subscription or the whole tenant from Resource Graph. It is the default fetcher of the resource registry.
+func (c *Client) FetchResources(ctx context.Context, scope resource.Scope) ([]*models.Resource, error) {
+	if scope.ResourceGroup != "" {
+		return c.ListResourcesByResourceGroup(ctx, scope.SubscriptionID, scope.ResourceGroup, scope.ResourceType)
+	}
+	return c.ListResourcesGraph(ctx, scope.SubscriptionID, scope.ResourceType)
+}
+
 // ListResources returns all resources in the specified subscription, optionally filtered by resource type
 func (c *Client) ListResources(ctx context.Context, subscriptionID string, resourceType string) ([]*models.Resource, error) {
 	client, err := armresources.NewClient(subscriptionID, c.credential, nil)
@@ -16,13 +30,13 @@ func (c *Client) ListResources(ctx context.Context, subscriptionID string, resou
 		return nil, fmt.Errorf("failed to create resources client: %w", err)
 	}
 
-	var options *armresources.ClientListOptions
+	options := &armresources.ClientListOptions{
+		Expand: to.Ptr(listExpand),
+	}
 	if resourceType != "" {
 		// Filter by resource type if specified
 		filter := fmt.Sprintf("resourceType eq '%s'", resourceType)
-		options = &armresources.ClientListOptions{
-			Filter: &filter,
-		}
+		options.Filter = &filter
 	}
 
 	pager := client.NewListPager(options)
@@ -63,6 +77,8 @@ func (c *Client) ListResources(ctx context.Context, subscriptionID string, resou
 				Type:          resourceType,
 				Location:      location,
 				ResourceGroup: resourceGroup,
+				Kind:          stringValue(resource.Kind),
+				SKU:           convertResourceSKU(resource.SKU),
 				Tags:          resource.Tags,
 				Properties:    make(map[string]interface{}),
 			}
@@ -75,6 +91,7 @@ func (c *Client) ListResources(ctx context.Context, subscriptionID string, resou
 					res.Properties = props
 				}
 			}
+			addProvisioningState(res, resource.ProvisioningState)
 
 			resources = append(resources, res)
 		}
@@ -90,12 +107,12 @@ func (c *Client) ListResourcesByResourceGroup(ctx context.Context, subscriptionI
 		return nil, fmt.Errorf("failed to create resources client: %w", err)
 	}
 
-	var options *armresources.ClientListByResourceGroupOptions
+	options := &armresources.ClientListByResourceGroupOptions{
+		Expand: to.Ptr(listExpand),
+	}
 	if resourceType != "" {
 		filter := fmt.Sprintf("resourceType eq '%s'", resourceType)
-		options = &armresources.ClientListByResourceGroupOptions{
-			Filter: &filter,
-		}
+		options.Filter = &filter
 	}
 
 	pager := client.NewListByResourceGroupPager(resourceGroupName, options)
@@ -133,6 +150,8 @@ func (c *Client) ListResourcesByResourceGroup(ctx context.Context, subscriptionI
 				Type:          resourceType,
 				Location:      location,
 				ResourceGroup: resourceGroupName,
+				Kind:          stringValue(resource.Kind),
+				SKU:           convertResourceSKU(resource.SKU),
 				Tags:          resource.Tags,
 				Properties:    make(map[string]interface{}),
 			}
@@ -142,6 +161,7 @@ func (c *Client) ListResourcesByResourceGroup(ctx context.Context, subscriptionI
 					res.Properties = props
 				}
 			}
+			addProvisioningState(res, resource.ProvisioningState)
 
 			resources = append(resources, res)
 		}
@@ -150,32 +170,33 @@ func (c *Client) ListResourcesByResourceGroup(ctx context.Context, subscriptionI
 	return resources, nil
 }
 
-// GetResourceTypeCounts returns resource type summaries with counts for a resource group
-func (c *Client) GetResourceTypeCounts(ctx context.Context, subscriptionID, resourceGroupName string) ([]*models.ResourceTypeSummary, error) {
-	// Get all resources in the resource group
-	resources, err := c.ListResourcesByResourceGroup(ctx, subscriptionID, resourceGroupName, "")
-	if err != nil {
-		return nil, fmt.Errorf("failed to list resources: %w", err)
+// convertResourceSKU converts a resource SKU to a map keyed by the ARM field names, so that handlers can
+// address its fields as sku.name or sku.tier
+func convertResourceSKU(sku *armresources.SKU) map[string]interface{} {
+	if sku == nil {
+		return nil
 	}
-
-	// Aggregate by resource type
-	typeCounts := make(map[string]int)
-	for _, resource := range resources {
-		if resource.Type != "" {
-			typeCounts[resource.Type]++
+	fields := make(map[string]interface{})
+	for name, value := range map[string]*string{"name": sku.Name, "tier": sku.Tier, "size": sku.Size, "family": sku.Family, "model": sku.Model} {
+		if value != nil {
+			fields[name] = *value
 		}
 	}
-
-	// Convert to ResourceTypeSummary slice
-	summaries := make([]*models.ResourceTypeSummary, 0, len(typeCounts))
-	for resourceType, count := range typeCounts {
-		summaries = append(summaries, &models.ResourceTypeSummary{
-			Type:  resourceType,
-			Count: count,
-		})
+	if sku.Capacity != nil {
+		fields["capacity"] = *sku.Capacity
 	}
+	return fields
+}
 
-	return summaries, nil
+// addProvisioningState adds the provisioning state requested with $expand to the resource properties.
+// Resource lists return it next to the properties, which are often empty.
+func addProvisioningState(resource *models.Resource, state *string) {
+	if state == nil {
+		return
+	}
+	if _, ok := resource.Properties["provisioningState"]; !ok {
+		resource.Properties["provisioningState"] = *state
+	}
 }
 
 // extractResourceGroupFromID extracts the resource group name from a resource ID
@@ -0,0 +1,131 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+const (
+	armEndpoint = "https://management.azure.com"
+	armScope    = "https://management.azure.com/.default"
+)
+
+// ResolveAPIVersion returns the latest stable API version of a resource type, read from the metadata of
+// its resource provider. Types that only have preview versions resolve to the latest preview version.
+// Resolved versions are cached for the lifetime of the client.
+func (c *Client) ResolveAPIVersion(ctx context.Context, subscriptionID, resourceType string) (string, error) {
+	cacheKey := strings.ToLower(resourceType)
+	if version, ok := c.apiVersions.Load(cacheKey); ok {
+		return version.(string), nil
+	}
+
+	namespace, typeName, err := splitResourceType(resourceType)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armresources.NewProvidersClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create providers client: %w", err)
+	}
+	provider, err := client.Get(ctx, namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read resource provider %s: %w", namespace, err)
+	}
+
+	for _, providerType := range provider.ResourceTypes {
+		if providerType == nil || !strings.EqualFold(stringValue(providerType.ResourceType), typeName) {
+			continue
+		}
+		var versions []string
+		for _, version := range providerType.APIVersions {
+			if version != nil {
+				versions = append(versions, *version)
+			}
+		}
+		version := latestAPIVersion(versions)
+		if version == "" {
+			break
+		}
+		c.apiVersions.Store(cacheKey, version)
+		return version, nil
+	}
+	return "", fmt.Errorf("resource provider %s publishes no API version for %s", namespace, typeName)
+}
+
+// GetResourceJSON reads the full ARM representation of a resource with the latest stable API version of its type
+func (c *Client) GetResourceJSON(ctx context.Context, subscriptionID, resourceID, resourceType string) (*models.ResourceDocument, error) {
+	apiVersion, err := c.ResolveAPIVersion(ctx, subscriptionID, resourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve API version: %w", err)
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, armEndpoint+resourceID)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+
+	pipeline := runtime.NewPipeline("azure-control-tower", "", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(c.credential, []string{armScope}, nil)},
+	}, nil)
+	resp, err := pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, fmt.Errorf("failed to read resource: %w", runtime.NewResponseError(resp))
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+
+	return &models.ResourceDocument{
+		ID:         resourceID,
+		APIVersion: apiVersion,
+		JSON:       body,
+	}, nil
+}
+
+// splitResourceType splits a resource type such as Microsoft.Sql/servers/databases into its provider
+// namespace and the type name within the provider
+func splitResourceType(resourceType string) (string, string, error) {
+	namespace, typeName, ok := strings.Cut(resourceType, "/")
+	if !ok || namespace == "" || typeName == "" {
+		return "", "", fmt.Errorf("invalid resource type %q", resourceType)
+	}
+	return namespace, typeName, nil
+}
+
+// latestAPIVersion returns the newest stable version, or the newest preview version when there is no
+// stable one. Versions start with their date, so they order as text, and pre-release versions carry a
+// suffix after the date such as -preview or -beta.
+func latestAPIVersion(versions []string) string {
+	sorted := append([]string(nil), versions...)
+	sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	for _, version := range sorted {
+		if len(version) <= len("2006-01-02") || version[len("2006-01-02")] != '-' {
+			return version
+		}
+	}
+	if len(sorted) > 0 {
+		return sorted[0]
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expected string
+	}{
+		{
+			name:     "Stable preferred over newer preview",
+			versions: []string{"2023-01-01", "2024-11-01-preview", "2023-07-01", "2022-07-01"},
+			expected: "2023-07-01",
+		},
+		{
+			name:     "Other pre-release suffixes skipped",
+			versions: []string{"2021-04-01-beta", "2020-06-01", "2021-06-01-privatepreview"},
+			expected: "2020-06-01",
+		},
+		{
+			name:     "Only preview versions",
+			versions: []string{"2023-05-01-preview", "2024-02-01-preview"},
+			expected: "2024-02-01-preview",
+		},
+		{
+			name:     "No versions",
+			versions: nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, latestAPIVersion(tt.versions))
+		})
+	}
+}
+
+func TestSplitResourceType(t *testing.T) {
+	namespace, typeName, err := splitResourceType("Microsoft.Sql/servers/databases")
+	require.NoError(t, err)
+	assert.Equal(t, "Microsoft.Sql", namespace)
+	assert.Equal(t, "servers/databases", typeName)
+
+	for _, invalid := range []string{"", "Microsoft.Sql", "Microsoft.Sql/", "/servers"} {
+		_, _, err := splitResourceType(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -3,6 +3,10 @@ package azure
 import (
 	"testing"
 
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -172,3 +176,26 @@ func BenchmarkExtractResourceGroupFromID(b *testing.B) {
 		_ = extractResourceGroupFromID(id)
 	}
 }
+
+func TestConvertResourceSKU(t *testing.T) {
+	assert.Nil(t, convertResourceSKU(nil))
+	assert.Equal(t, map[string]interface{}{"name": "Standard_LRS", "tier": "Standard"},
+		convertResourceSKU(&armresources.SKU{Name: to.Ptr("Standard_LRS"), Tier: to.Ptr("Standard")}))
+	assert.Equal(t, map[string]interface{}{"name": "P1v3", "capacity": int32(2)},
+		convertResourceSKU(&armresources.SKU{Name: to.Ptr("P1v3"), Capacity: to.Ptr(int32(2))}))
+}
+
+func TestAddProvisioningState(t *testing.T) {
+	resource := &models.Resource{Properties: map[string]interface{}{}}
+	addProvisioningState(resource, to.Ptr("Succeeded"))
+	assert.Equal(t, "Succeeded", resource.Properties["provisioningState"])
+
+	// Properties returned by the list take precedence
+	resource = &models.Resource{Properties: map[string]interface{}{"provisioningState": "Updating"}}
+	addProvisioningState(resource, to.Ptr("Succeeded"))
+	assert.Equal(t, "Updating", resource.Properties["provisioningState"])
+
+	resource = &models.Resource{Properties: map[string]interface{}{}}
+	addProvisioningState(resource, nil)
+	assert.Empty(t, resource.Properties)
+}
@@ -0,0 +1,70 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/models"
+)
+
+// ResourceSearchLimit is the most resources a search returns; narrower criteria find the others
+const ResourceSearchLimit = 1000
+
+// SearchResources finds the resources matching the criteria in every subscription the caller can read,
+// ordered by name
+func (c *Client) SearchResources(ctx context.Context, criteria models.ResourceSearch) ([]*models.ResourceSearchResult, error) {
+	query, err := buildSearchQuery(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := c.queryResourceGraphObjects(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search resources: %w", err)
+	}
+	results := make([]*models.ResourceSearchResult, 0, len(objects))
+	for _, object := range objects {
+		subscriptionID, _ := object["subscriptionId"].(string)
+		results = append(results, &models.ResourceSearchResult{
+			Resource:       convertGraphResource(object),
+			SubscriptionID: subscriptionID,
+		})
+	}
+	return results, nil
+}
+
+// buildSearchQuery builds the Resource Graph query of a search. Names and types match on a part, tags on
+// their name and optional value, and locations on their name, all ignoring case.
+func buildSearchQuery(criteria models.ResourceSearch) (string, error) {
+	var filters []string
+	if name := strings.TrimSpace(criteria.Name); name != "" {
+		filters = append(filters, "name contains "+kqlString(name))
+	}
+	if resourceType := strings.TrimSpace(criteria.Type); resourceType != "" {
+		filters = append(filters, "type contains "+kqlString(resourceType))
+	}
+	if tag := strings.TrimSpace(criteria.Tag); tag != "" {
+		key, value, hasValue := strings.Cut(tag, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return "", fmt.Errorf("tag must be a tag name or name=value")
+		}
+		if hasValue {
+			filters = append(filters, fmt.Sprintf("tostring(tags[%s]) =~ %s", kqlString(key), kqlString(strings.TrimSpace(value))))
+		} else {
+			filters = append(filters, fmt.Sprintf("isnotnull(tags[%s])", kqlString(key)))
+		}
+	}
+	if location := strings.TrimSpace(criteria.Location); location != "" {
+		// Locations are stored without spaces, so "West Europe" finds westeurope
+		filters = append(filters, "location =~ "+kqlString(strings.ReplaceAll(location, " ", "")))
+	}
+	if len(filters) == 0 {
+		return "", fmt.Errorf("enter a name, type, tag or location to search for")
+	}
+
+	return fmt.Sprintf("Resources | where %s | project %s, subscriptionId | order by name asc | take %s",
+		strings.Join(filters, " and "), graphResourceColumns, strconv.Itoa(ResourceSearchLimit)), nil
+}
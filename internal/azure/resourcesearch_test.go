@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	const suffix = " | project id, name, type, location, resourceGroup, kind, sku, tags, properties, subscriptionId | order by name asc | take 1000"
+
+	tests := []struct {
+		name     string
+		criteria models.ResourceSearch
+		expected string
+	}{
+		{
+			name:     "Name",
+			criteria: models.ResourceSearch{Name: "stlogs"},
+			expected: "Resources | where name contains 'stlogs'" + suffix,
+		},
+		{
+			name:     "Type",
+			criteria: models.ResourceSearch{Type: "Microsoft.Storage/storageAccounts"},
+			expected: "Resources | where type contains 'Microsoft.Storage/storageAccounts'" + suffix,
+		},
+		{
+			name:     "Tag name",
+			criteria: models.ResourceSearch{Tag: "env"},
+			expected: "Resources | where isnotnull(tags['env'])" + suffix,
+		},
+		{
+			name:     "Tag name and value",
+			criteria: models.ResourceSearch{Tag: "env = prod"},
+			expected: "Resources | where tostring(tags['env']) =~ 'prod'" + suffix,
+		},
+		{
+			name:     "Location with spaces",
+			criteria: models.ResourceSearch{Location: "West Europe"},
+			expected: "Resources | where location =~ 'WestEurope'" + suffix,
+		},
+		{
+			name:     "All criteria",
+			criteria: models.ResourceSearch{Name: "logs", Type: "storageAccounts", Tag: "env=prod", Location: "westeurope"},
+			expected: "Resources | where name contains 'logs' and type contains 'storageAccounts' and tostring(tags['env']) =~ 'prod' and location =~ 'westeurope'" + suffix,
+		},
+		{
+			name:     "Quotes are escaped",
+			criteria: models.ResourceSearch{Name: "it's"},
+			expected: `Resources | where name contains 'it\'s'` + suffix,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := buildSearchQuery(tt.criteria)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestBuildSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria models.ResourceSearch
+	}{
+		{name: "No criteria", criteria: models.ResourceSearch{}},
+		{name: "Blank criteria", criteria: models.ResourceSearch{Name: "  ", Location: " "}},
+		{name: "Tag without name", criteria: models.ResourceSearch{Tag: "=prod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildSearchQuery(tt.criteria)
+			assert.Error(t, err)
+		})
+	}
+}
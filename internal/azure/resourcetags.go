@@ -0,0 +1,78 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// Limits of tag names and values set through ARM
+const (
+	maxTagNameLength  = 512
+	maxTagValueLength = 256
+)
+
+// invalidTagNameCharacters cannot be used in tag names
+const invalidTagNameCharacters = `<>%&\?/`
+
+// SetResourceTag sets a tag on a resource, keeping its other tags
+func (c *Client) SetResourceTag(ctx context.Context, resourceID, name, value string) error {
+	return c.patchResourceTags(ctx, resourceID, armresources.TagsPatchOperationMerge, map[string]*string{name: to.Ptr(value)})
+}
+
+// RemoveResourceTag removes a tag from a resource, keeping its other tags. ARM removes tags by name and
+// value, so the current value of the tag is given.
+func (c *Client) RemoveResourceTag(ctx context.Context, resourceID, name, value string) error {
+	return c.patchResourceTags(ctx, resourceID, armresources.TagsPatchOperationDelete, map[string]*string{name: to.Ptr(value)})
+}
+
+// patchResourceTags applies a tags patch operation to a resource
+func (c *Client) patchResourceTags(ctx context.Context, resourceID string, operation armresources.TagsPatchOperation, tags map[string]*string) error {
+	subscriptionID := extractSubscriptionFromID(resourceID)
+	if subscriptionID == "" {
+		return fmt.Errorf("invalid resource ID %q", resourceID)
+	}
+
+	client, err := armresources.NewTagsClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create tags client: %w", err)
+	}
+
+	patch := armresources.TagsPatchResource{
+		Operation:  to.Ptr(operation),
+		Properties: &armresources.Tags{Tags: tags},
+	}
+	if _, err := client.UpdateAtScope(ctx, resourceID, patch, nil); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+	return nil
+}
+
+// ValidateTag checks a tag name and value against the limits of ARM
+func ValidateTag(name, value string) error {
+	if name == "" {
+		return fmt.Errorf("a tag name is required")
+	}
+	if len(name) > maxTagNameLength {
+		return fmt.Errorf("tag name is longer than %d characters", maxTagNameLength)
+	}
+	if strings.ContainsAny(name, invalidTagNameCharacters) {
+		return fmt.Errorf("tag name %q cannot contain any of %s", name, invalidTagNameCharacters)
+	}
+	if len(value) > maxTagValueLength {
+		return fmt.Errorf("tag value is longer than %d characters", maxTagValueLength)
+	}
+	return nil
+}
+
+// extractSubscriptionFromID extracts the subscription ID from a resource ID
+func extractSubscriptionFromID(id string) string {
+	parts := splitResourceID(id)
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "subscriptions") {
+		return parts[1]
+	}
+	return ""
+}
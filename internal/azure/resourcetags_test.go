@@ -0,0 +1,44 @@
+package azure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagName     string
+		tagValue    string
+		expectError bool
+	}{
+		{name: "Name and value", tagName: "env", tagValue: "prod"},
+		{name: "Empty value", tagName: "owner", tagValue: ""},
+		{name: "Name with spaces and dashes", tagName: "cost center-1", tagValue: "42"},
+		{name: "Missing name", tagName: "", tagValue: "prod", expectError: true},
+		{name: "Slash in name", tagName: "team/app", tagValue: "x", expectError: true},
+		{name: "Percent in name", tagName: "100%", tagValue: "x", expectError: true},
+		{name: "Name too long", tagName: strings.Repeat("a", 513), tagValue: "x", expectError: true},
+		{name: "Value too long", tagName: "env", tagValue: strings.Repeat("v", 257), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTag(tt.tagName, tt.tagValue)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExtractSubscriptionFromID(t *testing.T) {
+	assert.Equal(t, "sub-123", extractSubscriptionFromID("/subscriptions/sub-123/resourceGroups/rg/providers/Microsoft.Web/sites/app"))
+	assert.Equal(t, "sub-123", extractSubscriptionFromID("/Subscriptions/sub-123/resourceGroups/rg"))
+	assert.Empty(t, extractSubscriptionFromID("/providers/Microsoft.Management/managementGroups/mg"))
+	assert.Empty(t, extractSubscriptionFromID(""))
+}
@@ -14,30 +14,15 @@ import (
 
 // ListContainers lists all containers in a storage account
 func (c *Client) ListContainers(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName string) ([]*models.Container, error) {
-	// Get storage account keys
-	keys, err := c.getStorageAccountKeys(ctx, subscriptionID, resourceGroupName, storageAccountName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get storage account keys: %w", err)
-	}
-
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no storage account keys found")
-	}
-
-	// Use the first key to create blob service client
-	credential, err := azblob.NewSharedKeyCredential(storageAccountName, keys[0])
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create credential: %w", err)
-	}
-
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
-	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create blob client: %w", err)
+		return nil, err
 	}
 
-	// List containers
-	pager := client.NewListContainersPager(nil)
+	// List containers, including metadata so it can be shown and edited
+	pager := client.NewListContainersPager(&azblob.ListContainersOptions{
+		Include: azblob.ListContainersInclude{Metadata: true},
+	})
 	var containers []*models.Container
 
 	for pager.More() {
@@ -66,6 +51,12 @@ func (c *Client) ListContainers(ctx context.Context, subscriptionID, resourceGro
 				if containerItem.Properties.PublicAccess != nil {
 					container.PublicAccess = string(*containerItem.Properties.PublicAccess)
 				}
+				if containerItem.Properties.HasImmutabilityPolicy != nil {
+					container.HasImmutabilityPolicy = *containerItem.Properties.HasImmutabilityPolicy
+				}
+				if containerItem.Properties.HasLegalHold != nil {
+					container.HasLegalHold = *containerItem.Properties.HasLegalHold
+				}
 			}
 
 			if containerItem.Metadata != nil {
@@ -87,26 +78,9 @@ func (c *Client) ListContainers(ctx context.Context, subscriptionID, resourceGro
 // prefix: the current folder path prefix (e.g., "folder1/subfolder/")
 // Returns only immediate children (folders and files) of the prefix
 func (c *Client) ListBlobs(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, prefix string) ([]*models.Blob, error) {
-	// Get storage account keys
-	keys, err := c.getStorageAccountKeys(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get storage account keys: %w", err)
-	}
-
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no storage account keys found")
-	}
-
-	// Use the first key to create blob service client
-	credential, err := azblob.NewSharedKeyCredential(storageAccountName, keys[0])
-	if err != nil {
-		return nil, fmt.Errorf("failed to create credential: %w", err)
-	}
-
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
-	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create blob client: %w", err)
+		return nil, err
 	}
 
 	// List all blobs with the prefix (flat listing)
@@ -184,6 +158,12 @@ func (c *Client) ListBlobs(ctx context.Context, subscriptionID, resourceGroupNam
 					if blobItem.Properties.ETag != nil {
 						blob.ETag = string(*blobItem.Properties.ETag)
 					}
+					if blobItem.Properties.AccessTier != nil {
+						blob.AccessTier = string(*blobItem.Properties.AccessTier)
+					}
+					if blobItem.Properties.ArchiveStatus != nil {
+						blob.ArchiveStatus = string(*blobItem.Properties.ArchiveStatus)
+					}
 				}
 
 				if blobItem.Metadata != nil {
@@ -257,26 +237,9 @@ func getParentFolderPath(blobPath, currentPrefix string) string {
 
 // GetBlobDetails gets detailed information about a blob
 func (c *Client) GetBlobDetails(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, blobName string) (*models.Blob, error) {
-	// Get storage account keys
-	keys, err := c.getStorageAccountKeys(ctx, subscriptionID, resourceGroupName, storageAccountName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get storage account keys: %w", err)
-	}
-
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no storage account keys found")
-	}
-
-	// Use the first key to create blob service client
-	credential, err := azblob.NewSharedKeyCredential(storageAccountName, keys[0])
-	if err != nil {
-		return nil, fmt.Errorf("failed to create credential: %w", err)
-	}
-
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
-	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create blob client: %w", err)
+		return nil, err
 	}
 
 	// Get blob properties
@@ -305,6 +268,21 @@ func (c *Client) GetBlobDetails(ctx context.Context, subscriptionID, resourceGro
 	if props.ETag != nil {
 		blob.ETag = string(*props.ETag)
 	}
+	if props.CacheControl != nil {
+		blob.CacheControl = *props.CacheControl
+	}
+	if props.ContentDisposition != nil {
+		blob.ContentDisposition = *props.ContentDisposition
+	}
+	if props.AccessTier != nil {
+		blob.AccessTier = *props.AccessTier
+	}
+	if props.ArchiveStatus != nil {
+		blob.ArchiveStatus = *props.ArchiveStatus
+	}
+	if props.RehydratePriority != nil {
+		blob.RehydratePriority = *props.RehydratePriority
+	}
 
 	if props.Metadata != nil {
 		for k, v := range props.Metadata {
@@ -317,6 +295,43 @@ func (c *Client) GetBlobDetails(ctx context.Context, subscriptionID, resourceGro
 	return blob, nil
 }
 
+// newBlobClient creates a blob service client for a storage account.
+// When the client was created for a direct storage endpoint, that endpoint is used and ARM is bypassed;
+// otherwise the first storage account key is fetched through ARM.
+func (c *Client) newBlobClient(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName string) (*azblob.Client, error) {
+	if c.storageEndpoint != nil {
+		client, err := newEndpointBlobClient(c.storageEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create blob client: %w", err)
+		}
+		return client, nil
+	}
+
+	// Get storage account keys
+	keys, err := c.getStorageAccountKeys(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage account keys: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no storage account keys found")
+	}
+
+	// Use the first key to create blob service client
+	credential, err := azblob.NewSharedKeyCredential(storageAccountName, keys[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
+	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob client: %w", err)
+	}
+
+	return client, nil
+}
+
 // getStorageAccountKeys retrieves the storage account keys
 func (c *Client) getStorageAccountKeys(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName string) ([]string, error) {
 	client, err := armstorage.NewAccountsClient(subscriptionID, c.credential, nil)
@@ -0,0 +1,171 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// AccessTiers are the blob access tiers that can be set explicitly
+var AccessTiers = []string{"Hot", "Cool", "Cold", "Archive"}
+
+// RehydratePriorities are the priorities for rehydrating a blob out of the archive tier
+var RehydratePriorities = []string{"Standard", "High"}
+
+// UpdateBlobProperties sets the content type, cache control, content disposition and metadata of a blob.
+// The remaining HTTP headers (content encoding, language and MD5) are preserved.
+func (c *Client) UpdateBlobProperties(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, updated *models.Blob) error {
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+	blobClient := client.ServiceClient().NewContainerClient(containerName).NewBlobClient(updated.Name)
+
+	// Setting HTTP headers replaces all of them, so start from the current values
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get blob properties: %w", err)
+	}
+
+	headers := blob.HTTPHeaders{
+		BlobContentEncoding: props.ContentEncoding,
+		BlobContentLanguage: props.ContentLanguage,
+		BlobContentMD5:      props.ContentMD5,
+	}
+	if updated.ContentType != "" {
+		headers.BlobContentType = &updated.ContentType
+	}
+	if updated.CacheControl != "" {
+		headers.BlobCacheControl = &updated.CacheControl
+	}
+	if updated.ContentDisposition != "" {
+		headers.BlobContentDisposition = &updated.ContentDisposition
+	}
+
+	// Guard both writes with the ETag read above so concurrent changes are not overwritten
+	conditions := &blob.AccessConditions{
+		ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfMatch: props.ETag},
+	}
+	headersResp, err := blobClient.SetHTTPHeaders(ctx, headers, &blob.SetHTTPHeadersOptions{AccessConditions: conditions})
+	if err != nil {
+		return fmt.Errorf("failed to set blob properties: %w", err)
+	}
+
+	conditions.ModifiedAccessConditions.IfMatch = headersResp.ETag
+	if _, err := blobClient.SetMetadata(ctx, toMetadataPointers(updated.Metadata), &blob.SetMetadataOptions{AccessConditions: conditions}); err != nil {
+		return fmt.Errorf("failed to set blob metadata: %w", err)
+	}
+	return nil
+}
+
+// BlobContainer runs operations on the blobs of one container, with the client resolved once for actions
+// on many blobs
+type BlobContainer struct {
+	client *container.Client
+}
+
+// OpenBlobContainer resolves the client of a container for operations on its blobs
+func (c *Client) OpenBlobContainer(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string) (*BlobContainer, error) {
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return nil, err
+	}
+	return &BlobContainer{client: client.ServiceClient().NewContainerClient(containerName)}, nil
+}
+
+// SetTier changes the access tier of a blob.
+// The rehydrate priority only applies to a blob currently in the archive tier.
+func (bc *BlobContainer) SetTier(ctx context.Context, b *models.Blob, tier, rehydratePriority string) error {
+	accessTier, err := parseAccessTier(tier)
+	if err != nil {
+		return err
+	}
+	priority, err := parseRehydratePriority(rehydratePriority)
+	if err != nil {
+		return err
+	}
+
+	options := &blob.SetTierOptions{}
+	if strings.EqualFold(b.AccessTier, string(blob.AccessTierArchive)) && accessTier != blob.AccessTierArchive {
+		options.RehydratePriority = priority
+	}
+	if _, err := bc.client.NewBlobClient(b.Name).SetTier(ctx, accessTier, options); err != nil {
+		return fmt.Errorf("failed to set access tier: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a blob with its snapshots
+func (bc *BlobContainer) Delete(ctx context.Context, name string) error {
+	options := &blob.DeleteOptions{DeleteSnapshots: to.Ptr(blob.DeleteSnapshotsOptionTypeInclude)}
+	if _, err := bc.client.NewBlobClient(name).Delete(ctx, options); err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// Download writes a blob to a local file, creating the directories of the path. An existing file is
+// never overwritten.
+func (bc *BlobContainer) Download(ctx context.Context, name, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	_, err = bc.client.NewBlobClient(name).DownloadFile(ctx, file, nil)
+	closeErr := file.Close()
+	if err != nil {
+		// A partial file would pass for the blob, so it is removed
+		os.Remove(path)
+		return fmt.Errorf("failed to download blob: %w", err)
+	}
+	return closeErr
+}
+
+// BlobDownloadPath returns the local path a blob is downloaded to: its path below the folder it was
+// chosen in, inside dir, so downloads keep the folder structure. Names leading out of dir are rejected.
+func BlobDownloadPath(dir, folderPrefix, name string) (string, error) {
+	relative := strings.TrimPrefix(name, folderPrefix)
+	path := filepath.Join(dir, filepath.FromSlash(relative))
+	inside, err := filepath.Rel(dir, path)
+	if err != nil || relative == "" || inside == "." || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("blob %q cannot be downloaded to %s", name, dir)
+	}
+	return path, nil
+}
+
+// parseAccessTier converts a tier name to the SDK value
+func parseAccessTier(tier string) (blob.AccessTier, error) {
+	for _, t := range AccessTiers {
+		if strings.EqualFold(t, strings.TrimSpace(tier)) {
+			return blob.AccessTier(t), nil
+		}
+	}
+	return "", fmt.Errorf("unknown access tier %q (use %s)", tier, strings.Join(AccessTiers, ", "))
+}
+
+// parseRehydratePriority converts a priority name to the SDK value; empty means the service default
+func parseRehydratePriority(priority string) (*blob.RehydratePriority, error) {
+	priority = strings.TrimSpace(priority)
+	if priority == "" {
+		return nil, nil
+	}
+	for _, p := range RehydratePriorities {
+		if strings.EqualFold(p, priority) {
+			value := blob.RehydratePriority(p)
+			return &value, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown rehydrate priority %q (use %s)", priority, strings.Join(RehydratePriorities, " or "))
+}
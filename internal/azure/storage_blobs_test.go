@@ -0,0 +1,97 @@
+package azure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccessTier(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    blob.AccessTier
+		expectError bool
+	}{
+		{name: "Hot", input: "Hot", expected: blob.AccessTierHot},
+		{name: "Case insensitive", input: "cool", expected: blob.AccessTierCool},
+		{name: "Cold", input: " Cold ", expected: blob.AccessTierCold},
+		{name: "Archive", input: "ARCHIVE", expected: blob.AccessTierArchive},
+		{name: "Premium tiers are not supported", input: "P10", expectError: true},
+		{name: "Empty", input: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, err := parseAccessTier(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tier)
+		})
+	}
+}
+
+func TestParseRehydratePriority(t *testing.T) {
+	high := blob.RehydratePriorityHigh
+	standard := blob.RehydratePriorityStandard
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    *blob.RehydratePriority
+		expectError bool
+	}{
+		{name: "Empty uses service default", input: ""},
+		{name: "Standard", input: "Standard", expected: &standard},
+		{name: "High is case insensitive", input: "high", expected: &high},
+		{name: "Unknown", input: "urgent", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priority, err := parseRehydratePriority(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, priority)
+		})
+	}
+}
+
+func TestBlobDownloadPath(t *testing.T) {
+	dir := filepath.Join("downloads", "run")
+	tests := []struct {
+		name         string
+		folderPrefix string
+		blobName     string
+		expected     string
+		expectError  bool
+	}{
+		{name: "Blob in the root", blobName: "report.csv", expected: filepath.Join(dir, "report.csv")},
+		{name: "Folder prefix is removed", folderPrefix: "logs/2024/", blobName: "logs/2024/app.log", expected: filepath.Join(dir, "app.log")},
+		{name: "Subfolders are kept", folderPrefix: "logs/", blobName: "logs/2024/01/app.log", expected: filepath.Join(dir, "2024", "01", "app.log")},
+		{name: "Parent reference", blobName: "../secrets.txt", expectError: true},
+		{name: "Parent reference inside a folder", folderPrefix: "logs/", blobName: "logs/../../x", expectError: true},
+		{name: "Name of the folder itself", folderPrefix: "logs/", blobName: "logs/", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := BlobDownloadPath(dir, tt.folderPrefix, tt.blobName)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
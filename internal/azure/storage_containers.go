@@ -0,0 +1,566 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// errRequiresARM is returned for management operations that are unavailable on a direct storage endpoint
+var errRequiresARM = errors.New("this operation requires Azure Resource Manager access and is not available on a direct storage endpoint")
+
+// maxStoredAccessPolicies is the service limit of stored access policies per container
+const maxStoredAccessPolicies = 5
+
+// CreateContainer creates a container with the given public access level and metadata
+func (c *Client) CreateContainer(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, publicAccess string, metadata map[string]string) error {
+	if err := ValidateContainerName(containerName); err != nil {
+		return err
+	}
+	access, err := parsePublicAccessLevel(publicAccess)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	options := &container.CreateOptions{
+		Access:   access,
+		Metadata: toMetadataPointers(metadata),
+	}
+	if _, err := client.ServiceClient().NewContainerClient(containerName).Create(ctx, options); err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+	return nil
+}
+
+// DeleteContainer deletes a container and all blobs in it
+func (c *Client) DeleteContainer(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string) error {
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.ServiceClient().NewContainerClient(containerName).Delete(ctx, nil); err != nil {
+		return fmt.Errorf("failed to delete container: %w", err)
+	}
+	return nil
+}
+
+// SetContainerMetadata replaces the metadata of a container
+func (c *Client) SetContainerMetadata(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, metadata map[string]string) error {
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	options := &container.SetMetadataOptions{
+		Metadata: toMetadataPointers(metadata),
+	}
+	if _, err := client.ServiceClient().NewContainerClient(containerName).SetMetadata(ctx, options); err != nil {
+		return fmt.Errorf("failed to set container metadata: %w", err)
+	}
+	return nil
+}
+
+// SetContainerPublicAccess changes the public access level of a container, keeping its stored access policies
+func (c *Client) SetContainerPublicAccess(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, publicAccess string) error {
+	access, err := parsePublicAccessLevel(publicAccess)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+	containerClient := client.ServiceClient().NewContainerClient(containerName)
+
+	// Setting the access level replaces the whole ACL, so the existing policies must be sent back
+	current, err := containerClient.GetAccessPolicy(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get container access policy: %w", err)
+	}
+
+	options := &container.SetAccessPolicyOptions{
+		Access:       access,
+		ContainerACL: current.SignedIdentifiers,
+	}
+	if _, err := containerClient.SetAccessPolicy(ctx, options); err != nil {
+		return fmt.Errorf("failed to set container public access: %w", err)
+	}
+	return nil
+}
+
+// GetContainerAccessPolicies returns the stored access policies of a container
+func (c *Client) GetContainerAccessPolicies(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string) ([]*models.StoredAccessPolicy, error) {
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.ServiceClient().NewContainerClient(containerName).GetAccessPolicy(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container access policy: %w", err)
+	}
+
+	var policies []*models.StoredAccessPolicy
+	for _, identifier := range resp.SignedIdentifiers {
+		if identifier == nil || identifier.ID == nil {
+			continue
+		}
+		policy := &models.StoredAccessPolicy{
+			ID: *identifier.ID,
+		}
+		if identifier.AccessPolicy != nil {
+			if identifier.AccessPolicy.Permission != nil {
+				policy.Permission = *identifier.AccessPolicy.Permission
+			}
+			policy.Start = identifier.AccessPolicy.Start
+			policy.Expiry = identifier.AccessPolicy.Expiry
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// SetContainerAccessPolicies replaces the stored access policies of a container, keeping its public access level
+func (c *Client) SetContainerAccessPolicies(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, policies []*models.StoredAccessPolicy) error {
+	if len(policies) > maxStoredAccessPolicies {
+		return fmt.Errorf("a container can have at most %d stored access policies", maxStoredAccessPolicies)
+	}
+
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+	containerClient := client.ServiceClient().NewContainerClient(containerName)
+
+	current, err := containerClient.GetAccessPolicy(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get container access policy: %w", err)
+	}
+
+	identifiers := make([]*container.SignedIdentifier, 0, len(policies))
+	for _, policy := range policies {
+		id := policy.ID
+		permission := policy.Permission
+		identifiers = append(identifiers, &container.SignedIdentifier{
+			ID: &id,
+			AccessPolicy: &container.AccessPolicy{
+				Permission: &permission,
+				Start:      policy.Start,
+				Expiry:     policy.Expiry,
+			},
+		})
+	}
+
+	options := &container.SetAccessPolicyOptions{
+		Access:       current.BlobPublicAccess,
+		ContainerACL: identifiers,
+	}
+	if _, err := containerClient.SetAccessPolicy(ctx, options); err != nil {
+		return fmt.Errorf("failed to set container access policies: %w", err)
+	}
+	return nil
+}
+
+// GetContainerProtection returns the legal hold and immutability policy settings of a container
+func (c *Client) GetContainerProtection(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string) (*models.ContainerProtection, error) {
+	client, err := c.blobContainersClient(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(ctx, resourceGroupName, storageAccountName, containerName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container: %w", err)
+	}
+
+	protection := &models.ContainerProtection{}
+	props := resp.ContainerProperties
+	if props == nil {
+		return protection, nil
+	}
+
+	if props.HasLegalHold != nil {
+		protection.HasLegalHold = *props.HasLegalHold
+	}
+	if props.LegalHold != nil {
+		for _, tag := range props.LegalHold.Tags {
+			if tag != nil && tag.Tag != nil {
+				protection.LegalHoldTags = append(protection.LegalHoldTags, *tag.Tag)
+			}
+		}
+	}
+
+	if props.HasImmutabilityPolicy != nil {
+		protection.HasImmutabilityPolicy = *props.HasImmutabilityPolicy
+	}
+	if props.ImmutabilityPolicy != nil {
+		if props.ImmutabilityPolicy.Etag != nil {
+			protection.ImmutabilityETag = *props.ImmutabilityPolicy.Etag
+		}
+		if policy := props.ImmutabilityPolicy.Properties; policy != nil {
+			if policy.ImmutabilityPeriodSinceCreationInDays != nil {
+				protection.ImmutabilityPeriodDays = *policy.ImmutabilityPeriodSinceCreationInDays
+			}
+			if policy.State != nil {
+				protection.ImmutabilityState = string(*policy.State)
+			}
+			if policy.AllowProtectedAppendWrites != nil {
+				protection.AllowProtectedAppendWrites = *policy.AllowProtectedAppendWrites
+			}
+		}
+	}
+
+	return protection, nil
+}
+
+// SetContainerLegalHold adds legal hold tags to a container
+func (c *Client) SetContainerLegalHold(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, tags []string) error {
+	client, err := c.blobContainersClient(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.SetLegalHold(ctx, resourceGroupName, storageAccountName, containerName, armstorage.LegalHold{Tags: toStringPointers(tags)}, nil); err != nil {
+		return fmt.Errorf("failed to set legal hold: %w", err)
+	}
+	return nil
+}
+
+// ClearContainerLegalHold removes legal hold tags from a container
+func (c *Client) ClearContainerLegalHold(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, tags []string) error {
+	client, err := c.blobContainersClient(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.ClearLegalHold(ctx, resourceGroupName, storageAccountName, containerName, armstorage.LegalHold{Tags: toStringPointers(tags)}, nil); err != nil {
+		return fmt.Errorf("failed to clear legal hold: %w", err)
+	}
+	return nil
+}
+
+// SetContainerImmutabilityPolicy creates or updates an unlocked time-based retention policy.
+// etag must be the current policy ETag when updating an existing policy, or empty when creating one.
+func (c *Client) SetContainerImmutabilityPolicy(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, periodDays int32, allowProtectedAppendWrites bool, etag string) error {
+	if periodDays < 1 || periodDays > 146000 {
+		return fmt.Errorf("immutability period must be between 1 and 146000 days")
+	}
+
+	client, err := c.blobContainersClient(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	options := &armstorage.BlobContainersClientCreateOrUpdateImmutabilityPolicyOptions{
+		Parameters: &armstorage.ImmutabilityPolicy{
+			Properties: &armstorage.ImmutabilityPolicyProperty{
+				ImmutabilityPeriodSinceCreationInDays: &periodDays,
+				AllowProtectedAppendWrites:            &allowProtectedAppendWrites,
+			},
+		},
+	}
+	if etag != "" {
+		options.IfMatch = &etag
+	}
+
+	if _, err := client.CreateOrUpdateImmutabilityPolicy(ctx, resourceGroupName, storageAccountName, containerName, options); err != nil {
+		return fmt.Errorf("failed to set immutability policy: %w", err)
+	}
+	return nil
+}
+
+// DeleteContainerImmutabilityPolicy deletes an unlocked immutability policy
+func (c *Client) DeleteContainerImmutabilityPolicy(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, etag string) error {
+	client, err := c.blobContainersClient(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.DeleteImmutabilityPolicy(ctx, resourceGroupName, storageAccountName, containerName, etag, nil); err != nil {
+		return fmt.Errorf("failed to delete immutability policy: %w", err)
+	}
+	return nil
+}
+
+// LockContainerImmutabilityPolicy locks an immutability policy. A locked policy cannot be deleted or shortened.
+func (c *Client) LockContainerImmutabilityPolicy(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, etag string) error {
+	client, err := c.blobContainersClient(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.LockImmutabilityPolicy(ctx, resourceGroupName, storageAccountName, containerName, etag, nil); err != nil {
+		return fmt.Errorf("failed to lock immutability policy: %w", err)
+	}
+	return nil
+}
+
+// blobContainersClient creates the ARM blob containers client used for protection settings
+func (c *Client) blobContainersClient(subscriptionID string) (*armstorage.BlobContainersClient, error) {
+	if c.storageEndpoint != nil {
+		return nil, errRequiresARM
+	}
+
+	client, err := armstorage.NewBlobContainersClient(subscriptionID, c.credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob containers client: %w", err)
+	}
+	return client, nil
+}
+
+// ValidateContainerName checks a name against the Azure container naming rules
+func ValidateContainerName(name string) error {
+	if len(name) < 3 || len(name) > 63 {
+		return fmt.Errorf("container name must be between 3 and 63 characters long")
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '-':
+			if i == 0 || i == len(name)-1 {
+				return fmt.Errorf("container name must start and end with a letter or number")
+			}
+			if name[i-1] == '-' {
+				return fmt.Errorf("container name cannot contain consecutive hyphens")
+			}
+		default:
+			return fmt.Errorf("container name can only contain lowercase letters, numbers and hyphens")
+		}
+	}
+	return nil
+}
+
+// parsePublicAccessLevel converts a display access level to the SDK value; private access is nil
+func parsePublicAccessLevel(level string) (*container.PublicAccessType, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "", "private", "none":
+		return nil, nil
+	case "blob":
+		access := container.PublicAccessTypeBlob
+		return &access, nil
+	case "container":
+		access := container.PublicAccessTypeContainer
+		return &access, nil
+	default:
+		return nil, fmt.Errorf("unknown public access level %q (use Private, Blob or Container)", level)
+	}
+}
+
+// ParseMetadata parses "key=value" lines into a metadata map.
+// Blank lines are ignored; keys must be valid C# identifiers as required by the storage service.
+func ParseMetadata(text string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("line %d: expected key=value", i+1)
+		}
+		key := strings.TrimSpace(kv[0])
+		if !isValidMetadataKey(key) {
+			return nil, fmt.Errorf("line %d: invalid metadata key %q", i+1, key)
+		}
+		if _, exists := metadata[strings.ToLower(key)]; exists {
+			return nil, fmt.Errorf("line %d: duplicate metadata key %q", i+1, key)
+		}
+		metadata[strings.ToLower(key)] = strings.TrimSpace(kv[1])
+	}
+	return metadata, nil
+}
+
+// FormatMetadata formats metadata as sorted "key=value" lines, the inverse of ParseMetadata
+func FormatMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + "=" + metadata[key]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isValidMetadataKey reports whether a key is a valid C# identifier
+func isValidMetadataKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLegalHoldTags parses a comma or space separated list of legal hold tags
+func ParseLegalHoldTags(text string) ([]string, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one legal hold tag is required")
+	}
+
+	tags := make([]string, 0, len(fields))
+	for _, tag := range fields {
+		if len(tag) < 3 || len(tag) > 23 {
+			return nil, fmt.Errorf("legal hold tag %q must be between 3 and 23 characters", tag)
+		}
+		for _, r := range tag {
+			if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+				return nil, fmt.Errorf("legal hold tag %q must be alphanumeric", tag)
+			}
+		}
+		tags = append(tags, strings.ToLower(tag))
+	}
+	return tags, nil
+}
+
+// ParseStoredAccessPolicies parses one policy per line in the form "id permissions [start] [expiry]".
+// Dates use YYYY-MM-DD or RFC 3339; "-" leaves a date unset.
+func ParseStoredAccessPolicies(text string) ([]*models.StoredAccessPolicy, error) {
+	var policies []*models.StoredAccessPolicy
+	seen := make(map[string]bool)
+
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("line %d: expected \"id permissions [start] [expiry]\"", i+1)
+		}
+
+		id := fields[0]
+		if len(id) > 64 {
+			return nil, fmt.Errorf("line %d: policy id must be at most 64 characters", i+1)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("line %d: duplicate policy id %q", i+1, id)
+		}
+		seen[id] = true
+
+		permission, err := normalizeContainerPermissions(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		policy := &models.StoredAccessPolicy{ID: id, Permission: permission}
+		if len(fields) > 2 {
+			if policy.Start, err = parsePolicyTime(fields[2]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid start: %w", i+1, err)
+			}
+		}
+		if len(fields) > 3 {
+			if policy.Expiry, err = parsePolicyTime(fields[3]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid expiry: %w", i+1, err)
+			}
+		}
+		if policy.Start != nil && policy.Expiry != nil && !policy.Expiry.After(*policy.Start) {
+			return nil, fmt.Errorf("line %d: expiry must be after start", i+1)
+		}
+
+		policies = append(policies, policy)
+	}
+
+	if len(policies) > maxStoredAccessPolicies {
+		return nil, fmt.Errorf("a container can have at most %d stored access policies", maxStoredAccessPolicies)
+	}
+	return policies, nil
+}
+
+// FormatStoredAccessPolicies formats policies in the line format accepted by ParseStoredAccessPolicies
+func FormatStoredAccessPolicies(policies []*models.StoredAccessPolicy) string {
+	lines := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		start, expiry := "-", "-"
+		if policy.Start != nil {
+			start = policy.Start.UTC().Format(time.RFC3339)
+		}
+		if policy.Expiry != nil {
+			expiry = policy.Expiry.UTC().Format(time.RFC3339)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s %s", policy.ID, policy.Permission, start, expiry))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// normalizeContainerPermissions validates container permission letters and returns them in canonical order
+func normalizeContainerPermissions(permissions string) (string, error) {
+	const canonical = "racwdxyltfmeopi"
+	for _, r := range permissions {
+		if !strings.ContainsRune(canonical, r) {
+			return "", fmt.Errorf("invalid permission %q (allowed: %s)", r, canonical)
+		}
+	}
+
+	var normalized strings.Builder
+	for _, r := range canonical {
+		if strings.ContainsRune(permissions, r) {
+			normalized.WriteRune(r)
+		}
+	}
+	return normalized.String(), nil
+}
+
+// parsePolicyTime parses a policy date; "-" means unset
+func parsePolicyTime(value string) (*time.Time, error) {
+	if value == "-" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	return &t, nil
+}
+
+// toMetadataPointers converts metadata to the pointer map used by the SDK
+func toMetadataPointers(metadata map[string]string) map[string]*string {
+	if metadata == nil {
+		return nil
+	}
+	result := make(map[string]*string, len(metadata))
+	for k, v := range metadata {
+		value := v
+		result[k] = &value
+	}
+	return result
+}
+
+// toStringPointers converts a string slice to the pointer slice used by the SDK
+func toStringPointers(values []string) []*string {
+	result := make([]*string, len(values))
+	for i := range values {
+		value := values[i]
+		result[i] = &value
+	}
+	return result
+}
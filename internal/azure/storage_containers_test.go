@@ -0,0 +1,212 @@
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContainerName(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError bool
+	}{
+		{name: "Valid name", input: "my-container-01"},
+		{name: "Minimum length", input: "abc"},
+		{name: "Too short", input: "ab", expectError: true},
+		{name: "Too long", input: "a123456789012345678901234567890123456789012345678901234567890123", expectError: true},
+		{name: "Uppercase letters", input: "MyContainer", expectError: true},
+		{name: "Leading hyphen", input: "-container", expectError: true},
+		{name: "Trailing hyphen", input: "container-", expectError: true},
+		{name: "Consecutive hyphens", input: "my--container", expectError: true},
+		{name: "Invalid character", input: "my_container", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContainerName(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParsePublicAccessLevel(t *testing.T) {
+	blob := container.PublicAccessTypeBlob
+	full := container.PublicAccessTypeContainer
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    *container.PublicAccessType
+		expectError bool
+	}{
+		{name: "Private", input: "Private"},
+		{name: "Empty means private", input: ""},
+		{name: "Blob", input: "Blob", expected: &blob},
+		{name: "Container is case insensitive", input: "container", expected: &full},
+		{name: "Unknown level", input: "public", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := parsePublicAccessLevel(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, access)
+		})
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:     "Key value lines",
+			input:    "env=prod\n\nOwner = team-a\n",
+			expected: map[string]string{"env": "prod", "owner": "team-a"},
+		},
+		{
+			name:     "Value containing equals sign",
+			input:    "query=a=b",
+			expected: map[string]string{"query": "a=b"},
+		},
+		{
+			name:     "Empty input",
+			input:    "  \n",
+			expected: map[string]string{},
+		},
+		{name: "Missing equals sign", input: "env", expectError: true},
+		{name: "Key starting with digit", input: "1env=prod", expectError: true},
+		{name: "Key with hyphen", input: "my-key=value", expectError: true},
+		{name: "Duplicate key ignoring case", input: "env=a\nENV=b", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := ParseMetadata(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, metadata)
+		})
+	}
+}
+
+func TestFormatMetadataRoundTrip(t *testing.T) {
+	metadata := map[string]string{"owner": "team-a", "env": "prod"}
+
+	text := FormatMetadata(metadata)
+	assert.Equal(t, "env=prod\nowner=team-a", text)
+
+	parsed, err := ParseMetadata(text)
+	require.NoError(t, err)
+	assert.Equal(t, metadata, parsed)
+}
+
+func TestParseLegalHoldTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []string
+		expectError bool
+	}{
+		{name: "Comma separated", input: "Case123, audit2024", expected: []string{"case123", "audit2024"}},
+		{name: "Space separated", input: "abc def", expected: []string{"abc", "def"}},
+		{name: "Empty", input: " , ", expectError: true},
+		{name: "Too short", input: "ab", expectError: true},
+		{name: "Too long", input: "abcdefghijklmnopqrstuvwx", expectError: true},
+		{name: "Not alphanumeric", input: "case-1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := ParseLegalHoldTags(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+}
+
+func TestParseStoredAccessPolicies(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiry := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    []*models.StoredAccessPolicy
+		expectError bool
+	}{
+		{
+			name:  "Permissions are normalized",
+			input: "readers lr 2025-01-01 2025-12-31",
+			expected: []*models.StoredAccessPolicy{
+				{ID: "readers", Permission: "rl", Start: &start, Expiry: &expiry},
+			},
+		},
+		{
+			name:  "Dates are optional",
+			input: "writers wc\n\nreaders r - 2025-12-31T00:00:00Z",
+			expected: []*models.StoredAccessPolicy{
+				{ID: "writers", Permission: "cw"},
+				{ID: "readers", Permission: "r", Expiry: &expiry},
+			},
+		},
+		{name: "Missing permissions", input: "readers", expectError: true},
+		{name: "Invalid permission", input: "readers rz", expectError: true},
+		{name: "Invalid date", input: "readers r 01/01/2025", expectError: true},
+		{name: "Expiry before start", input: "readers r 2025-12-31 2025-01-01", expectError: true},
+		{name: "Duplicate id", input: "a r\na w", expectError: true},
+		{name: "Too many policies", input: "a r\nb r\nc r\nd r\ne r\nf r", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParseStoredAccessPolicies(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, policies)
+		})
+	}
+}
+
+func TestFormatStoredAccessPoliciesRoundTrip(t *testing.T) {
+	expiry := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	policies := []*models.StoredAccessPolicy{
+		{ID: "readers", Permission: "rl", Expiry: &expiry},
+		{ID: "writers", Permission: "cw"},
+	}
+
+	text := FormatStoredAccessPolicies(policies)
+	assert.Equal(t, "readers rl - 2025-12-31T00:00:00Z\nwriters cw - -", text)
+
+	parsed, err := ParseStoredAccessPolicies(text)
+	require.NoError(t, err)
+	assert.Equal(t, policies, parsed)
+}
@@ -0,0 +1,333 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+)
+
+const (
+	// copyPollInterval is how often pending server-side copies are checked
+	copyPollInterval = 2 * time.Second
+	// copySourceSASExpiry bounds how long the read-only source URL handed to the destination stays valid
+	copySourceSASExpiry = 24 * time.Hour
+	// copyBlockSize is the size of the blocks staged from the source URL for copies within an account;
+	// with the limit of 50,000 blocks per blob it covers blobs of up to about 4.7 TiB
+	copyBlockSize = 100 * 1024 * 1024
+)
+
+// copyJob tracks one blob of a copy or move
+type copyJob struct {
+	sourceName string
+	destName   string
+	size       int64
+	md5        []byte
+}
+
+// ListBlobNames returns the names of all blobs under a prefix, recursively
+func (c *Client) ListBlobNames(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, prefix string) ([]string, error) {
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &azblob.ListBlobsFlatOptions{}
+	if prefix != "" {
+		options.Prefix = &prefix
+	}
+
+	var names []string
+	pager := client.NewListBlobsFlatPager(containerName, options)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page: %w", err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name != nil {
+				names = append(names, *item.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// FindStorageAccount returns the subscription and resource group of a storage account. Account names are
+// globally unique, so every subscription the caller can read is searched with Resource Graph.
+func (c *Client) FindStorageAccount(ctx context.Context, storageAccountName string) (string, string, error) {
+	query := fmt.Sprintf("Resources | where type =~ 'Microsoft.Storage/storageAccounts' and name =~ %s | project id", kqlString(storageAccountName))
+	objects, err := c.queryResourceGraphObjects(ctx, query, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find storage account %q: %w", storageAccountName, err)
+	}
+	for _, object := range objects {
+		id, _ := object["id"].(string)
+		subscriptionID := extractSubscriptionFromID(id)
+		resourceGroup := extractResourceGroupFromID(id)
+		if subscriptionID != "" && resourceGroup != "" {
+			return subscriptionID, resourceGroup, nil
+		}
+	}
+	return "", "", fmt.Errorf("storage account %q not found in any accessible subscription", storageAccountName)
+}
+
+// CopyBlobs copies blobs server-side from one location to another and waits for pending copies to finish.
+// Block blobs copied within an account are staged block by block from the source URL, which completes
+// with each request; other copies are started asynchronously with Copy Blob and polled. Blob names are
+// placed under destination.Path relative to source.Path, so copying "a/b/c.txt" from source path "a/"
+// to destination path "x/" creates "x/b/c.txt". When move is set, each source blob is deleted once its
+// copy has succeeded and the destination length matches the source, and for Copy Blob also its MD5.
+// onProgress is called after every state change; the final progress is also returned.
+func (c *Client) CopyBlobs(ctx context.Context, source models.BlobLocation, names []string, destination models.BlobLocation, move bool, onProgress func(models.CopyProgress)) (models.CopyProgress, error) {
+	progress := models.CopyProgress{Total: len(names)}
+	report := func() {
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+	fail := func(name string, err error) {
+		progress.Failed++
+		progress.Errors = append(progress.Errors, fmt.Sprintf("%s: %v", name, err))
+		report()
+	}
+
+	sourceClient, err := c.newBlobClient(ctx, source.SubscriptionID, source.ResourceGroup, source.StorageAccount)
+	if err != nil {
+		return progress, err
+	}
+	destClient, err := c.newBlobClient(ctx, destination.SubscriptionID, destination.ResourceGroup, destination.StorageAccount)
+	if err != nil {
+		return progress, err
+	}
+	sourceContainer := sourceClient.ServiceClient().NewContainerClient(source.Container)
+	destContainer := destClient.ServiceClient().NewContainerClient(destination.Container)
+	sameAccount := strings.EqualFold(source.StorageAccount, destination.StorageAccount)
+	sameContainer := sameAccount && source.Container == destination.Container
+
+	// Start all copies; the service runs them in parallel
+	var pending []*copyJob
+	for _, name := range names {
+		job := &copyJob{
+			sourceName: name,
+			destName:   destinationBlobName(name, source.Path, destination.Path),
+		}
+		if sameContainer && job.sourceName == job.destName {
+			fail(name, fmt.Errorf("source and destination are the same"))
+			continue
+		}
+
+		sourceBlob := sourceContainer.NewBlobClient(name)
+		props, err := sourceBlob.GetProperties(ctx, nil)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		if props.ContentLength != nil {
+			job.size = *props.ContentLength
+		}
+		job.md5 = props.ContentMD5
+		progress.BytesTotal += job.size
+
+		if sameAccount && props.BlobType != nil && *props.BlobType == blob.BlobTypeBlockBlob {
+			// The copy has finished when this returns; it has no copy status, so the first check verifies it.
+			// Its MD5 is the one set from the source, so only the length is compared.
+			if err := copyBlockBlobFromURL(ctx, destContainer.NewBlockBlobClient(job.destName), sourceCopyURL(sourceBlob), props); err != nil {
+				fail(name, err)
+				continue
+			}
+			job.md5 = nil
+		} else if _, err := destContainer.NewBlobClient(job.destName).StartCopyFromURL(ctx, sourceCopyURL(sourceBlob), nil); err != nil {
+			fail(name, err)
+			continue
+		}
+		pending = append(pending, job)
+		progress.Pending = len(pending)
+		report()
+	}
+
+	// Poll until every copy has left the pending state
+	for len(pending) > 0 {
+		var stillPending []*copyJob
+		var bytesCopied int64
+		for _, job := range pending {
+			props, err := destContainer.NewBlobClient(job.destName).GetProperties(ctx, nil)
+			if err != nil {
+				fail(job.sourceName, err)
+				continue
+			}
+
+			status := blob.CopyStatusTypeSuccess
+			if props.CopyStatus != nil {
+				status = *props.CopyStatus
+			}
+			switch status {
+			case blob.CopyStatusTypePending:
+				if props.CopyProgress != nil {
+					copied, _ := parseCopyProgress(*props.CopyProgress)
+					bytesCopied += copied
+				}
+				stillPending = append(stillPending, job)
+				continue
+			case blob.CopyStatusTypeSuccess:
+			default:
+				description := string(status)
+				if props.CopyStatusDescription != nil {
+					description += ": " + *props.CopyStatusDescription
+				}
+				fail(job.sourceName, fmt.Errorf("copy %s", description))
+				continue
+			}
+
+			progress.BytesCopied += job.size
+			var destSize int64
+			if props.ContentLength != nil {
+				destSize = *props.ContentLength
+			}
+			if err := verifyCopy(job.size, destSize, job.md5, props.ContentMD5); err != nil {
+				fail(job.sourceName, err)
+				continue
+			}
+			progress.Succeeded++
+
+			if move {
+				if _, err := sourceContainer.NewBlobClient(job.sourceName).Delete(ctx, nil); err != nil {
+					progress.Errors = append(progress.Errors, fmt.Sprintf("%s: copied but not deleted: %v", job.sourceName, err))
+				} else {
+					progress.Deleted++
+				}
+			}
+		}
+
+		pending = stillPending
+		progress.Pending = len(pending)
+		if len(pending) == 0 {
+			report()
+			break
+		}
+		// Report in-flight bytes without adding them to the running total, which only counts finished copies
+		if onProgress != nil {
+			inFlight := progress
+			inFlight.BytesCopied += bytesCopied
+			onProgress(inFlight)
+		}
+
+		select {
+		case <-ctx.Done():
+			return progress, fmt.Errorf("stopped waiting for %d pending copies: %w", len(pending), ctx.Err())
+		case <-time.After(copyPollInterval):
+		}
+	}
+
+	if progress.Failed > 0 {
+		return progress, fmt.Errorf("%d of %d blobs failed", progress.Failed, progress.Total)
+	}
+	return progress, nil
+}
+
+// copyBlockBlobFromURL copies a block blob synchronously by staging its ranges as blocks read from the
+// source URL and committing them with the properties and metadata of the source. Put Block From URL
+// does not carry the source MD5 over, so it is set from the source properties and only the length is
+// checked independently.
+func copyBlockBlobFromURL(ctx context.Context, dest *blockblob.Client, sourceURL string, props blob.GetPropertiesResponse) error {
+	var size int64
+	if props.ContentLength != nil {
+		size = *props.ContentLength
+	}
+
+	var blockIDs []string
+	for i, r := range copyBlockRanges(size, copyBlockSize) {
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", i)))
+		if _, err := dest.StageBlockFromURL(ctx, blockID, sourceURL, &blockblob.StageBlockFromURLOptions{Range: r}); err != nil {
+			return fmt.Errorf("failed to stage block %d: %w", i, err)
+		}
+		blockIDs = append(blockIDs, blockID)
+	}
+
+	options := &blockblob.CommitBlockListOptions{
+		Metadata: props.Metadata,
+		HTTPHeaders: &blob.HTTPHeaders{
+			BlobContentType:        props.ContentType,
+			BlobContentEncoding:    props.ContentEncoding,
+			BlobContentLanguage:    props.ContentLanguage,
+			BlobContentDisposition: props.ContentDisposition,
+			BlobCacheControl:       props.CacheControl,
+			BlobContentMD5:         props.ContentMD5,
+		},
+	}
+	if _, err := dest.CommitBlockList(ctx, blockIDs, options); err != nil {
+		return fmt.Errorf("failed to commit blocks: %w", err)
+	}
+	return nil
+}
+
+// copyBlockRanges splits a blob of the given size into consecutive ranges of at most blockSize bytes
+func copyBlockRanges(size, blockSize int64) []blob.HTTPRange {
+	var ranges []blob.HTTPRange
+	for offset := int64(0); offset < size; offset += blockSize {
+		ranges = append(ranges, blob.HTTPRange{Offset: offset, Count: min(blockSize, size-offset)})
+	}
+	return ranges
+}
+
+// sourceCopyURL returns a URL the destination service can read the source blob from.
+// With a shared key a short-lived read-only SAS is generated; otherwise the client URL
+// already carries its SAS token (or the blob is public).
+func sourceCopyURL(sourceBlob *blob.Client) string {
+	sasURL, err := sourceBlob.GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(copySourceSASExpiry), nil)
+	if err != nil {
+		return sourceBlob.URL()
+	}
+	return sasURL
+}
+
+// destinationBlobName maps a source blob name to its destination name by replacing the source prefix
+func destinationBlobName(name, sourcePrefix, destinationPrefix string) string {
+	relative := strings.TrimPrefix(name, sourcePrefix)
+	return NormalizeBlobPrefix(destinationPrefix) + relative
+}
+
+// NormalizeBlobPrefix turns a user-entered folder path into a blob prefix: no leading slash,
+// a single trailing slash, and empty for the container root
+func NormalizeBlobPrefix(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return ""
+	}
+	return path + "/"
+}
+
+// parseCopyProgress parses the x-ms-copy-progress value "copied/total"
+func parseCopyProgress(value string) (int64, int64) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	copied, err1 := strconv.ParseInt(parts[0], 10, 64)
+	total, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0
+	}
+	return copied, total
+}
+
+// verifyCopy checks that a completed copy matches its source in length and, when both are known, MD5
+func verifyCopy(sourceSize, destSize int64, sourceMD5, destMD5 []byte) error {
+	if sourceSize != destSize {
+		return fmt.Errorf("verification failed: destination has %d bytes, source has %d", destSize, sourceSize)
+	}
+	if len(sourceMD5) > 0 && len(destMD5) > 0 && !bytes.Equal(sourceMD5, destMD5) {
+		return fmt.Errorf("verification failed: content MD5 differs")
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBlobPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Empty is container root", input: "", expected: ""},
+		{name: "Slash is container root", input: "/", expected: ""},
+		{name: "Adds trailing slash", input: "backup/2024", expected: "backup/2024/"},
+		{name: "Removes leading slash", input: "/backup/", expected: "backup/"},
+		{name: "Trims whitespace", input: "  logs ", expected: "logs/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeBlobPrefix(tt.input))
+		})
+	}
+}
+
+func TestDestinationBlobName(t *testing.T) {
+	tests := []struct {
+		name              string
+		blobName          string
+		sourcePrefix      string
+		destinationPrefix string
+		expected          string
+	}{
+		{name: "Root to root", blobName: "file.txt", expected: "file.txt"},
+		{name: "Root to folder", blobName: "file.txt", destinationPrefix: "archive", expected: "archive/file.txt"},
+		{name: "Keeps nested structure", blobName: "data/2024/jan/a.csv", sourcePrefix: "data/", destinationPrefix: "backup/", expected: "backup/2024/jan/a.csv"},
+		{name: "Folder to root", blobName: "data/a.csv", sourcePrefix: "data/", expected: "a.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, destinationBlobName(tt.blobName, tt.sourcePrefix, tt.destinationPrefix))
+		})
+	}
+}
+
+func TestParseCopyProgress(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedCopied int64
+		expectedTotal  int64
+	}{
+		{name: "Valid progress", input: "1024/4096", expectedCopied: 1024, expectedTotal: 4096},
+		{name: "Missing separator", input: "1024", expectedCopied: 0, expectedTotal: 0},
+		{name: "Not a number", input: "abc/4096", expectedCopied: 0, expectedTotal: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied, total := parseCopyProgress(tt.input)
+			assert.Equal(t, tt.expectedCopied, copied)
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func TestVerifyCopy(t *testing.T) {
+	tests := []struct {
+		name        string
+		sourceSize  int64
+		destSize    int64
+		sourceMD5   []byte
+		destMD5     []byte
+		expectError bool
+	}{
+		{name: "Matching size without MD5", sourceSize: 10, destSize: 10},
+		{name: "Matching size and MD5", sourceSize: 10, destSize: 10, sourceMD5: []byte{1, 2}, destMD5: []byte{1, 2}},
+		{name: "MD5 only on one side", sourceSize: 10, destSize: 10, sourceMD5: []byte{1, 2}},
+		{name: "Size mismatch", sourceSize: 10, destSize: 9, expectError: true},
+		{name: "MD5 mismatch", sourceSize: 10, destSize: 10, sourceMD5: []byte{1, 2}, destMD5: []byte{3, 4}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyCopy(tt.sourceSize, tt.destSize, tt.sourceMD5, tt.destMD5)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCopyBlockRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int64
+		blockSize int64
+		expected  []blob.HTTPRange
+	}{
+		{name: "Empty blob has no blocks", size: 0, blockSize: 10, expected: nil},
+		{name: "Smaller than a block", size: 4, blockSize: 10, expected: []blob.HTTPRange{{Offset: 0, Count: 4}}},
+		{name: "Exact blocks", size: 20, blockSize: 10, expected: []blob.HTTPRange{{Offset: 0, Count: 10}, {Offset: 10, Count: 10}}},
+		{name: "Shorter last block", size: 25, blockSize: 10, expected: []blob.HTTPRange{{Offset: 0, Count: 10}, {Offset: 10, Count: 10}, {Offset: 20, Count: 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, copyBlockRanges(tt.size, tt.blockSize))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package azure
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+const (
+	// Well-known Azurite development storage account
+	// See https://learn.microsoft.com/azure/storage/common/storage-use-azurite#well-known-storage-account-and-key
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	azuriteBlobURL     = "http://127.0.0.1:10000/devstoreaccount1/"
+)
+
+// NewStorageEndpointClient creates a client that talks to a single blob endpoint directly,
+// without Azure credentials or Resource Manager access
+func NewStorageEndpointClient(endpoint *models.StorageEndpoint) *Client {
+	return &Client{
+		storageEndpoint: endpoint,
+	}
+}
+
+// GetStorageEndpoint returns the direct storage endpoint, or nil when the client uses ARM
+func (c *Client) GetStorageEndpoint() *models.StorageEndpoint {
+	return c.storageEndpoint
+}
+
+// ParseStorageConnectionString parses an Azure Storage connection string into a storage endpoint.
+// Supports account key, SAS and "UseDevelopmentStorage=true" (Azurite) connection strings.
+func ParseStorageConnectionString(connectionString string) (*models.StorageEndpoint, error) {
+	values := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimSpace(connectionString), ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid connection string segment %q", part)
+		}
+		values[strings.ToLower(kv[0])] = kv[1]
+	}
+
+	if strings.EqualFold(values["usedevelopmentstorage"], "true") {
+		serviceURL := azuriteBlobURL
+		if proxy := values["developmentstorageproxyuri"]; proxy != "" {
+			serviceURL = strings.TrimSuffix(proxy, "/") + ":10000/" + azuriteAccountName + "/"
+		}
+		return NewStorageEndpoint(serviceURL, azuriteAccountName, azuriteAccountKey, "")
+	}
+
+	serviceURL := values["blobendpoint"]
+	accountName := values["accountname"]
+	if serviceURL == "" {
+		if accountName == "" {
+			return nil, fmt.Errorf("connection string needs either AccountName or BlobEndpoint")
+		}
+		protocol := values["defaultendpointsprotocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := values["endpointsuffix"]
+		if suffix == "" {
+			suffix = "core.windows.net"
+		}
+		serviceURL = fmt.Sprintf("%s://%s.blob.%s/", protocol, accountName, suffix)
+	}
+
+	accountKey := values["accountkey"]
+	sasToken := values["sharedaccesssignature"]
+	if accountKey == "" && sasToken == "" {
+		return nil, fmt.Errorf("connection string needs either AccountKey or SharedAccessSignature")
+	}
+
+	return NewStorageEndpoint(serviceURL, accountName, accountKey, sasToken)
+}
+
+// NewStorageEndpoint builds a storage endpoint from a blob service URL.
+// The account name is inferred from the URL when not given, either from the host
+// (account.blob.core.windows.net) or from the first path segment (emulator-style URLs).
+// A SAS token embedded in the URL query is used when sasToken is empty.
+func NewStorageEndpoint(serviceURL, accountName, accountKey, sasToken string) (*models.StorageEndpoint, error) {
+	parsed, err := url.Parse(strings.TrimSpace(serviceURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage endpoint URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("storage endpoint URL must use http or https: %s", serviceURL)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("storage endpoint URL has no host: %s", serviceURL)
+	}
+
+	sasToken = strings.TrimPrefix(strings.TrimSpace(sasToken), "?")
+	if sasToken == "" {
+		sasToken = parsed.RawQuery
+	}
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+
+	if accountName == "" {
+		accountName = accountNameFromServiceURL(parsed)
+	}
+	if accountName == "" {
+		return nil, fmt.Errorf("could not determine storage account name from %s", serviceURL)
+	}
+
+	return &models.StorageEndpoint{
+		ServiceURL:  parsed.String(),
+		AccountName: accountName,
+		AccountKey:  accountKey,
+		SASToken:    sasToken,
+	}, nil
+}
+
+// accountNameFromServiceURL infers the storage account name from a blob service URL
+func accountNameFromServiceURL(serviceURL *url.URL) string {
+	host := serviceURL.Hostname()
+	if idx := strings.Index(host, ".blob."); idx > 0 {
+		return host[:idx]
+	}
+
+	// Emulators use path-style URLs: http://127.0.0.1:10000/devstoreaccount1/
+	path := strings.Trim(serviceURL.Path, "/")
+	if path != "" {
+		return strings.Split(path, "/")[0]
+	}
+	return ""
+}
+
+// newEndpointBlobClient creates a blob client for a direct storage endpoint
+func newEndpointBlobClient(endpoint *models.StorageEndpoint) (*azblob.Client, error) {
+	if endpoint.AccountKey != "" {
+		credential, err := azblob.NewSharedKeyCredential(endpoint.AccountName, endpoint.AccountKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create credential: %w", err)
+		}
+		return azblob.NewClientWithSharedKeyCredential(endpoint.ServiceURL, credential, nil)
+	}
+
+	serviceURL := endpoint.ServiceURL
+	if endpoint.SASToken != "" {
+		serviceURL += "?" + endpoint.SASToken
+	}
+	return azblob.NewClientWithNoCredential(serviceURL, nil)
+}
@@ -0,0 +1,163 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStorageConnectionString(t *testing.T) {
+	tests := []struct {
+		name             string
+		connectionString string
+		expectedURL      string
+		expectedAccount  string
+		expectedKey      string
+		expectedSAS      string
+		expectError      bool
+	}{
+		{
+			name:             "Azurite development storage",
+			connectionString: "UseDevelopmentStorage=true",
+			expectedURL:      "http://127.0.0.1:10000/devstoreaccount1/",
+			expectedAccount:  "devstoreaccount1",
+			expectedKey:      azuriteAccountKey,
+		},
+		{
+			name:             "Azurite with proxy URI",
+			connectionString: "UseDevelopmentStorage=true;DevelopmentStorageProxyUri=http://azurite",
+			expectedURL:      "http://azurite:10000/devstoreaccount1/",
+			expectedAccount:  "devstoreaccount1",
+			expectedKey:      azuriteAccountKey,
+		},
+		{
+			name:             "Account name and key",
+			connectionString: "DefaultEndpointsProtocol=https;AccountName=mystorage;AccountKey=c2VjcmV0;EndpointSuffix=core.windows.net",
+			expectedURL:      "https://mystorage.blob.core.windows.net/",
+			expectedAccount:  "mystorage",
+			expectedKey:      "c2VjcmV0",
+		},
+		{
+			name:             "Explicit Azurite blob endpoint",
+			connectionString: "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=a2V5;BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;",
+			expectedURL:      "http://127.0.0.1:10000/devstoreaccount1/",
+			expectedAccount:  "devstoreaccount1",
+			expectedKey:      "a2V5",
+		},
+		{
+			name:             "SAS connection string",
+			connectionString: "BlobEndpoint=https://mystorage.blob.core.windows.net/;SharedAccessSignature=sv=2022-11-02&sig=abc%3D",
+			expectedURL:      "https://mystorage.blob.core.windows.net/",
+			expectedAccount:  "mystorage",
+			expectedSAS:      "sv=2022-11-02&sig=abc%3D",
+		},
+		{
+			name:             "Missing credentials",
+			connectionString: "AccountName=mystorage",
+			expectError:      true,
+		},
+		{
+			name:             "Missing account and endpoint",
+			connectionString: "AccountKey=a2V5",
+			expectError:      true,
+		},
+		{
+			name:             "Malformed segment",
+			connectionString: "AccountName",
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := ParseStorageConnectionString(tt.connectionString)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedURL, endpoint.ServiceURL)
+			assert.Equal(t, tt.expectedAccount, endpoint.AccountName)
+			assert.Equal(t, tt.expectedKey, endpoint.AccountKey)
+			assert.Equal(t, tt.expectedSAS, endpoint.SASToken)
+		})
+	}
+}
+
+func TestNewStorageEndpoint(t *testing.T) {
+	tests := []struct {
+		name            string
+		serviceURL      string
+		accountName     string
+		sasToken        string
+		expectedURL     string
+		expectedAccount string
+		expectedSAS     string
+		expectError     bool
+	}{
+		{
+			name:            "Public cloud URL infers account from host",
+			serviceURL:      "https://mystorage.blob.core.windows.net",
+			expectedURL:     "https://mystorage.blob.core.windows.net/",
+			expectedAccount: "mystorage",
+		},
+		{
+			name:            "Emulator URL infers account from path",
+			serviceURL:      "http://localhost:10000/devstoreaccount1",
+			expectedURL:     "http://localhost:10000/devstoreaccount1/",
+			expectedAccount: "devstoreaccount1",
+		},
+		{
+			name:            "SAS token taken from URL query",
+			serviceURL:      "https://mystorage.blob.core.windows.net/?sv=2022-11-02&sig=abc",
+			expectedURL:     "https://mystorage.blob.core.windows.net/",
+			expectedAccount: "mystorage",
+			expectedSAS:     "sv=2022-11-02&sig=abc",
+		},
+		{
+			name:            "Explicit SAS token wins and loses leading question mark",
+			serviceURL:      "https://mystorage.blob.core.windows.net/?sv=old",
+			sasToken:        "?sv=new",
+			expectedURL:     "https://mystorage.blob.core.windows.net/",
+			expectedAccount: "mystorage",
+			expectedSAS:     "sv=new",
+		},
+		{
+			name:            "Explicit account name",
+			serviceURL:      "https://blob.example.com/",
+			accountName:     "custom",
+			expectedURL:     "https://blob.example.com/",
+			expectedAccount: "custom",
+		},
+		{
+			name:        "Account name cannot be inferred",
+			serviceURL:  "https://blob.example.com/",
+			expectError: true,
+		},
+		{
+			name:        "Unsupported scheme",
+			serviceURL:  "ftp://mystorage.blob.core.windows.net/",
+			expectError: true,
+		},
+		{
+			name:        "Missing host",
+			serviceURL:  "devstoreaccount1",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := NewStorageEndpoint(tt.serviceURL, tt.accountName, "", tt.sasToken)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedURL, endpoint.ServiceURL)
+			assert.Equal(t, tt.expectedAccount, endpoint.AccountName)
+			assert.Equal(t, tt.expectedSAS, endpoint.SASToken)
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// sizeUnits maps size suffixes to their multiplier; sizes use binary units like the blob list
+var sizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+	"T":   1 << 40,
+	"TB":  1 << 40,
+	"TIB": 1 << 40,
+}
+
+// tagOperators are the comparison operators supported by blob index tag queries, longest first
+var tagOperators = []string{">=", "<=", "=", ">", "<"}
+
+// blobMatcher tests blobs against the name, size and date conditions of a search query
+type blobMatcher struct {
+	query    models.BlobSearchQuery
+	regex    *regexp.Regexp
+	baseName bool // Glob without "/" is matched against the last path segment only
+}
+
+// newBlobMatcher validates a search query and prepares its name pattern
+func newBlobMatcher(query models.BlobSearchQuery) (*blobMatcher, error) {
+	matcher := &blobMatcher{query: query}
+	if query.Pattern == "" {
+		return matcher, nil
+	}
+
+	if query.UseRegex {
+		regex, err := regexp.Compile(query.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		matcher.regex = regex
+		return matcher, nil
+	}
+
+	if _, err := path.Match(query.Pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", query.Pattern, err)
+	}
+	matcher.baseName = !strings.Contains(query.Pattern, "/")
+	return matcher, nil
+}
+
+// matchName reports whether a full blob name matches the prefix and pattern
+func (m *blobMatcher) matchName(name string) bool {
+	if !strings.HasPrefix(name, m.query.Prefix) {
+		return false
+	}
+	switch {
+	case m.query.Pattern == "":
+		return true
+	case m.regex != nil:
+		return m.regex.MatchString(name)
+	case m.baseName:
+		matched, _ := path.Match(m.query.Pattern, path.Base(name))
+		return matched
+	default:
+		matched, _ := path.Match(m.query.Pattern, name)
+		return matched
+	}
+}
+
+// matchProperties reports whether a blob's size and last modified time are within the query bounds
+func (m *blobMatcher) matchProperties(blob *models.Blob) bool {
+	q := m.query
+	if q.MinSize != nil && blob.Size < *q.MinSize {
+		return false
+	}
+	if q.MaxSize != nil && blob.Size > *q.MaxSize {
+		return false
+	}
+	if q.ModifiedAfter != nil && blob.LastModified.Before(*q.ModifiedAfter) {
+		return false
+	}
+	if q.ModifiedBefore != nil && blob.LastModified.After(*q.ModifiedBefore) {
+		return false
+	}
+	return true
+}
+
+// SearchBlobs scans a whole container for blobs matching a query. Matches are streamed to onResults
+// after every page together with the number of blobs scanned so far, so large containers show results early.
+// Name, size and date conditions are applied while listing. A tag filter uses the Find Blobs by Tags API
+// to narrow the candidates first; properties are then fetched for each candidate.
+func (c *Client) SearchBlobs(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName string, query models.BlobSearchQuery, onResults func(results []*models.Blob, scanned int)) error {
+	matcher, err := newBlobMatcher(query)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.newBlobClient(ctx, subscriptionID, resourceGroupName, storageAccountName)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(query.TagFilter) != "" {
+		where, err := BuildTagFilter(query.TagFilter)
+		if err != nil {
+			return err
+		}
+		return searchBlobsByTags(ctx, client.ServiceClient().NewContainerClient(containerName), where, matcher, onResults)
+	}
+
+	options := &azblob.ListBlobsFlatOptions{}
+	if query.Prefix != "" {
+		options.Prefix = &query.Prefix
+	}
+
+	scanned := 0
+	pager := client.NewListBlobsFlatPager(containerName, options)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get next page: %w", err)
+		}
+
+		var results []*models.Blob
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+			scanned++
+			if !matcher.matchName(*item.Name) {
+				continue
+			}
+
+			blob := &models.Blob{
+				Name:        *item.Name,
+				DisplayName: *item.Name,
+				Metadata:    make(map[string]string),
+			}
+			if item.Properties != nil {
+				if item.Properties.ContentLength != nil {
+					blob.Size = *item.Properties.ContentLength
+				}
+				if item.Properties.ContentType != nil {
+					blob.ContentType = *item.Properties.ContentType
+				}
+				if item.Properties.LastModified != nil {
+					blob.LastModified = *item.Properties.LastModified
+				}
+				if item.Properties.ETag != nil {
+					blob.ETag = string(*item.Properties.ETag)
+				}
+				if item.Properties.AccessTier != nil {
+					blob.AccessTier = string(*item.Properties.AccessTier)
+				}
+				if item.Properties.ArchiveStatus != nil {
+					blob.ArchiveStatus = string(*item.Properties.ArchiveStatus)
+				}
+			}
+			if matcher.matchProperties(blob) {
+				results = append(results, blob)
+			}
+		}
+		onResults(results, scanned)
+	}
+	return nil
+}
+
+// searchBlobsByTags finds blobs with the Find Blobs by Tags API and applies the remaining conditions
+func searchBlobsByTags(ctx context.Context, containerClient *container.Client, where string, matcher *blobMatcher, onResults func([]*models.Blob, int)) error {
+	scanned := 0
+	options := &container.FilterBlobsOptions{}
+	for {
+		resp, err := containerClient.FilterBlobs(ctx, where, options)
+		if err != nil {
+			return fmt.Errorf("failed to find blobs by tags: %w", err)
+		}
+
+		var results []*models.Blob
+		for _, item := range resp.Blobs {
+			if item.Name == nil {
+				continue
+			}
+			scanned++
+			if !matcher.matchName(*item.Name) {
+				continue
+			}
+
+			props, err := containerClient.NewBlobClient(*item.Name).GetProperties(ctx, nil)
+			if err != nil {
+				// The blob may have been deleted since the tag index was queried
+				continue
+			}
+			blob := &models.Blob{
+				Name:        *item.Name,
+				DisplayName: *item.Name,
+				Metadata:    make(map[string]string),
+			}
+			if props.ContentLength != nil {
+				blob.Size = *props.ContentLength
+			}
+			if props.ContentType != nil {
+				blob.ContentType = *props.ContentType
+			}
+			if props.LastModified != nil {
+				blob.LastModified = *props.LastModified
+			}
+			if props.ETag != nil {
+				blob.ETag = string(*props.ETag)
+			}
+			if props.AccessTier != nil {
+				blob.AccessTier = *props.AccessTier
+			}
+			if props.ArchiveStatus != nil {
+				blob.ArchiveStatus = *props.ArchiveStatus
+			}
+			if matcher.matchProperties(blob) {
+				results = append(results, blob)
+			}
+		}
+		onResults(results, scanned)
+
+		if resp.NextMarker == nil || *resp.NextMarker == "" {
+			return nil
+		}
+		options.Marker = resp.NextMarker
+	}
+}
+
+// BuildTagFilter turns "key=value, key2>=value2" into a blob index tag expression such as
+// "key" = 'value' AND "key2" >= 'value2'. Input that already contains quotes is used as is.
+func BuildTagFilter(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if strings.ContainsAny(input, `'"`) {
+		return input, nil
+	}
+
+	var conditions []string
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// The first operator in the condition splits it; at the same position the longer
+		// operator wins because tagOperators lists it first, so ">=" is not read as ">"
+		operator := ""
+		index := -1
+		for _, op := range tagOperators {
+			if i := strings.Index(part, op); i >= 0 && (index < 0 || i < index) {
+				operator, index = op, i
+			}
+		}
+		if index < 0 {
+			return "", fmt.Errorf("invalid tag condition %q: expected key=value", part)
+		}
+
+		key := strings.TrimSpace(part[:index])
+		value := strings.TrimSpace(part[index+len(operator):])
+		if key == "" {
+			return "", fmt.Errorf("invalid tag condition %q: tag name is empty", part)
+		}
+		conditions = append(conditions, fmt.Sprintf(`"%s" %s '%s'`, key, operator, value))
+	}
+
+	if len(conditions) == 0 {
+		return "", fmt.Errorf("tag filter is empty")
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// ParseSize parses a size such as "512", "10KB", "1.5G" or "2 MiB" into bytes
+func ParseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end < 0 {
+		end = len(value)
+	}
+
+	number, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q: expected a number with an optional unit (B, KB, MB, GB, TB)", value)
+	}
+	multiplier, ok := sizeUnits[strings.TrimSpace(value[end:])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit in %q: use B, KB, MB, GB or TB", value)
+	}
+	return int64(number * float64(multiplier)), nil
+}
+
+// ParseSearchTime parses a date or date and time for search bounds, interpreted in local time
+// unless an RFC 3339 offset is given
+func ParseSearchTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339", value)
+}
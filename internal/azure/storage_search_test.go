@@ -0,0 +1,149 @@
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobMatcher_MatchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    models.BlobSearchQuery
+		blobName string
+		expected bool
+	}{
+		{name: "Empty pattern matches everything", query: models.BlobSearchQuery{}, blobName: "a/b/c.txt", expected: true},
+		{name: "Glob matches base name at any depth", query: models.BlobSearchQuery{Pattern: "*.csv"}, blobName: "data/2024/jan.csv", expected: true},
+		{name: "Glob does not match other extensions", query: models.BlobSearchQuery{Pattern: "*.csv"}, blobName: "data/2024/jan.json", expected: false},
+		{name: "Glob with slash matches full name", query: models.BlobSearchQuery{Pattern: "data/*/jan.csv"}, blobName: "data/2024/jan.csv", expected: true},
+		{name: "Glob with slash respects depth", query: models.BlobSearchQuery{Pattern: "data/*.csv"}, blobName: "data/2024/jan.csv", expected: false},
+		{name: "Regex matches anywhere in full name", query: models.BlobSearchQuery{Pattern: `2024/.*\.csv$`, UseRegex: true}, blobName: "data/2024/jan.csv", expected: true},
+		{name: "Regex no match", query: models.BlobSearchQuery{Pattern: `^logs/`, UseRegex: true}, blobName: "data/logs/a.txt", expected: false},
+		{name: "Prefix excludes other folders", query: models.BlobSearchQuery{Prefix: "logs/", Pattern: "*.txt"}, blobName: "data/a.txt", expected: false},
+		{name: "Prefix includes nested blobs", query: models.BlobSearchQuery{Prefix: "logs/", Pattern: "*.txt"}, blobName: "logs/2024/a.txt", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := newBlobMatcher(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, matcher.matchName(tt.blobName))
+		})
+	}
+}
+
+func TestNewBlobMatcher_InvalidPattern(t *testing.T) {
+	_, err := newBlobMatcher(models.BlobSearchQuery{Pattern: "[abc"})
+	assert.Error(t, err)
+
+	_, err = newBlobMatcher(models.BlobSearchQuery{Pattern: "(abc", UseRegex: true})
+	assert.Error(t, err)
+}
+
+func TestBlobMatcher_MatchProperties(t *testing.T) {
+	minSize := int64(100)
+	maxSize := int64(1000)
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	query := models.BlobSearchQuery{MinSize: &minSize, MaxSize: &maxSize, ModifiedAfter: &after, ModifiedBefore: &before}
+
+	tests := []struct {
+		name     string
+		blob     models.Blob
+		expected bool
+	}{
+		{name: "Within bounds", blob: models.Blob{Size: 500, LastModified: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, expected: true},
+		{name: "Bounds are inclusive", blob: models.Blob{Size: 1000, LastModified: after}, expected: true},
+		{name: "Too small", blob: models.Blob{Size: 99, LastModified: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, expected: false},
+		{name: "Too large", blob: models.Blob{Size: 1001, LastModified: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, expected: false},
+		{name: "Too old", blob: models.Blob{Size: 500, LastModified: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}, expected: false},
+		{name: "Too new", blob: models.Blob{Size: 500, LastModified: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}, expected: false},
+	}
+
+	matcher, err := newBlobMatcher(query)
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matcher.matchProperties(&tt.blob))
+		})
+	}
+}
+
+func TestBuildTagFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{name: "Single condition", input: "project=alpha", expected: `"project" = 'alpha'`},
+		{name: "Several conditions", input: "project = alpha, env=prod", expected: `"project" = 'alpha' AND "env" = 'prod'`},
+		{name: "Range operators", input: "date>=2024-01-01,date<2025-01-01", expected: `"date" >= '2024-01-01' AND "date" < '2025-01-01'`},
+		{name: "Raw expression is kept", input: `"project" = 'alpha'`, expected: `"project" = 'alpha'`},
+		{name: "Missing operator", input: "project", expectErr: true},
+		{name: "Missing key", input: "=alpha", expectErr: true},
+		{name: "Empty", input: " , ", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildTagFilter(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  int64
+		expectErr bool
+	}{
+		{name: "Bytes", input: "512", expected: 512},
+		{name: "Kilobytes", input: "10KB", expected: 10 * 1024},
+		{name: "Fractional gigabytes", input: "1.5G", expected: 1536 * 1024 * 1024},
+		{name: "Lowercase with space", input: "2 mib", expected: 2 * 1024 * 1024},
+		{name: "Unknown unit", input: "10XB", expectErr: true},
+		{name: "Not a number", input: "big", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSize(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParseSearchTime(t *testing.T) {
+	result, err := ParseSearchTime("2024-03-15")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local), result)
+
+	result, err = ParseSearchTime("2024-03-15 08:30")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 8, 30, 0, 0, time.Local), result)
+
+	result, err = ParseSearchTime("2024-03-15T08:30:00Z")
+	require.NoError(t, err)
+	assert.True(t, result.Equal(time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)))
+
+	_, err = ParseSearchTime("15/03/2024")
+	assert.Error(t, err)
+}
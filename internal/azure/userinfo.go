@@ -48,6 +48,16 @@ func (c *Client) GetUserInfo(ctx context.Context) (*models.UserInfo, error) {
 		userInfo.TenantID = tid
 	}
 
+	// Extract the object ID, and the application ID of a service principal
+	if oid, ok := claims["oid"].(string); ok {
+		userInfo.ObjectID = oid
+	}
+	if idtyp, ok := claims["idtyp"].(string); ok && idtyp == "app" {
+		if appID, ok := claims["appid"].(string); ok {
+			userInfo.AppID = appID
+		}
+	}
+
 	// Extract user name (prefer "name", fallback to "preferred_username")
 	if name, ok := claims["name"].(string); ok && name != "" {
 		userInfo.Name = name
@@ -0,0 +1,86 @@
+// Package bulk runs an action on several items, a few at a time, and reports the outcome of each item.
+package bulk
+
+import (
+	"context"
+	"sync"
+)
+
+// Concurrency is the number of items an action runs on at once, low enough to stay clear of Azure throttling
+const Concurrency = 4
+
+// Item is one item of a bulk action: the label it is reported with and the action itself
+type Item struct {
+	Label string
+	Run   func(ctx context.Context) error
+}
+
+// Result is the outcome of the action on one item
+type Result struct {
+	Label string
+	Error string // Empty when the item succeeded
+}
+
+// Progress reports the state of a bulk action
+type Progress struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Results   []Result // Outcome of every finished item, in the order of the items
+}
+
+// Run runs the items with at most concurrency of them at once. onProgress, which may be nil, is called
+// after each item with the counts so far; the results are only filled in the returned progress. Once
+// ctx is cancelled no further items are started, and the error of the context is returned with the
+// progress of the items that finished.
+func Run(ctx context.Context, items []Item, concurrency int, onProgress func(Progress)) (Progress, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	progress := Progress{Total: len(items)}
+	results := make([]*Result, len(items))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i, item := range items {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, item Item) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			err := item.Run(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result := &Result{Label: item.Label}
+			if err != nil {
+				result.Error = err.Error()
+				progress.Failed++
+			} else {
+				progress.Succeeded++
+			}
+			results[i] = result
+			if onProgress != nil {
+				onProgress(Progress{Total: progress.Total, Succeeded: progress.Succeeded, Failed: progress.Failed})
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result != nil {
+			progress.Results = append(progress.Results, *result)
+		}
+	}
+	return progress, ctx.Err()
+}
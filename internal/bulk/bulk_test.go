@@ -0,0 +1,110 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReportsEveryItemInOrder(t *testing.T) {
+	var items []Item
+	for i := 0; i < 10; i++ {
+		i := i
+		items = append(items, Item{
+			Label: fmt.Sprintf("item-%d", i),
+			Run: func(ctx context.Context) error {
+				// Later items finish first, so the results must be put back in item order
+				time.Sleep(time.Duration(10-i) * time.Millisecond)
+				if i%3 == 0 {
+					return fmt.Errorf("failed %d", i)
+				}
+				return nil
+			},
+		})
+	}
+
+	var calls int32
+	progress, err := Run(context.Background(), items, 3, func(p Progress) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, 10, p.Total)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&calls))
+	assert.Equal(t, 10, progress.Total)
+	assert.Equal(t, 6, progress.Succeeded)
+	assert.Equal(t, 4, progress.Failed)
+	require.Len(t, progress.Results, 10)
+	for i, result := range progress.Results {
+		assert.Equal(t, fmt.Sprintf("item-%d", i), result.Label)
+		if i%3 == 0 {
+			assert.Equal(t, fmt.Sprintf("failed %d", i), result.Error)
+		} else {
+			assert.Empty(t, result.Error)
+		}
+	}
+}
+
+func TestRunLimitsConcurrency(t *testing.T) {
+	var running, peak int32
+	var items []Item
+	for i := 0; i < 20; i++ {
+		items = append(items, Item{
+			Label: fmt.Sprintf("item-%d", i),
+			Run: func(ctx context.Context) error {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					seen := atomic.LoadInt32(&peak)
+					if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			},
+		})
+	}
+
+	progress, err := Run(context.Background(), items, 4, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 20, progress.Succeeded)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(4))
+}
+
+func TestRunStopsStartingItemsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var started int32
+	var items []Item
+	for i := 0; i < 10; i++ {
+		i := i
+		items = append(items, Item{
+			Label: fmt.Sprintf("item-%d", i),
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&started, 1)
+				if i == 1 {
+					cancel()
+				}
+				return nil
+			},
+		})
+	}
+
+	progress, err := Run(ctx, items, 1, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&started))
+	assert.Equal(t, 10, progress.Total)
+	assert.Equal(t, 2, progress.Succeeded)
+	assert.Len(t, progress.Results, 2)
+}
+
+func TestRunWithoutItems(t *testing.T) {
+	progress, err := Run(context.Background(), nil, Concurrency, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Progress{}, progress)
+}
@@ -0,0 +1,126 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// MethodOSC52 names copying through the terminal with the OSC 52 escape sequence
+const MethodOSC52 = "OSC 52"
+
+// Clipboard copies text to the system clipboard with a native tool such as pbcopy, wl-copy or xclip,
+// or through the terminal with OSC 52. In SSH sessions only OSC 52 is used, because native tools
+// would write to the clipboard of the remote host.
+type Clipboard struct {
+	command []string     // Native tool and arguments; nil when none is available or in SSH sessions
+	osc52   func([]byte) // Writes an OSC 52 sequence to the terminal; nil when unavailable
+	run     func(command []string, input string) error
+
+	mu         sync.Mutex
+	generation int
+}
+
+// New detects the native clipboard tool for the current system. osc52 posts data to the terminal
+// clipboard, typically tcell's Screen.SetClipboard.
+func New(osc52 func([]byte)) *Clipboard {
+	return &Clipboard{
+		command: detectCommand(runtime.GOOS, os.Getenv, exec.LookPath),
+		osc52:   osc52,
+		run:     runCommand,
+	}
+}
+
+// detectCommand returns the native clipboard tool to use, or nil to use OSC 52
+func detectCommand(goos string, getenv func(string) string, lookPath func(string) (string, error)) []string {
+	if getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != "" {
+		return nil
+	}
+
+	var candidates [][]string
+	switch goos {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip.exe"}}
+	default:
+		if getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		if getenv("DISPLAY") != "" {
+			candidates = append(candidates, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+		}
+		// WSL exposes the Windows clipboard tool
+		candidates = append(candidates, []string{"clip.exe"})
+	}
+
+	for _, candidate := range candidates {
+		if _, err := lookPath(candidate[0]); err == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// runCommand runs a clipboard tool with input on stdin
+func runCommand(command []string, input string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(input)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", command[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Available reports whether text can be copied at all
+func (c *Clipboard) Available() bool {
+	return c.command != nil || c.osc52 != nil
+}
+
+// Copy copies text to the clipboard and returns the method used and a generation
+// number to pass to ClearIfUnchanged. The native tool falls back to OSC 52 when it fails.
+func (c *Clipboard) Copy(text string) (string, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	method, err := c.write(text)
+	if err != nil {
+		return "", 0, err
+	}
+	c.generation++
+	return method, c.generation, nil
+}
+
+// ClearIfUnchanged empties the clipboard unless something was copied with Copy since the given generation.
+// Text copied by other applications in the meantime cannot be detected and is cleared as well.
+func (c *Clipboard) ClearIfUnchanged(generation int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return nil
+	}
+	_, err := c.write("")
+	return err
+}
+
+// write copies text with the native tool or OSC 52
+func (c *Clipboard) write(text string) (string, error) {
+	if c.command != nil {
+		err := c.run(c.command, text)
+		if err == nil {
+			return c.command[0], nil
+		}
+		if c.osc52 == nil {
+			return "", err
+		}
+	}
+	if c.osc52 == nil {
+		return "", fmt.Errorf("no clipboard available: install pbcopy, wl-copy, xclip or xsel, or use a terminal with OSC 52 support")
+	}
+	c.osc52([]byte(text))
+	return MethodOSC52, nil
+}
@@ -0,0 +1,87 @@
+package clipboard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		env       map[string]string
+		installed []string
+		expected  []string
+	}{
+		{name: "macOS", goos: "darwin", installed: []string{"pbcopy"}, expected: []string{"pbcopy"}},
+		{name: "Wayland", goos: "linux", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, installed: []string{"wl-copy", "xclip"}, expected: []string{"wl-copy"}},
+		{name: "X11 with xsel only", goos: "linux", env: map[string]string{"DISPLAY": ":0"}, installed: []string{"xsel"}, expected: []string{"xsel", "--clipboard", "--input"}},
+		{name: "WSL", goos: "linux", installed: []string{"clip.exe", "xclip"}, expected: []string{"clip.exe"}},
+		{name: "Headless Linux", goos: "linux", installed: []string{"xclip"}, expected: nil},
+		{name: "SSH session", goos: "darwin", env: map[string]string{"SSH_TTY": "/dev/pts/1"}, installed: []string{"pbcopy"}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			lookPath := func(file string) (string, error) {
+				for _, installed := range tt.installed {
+					if installed == file {
+						return "/usr/bin/" + file, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+			assert.Equal(t, tt.expected, detectCommand(tt.goos, getenv, lookPath))
+		})
+	}
+}
+
+func TestCopyAndClear(t *testing.T) {
+	var native, terminal []string
+	c := &Clipboard{
+		command: []string{"xclip"},
+		osc52:   func(data []byte) { terminal = append(terminal, string(data)) },
+		run: func(command []string, input string) error {
+			native = append(native, input)
+			return nil
+		},
+	}
+
+	method, first, err := c.Copy("one")
+	require.NoError(t, err)
+	assert.Equal(t, "xclip", method)
+
+	_, second, err := c.Copy("two")
+	require.NoError(t, err)
+
+	// The first copy has been replaced, so its timer must not clear the clipboard
+	require.NoError(t, c.ClearIfUnchanged(first))
+	assert.Equal(t, []string{"one", "two"}, native)
+
+	require.NoError(t, c.ClearIfUnchanged(second))
+	assert.Equal(t, []string{"one", "two", ""}, native)
+	assert.Empty(t, terminal)
+}
+
+func TestCopy_Fallback(t *testing.T) {
+	var terminal []string
+	c := &Clipboard{
+		command: []string{"xclip"},
+		osc52:   func(data []byte) { terminal = append(terminal, string(data)) },
+		run:     func(command []string, input string) error { return errors.New("cannot open display") },
+	}
+
+	method, _, err := c.Copy("value")
+	require.NoError(t, err)
+	assert.Equal(t, MethodOSC52, method)
+	assert.Equal(t, []string{"value"}, terminal)
+
+	c = &Clipboard{}
+	assert.False(t, c.Available())
+	_, _, err = c.Copy("value")
+	assert.Error(t, err)
+}
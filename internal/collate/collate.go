@@ -0,0 +1,91 @@
+// Package collate compares the text of table cells by what it shows: numbers, sizes, dates or text.
+package collate
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kind is what a cell value holds, from the most to the least specific, which is also the order of
+// values of different kinds
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindSize
+	kindDate
+	kindText
+)
+
+// sizePattern matches sizes such as 512 B, 1.5 KB or 2 GiB, with units in powers of 1024
+var sizePattern = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*([KMGTPE]?)i?B$`)
+
+// datePattern finds a date, with an optional time, in a value such as "2024-01-02 (EXPIRED)"
+var datePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(?:[ T]\d{2}:\d{2}(?::\d{2})?)?`)
+
+// dateLayouts are the layouts of the dates found by datePattern
+var dateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// Empty tells whether a value shows nothing, such as "" or the "-" placeholder. Tables sort empty values
+// last in either order.
+func Empty(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || value == "-"
+}
+
+// Compare returns a negative number, zero or a positive number as a sorts before, with or after b.
+// Numbers such as 42 or 1,024 sort first by value, then sizes such as 1.5 KB in bytes, dates such as
+// 2024-01-02 15:04:05 in time and last other values as text, ignoring case. Ordering by kind first
+// keeps the order consistent when a column mixes kinds, as sorting requires.
+func Compare(a, b string) int {
+	kindA, valueA := parse(a)
+	kindB, valueB := parse(b)
+	if kindA != kindB {
+		if kindA < kindB {
+			return -1
+		}
+		return 1
+	}
+	if kindA != kindText {
+		switch {
+		case valueA < valueB:
+			return -1
+		case valueA > valueB:
+			return 1
+		}
+		return 0
+	}
+
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// parse returns the kind of a value and, except for text, the number it stands for
+func parse(value string) (kind, float64) {
+	value = strings.TrimSpace(value)
+	// ParseFloat also reads Inf and Infinity, which are names rather than numbers in a table
+	if number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64); err == nil && !math.IsNaN(number) && !math.IsInf(number, 0) {
+		return kindNumber, number
+	}
+	if match := sizePattern.FindStringSubmatch(value); match != nil {
+		number, _ := strconv.ParseFloat(match[1], 64)
+		exponent := 0
+		if match[2] != "" {
+			exponent = strings.Index("KMGTPE", strings.ToUpper(match[2])) + 1
+		}
+		return kindSize, number * math.Pow(1024, float64(exponent))
+	}
+	if match := datePattern.FindString(value); match != "" {
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, match); err == nil {
+				return kindDate, float64(t.Unix())
+			}
+		}
+	}
+	return kindText, 0
+}
@@ -0,0 +1,77 @@
+package collate
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected int
+	}{
+		{name: "Numbers by value", a: "9", b: "10", expected: -1},
+		{name: "Numbers with separators", a: "1,024", b: "999", expected: 1},
+		{name: "Negative numbers", a: "-5", b: "3", expected: -1},
+		{name: "Equal numbers", a: "42", b: "42.0", expected: 0},
+		{name: "Sizes by bytes", a: "900 B", b: "1.0 KB", expected: -1},
+		{name: "Sizes across units", a: "2.0 MB", b: "512.0 KB", expected: 1},
+		{name: "Binary size units", a: "1 GiB", b: "1.0 GB", expected: 0},
+		{name: "Dates by time", a: "2024-02-01 09:00:00", b: "2024-01-31 23:59:59", expected: 1},
+		{name: "Dates inside text", a: "⚠️ 2023-12-31 (EXPIRED)", b: "2024-01-01", expected: -1},
+		{name: "Text ignores case", a: "alpha", b: "Beta", expected: -1},
+		{name: "Text with equal letters", a: "Alpha", b: "alpha", expected: -1},
+		{name: "Numbers before sizes", a: "10", b: "9 KB", expected: -1},
+		{name: "Sizes before dates", a: "1 TB", b: "2024-01-01", expected: -1},
+		{name: "Dates before text", a: "2024-01-01", b: "abc", expected: -1},
+		{name: "Numbers before text", a: "900", b: "-", expected: -1},
+		{name: "Infinity is text", a: "Infinity", b: "5", expected: 1},
+		{name: "Inf is text", a: "+inf", b: "1e308", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sign(Compare(tt.a, tt.b)))
+			assert.Equal(t, -tt.expected, sign(Compare(tt.b, tt.a)))
+		})
+	}
+}
+
+func TestCompareSortsSizes(t *testing.T) {
+	values := []string{"1.5 GB", "12 B", "3.0 KB", "700.0 MB", "1023 B"}
+	sort.Slice(values, func(i, j int) bool { return Compare(values[i], values[j]) < 0 })
+	assert.Equal(t, []string{"12 B", "1023 B", "3.0 KB", "700.0 MB", "1.5 GB"}, values)
+}
+
+func TestCompareSortsMixedKinds(t *testing.T) {
+	values := []string{"10", "abc", "9", "2.0 KB", "-", "2024-01-01", "100 B", "Infinity", "1,000"}
+	sort.SliceStable(values, func(i, j int) bool { return Compare(values[i], values[j]) < 0 })
+	assert.Equal(t, []string{"9", "10", "1,000", "100 B", "2.0 KB", "2024-01-01", "-", "abc", "Infinity"}, values)
+
+	// The order is transitive: sorting any permutation gives the same result
+	reversed := []string{"1,000", "Infinity", "100 B", "2024-01-01", "-", "2.0 KB", "9", "abc", "10"}
+	sort.SliceStable(reversed, func(i, j int) bool { return Compare(reversed[i], reversed[j]) < 0 })
+	assert.Equal(t, values, reversed)
+}
+
+func TestEmpty(t *testing.T) {
+	assert.True(t, Empty(""))
+	assert.True(t, Empty("  "))
+	assert.True(t, Empty("-"))
+	assert.False(t, Empty("0"))
+	assert.False(t, Empty("--"))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+)
+
+// FormatCSV exports an expiry report as comma separated values
+const FormatCSV = "csv"
+
+// ReportFormats lists the expiry report formats in display order
+var ReportFormats = []string{FormatCSV, FormatJSON}
+
+// expiryCSVHeader is the header row of a CSV expiry report
+var expiryCSVHeader = []string{"status", "days_left", "expires", "type", "name", "enabled", "vault", "resource_group", "subscription", "subscription_id"}
+
+// RenderExpiryReport renders an expiry report as CSV, one row per item, or as JSON including the
+// vaults that could not be read
+func RenderExpiryReport(report *models.ExpiryReport, format string) ([]byte, error) {
+	switch format {
+	case FormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write(expiryCSVHeader)
+		for _, item := range report.Items {
+			_ = w.Write([]string{
+				item.Status,
+				strconv.Itoa(item.DaysLeft),
+				item.Expires.UTC().Format(time.RFC3339),
+				item.Type,
+				item.Name,
+				strconv.FormatBool(item.Enabled),
+				item.Vault,
+				item.ResourceGroup,
+				item.SubscriptionName,
+				item.SubscriptionID,
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write CSV: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unknown report format %q, use one of %s", format, strings.Join(ReportFormats, ", "))
+	}
+}
+
+// DefaultReportFileName returns the conventional file name for an expiry report format
+func DefaultReportFileName(format string) string {
+	return "keyvault-expiry." + format
+}
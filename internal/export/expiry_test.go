@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testExpiryReport() *models.ExpiryReport {
+	return &models.ExpiryReport{
+		Generated:     time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		WithinDays:    30,
+		VaultsScanned: 2,
+		Items: []*models.ExpiryItem{
+			{
+				SubscriptionID:   "sub-1",
+				SubscriptionName: "Production, EU",
+				ResourceGroup:    "rg-web",
+				Vault:            "kv-web",
+				Type:             models.VaultItemCertificate,
+				Name:             "tls",
+				Enabled:          true,
+				Expires:          time.Date(2024, 2, 28, 12, 0, 0, 0, time.UTC),
+				DaysLeft:         -2,
+				Status:           models.ExpiryStatusExpired,
+			},
+		},
+		Errors: []string{"Production, EU/kv-locked: forbidden"},
+	}
+}
+
+func TestRenderExpiryReportCSV(t *testing.T) {
+	data, err := RenderExpiryReport(testExpiryReport(), FormatCSV)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "status,days_left,expires,type,name,enabled,vault,resource_group,subscription,subscription_id", lines[0])
+	assert.Equal(t, `Expired,-2,2024-02-28T12:00:00Z,certificate,tls,true,kv-web,rg-web,"Production, EU",sub-1`, lines[1])
+}
+
+func TestRenderExpiryReportJSON(t *testing.T) {
+	data, err := RenderExpiryReport(testExpiryReport(), FormatJSON)
+	require.NoError(t, err)
+
+	var decoded models.ExpiryReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 30, decoded.WithinDays)
+	require.Len(t, decoded.Items, 1)
+	assert.Equal(t, "tls", decoded.Items[0].Name)
+	assert.Equal(t, -2, decoded.Items[0].DaysLeft)
+	assert.Equal(t, []string{"Production, EU/kv-locked: forbidden"}, decoded.Errors)
+}
+
+func TestRenderExpiryReportUnknownFormat(t *testing.T) {
+	_, err := RenderExpiryReport(testExpiryReport(), FormatDotenv)
+	assert.Error(t, err)
+}
+
+func TestDefaultReportFileName(t *testing.T) {
+	assert.Equal(t, "keyvault-expiry.csv", DefaultReportFileName(FormatCSV))
+	assert.Equal(t, "keyvault-expiry.json", DefaultReportFileName(FormatJSON))
+}
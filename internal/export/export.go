@@ -0,0 +1,226 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatDotenv     = "dotenv"
+	FormatJSON       = "json"
+	FormatKubernetes = "kubernetes"
+)
+
+// Formats lists the export formats in display order
+var Formats = []string{FormatDotenv, FormatJSON, FormatKubernetes}
+
+// Name mappings from secret names to exported keys
+const (
+	MappingUpperSnake = "upper-snake" // db-password -> DB_PASSWORD
+	MappingLowerSnake = "lower-snake" // db-password -> db_password
+	MappingNone       = "none"        // db-password -> db-password
+)
+
+// Mappings lists the name mappings in display order
+var Mappings = []string{MappingUpperSnake, MappingLowerSnake, MappingNone}
+
+var (
+	dotenvKeyPattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	kubernetesKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	kubernetesNameChars  = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// Options configures an export
+type Options struct {
+	Format  string
+	Mapping string
+	Prefix  string // Prepended to every key after mapping
+
+	// Kubernetes Secret metadata
+	SecretName string
+	Namespace  string
+}
+
+// Validate checks the format, the name mapping and, for Kubernetes, the Secret name
+func (o Options) Validate() error {
+	if !contains(Formats, o.Format) {
+		return fmt.Errorf("unknown export format %q, use one of %s", o.Format, strings.Join(Formats, ", "))
+	}
+	if !contains(Mappings, o.Mapping) {
+		return fmt.Errorf("unknown name mapping %q, use one of %s", o.Mapping, strings.Join(Mappings, ", "))
+	}
+	if o.Format == FormatKubernetes && KubernetesName(o.SecretName) == "" {
+		return fmt.Errorf("enter a name for the Kubernetes Secret")
+	}
+	return nil
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is one exported secret
+type Entry struct {
+	Name  string // Secret name in the vault
+	Key   string // Key in the exported file
+	Value string
+}
+
+// MapName maps a secret name to an export key
+func MapName(name, mapping, prefix string) string {
+	switch mapping {
+	case MappingUpperSnake:
+		name = strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	case MappingLowerSnake:
+		name = strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+	}
+	return prefix + name
+}
+
+// Fetch reads the value of every named secret with get and maps the names to keys.
+// It fails on the first unreadable secret and when two secrets map to the same key, so a partial export is never written.
+func Fetch(ctx context.Context, names []string, opts Options, get func(ctx context.Context, name string) (string, error)) ([]Entry, error) {
+	keys := make(map[string]string)
+	entries := make([]Entry, 0, len(names))
+	for _, name := range names {
+		key := MapName(name, opts.Mapping, opts.Prefix)
+		if other, ok := keys[key]; ok {
+			return nil, fmt.Errorf("secrets %s and %s both map to %s", other, name, key)
+		}
+		keys[key] = name
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		value, err := get(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		entries = append(entries, Entry{Name: name, Key: key, Value: value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+// Render formats entries in the export format
+func Render(entries []Entry, opts Options) ([]byte, error) {
+	switch opts.Format {
+	case FormatDotenv:
+		return renderDotenv(entries)
+	case FormatJSON:
+		return renderJSON(entries)
+	case FormatKubernetes:
+		return renderKubernetes(entries, opts)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", opts.Format)
+	}
+}
+
+// renderDotenv writes KEY="value" lines. Values are always double quoted with backslashes, quotes,
+// dollar signs and line breaks escaped so that multi-line values such as PEM keys survive.
+func renderDotenv(entries []Entry) ([]byte, error) {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+
+	var out strings.Builder
+	for _, entry := range entries {
+		if !dotenvKeyPattern.MatchString(entry.Key) {
+			return nil, fmt.Errorf("%s is not a valid environment variable name; use the upper-snake or lower-snake mapping", entry.Key)
+		}
+		out.WriteString(fmt.Sprintf("%s=\"%s\"\n", entry.Key, escaper.Replace(entry.Value)))
+	}
+	return []byte(out.String()), nil
+}
+
+// renderJSON writes a flat JSON object of keys to values
+func renderJSON(entries []Entry) ([]byte, error) {
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// renderKubernetes writes an Opaque Secret manifest with base64 encoded data
+func renderKubernetes(entries []Entry, opts Options) ([]byte, error) {
+	name := KubernetesName(opts.SecretName)
+	if name == "" {
+		return nil, fmt.Errorf("enter a name for the Kubernetes Secret")
+	}
+
+	var out strings.Builder
+	out.WriteString("apiVersion: v1\nkind: Secret\nmetadata:\n")
+	out.WriteString(fmt.Sprintf("  name: %s\n", name))
+	if namespace := KubernetesName(opts.Namespace); namespace != "" {
+		out.WriteString(fmt.Sprintf("  namespace: %s\n", namespace))
+	}
+	out.WriteString("type: Opaque\ndata:\n")
+	for _, entry := range entries {
+		if !kubernetesKeyPattern.MatchString(entry.Key) {
+			return nil, fmt.Errorf("%s is not a valid Kubernetes Secret key", entry.Key)
+		}
+		out.WriteString(fmt.Sprintf("  %q: %s\n", entry.Key, base64.StdEncoding.EncodeToString([]byte(entry.Value))))
+	}
+	return []byte(out.String()), nil
+}
+
+// KubernetesName turns a name into a valid Kubernetes object name (RFC 1123 label)
+func KubernetesName(name string) string {
+	name = kubernetesNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-")
+}
+
+// DefaultFileName returns the conventional file name for an export format
+func DefaultFileName(format string) string {
+	switch format {
+	case FormatJSON:
+		return "secrets.json"
+	case FormatKubernetes:
+		return "secret.yaml"
+	default:
+		return ".env"
+	}
+}
+
+// WriteFile writes an export readable only by the current user. An existing file is truncated
+// and its permissions are tightened to 0600 before the values are written.
+func WriteFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapName(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  string
+		prefix   string
+		expected string
+	}{
+		{name: "db-password", mapping: MappingUpperSnake, expected: "DB_PASSWORD"},
+		{name: "Api-Key", mapping: MappingLowerSnake, expected: "api_key"},
+		{name: "db-password", mapping: MappingNone, expected: "db-password"},
+		{name: "db-password", mapping: MappingUpperSnake, prefix: "APP_", expected: "APP_DB_PASSWORD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MapName(tt.name, tt.mapping, tt.prefix))
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	get := func(ctx context.Context, name string) (string, error) {
+		if name == "broken" {
+			return "", errors.New("forbidden")
+		}
+		return "value-" + name, nil
+	}
+	opts := Options{Mapping: MappingUpperSnake}
+
+	entries, err := Fetch(context.Background(), []string{"z-key", "a-key"}, opts, get)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "a-key", Key: "A_KEY", Value: "value-a-key"},
+		{Name: "z-key", Key: "Z_KEY", Value: "value-z-key"},
+	}, entries)
+
+	_, err = Fetch(context.Background(), []string{"a-key", "broken"}, opts, get)
+	assert.ErrorContains(t, err, "broken")
+
+	_, err = Fetch(context.Background(), []string{"db-password", "DB-PASSWORD"}, opts, get)
+	assert.ErrorContains(t, err, "both map to DB_PASSWORD")
+}
+
+func TestRender_Dotenv(t *testing.T) {
+	entries := []Entry{
+		{Key: "DB_PASSWORD", Value: `p"a$s\s`},
+		{Key: "TLS_KEY", Value: "line1\nline2"},
+	}
+
+	data, err := Render(entries, Options{Format: FormatDotenv})
+	require.NoError(t, err)
+	assert.Equal(t, "DB_PASSWORD=\"p\\\"a\\$s\\\\s\"\nTLS_KEY=\"line1\\nline2\"\n", string(data))
+
+	_, err = Render([]Entry{{Key: "db-password", Value: "x"}}, Options{Format: FormatDotenv})
+	assert.Error(t, err, "Dashes are not valid in environment variable names")
+}
+
+func TestRender_JSON(t *testing.T) {
+	data, err := Render([]Entry{{Key: "db-password", Value: "secret"}}, Options{Format: FormatJSON})
+	require.NoError(t, err)
+
+	var values map[string]string
+	require.NoError(t, json.Unmarshal(data, &values))
+	assert.Equal(t, map[string]string{"db-password": "secret"}, values)
+}
+
+func TestRender_Kubernetes(t *testing.T) {
+	entries := []Entry{{Key: "DB_PASSWORD", Value: "secret"}}
+
+	data, err := Render(entries, Options{Format: FormatKubernetes, SecretName: "My_Vault", Namespace: "apps"})
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: Secret
+metadata:
+  name: my-vault
+  namespace: apps
+type: Opaque
+data:
+  "DB_PASSWORD": c2VjcmV0
+`, string(data))
+
+	_, err = Render(entries, Options{Format: FormatKubernetes})
+	assert.Error(t, err, "Secret name is required")
+
+	_, err = Render(entries, Options{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, Options{Format: FormatDotenv, Mapping: MappingUpperSnake}.Validate())
+	assert.NoError(t, Options{Format: FormatKubernetes, Mapping: MappingNone, SecretName: "app"}.Validate())
+	assert.Error(t, Options{Format: "xml", Mapping: MappingNone}.Validate())
+	assert.Error(t, Options{Format: FormatJSON, Mapping: "camel"}.Validate())
+	assert.Error(t, Options{Format: FormatKubernetes, Mapping: MappingNone, SecretName: "--"}.Validate())
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("old content that is longer"), 0644))
+
+	require.NoError(t, WriteFile(path, []byte("A=\"1\"\n")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "A=\"1\"\n", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
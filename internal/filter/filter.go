@@ -0,0 +1,549 @@
+// Package filter parses and matches the filters typed in table views: words, quoted phrases, regular
+// expressions and fuzzy terms, optionally qualified by a column or a tag, combined with NOT, AND and OR.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tagField qualifies a term with a tag instead of a column
+const tagField = "tag"
+
+// Fields describes what a filter can refer to
+type Fields struct {
+	Columns []string // Names of the columns qualifiers can refer to, indexed as by Row.Cell
+	Shown   []int    // Indexes of the columns unqualified terms search
+	Tags    bool     // Whether rows have tags for tag: terms
+}
+
+// Row gives a filter the values of a row
+type Row interface {
+	Cell(column int) string                   // Text of a column, by its index in Fields.Columns
+	Tag(name string) (value string, set bool) // Value of a tag, whose name matches without regard to case
+}
+
+// Filter is a parsed filter
+type Filter struct {
+	root node
+}
+
+// SyntaxError is a filter that cannot be parsed
+type SyntaxError struct {
+	Pos int // Byte offset of the problem in the filter text
+	Msg string
+}
+
+// Error describes the problem and where it is, counting characters from 1
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+// Parse parses a filter. Terms next to each other must all match; AND, OR and NOT, or &, | and !,
+// combine them, with NOT binding tightest and OR loosest, and parentheses group them. A term is one of:
+//
+//	word            a cell of a shown column contains word, ignoring case
+//	"two words"     a cell contains the phrase
+//	/regexp/        a cell matches the regular expression, ignoring case
+//	~word           a cell contains the letters of word in order, such as ~kvprd for kv-prod
+//	column:value    the named column matches value, which can be any of the above; when no column
+//	                has the name, such as in 12:30, the whole word is searched as text
+//	tag:name        the row has the tag
+//	tag:name=value  the tag has the value, which can be a word, a phrase, a regexp or a fuzzy term
+//
+// Column names ignore case, spaces, dashes and underscores, so resourcegroup: names "Resource Group".
+// An empty filter matches every row.
+func Parse(text string, fields Fields) (*Filter, error) {
+	p := &parser{text: text, fields: fields}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return &Filter{}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok.describe())}
+	}
+	return &Filter{root: root}, nil
+}
+
+// Match reports whether a row matches the filter
+func (f *Filter) Match(row Row) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.match(row)
+}
+
+// node is a parsed expression
+type node interface {
+	match(row Row) bool
+}
+
+type orNode []node
+
+func (n orNode) match(row Row) bool {
+	for _, child := range n {
+		if child.match(row) {
+			return true
+		}
+	}
+	return false
+}
+
+type andNode []node
+
+func (n andNode) match(row Row) bool {
+	for _, child := range n {
+		if !child.match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+type notNode struct {
+	child node
+}
+
+func (n notNode) match(row Row) bool {
+	return !n.child.match(row)
+}
+
+// cellNode matches a value against the cells of some columns, any of which may match
+type cellNode struct {
+	columns []int
+	value   matcher
+}
+
+func (n cellNode) match(row Row) bool {
+	for _, column := range n.columns {
+		if n.value.match(row.Cell(column)) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagNode matches rows having a tag, with a value matching value when it is set
+type tagNode struct {
+	name  string
+	value matcher
+}
+
+func (n tagNode) match(row Row) bool {
+	value, set := row.Tag(n.name)
+	if !set {
+		return false
+	}
+	return n.value == nil || n.value.match(value)
+}
+
+// matcher matches a single value
+type matcher interface {
+	match(value string) bool
+}
+
+// containsMatcher matches values containing text, ignoring case
+type containsMatcher string
+
+func (m containsMatcher) match(value string) bool {
+	return strings.Contains(strings.ToLower(value), string(m))
+}
+
+// equalMatcher matches values equal to text, ignoring case
+type equalMatcher string
+
+func (m equalMatcher) match(value string) bool {
+	return strings.EqualFold(value, string(m))
+}
+
+type regexpMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m regexpMatcher) match(value string) bool {
+	return m.re.MatchString(value)
+}
+
+// fuzzyMatcher matches values containing its letters in order, ignoring case
+type fuzzyMatcher string
+
+func (m fuzzyMatcher) match(value string) bool {
+	pattern := string(m)
+	for _, r := range strings.ToLower(value) {
+		if pattern == "" {
+			break
+		}
+		next, size := utf8.DecodeRuneInString(pattern)
+		if r == next {
+			pattern = pattern[size:]
+		}
+	}
+	return pattern == ""
+}
+
+// tokenKind is the kind of a scanned token
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+// token is a scanned operator, parenthesis or term
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+	term node
+}
+
+// describe names a token in error messages
+func (t token) describe() string {
+	if t.kind == tokenTerm {
+		return fmt.Sprintf("%q", t.text)
+	}
+	return t.text
+}
+
+// parser scans a filter into tokens, then parses them by recursive descent
+type parser struct {
+	text   string
+	fields Fields
+	tokens []token
+	pos    int
+}
+
+// scan splits the text into tokens, parsing the terms as it goes
+func (p *parser) scan() error {
+	i := 0
+	for i < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			p.tokens = append(p.tokens, token{kind: tokenOpen, pos: i, text: "("})
+			i++
+		case r == ')':
+			p.tokens = append(p.tokens, token{kind: tokenClose, pos: i, text: ")"})
+			i++
+		case r == '!':
+			p.tokens = append(p.tokens, token{kind: tokenNot, pos: i, text: "!"})
+			i++
+		default:
+			end := p.wordEnd(i)
+			switch word := p.text[i:end]; word {
+			case "AND", "&", "&&":
+				p.tokens = append(p.tokens, token{kind: tokenAnd, pos: i, text: word})
+				i = end
+			case "OR", "|", "||":
+				p.tokens = append(p.tokens, token{kind: tokenOr, pos: i, text: word})
+				i = end
+			case "NOT":
+				p.tokens = append(p.tokens, token{kind: tokenNot, pos: i, text: word})
+				i = end
+			default:
+				term, next, err := p.scanTerm(i)
+				if err != nil {
+					return err
+				}
+				p.tokens = append(p.tokens, token{kind: tokenTerm, pos: i, text: p.text[i:next], term: term})
+				i = next
+			}
+		}
+	}
+	return nil
+}
+
+// wordEnd returns the end of the unquoted word starting at i, which stops at spaces and parentheses
+func (p *parser) wordEnd(i int) int {
+	for i < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[i:])
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// scanTerm parses the term starting at i and returns it with the offset after it
+func (p *parser) scanTerm(i int) (node, int, error) {
+	if field, valueStart, ok := p.fieldAt(i); ok {
+		if strings.EqualFold(field, tagField) && p.fields.Tags {
+			return p.scanTag(i, valueStart)
+		}
+		column, found := p.column(field)
+		if !found {
+			// Not a column, so the colon is part of a word such as 12:30 or https://example.com
+			end := p.wordEnd(i)
+			return cellNode{columns: p.fields.Shown, value: containsMatcher(strings.ToLower(p.text[i:end]))}, end, nil
+		}
+		if p.wordEnd(valueStart) == valueStart {
+			return nil, 0, &SyntaxError{Pos: valueStart, Msg: fmt.Sprintf("missing value after %s:", field)}
+		}
+		value, next, err := p.scanValue(valueStart, false)
+		if err != nil {
+			return nil, 0, err
+		}
+		return cellNode{columns: []int{column}, value: value}, next, nil
+	}
+
+	value, next, err := p.scanValue(i, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cellNode{columns: p.fields.Shown, value: value}, next, nil
+}
+
+// fieldAt reports whether a term starting at i is qualified with a field name followed by a colon,
+// returning the name and the offset of the value
+func (p *parser) fieldAt(i int) (string, int, bool) {
+	j := i
+	for j < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[j:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			break
+		}
+		j += size
+	}
+	if j == i || j >= len(p.text) || p.text[j] != ':' {
+		return "", 0, false
+	}
+	return p.text[i:j], j + 1, true
+}
+
+// column finds a column by name, ignoring case, spaces, dashes and underscores
+func (p *parser) column(name string) (int, bool) {
+	key := columnKey(name)
+	for i, column := range p.fields.Columns {
+		if columnKey(column) == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// columnKey normalizes a column name for qualifiers
+func columnKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// scanTag parses a tag: term whose tag name starts at i
+func (p *parser) scanTag(start, i int) (node, int, error) {
+	name, next, err := p.scanText(i, func(r rune) bool { return r == '=' })
+	if err != nil {
+		return nil, 0, err
+	}
+	if name == "" {
+		return nil, 0, &SyntaxError{Pos: i, Msg: "missing tag name after tag:"}
+	}
+	if next >= len(p.text) || p.text[next] != '=' {
+		return tagNode{name: name}, next, nil
+	}
+
+	value, next, err := p.scanValue(next+1, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tagNode{name: name, value: value}, next, nil
+}
+
+// scanValue parses the value of a term at i: a word, a quoted phrase, a /regexp/ or a ~fuzzy term.
+// Words and phrases match exactly when exact is set, and are contained otherwise.
+func (p *parser) scanValue(i int, exact bool) (matcher, int, error) {
+	if i < len(p.text) && p.text[i] == '/' {
+		return p.scanRegexp(i)
+	}
+
+	fuzzy := i < len(p.text) && p.text[i] == '~'
+	if fuzzy {
+		i++
+	}
+	text, next, err := p.scanText(i, func(rune) bool { return false })
+	if err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case fuzzy:
+		if text == "" {
+			return nil, 0, &SyntaxError{Pos: i, Msg: "missing text after ~"}
+		}
+		return fuzzyMatcher(strings.ToLower(text)), next, nil
+	case exact:
+		return equalMatcher(text), next, nil
+	}
+	return containsMatcher(strings.ToLower(text)), next, nil
+}
+
+// scanText parses a quoted phrase, in which \" and \\ are escapes, or a word ending at a space, a
+// parenthesis or a rune for which stop is true
+func (p *parser) scanText(i int, stop func(rune) bool) (string, int, error) {
+	if i >= len(p.text) || p.text[i] != '"' {
+		j := i
+		for j < len(p.text) {
+			r, size := utf8.DecodeRuneInString(p.text[j:])
+			if unicode.IsSpace(r) || r == '(' || r == ')' || stop(r) {
+				break
+			}
+			j += size
+		}
+		return p.text[i:j], j, nil
+	}
+
+	var text strings.Builder
+	for j := i + 1; j < len(p.text); j++ {
+		switch c := p.text[j]; {
+		case c == '"':
+			return text.String(), j + 1, nil
+		case c == '\\' && j+1 < len(p.text) && (p.text[j+1] == '"' || p.text[j+1] == '\\'):
+			text.WriteByte(p.text[j+1])
+			j++
+		default:
+			text.WriteByte(c)
+		}
+	}
+	return "", 0, &SyntaxError{Pos: i, Msg: "unterminated quote"}
+}
+
+// scanRegexp parses a /regexp/ starting at i, in which \/ stands for a slash. Regular expressions
+// ignore case.
+func (p *parser) scanRegexp(i int) (matcher, int, error) {
+	var pattern strings.Builder
+	for j := i + 1; j < len(p.text); j++ {
+		switch c := p.text[j]; {
+		case c == '/':
+			if pattern.Len() == 0 {
+				return nil, 0, &SyntaxError{Pos: i, Msg: "empty regular expression"}
+			}
+			re, err := regexp.Compile("(?i)" + pattern.String())
+			if err != nil {
+				return nil, 0, &SyntaxError{Pos: i, Msg: fmt.Sprintf("invalid regular expression: %s", regexpProblem(err))}
+			}
+			return regexpMatcher{re: re}, j + 1, nil
+		case c == '\\' && j+1 < len(p.text) && p.text[j+1] == '/':
+			pattern.WriteByte('/')
+			j++
+		case c == '\\' && j+1 < len(p.text):
+			pattern.WriteString(p.text[j : j+2])
+			j++
+		default:
+			pattern.WriteByte(c)
+		}
+	}
+	return nil, 0, &SyntaxError{Pos: i, Msg: "unterminated regular expression, missing closing /"}
+}
+
+// regexpProblem returns what is wrong with a regular expression, without the pattern that the regexp
+// package repeats in its errors
+func regexpProblem(err error) string {
+	var syntaxErr *syntax.Error
+	if errors.As(err, &syntaxErr) {
+		return string(syntaxErr.Code)
+	}
+	return err.Error()
+}
+
+// parseOr parses terms separated by OR
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{first}
+	for p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOr {
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, next)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return orNode(nodes), nil
+}
+
+// parseAnd parses terms separated by AND or next to each other
+func (p *parser) parseAnd() (node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{first}
+	for p.pos < len(p.tokens) {
+		switch p.tokens[p.pos].kind {
+		case tokenAnd:
+			p.pos++
+		case tokenTerm, tokenNot, tokenOpen:
+		default:
+			if len(nodes) == 1 {
+				return first, nil
+			}
+			return andNode(nodes), nil
+		}
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, next)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return andNode(nodes), nil
+}
+
+// parseUnary parses a negated term, a parenthesized expression or a term
+func (p *parser) parseUnary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, &SyntaxError{Pos: len(p.text), Msg: "missing term at the end"}
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokenNot:
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child: child}, nil
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenClose {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "unclosed ("}
+		}
+		p.pos++
+		return inner, nil
+	case tokenTerm:
+		return tok.term, nil
+	}
+	return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected a term before %s", tok.describe())}
+}
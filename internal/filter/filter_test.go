@@ -0,0 +1,183 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRow is a row of the test fields: Name, Type, Location and Resource Group
+type testRow struct {
+	cells []string
+	tags  map[string]string
+}
+
+func (r testRow) Cell(column int) string {
+	return r.cells[column]
+}
+
+func (r testRow) Tag(name string) (string, bool) {
+	for key, value := range r.tags {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+var testFields = Fields{
+	Columns: []string{"Name", "Type", "Location", "Resource Group"},
+	Shown:   []int{0, 1, 2},
+	Tags:    true,
+}
+
+var testRows = map[string]testRow{
+	"prod-web": {
+		cells: []string{"prod-web", "Microsoft.Web/sites", "westeurope", "rg-prod"},
+		tags:  map[string]string{"env": "prod", "owner": "alice"},
+	},
+	"prod-kv": {
+		cells: []string{"prod-kv", "Microsoft.KeyVault/vaults", "northeurope", "rg-prod"},
+		tags:  map[string]string{"Env": "Prod"},
+	},
+	"dev-web": {
+		cells: []string{"dev-web", "Microsoft.Web/sites", "westeurope", "rg-dev"},
+		tags:  map[string]string{"env": "dev", "owner": "bob"},
+	},
+	"shared-storage": {
+		cells: []string{"shared-storage", "Microsoft.Storage/storageAccounts", "eastus", "rg-shared"},
+	},
+}
+
+func TestParseAndMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{name: "Empty", filter: "  ", want: []string{"dev-web", "prod-kv", "prod-web", "shared-storage"}},
+		{name: "Word ignores case", filter: "PROD", want: []string{"prod-kv", "prod-web"}},
+		{name: "Words must all match", filter: "prod web", want: []string{"prod-web"}},
+		{name: "Quoted phrase", filter: `"web/sites"`, want: []string{"dev-web", "prod-web"}},
+		{name: "Regexp", filter: `/^prod-.*$/`, want: []string{"prod-kv", "prod-web"}},
+		{name: "Regexp ignores case", filter: `/^SHARED/`, want: []string{"shared-storage"}},
+		{name: "Regexp with escaped slash", filter: `/web\/sites$/`, want: []string{"dev-web", "prod-web"}},
+		{name: "Fuzzy", filter: "~shst", want: []string{"shared-storage"}},
+		{name: "Column qualifier", filter: "location:westeurope", want: []string{"dev-web", "prod-web"}},
+		{name: "Column qualifier contains", filter: "type:vaults", want: []string{"prod-kv"}},
+		{name: "Column qualifier with regexp", filter: "name:/web$/", want: []string{"dev-web", "prod-web"}},
+		{name: "Column name without spaces", filter: "resourcegroup:shared", want: []string{"shared-storage"}},
+		{name: "Column name with dash", filter: "resource-group:rg-dev", want: []string{"dev-web"}},
+		{name: "Hidden column qualifier", filter: "Resource_Group:prod", want: []string{"prod-kv", "prod-web"}},
+		{name: "Unqualified terms skip hidden columns", filter: "rg-", want: []string{}},
+		{name: "Tag value", filter: "tag:env=prod", want: []string{"prod-kv", "prod-web"}},
+		{name: "Tag value is exact", filter: "tag:env=pro", want: []string{}},
+		{name: "Tag value regexp", filter: "tag:env=/^d/", want: []string{"dev-web"}},
+		{name: "Tag exists", filter: "tag:owner", want: []string{"dev-web", "prod-web"}},
+		{name: "Negated tag", filter: "!tag:owner", want: []string{"prod-kv", "shared-storage"}},
+		{name: "NOT keyword", filter: "NOT prod", want: []string{"dev-web", "shared-storage"}},
+		{name: "OR", filter: "name:dev OR type:storage", want: []string{"dev-web", "shared-storage"}},
+		{name: "Pipe", filter: "name:dev | name:kv", want: []string{"dev-web", "prod-kv"}},
+		{name: "AND binds tighter than OR", filter: "prod AND web OR eastus", want: []string{"prod-web", "shared-storage"}},
+		{name: "Parentheses", filter: "prod (web | kv) !tag:owner", want: []string{"prod-kv"}},
+		{name: "Negated group", filter: "!(prod | dev)", want: []string{"shared-storage"}},
+		{name: "Lowercase keywords are words", filter: "and", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.filter, testFields)
+			require.NoError(t, err)
+
+			got := []string{}
+			for name, row := range testRows {
+				if f.Match(row) {
+					got = append(got, name)
+				}
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr string
+		wantPos int
+	}{
+		{name: "Missing value", filter: "location: west", wantErr: "missing value after location:", wantPos: 9},
+		{name: "Unterminated regexp", filter: "/^prod", wantErr: "unterminated regular expression", wantPos: 0},
+		{name: "Invalid regexp", filter: "name:/a(b/", wantErr: "invalid regular expression: missing closing )", wantPos: 5},
+		{name: "Empty regexp", filter: "//", wantErr: "empty regular expression", wantPos: 0},
+		{name: "Unterminated quote", filter: `"prod web`, wantErr: "unterminated quote", wantPos: 0},
+		{name: "Unclosed parenthesis", filter: "(prod | dev", wantErr: "unclosed (", wantPos: 0},
+		{name: "Unexpected parenthesis", filter: "prod)", wantErr: "unexpected )", wantPos: 4},
+		{name: "Missing term after OR", filter: "prod OR", wantErr: "missing term at the end", wantPos: 7},
+		{name: "Operator without term", filter: "OR prod", wantErr: "expected a term before OR", wantPos: 0},
+		{name: "Missing tag name", filter: "tag:=prod", wantErr: "missing tag name", wantPos: 4},
+		{name: "Missing fuzzy text", filter: "~", wantErr: "missing text after ~", wantPos: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.filter, testFields)
+			require.Error(t, err)
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Contains(t, syntaxErr.Msg, tt.wantErr)
+			assert.Equal(t, tt.wantPos, syntaxErr.Pos)
+		})
+	}
+}
+
+func TestParseTagWithoutTags(t *testing.T) {
+	fields := Fields{Columns: []string{"Name"}, Shown: []int{0}}
+	// Without tags or a Tag column, tag:env=prod is searched as text
+	f, err := Parse("tag:env=prod", fields)
+	require.NoError(t, err)
+	assert.True(t, f.Match(testRow{cells: []string{"label tag:env=prod"}}))
+	assert.False(t, f.Match(testRow{cells: []string{"prod"}}))
+
+	// A column named Tag is qualified like any other column when rows have no tags
+	fields.Columns = append(fields.Columns, "Tag")
+	f, err = Parse("tag:v1", fields)
+	require.NoError(t, err)
+	assert.True(t, f.Match(testRow{cells: []string{"app", "v1.2"}}))
+}
+
+func TestParseWordsWithColons(t *testing.T) {
+	rows := map[string]testRow{
+		"time": {cells: []string{"backup 12:30", "Microsoft.Automation/schedules", "eastus", "rg-ops"}},
+		"url":  {cells: []string{"probe https://example.com/health", "eastus2:foo", "eastus2", "rg-ops"}},
+		"web":  {cells: []string{"web", "Microsoft.Web/sites", "eastus", "rg-web"}},
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{name: "Time", filter: "12:30", want: []string{"time"}},
+		{name: "URL", filter: "https://example.com/health", want: []string{"url"}},
+		{name: "Unknown qualifier", filter: "eastus2:foo", want: []string{"url"}},
+		{name: "Word with colon and a column qualifier", filter: "12:30 location:eastus", want: []string{"time"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.filter, testFields)
+			require.NoError(t, err)
+
+			got := []string{}
+			for name, row := range rows {
+				if f.Match(row) {
+					got = append(got, name)
+				}
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
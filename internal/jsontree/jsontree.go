@@ -0,0 +1,197 @@
+// Package jsontree parses JSON documents into trees that keep the order of object keys, for display
+// and conversion to YAML.
+package jsontree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is the JSON type of a node
+type Kind int
+
+const (
+	Object Kind = iota
+	Array
+	String
+	Number
+	Bool
+	Null
+)
+
+// RootPath is the path of the root node
+const RootPath = "."
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Node is a value of a JSON document
+type Node struct {
+	Key      string // Object key, or index of an array element; empty for the root
+	Path     string // jq path from the root, such as .properties.ipRules[0].value
+	Kind     Kind
+	Value    string // Text of scalar values; strings are unquoted
+	Children []*Node
+}
+
+// Parse parses a JSON document
+func Parse(data []byte) (*Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := decodeNode(decoder, "", RootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("failed to parse JSON: unexpected data after the document")
+	}
+	return root, nil
+}
+
+// decodeNode decodes the next value of the decoder and its children
+func decodeNode(decoder *json.Decoder, key, path string) (*Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &Node{Key: key, Path: path}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			node.Kind = Object
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				name, _ := keyToken.(string)
+				child, err := decodeNode(decoder, name, keyPath(path, name))
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, child)
+			}
+		} else {
+			node.Kind = Array
+			for i := 0; decoder.More(); i++ {
+				child, err := decodeNode(decoder, strconv.Itoa(i), indexPath(path, i))
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, child)
+			}
+		}
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.Kind = String
+		node.Value = value
+	case json.Number:
+		node.Kind = Number
+		node.Value = value.String()
+	case bool:
+		node.Kind = Bool
+		node.Value = strconv.FormatBool(value)
+	case nil:
+		node.Kind = Null
+		node.Value = "null"
+	}
+	return node, nil
+}
+
+// keyPath returns the path of an object member. Keys that are not identifiers are quoted.
+func keyPath(parent, key string) string {
+	prefix := strings.TrimSuffix(parent, RootPath)
+	if identifierPattern.MatchString(key) {
+		return prefix + "." + key
+	}
+	if prefix == "" {
+		prefix = RootPath
+	}
+	return prefix + "[" + strconv.Quote(key) + "]"
+}
+
+// indexPath returns the path of an array element
+func indexPath(parent string, index int) string {
+	return fmt.Sprintf("%s[%d]", parent, index)
+}
+
+// IsContainer reports whether the node is an object or an array
+func (n *Node) IsContainer() bool {
+	return n.Kind == Object || n.Kind == Array
+}
+
+// Search returns the nodes whose key or scalar value contains the query, ignoring case, in document order
+func Search(root *Node, query string) []*Node {
+	query = strings.ToLower(query)
+	if query == "" {
+		return nil
+	}
+
+	var matches []*Node
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if strings.Contains(strings.ToLower(node.Key), query) ||
+			(!node.IsContainer() && strings.Contains(strings.ToLower(node.Value), query)) {
+			matches = append(matches, node)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return matches
+}
+
+// YAML renders the document as YAML, keeping the order of object keys
+func (n *Node) YAML() (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(n.yamlNode()); err != nil {
+		return "", fmt.Errorf("failed to render YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to render YAML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// yamlNode converts the node to a YAML node with explicit tags, so that strings such as "true" stay strings
+func (n *Node) yamlNode() *yaml.Node {
+	switch n.Kind {
+	case Object:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, child := range n.Children {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: child.Key}, child.yamlNode())
+		}
+		return node
+	case Array:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, child := range n.Children {
+			node.Content = append(node.Content, child.yamlNode())
+		}
+		return node
+	case Number:
+		tag := "!!int"
+		if strings.ContainsAny(n.Value, ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: n.Value}
+	case Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: n.Value}
+	case Null:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: n.Value}
+	}
+}
@@ -0,0 +1,109 @@
+package jsontree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{
+	"name": "kv-1",
+	"tags": {"cost-center": "42", "env": "true"},
+	"properties": {
+		"enabled": true,
+		"retentionDays": 90,
+		"ratio": 0.5,
+		"deletedAt": null,
+		"ipRules": [{"value": "203.0.113.10/32"}],
+		"empty": {}
+	}
+}`
+
+func TestParse(t *testing.T) {
+	root, err := Parse([]byte(testDocument))
+	require.NoError(t, err)
+
+	assert.Equal(t, Object, root.Kind)
+	assert.Equal(t, RootPath, root.Path)
+	require.Len(t, root.Children, 3)
+
+	// Keys keep their document order
+	assert.Equal(t, "name", root.Children[0].Key)
+	assert.Equal(t, "tags", root.Children[1].Key)
+	assert.Equal(t, "properties", root.Children[2].Key)
+
+	assert.Equal(t, &Node{Key: "name", Path: ".name", Kind: String, Value: "kv-1"}, root.Children[0])
+	assert.Equal(t, `.tags["cost-center"]`, root.Children[1].Children[0].Path)
+
+	properties := root.Children[2]
+	assert.Equal(t, Bool, properties.Children[0].Kind)
+	assert.Equal(t, "true", properties.Children[0].Value)
+	assert.Equal(t, Number, properties.Children[1].Kind)
+	assert.Equal(t, "90", properties.Children[1].Value)
+	assert.Equal(t, Null, properties.Children[3].Kind)
+
+	ipRules := properties.Children[4]
+	assert.Equal(t, Array, ipRules.Kind)
+	require.Len(t, ipRules.Children, 1)
+	assert.Equal(t, "0", ipRules.Children[0].Key)
+	assert.Equal(t, ".properties.ipRules[0].value", ipRules.Children[0].Children[0].Path)
+}
+
+func TestParseRootArray(t *testing.T) {
+	root, err := Parse([]byte(`[{"my key": 1}]`))
+	require.NoError(t, err)
+	assert.Equal(t, ".[0]", root.Children[0].Path)
+	assert.Equal(t, `.[0]["my key"]`, root.Children[0].Children[0].Path)
+
+	root, err = Parse([]byte(`{"@odata.type": "x"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `.["@odata.type"]`, root.Children[0].Path)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{"", "{", `{"a": 1} {}`, `{"a": }`} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root, err := Parse([]byte(testDocument))
+	require.NoError(t, err)
+
+	var paths []string
+	for _, node := range Search(root, "IP") {
+		paths = append(paths, node.Path)
+	}
+	assert.Equal(t, []string{".properties.ipRules"}, paths)
+
+	paths = nil
+	for _, node := range Search(root, "203.0") {
+		paths = append(paths, node.Path)
+	}
+	assert.Equal(t, []string{".properties.ipRules[0].value"}, paths)
+
+	assert.Empty(t, Search(root, ""))
+}
+
+func TestYAML(t *testing.T) {
+	root, err := Parse([]byte(testDocument))
+	require.NoError(t, err)
+
+	text, err := root.YAML()
+	require.NoError(t, err)
+	assert.Equal(t, `name: kv-1
+tags:
+  cost-center: "42"
+  env: "true"
+properties:
+  enabled: true
+  retentionDays: 90
+  ratio: 0.5
+  deletedAt: null
+  ipRules:
+    - value: 203.0.113.10/32
+  empty: {}
+`, text)
+}
@@ -14,6 +14,17 @@ type KeyVault struct {
 	EnabledForDeploy  bool
 	EnabledForDisk    bool
 	EnabledForTemplate bool
+	SoftDeleteEnabled bool
+	SoftDeleteRetentionDays int32
+	PurgeProtectionEnabled bool
+	RBACAuthorization bool            // Data plane access is granted by Azure RBAC instead of access policies
+	AccessPolicies    []*AccessPolicy // Only used when RBACAuthorization is off
+	PublicNetworkAccess string // Enabled or Disabled
+	NetworkDefaultAction string // Allow or Deny for traffic not matched by a network rule
+	NetworkBypass     string   // AzureServices lets trusted Azure services through the firewall
+	IPRules           []string // IPv4 addresses and CIDR ranges allowed through the firewall
+	VirtualNetworkRules []string // Subnet resource IDs allowed through the firewall
+	PrivateEndpointConnections []*PrivateEndpointConnection
 	Tags              map[string]*string
 	Properties        map[string]interface{}
 }
@@ -43,6 +54,9 @@ type Key struct {
 	NotBefore *time.Time
 	Version   string
 	Tags      map[string]string
+	KeySize   int      // RSA modulus size in bits; only populated by key details
+	Curve     string   // EC curve such as P-256; only populated by key details
+	KeyOps    []string // Permitted operations; only populated by key details
 }
 
 // Certificate represents a Key Vault certificate
@@ -60,3 +74,301 @@ type Certificate struct {
 	ContentType string
 	Tags        map[string]string
 }
+
+// Key Vault item types, as used by deleted items and lifecycle operations
+const (
+	VaultItemSecret      = "secret"
+	VaultItemKey         = "key"
+	VaultItemCertificate = "certificate"
+)
+
+// DeletedItem represents a soft-deleted secret, key or certificate that can be recovered or purged
+type DeletedItem struct {
+	Type               string // One of the VaultItem constants
+	Name               string
+	RecoveryID         string
+	DeletedDate        *time.Time
+	ScheduledPurgeDate *time.Time
+	Enabled            bool
+	Tags               map[string]string
+}
+
+// VaultItemRef identifies a secret, key or certificate in a Key Vault
+type VaultItemRef struct {
+	Type string // One of the VaultItem constants
+	Name string
+}
+
+// BackupManifest describes a local Key Vault backup directory. The item files hold the
+// opaque backup blobs returned by Key Vault, which are encrypted by the service and can only
+// be restored into a vault in the same subscription and Azure geography.
+type BackupManifest struct {
+	FormatVersion int                  `json:"formatVersion"`
+	Vault         string               `json:"vault"`
+	VaultURL      string               `json:"vaultUrl"`
+	Created       time.Time            `json:"created"`
+	Encryption    string               `json:"encryption"` // Who encrypted the item files; see BackupEncryptionService
+	Items         []BackupManifestItem `json:"items"`
+}
+
+// BackupEncryptionService records in a backup manifest that the item files are encrypted by Key Vault
+// only, and are written to disk without any local encryption
+const BackupEncryptionService = "key-vault-service"
+
+// BackupManifestItem describes one backed up item in a backup manifest
+type BackupManifestItem struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	File   string `json:"file"` // Relative to the backup directory
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ItemResult is the outcome of backing up, restoring or copying one item
+type ItemResult struct {
+	Item  VaultItemRef
+	Error string // Empty when the item succeeded
+}
+
+// ItemProgress reports the state of an operation on several Key Vault items, such as a backup, restore or copy
+type ItemProgress struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Results   []ItemResult
+}
+
+// Secret sync statuses, comparing a source vault with a target vault
+const (
+	SyncStatusMissing    = "Missing in target"
+	SyncStatusDifferent  = "Different"
+	SyncStatusTargetOnly = "Only in target"
+	SyncStatusIdentical  = "Identical"
+)
+
+// SecretDiff compares a secret between a source and a target vault. Values are never kept;
+// when values are compared only their SHA-256 hashes are held while the diff is computed.
+type SecretDiff struct {
+	Name        string
+	Status      string   // One of the SyncStatus constants
+	Source      *Secret  // nil when the secret only exists in the target
+	Target      *Secret  // nil when the secret is missing in the target
+	Differences []string // Properties that differ, such as "content type", "tags" or "value"
+}
+
+// Key operations, as named by Key Vault
+const (
+	KeyOperationEncrypt   = "encrypt"
+	KeyOperationDecrypt   = "decrypt"
+	KeyOperationSign      = "sign"
+	KeyOperationVerify    = "verify"
+	KeyOperationWrapKey   = "wrapKey"
+	KeyOperationUnwrapKey = "unwrapKey"
+)
+
+// KeyCryptoRequest is one cryptographic operation with a Key Vault key
+type KeyCryptoRequest struct {
+	Operation string // One of the KeyOperation constants
+	Algorithm string
+	Input     []byte // Plaintext, ciphertext or key to wrap or unwrap; for sign and verify, the message to hash
+	Signature []byte // Only used to verify
+}
+
+// KeyCryptoResult is the result of a cryptographic operation
+type KeyCryptoResult struct {
+	KeyID    string // Key version that performed the operation
+	Output   []byte // Empty for verify
+	Verified bool   // Only set by verify
+}
+
+// NewKey describes a key to create in a Key Vault
+type NewKey struct {
+	Name    string
+	KeyType string // RSA, RSA-HSM, EC or EC-HSM
+	KeySize int    // RSA only
+	Curve   string // EC only
+	KeyOps  []string
+	Expires *time.Time
+	Tags    map[string]string
+}
+
+// KeyRotationPolicy is the automatic rotation policy of a key. Durations are ISO 8601, such as P90D;
+// empty durations are not set.
+type KeyRotationPolicy struct {
+	ExpiryTime         string // Expiry of new key versions
+	RotateAfterCreate  string
+	RotateBeforeExpiry string
+	NotifyBeforeExpiry string
+	Updated            *time.Time
+}
+
+// NewCertificate describes a certificate to create in a Key Vault, and the policy used to issue and renew it
+type NewCertificate struct {
+	Name            string
+	Subject         string   // X.500 distinguished name such as CN=www.contoso.com
+	DNSNames        []string // Subject alternative names
+	Emails          []string // Subject alternative names
+	Issuer          string   // Self, Unknown for a CSR signed outside Key Vault, or a configured issuer
+	KeyType         string   // RSA, RSA-HSM, EC or EC-HSM
+	KeySize         int      // RSA only
+	Curve           string   // EC only
+	Exportable      bool
+	ContentType     string // Secret format: application/x-pkcs12 or application/x-pem-file
+	ValidityMonths  int
+	AutoRenew       bool // Renew at RenewPercentage of the lifetime; otherwise only email the contacts
+	RenewPercentage int
+	Tags            map[string]string
+}
+
+// CertificateOperation is the state of a pending or completed certificate issuance
+type CertificateOperation struct {
+	Status                string // inProgress, completed, failed or cancelled
+	StatusDetails         string
+	Issuer                string
+	RequestID             string
+	Target                string
+	CSR                   []byte // DER encoded certificate signing request
+	Error                 string
+	CancellationRequested bool
+}
+
+// Expiry statuses, from the most to the least urgent
+const (
+	ExpiryStatusExpired  = "Expired"
+	ExpiryStatusCritical = "Critical" // Expires within a week
+	ExpiryStatusWarning  = "Warning"  // Expires within 30 days
+	ExpiryStatusUpcoming = "Upcoming"
+)
+
+// ExpiryItem is a secret, key or certificate that has expired or expires soon
+type ExpiryItem struct {
+	SubscriptionID   string    `json:"subscriptionId"`
+	SubscriptionName string    `json:"subscriptionName"`
+	ResourceGroup    string    `json:"resourceGroup"`
+	Vault            string    `json:"vault"`
+	Type             string    `json:"type"` // One of the VaultItem constants
+	Name             string    `json:"name"`
+	Enabled          bool      `json:"enabled"`
+	Expires          time.Time `json:"expires"`
+	DaysLeft         int       `json:"daysLeft"` // Negative once expired
+	Status           string    `json:"status"`   // One of the ExpiryStatus constants
+}
+
+// ExpiryReport lists the items expiring within a number of days across Key Vaults. Vaults that
+// could not be read are listed in Errors rather than failing the report.
+type ExpiryReport struct {
+	Generated     time.Time     `json:"generated"`
+	WithinDays    int           `json:"withinDays"`
+	VaultsScanned int           `json:"vaultsScanned"`
+	Items         []*ExpiryItem `json:"items"`
+	Errors        []string      `json:"errors,omitempty"`
+}
+
+// AccessPolicy grants an identity permissions in a vault that uses the access policy model
+type AccessPolicy struct {
+	TenantID      string
+	ObjectID      string
+	ApplicationID string // Set for a compound identity: the application acting on behalf of the object
+	DisplayName   string // Resolved through Microsoft Graph; empty when it could not be resolved
+	PrincipalType string
+	Secrets       []string
+	Keys          []string
+	Certificates  []string
+}
+
+// RoleAssignment grants a principal an Azure role at the vault scope or inherited from above it
+type RoleAssignment struct {
+	ID               string
+	PrincipalID      string
+	PrincipalType    string
+	DisplayName      string // Resolved through Microsoft Graph; empty when it could not be resolved
+	RoleDefinitionID string
+	RoleName         string
+	Scope            string
+	Inherited        bool // Assigned at the resource group, subscription or management group
+}
+
+// VaultCapability is an operation the current identity can or cannot perform in a vault
+type VaultCapability struct {
+	Name    string
+	Allowed bool
+}
+
+// VaultAccess describes how access to a vault is granted and what the current identity can do there.
+// Parts that could not be read are explained in Notes rather than failing the whole lookup.
+type VaultAccess struct {
+	Vault           *KeyVault
+	RBAC            bool
+	AccessPolicies  []*AccessPolicy
+	RoleAssignments []*RoleAssignment
+	Identity        *UserInfo
+	Capabilities    []VaultCapability // Nil when the effective permissions of the identity are unknown
+	Notes           []string
+}
+
+// PrivateEndpointConnection links a private endpoint to a vault
+type PrivateEndpointConnection struct {
+	Name              string
+	PrivateEndpointID string
+	Status            string // Pending, Approved, Rejected or Disconnected
+	Description       string
+	ProvisioningState string
+}
+
+// VaultConfig holds the editable configuration of a Key Vault
+type VaultConfig struct {
+	PublicNetworkAccess          string
+	NetworkDefaultAction         string
+	NetworkBypass                string
+	IPRules                      []string
+	VirtualNetworkRules          []string
+	PurgeProtection              bool
+	EnabledForDeployment         bool
+	EnabledForDiskEncryption     bool
+	EnabledForTemplateDeployment bool
+}
+
+// VaultConfigChange is one difference between two vault configurations. From is empty for an added
+// rule and To is empty for a removed rule.
+type VaultConfigChange struct {
+	Setting string
+	From    string
+	To      string
+}
+
+// Sources of secret audit events
+const (
+	AuditSourceVault          = "Key Vault"
+	AuditSourceActivityLog    = "Activity Log"
+	AuditSourceDiagnosticLogs = "Diagnostic logs"
+)
+
+// SecretAuditEvent is an operation on a secret recorded in the Activity Log or the vault's diagnostic logs
+type SecretAuditEvent struct {
+	Time      time.Time
+	Operation string
+	Version   string // Empty when the operation is not tied to a version
+	Caller    string
+	CallerIP  string
+	Result    string
+	Source    string
+}
+
+// SecretHistory is the timeline of a secret, newest first: the creation and updates of its versions,
+// attributed to a caller when a matching audit event was found, and the other audited operations.
+// Audit sources that could not be read are explained in Notes.
+type SecretHistory struct {
+	Name     string
+	Versions []*Secret
+	Timeline []*SecretAuditEvent
+	Notes    []string
+}
+
+// SecretVersionComparison tells whether two versions of a secret hold the same value. Values are
+// never kept; only their SHA-256 hashes are compared.
+type SecretVersionComparison struct {
+	Name     string
+	VersionA string
+	VersionB string
+	Changed  bool
+}
@@ -4,21 +4,91 @@ import "time"
 
 // Container represents a storage container
 type Container struct {
-	Name         string
-	LastModified time.Time
-	ETag         string
-	PublicAccess string
-	Metadata     map[string]string
+	Name                  string
+	LastModified          time.Time
+	ETag                  string
+	PublicAccess          string
+	Metadata              map[string]string
+	HasImmutabilityPolicy bool
+	HasLegalHold          bool
+}
+
+// StoredAccessPolicy represents a stored access policy (signed identifier) on a container
+type StoredAccessPolicy struct {
+	ID         string
+	Permission string // Permission letters, e.g. "rwdl"
+	Start      *time.Time
+	Expiry     *time.Time
+}
+
+// ContainerProtection describes the legal hold and immutability settings of a container.
+// These are only available through Azure Resource Manager.
+type ContainerProtection struct {
+	HasLegalHold               bool
+	LegalHoldTags              []string
+	HasImmutabilityPolicy      bool
+	ImmutabilityPeriodDays     int32
+	ImmutabilityState          string // Locked or Unlocked
+	AllowProtectedAppendWrites bool
+	ImmutabilityETag           string
 }
 
 // Blob represents a blob in a storage container
 type Blob struct {
-	Name         string
-	DisplayName  string // Display name (without prefix path)
-	Size         int64
-	ContentType  string
-	LastModified time.Time
-	ETag         string
-	Metadata     map[string]string
-	IsDirectory  bool
+	Name               string
+	DisplayName        string // Display name (without prefix path)
+	Size               int64
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+	AccessTier         string // Hot, Cool, Cold or Archive
+	ArchiveStatus      string // Set while an archived blob is being rehydrated, e.g. "rehydrate-pending-to-hot"
+	RehydratePriority  string // Standard or High, set while rehydrating
+	LastModified       time.Time
+	ETag               string
+	Metadata           map[string]string
+	IsDirectory        bool
+}
+
+// StorageEndpoint describes a blob service that is reached directly rather than
+// through Azure Resource Manager (e.g. the Azurite emulator or a SAS URL)
+type StorageEndpoint struct {
+	ServiceURL  string // Blob service URL, always ending with "/"
+	AccountName string
+	AccountKey  string // Shared key, empty when using SAS or anonymous access
+	SASToken    string // SAS query string without the leading "?"
+}
+
+// BlobLocation identifies a blob, or a folder prefix of blobs, in a storage account
+type BlobLocation struct {
+	SubscriptionID string
+	ResourceGroup  string
+	StorageAccount string
+	Container      string
+	Path           string // Blob name, or folder prefix ending with "/" (empty for the container root)
+}
+
+// BlobSearchQuery describes a container-wide blob search.
+// Unset bounds and an empty pattern match every blob.
+type BlobSearchQuery struct {
+	Prefix         string // Only search below this folder
+	Pattern        string // Glob, or a regular expression when UseRegex is set
+	UseRegex       bool
+	MinSize        *int64 // Inclusive
+	MaxSize        *int64 // Inclusive
+	ModifiedAfter  *time.Time
+	ModifiedBefore *time.Time
+	TagFilter      string // Blob index tag expression, e.g. "project" = 'alpha'
+}
+
+// CopyProgress reports the state of a server-side copy or move of several blobs
+type CopyProgress struct {
+	Total       int
+	Succeeded   int
+	Failed      int
+	Pending     int
+	Deleted     int // Source blobs deleted after a verified move
+	BytesCopied int64
+	BytesTotal  int64
+	Errors      []string
 }
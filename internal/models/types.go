@@ -23,12 +23,51 @@ type Resource struct {
 	Type          string
 	Location      string
 	ResourceGroup string
+	Kind          string
+	SKU           map[string]interface{} // SKU fields such as name and tier; nil when the resource has no SKU
 	Tags          map[string]*string
 	Properties    map[string]interface{} // Generic properties
 }
 
+// ResourceSearch holds the criteria of a search across subscriptions. Empty criteria match every resource.
+type ResourceSearch struct {
+	Name     string // Part of the resource name
+	Type     string // Part of the resource type, such as storageAccounts or Microsoft.Storage/storageAccounts
+	Tag      string // Tag name, or name=value for a tag with that value
+	Location string // Location name, such as westeurope
+}
+
+// ResourceSearchResult is a resource found by a search, with the subscription it belongs to
+type ResourceSearchResult struct {
+	Resource         *Resource
+	SubscriptionID   string
+	SubscriptionName string // Empty when the subscription is not in the subscriptions list
+}
+
 // ResourceTypeSummary represents a summary of resources by type
 type ResourceTypeSummary struct {
 	Type  string
 	Count int
 }
+
+// GraphColumn is a column of a Resource Graph query result
+type GraphColumn struct {
+	Name string
+	Type string // Kusto type, such as string, long or dynamic
+}
+
+// GraphQueryResult is a page of the result of a Resource Graph query
+type GraphQueryResult struct {
+	Columns      []GraphColumn
+	Rows         [][]interface{}
+	TotalRecords int64
+	SkipToken    string // Continues with the next page; empty on the last page
+	Truncated    bool   // The result was cut to the page size because it cannot be paged, as it has no id column
+}
+
+// ResourceDocument is the full ARM representation of a resource
+type ResourceDocument struct {
+	ID         string
+	APIVersion string // API version the document was read with
+	JSON       []byte
+}
@@ -5,5 +5,7 @@ type UserInfo struct {
 	Name     string
 	Email    string
 	TenantID string
+	ObjectID string // Object ID of the signed-in user or service principal
+	AppID    string // Application ID when signed in as a service principal
 }
 
@@ -1,5 +1,7 @@
 package navigation
 
+import "strings"
+
 // ViewType represents the current view type
 type ViewType int
 
@@ -10,13 +12,19 @@ const (
 	ViewResources
 	ViewResourceType
 	ViewDetails
-	ViewStorageExplorer
 	ViewBlobs
-	ViewKeyVaultExplorer
 	ViewKeyVaultSecrets
 	ViewKeyVaultKeys
 	ViewKeyVaultCertificates
 	ViewMenu
+	ViewBlobSearch
+	ViewKeyVaultSecretVersions
+	ViewKeyVaultDeletedItems
+	ViewKeyVaultSecretSync
+	ViewKeyVaultExpiry
+	ViewExplorer       // Explorer view provided by a resource handler, such as the storage and Key Vault explorers
+	ViewGraphQuery     // Resource Graph query console
+	ViewResourceSearch // Resources found by a search across subscriptions
 )
 
 // State manages navigation state
@@ -28,11 +36,17 @@ type State struct {
 	SelectedResourceType      string
 	InDetailsView             bool
 	SelectedStorageAccount    string
+	SelectedStorageRG         string // Resource group of the selected storage account, which may differ from SelectedResourceGroupName when browsing a whole subscription
 	SelectedContainer         string
 	SelectedBlob              string
 	BlobPathPrefix            string // Current folder path prefix in blob view
 	SelectedKeyVault          string
 	SelectedKeyVaultURL       string
+	SelectedKeyVaultRG        string // Resource group of the selected Key Vault, used to read its vault properties
+	SelectedSecret            string
+	StorageEndpointMode       bool     // Storage explorer opened directly on a blob endpoint, without ARM
+	GraphQueryReturnView      ViewType // View the Resource Graph console was opened from, shown again on ESC
+	SearchReturnView          ViewType // View the resource search was opened from, shown again on ESC
 }
 
 // NewState creates a new navigation state
@@ -95,13 +109,21 @@ func (s *State) NavigateBackFromDetails() {
 	s.InDetailsView = false
 }
 
-// NavigateToStorageExplorer navigates to the storage explorer view
-func (s *State) NavigateToStorageExplorer(storageAccountName string) {
-	s.CurrentView = ViewStorageExplorer
+// SelectStorageAccount selects the storage account the storage explorer is opened on. The explorer
+// itself is shown with NavigateToExplorer.
+func (s *State) SelectStorageAccount(storageAccountName, resourceGroupName string) {
 	s.SelectedStorageAccount = storageAccountName
+	s.SelectedStorageRG = resourceGroupName
 	s.SelectedContainer = ""
 	s.SelectedBlob = ""
-	s.InDetailsView = false
+	s.BlobPathPrefix = ""
+}
+
+// SelectStorageEndpoint selects the account of a direct blob endpoint. The storage explorer becomes
+// the root view since there is no ARM hierarchy above it.
+func (s *State) SelectStorageEndpoint(storageAccountName string) {
+	s.SelectStorageAccount(storageAccountName, "")
+	s.StorageEndpointMode = true
 }
 
 // NavigateToBlobs navigates to the blobs view for a container
@@ -115,7 +137,7 @@ func (s *State) NavigateToBlobs(containerName string) {
 
 // NavigateBackFromBlobs returns from blobs view to storage explorer
 func (s *State) NavigateBackFromBlobs() {
-	s.CurrentView = ViewStorageExplorer
+	s.CurrentView = ViewExplorer
 	s.SelectedContainer = ""
 	s.SelectedBlob = ""
 	s.BlobPathPrefix = ""
@@ -152,6 +174,30 @@ func (s *State) NavigateBackFromBlobFolder() {
 	s.SelectedBlob = ""
 }
 
+// NavigateToBlobSearch navigates to the container-wide blob search results
+func (s *State) NavigateToBlobSearch() {
+	s.CurrentView = ViewBlobSearch
+	s.SelectedBlob = ""
+	s.InDetailsView = false
+}
+
+// NavigateBackFromBlobSearch returns from search results to the blobs view at the folder the search started from
+func (s *State) NavigateBackFromBlobSearch() {
+	s.CurrentView = ViewBlobs
+	s.SelectedBlob = ""
+}
+
+// NavigateToBlobSearchResult opens the folder containing a search result in the blobs view
+func (s *State) NavigateToBlobSearchResult(blobName string) {
+	s.CurrentView = ViewBlobs
+	s.BlobPathPrefix = ""
+	if lastSlash := strings.LastIndex(blobName, "/"); lastSlash >= 0 {
+		s.BlobPathPrefix = blobName[:lastSlash+1]
+	}
+	s.SelectedBlob = blobName
+	s.InDetailsView = false
+}
+
 // NavigateToMenu navigates to the menu view
 func (s *State) NavigateToMenu() {
 	s.CurrentView = ViewMenu
@@ -165,12 +211,13 @@ func (s *State) NavigateBackFromMenu() {
 	s.NavigateToSubscriptions()
 }
 
-// NavigateToKeyVaultExplorer navigates to the Key Vault explorer view
-func (s *State) NavigateToKeyVaultExplorer(keyVaultName, vaultURL string) {
-	s.CurrentView = ViewKeyVaultExplorer
+// SelectKeyVault selects the Key Vault the Key Vault explorer is opened on. The explorer itself is
+// shown with NavigateToExplorer.
+func (s *State) SelectKeyVault(keyVaultName, vaultURL, resourceGroupName string) {
 	s.SelectedKeyVault = keyVaultName
 	s.SelectedKeyVaultURL = vaultURL
-	s.InDetailsView = false
+	s.SelectedKeyVaultRG = resourceGroupName
+	s.SelectedSecret = ""
 }
 
 // NavigateToKeyVaultSecrets navigates to the secrets view for a Key Vault
@@ -193,15 +240,104 @@ func (s *State) NavigateToKeyVaultCertificates() {
 
 // NavigateBackFromKeyVaultSecrets returns from secrets view to Key Vault explorer
 func (s *State) NavigateBackFromKeyVaultSecrets() {
-	s.CurrentView = ViewKeyVaultExplorer
+	s.CurrentView = ViewExplorer
+}
+
+// NavigateToKeyVaultSecretVersions navigates to the versions view for a secret
+func (s *State) NavigateToKeyVaultSecretVersions(secretName string) {
+	s.CurrentView = ViewKeyVaultSecretVersions
+	s.SelectedSecret = secretName
+	s.InDetailsView = false
+}
+
+// NavigateBackFromKeyVaultSecretVersions returns from the versions view to the secrets view
+func (s *State) NavigateBackFromKeyVaultSecretVersions() {
+	s.CurrentView = ViewKeyVaultSecrets
+	s.SelectedSecret = ""
 }
 
 // NavigateBackFromKeyVaultKeys returns from keys view to Key Vault explorer
 func (s *State) NavigateBackFromKeyVaultKeys() {
-	s.CurrentView = ViewKeyVaultExplorer
+	s.CurrentView = ViewExplorer
 }
 
 // NavigateBackFromKeyVaultCertificates returns from certificates view to Key Vault explorer
 func (s *State) NavigateBackFromKeyVaultCertificates() {
-	s.CurrentView = ViewKeyVaultExplorer
+	s.CurrentView = ViewExplorer
+}
+
+// NavigateToKeyVaultDeletedItems navigates to the deleted items view for a Key Vault
+func (s *State) NavigateToKeyVaultDeletedItems() {
+	s.CurrentView = ViewKeyVaultDeletedItems
+	s.InDetailsView = false
+}
+
+// NavigateBackFromKeyVaultDeletedItems returns from deleted items view to Key Vault explorer
+func (s *State) NavigateBackFromKeyVaultDeletedItems() {
+	s.CurrentView = ViewExplorer
+}
+
+// NavigateToKeyVaultSecretSync navigates to the secret sync view comparing the Key Vault with another vault
+func (s *State) NavigateToKeyVaultSecretSync() {
+	s.CurrentView = ViewKeyVaultSecretSync
+	s.InDetailsView = false
+}
+
+// NavigateBackFromKeyVaultSecretSync returns from secret sync view to Key Vault explorer
+func (s *State) NavigateBackFromKeyVaultSecretSync() {
+	s.CurrentView = ViewExplorer
+}
+
+// NavigateToKeyVaultExpiry navigates to the Key Vault expiry report, which spans subscriptions
+func (s *State) NavigateToKeyVaultExpiry() {
+	s.CurrentView = ViewKeyVaultExpiry
+	s.InDetailsView = false
+}
+
+// NavigateBackFromKeyVaultExpiry returns from the expiry report to the subscriptions view
+func (s *State) NavigateBackFromKeyVaultExpiry() {
+	s.NavigateToSubscriptions()
+}
+
+// NavigateToExplorer navigates to the explorer view of a resource handler
+func (s *State) NavigateToExplorer() {
+	s.CurrentView = ViewExplorer
+	s.InDetailsView = false
+}
+
+// NavigateToGraphQuery navigates to the Resource Graph query console, remembering the view it was opened from
+func (s *State) NavigateToGraphQuery() {
+	if s.CurrentView != ViewGraphQuery {
+		s.GraphQueryReturnView = s.CurrentView
+	}
+	s.CurrentView = ViewGraphQuery
+	s.InDetailsView = false
+}
+
+// NavigateBackFromGraphQuery returns from the Resource Graph query console to the view it was opened from
+func (s *State) NavigateBackFromGraphQuery() {
+	s.CurrentView = s.GraphQueryReturnView
+}
+
+// NavigateToResourceSearch navigates to the resource search results, remembering the view the search
+// was opened from
+func (s *State) NavigateToResourceSearch() {
+	if s.CurrentView != ViewResourceSearch {
+		s.SearchReturnView = s.CurrentView
+	}
+	s.CurrentView = ViewResourceSearch
+	s.InDetailsView = false
+}
+
+// NavigateBackFromResourceSearch returns from the search results to the view the search was opened from
+func (s *State) NavigateBackFromResourceSearch() {
+	s.CurrentView = s.SearchReturnView
+}
+
+// NavigateToSearchResult navigates to the resource type list that holds a resource found by a search,
+// selecting its subscription and resource group as if the user had browsed to it
+func (s *State) NavigateToSearchResult(subscriptionID, subscriptionName, resourceGroupName, resourceType string) {
+	s.NavigateToResourceGroups(subscriptionID, subscriptionName)
+	s.NavigateToResourceTypes(resourceGroupName)
+	s.NavigateToResourceType(resourceType)
 }
@@ -125,27 +125,52 @@ func TestNavigateBackFromDetails(t *testing.T) {
 	assert.Equal(t, ViewResourceGroups, state.CurrentView)
 }
 
-func TestNavigateToStorageExplorer(t *testing.T) {
+func TestSelectStorageAccount(t *testing.T) {
 	state := &State{
 		CurrentView:            ViewResourceType,
 		SelectedStorageAccount: "old-account",
 		SelectedContainer:      "old-container",
 		SelectedBlob:           "old-blob",
-		InDetailsView:          true,
+		BlobPathPrefix:         "old/path/",
 	}
 
-	state.NavigateToStorageExplorer("new-storage-account")
+	state.SelectStorageAccount("new-storage-account", "rg-storage")
 
-	assert.Equal(t, ViewStorageExplorer, state.CurrentView)
+	// The explorer is shown by NavigateToExplorer, so the view is unchanged
+	assert.Equal(t, ViewResourceType, state.CurrentView)
 	assert.Equal(t, "new-storage-account", state.SelectedStorageAccount)
+	assert.Equal(t, "rg-storage", state.SelectedStorageRG)
 	assert.Empty(t, state.SelectedContainer)
 	assert.Empty(t, state.SelectedBlob)
+	assert.Empty(t, state.BlobPathPrefix)
+
+	state.NavigateToExplorer()
+	assert.Equal(t, ViewExplorer, state.CurrentView)
+}
+
+func TestSelectStorageEndpoint(t *testing.T) {
+	state := NewState()
+
+	state.SelectStorageEndpoint("devstoreaccount1")
+	state.NavigateToExplorer()
+
+	assert.Equal(t, ViewExplorer, state.CurrentView)
+	assert.Equal(t, "devstoreaccount1", state.SelectedStorageAccount)
+	assert.True(t, state.StorageEndpointMode)
+	assert.Empty(t, state.SelectedSubscriptionID)
+	assert.Empty(t, state.SelectedContainer)
 	assert.False(t, state.InDetailsView)
+
+	// Blob navigation keeps endpoint mode
+	state.NavigateToBlobs("container")
+	state.NavigateBackFromBlobs()
+	assert.Equal(t, ViewExplorer, state.CurrentView)
+	assert.True(t, state.StorageEndpointMode)
 }
 
 func TestNavigateToBlobs(t *testing.T) {
 	state := &State{
-		CurrentView:            ViewStorageExplorer,
+		CurrentView:            ViewExplorer,
 		SelectedStorageAccount: "test-account",
 		SelectedContainer:      "old-container",
 		SelectedBlob:           "old-blob",
@@ -174,7 +199,7 @@ func TestNavigateBackFromBlobs(t *testing.T) {
 
 	state.NavigateBackFromBlobs()
 
-	assert.Equal(t, ViewStorageExplorer, state.CurrentView)
+	assert.Equal(t, ViewExplorer, state.CurrentView)
 	assert.Empty(t, state.SelectedContainer)
 	assert.Empty(t, state.SelectedBlob)
 	assert.Empty(t, state.BlobPathPrefix)
@@ -230,7 +255,7 @@ func TestNavigateBackFromBlobFolder_FromRoot(t *testing.T) {
 	state.NavigateBackFromBlobFolder()
 
 	// Should navigate back to storage explorer
-	assert.Equal(t, ViewStorageExplorer, state.CurrentView)
+	assert.Equal(t, ViewExplorer, state.CurrentView)
 	assert.Empty(t, state.SelectedContainer)
 	assert.Empty(t, state.BlobPathPrefix)
 }
@@ -264,7 +289,7 @@ func TestNavigateBackFromBlobFolder_ComplexPath(t *testing.T) {
 			name:         "Root level",
 			initialPath:  "",
 			expectedPath: "",
-			expectedView: ViewStorageExplorer,
+			expectedView: ViewExplorer,
 		},
 	}
 
@@ -285,6 +310,208 @@ func TestNavigateBackFromBlobFolder_ComplexPath(t *testing.T) {
 	}
 }
 
+func TestNavigateToBlobSearch(t *testing.T) {
+	state := &State{
+		CurrentView:       ViewBlobs,
+		SelectedContainer: "test-container",
+		BlobPathPrefix:    "folder/",
+		SelectedBlob:      "folder/file.txt",
+		InDetailsView:     true,
+	}
+
+	state.NavigateToBlobSearch()
+
+	assert.Equal(t, ViewBlobSearch, state.CurrentView)
+	assert.Equal(t, "test-container", state.SelectedContainer)
+	assert.Equal(t, "folder/", state.BlobPathPrefix, "Search starts from the current folder")
+	assert.Empty(t, state.SelectedBlob)
+	assert.False(t, state.InDetailsView)
+
+	state.NavigateBackFromBlobSearch()
+
+	assert.Equal(t, ViewBlobs, state.CurrentView)
+	assert.Equal(t, "folder/", state.BlobPathPrefix)
+}
+
+func TestNavigateToBlobSearchResult(t *testing.T) {
+	tests := []struct {
+		name           string
+		blobName       string
+		expectedPrefix string
+	}{
+		{name: "root blob", blobName: "file.txt", expectedPrefix: ""},
+		{name: "nested blob", blobName: "a/b/file.txt", expectedPrefix: "a/b/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &State{
+				CurrentView:    ViewBlobSearch,
+				BlobPathPrefix: "other/",
+			}
+
+			state.NavigateToBlobSearchResult(tt.blobName)
+
+			assert.Equal(t, ViewBlobs, state.CurrentView)
+			assert.Equal(t, tt.expectedPrefix, state.BlobPathPrefix)
+			assert.Equal(t, tt.blobName, state.SelectedBlob)
+		})
+	}
+}
+
+func TestNavigateToKeyVaultSecretVersions(t *testing.T) {
+	state := &State{
+		CurrentView:      ViewKeyVaultSecrets,
+		SelectedKeyVault: "test-vault",
+		InDetailsView:    true,
+	}
+
+	state.NavigateToKeyVaultSecretVersions("db-password")
+
+	assert.Equal(t, ViewKeyVaultSecretVersions, state.CurrentView)
+	assert.Equal(t, "db-password", state.SelectedSecret)
+	assert.False(t, state.InDetailsView)
+
+	state.NavigateBackFromKeyVaultSecretVersions()
+
+	assert.Equal(t, ViewKeyVaultSecrets, state.CurrentView)
+	assert.Empty(t, state.SelectedSecret)
+	assert.Equal(t, "test-vault", state.SelectedKeyVault, "Key Vault should be preserved")
+}
+
+func TestNavigateToKeyVaultDeletedItems(t *testing.T) {
+	state := &State{
+		CurrentView:         ViewExplorer,
+		SelectedKeyVault:    "test-vault",
+		SelectedKeyVaultURL: "https://test-vault.vault.azure.net/",
+		InDetailsView:       true,
+	}
+
+	state.NavigateToKeyVaultDeletedItems()
+
+	assert.Equal(t, ViewKeyVaultDeletedItems, state.CurrentView)
+	assert.False(t, state.InDetailsView)
+
+	state.NavigateBackFromKeyVaultDeletedItems()
+
+	assert.Equal(t, ViewExplorer, state.CurrentView)
+	assert.Equal(t, "test-vault", state.SelectedKeyVault, "Key Vault should be preserved")
+	assert.Equal(t, "https://test-vault.vault.azure.net/", state.SelectedKeyVaultURL)
+}
+
+func TestNavigateToKeyVaultSecretSync(t *testing.T) {
+	state := &State{
+		CurrentView:      ViewExplorer,
+		SelectedKeyVault: "test-vault",
+		InDetailsView:    true,
+	}
+
+	state.NavigateToKeyVaultSecretSync()
+
+	assert.Equal(t, ViewKeyVaultSecretSync, state.CurrentView)
+	assert.False(t, state.InDetailsView)
+
+	state.NavigateBackFromKeyVaultSecretSync()
+
+	assert.Equal(t, ViewExplorer, state.CurrentView)
+	assert.Equal(t, "test-vault", state.SelectedKeyVault, "Key Vault should be preserved")
+}
+
+func TestNavigateToKeyVaultExpiry(t *testing.T) {
+	state := &State{
+		CurrentView:            ViewSubscriptions,
+		SelectedSubscriptionID: "sub-123",
+		InDetailsView:          true,
+	}
+
+	state.NavigateToKeyVaultExpiry()
+
+	assert.Equal(t, ViewKeyVaultExpiry, state.CurrentView)
+	assert.False(t, state.InDetailsView)
+
+	state.NavigateBackFromKeyVaultExpiry()
+
+	assert.Equal(t, ViewSubscriptions, state.CurrentView)
+	assert.Empty(t, state.SelectedSubscriptionID)
+}
+
+func TestNavigateToExplorer(t *testing.T) {
+	state := &State{
+		CurrentView:          ViewResourceType,
+		SelectedResourceType: "Microsoft.Web/sites",
+		InDetailsView:        true,
+	}
+
+	state.NavigateToExplorer()
+
+	assert.Equal(t, ViewExplorer, state.CurrentView)
+	assert.Equal(t, "Microsoft.Web/sites", state.SelectedResourceType)
+	assert.False(t, state.InDetailsView)
+}
+
+func TestNavigateToGraphQuery(t *testing.T) {
+	state := &State{
+		CurrentView:               ViewResourceTypes,
+		SelectedSubscriptionID:    "sub-123",
+		SelectedResourceGroupName: "test-rg",
+		InDetailsView:             true,
+	}
+
+	state.NavigateToGraphQuery()
+
+	assert.Equal(t, ViewGraphQuery, state.CurrentView)
+	assert.False(t, state.InDetailsView)
+
+	// Running another query from the console keeps the view to return to
+	state.NavigateToGraphQuery()
+	assert.Equal(t, ViewResourceTypes, state.GraphQueryReturnView)
+
+	state.NavigateBackFromGraphQuery()
+
+	assert.Equal(t, ViewResourceTypes, state.CurrentView)
+	assert.Equal(t, "sub-123", state.SelectedSubscriptionID, "Subscription should be preserved")
+	assert.Equal(t, "test-rg", state.SelectedResourceGroupName, "Resource group should be preserved")
+}
+
+func TestNavigateToResourceSearch(t *testing.T) {
+	state := &State{
+		CurrentView:   ViewKeyVaultSecrets,
+		InDetailsView: true,
+	}
+
+	state.NavigateToResourceSearch()
+
+	assert.Equal(t, ViewResourceSearch, state.CurrentView)
+	assert.False(t, state.InDetailsView)
+
+	// Searching again from the results keeps the view to return to
+	state.NavigateToResourceSearch()
+	assert.Equal(t, ViewKeyVaultSecrets, state.SearchReturnView)
+
+	state.NavigateBackFromResourceSearch()
+
+	assert.Equal(t, ViewKeyVaultSecrets, state.CurrentView)
+}
+
+func TestNavigateToSearchResult(t *testing.T) {
+	state := &State{
+		CurrentView:               ViewResourceSearch,
+		SelectedSubscriptionID:    "sub-old",
+		SelectedSubscriptionName:  "Old Subscription",
+		SelectedResourceGroupName: "old-rg",
+		InDetailsView:             true,
+	}
+
+	state.NavigateToSearchResult("sub-123", "Test Subscription", "test-rg", "Microsoft.Storage/storageAccounts")
+
+	assert.Equal(t, ViewResourceType, state.CurrentView)
+	assert.Equal(t, "sub-123", state.SelectedSubscriptionID)
+	assert.Equal(t, "Test Subscription", state.SelectedSubscriptionName)
+	assert.Equal(t, "test-rg", state.SelectedResourceGroupName)
+	assert.Equal(t, "Microsoft.Storage/storageAccounts", state.SelectedResourceType)
+	assert.False(t, state.InDetailsView)
+}
+
 func TestNavigateToMenu(t *testing.T) {
 	state := &State{
 		CurrentView:   ViewResourceGroups,
@@ -317,18 +544,25 @@ func TestNavigateBackFromMenu(t *testing.T) {
 func TestViewTypeValues(t *testing.T) {
 	// Test that view type constants are unique
 	views := map[ViewType]string{
-		ViewSubscriptions:   "ViewSubscriptions",
-		ViewResourceGroups:  "ViewResourceGroups",
-		ViewResourceTypes:   "ViewResourceTypes",
-		ViewResources:       "ViewResources",
-		ViewResourceType:    "ViewResourceType",
-		ViewDetails:         "ViewDetails",
-		ViewStorageExplorer: "ViewStorageExplorer",
-		ViewBlobs:           "ViewBlobs",
-		ViewMenu:            "ViewMenu",
+		ViewSubscriptions:          "ViewSubscriptions",
+		ViewResourceGroups:         "ViewResourceGroups",
+		ViewResourceTypes:          "ViewResourceTypes",
+		ViewResources:              "ViewResources",
+		ViewResourceType:           "ViewResourceType",
+		ViewDetails:                "ViewDetails",
+		ViewBlobs:                  "ViewBlobs",
+		ViewMenu:                   "ViewMenu",
+		ViewBlobSearch:             "ViewBlobSearch",
+		ViewKeyVaultSecretVersions: "ViewKeyVaultSecretVersions",
+		ViewKeyVaultDeletedItems:   "ViewKeyVaultDeletedItems",
+		ViewKeyVaultSecretSync:     "ViewKeyVaultSecretSync",
+		ViewKeyVaultExpiry:         "ViewKeyVaultExpiry",
+		ViewExplorer:               "ViewExplorer",
+		ViewGraphQuery:             "ViewGraphQuery",
+		ViewResourceSearch:         "ViewResourceSearch",
 	}
 
-	assert.Len(t, views, 9, "All view types should be unique")
+	assert.Len(t, views, 16, "All view types should be unique")
 }
 
 func TestNavigationFlow_FullJourney(t *testing.T) {
@@ -353,8 +587,9 @@ func TestNavigationFlow_FullJourney(t *testing.T) {
 	assert.Equal(t, "Microsoft.Storage/storageAccounts", state.SelectedResourceType)
 
 	// Navigate to storage explorer
-	state.NavigateToStorageExplorer("mystorageaccount")
-	assert.Equal(t, ViewStorageExplorer, state.CurrentView)
+	state.SelectStorageAccount("mystorageaccount", "test-rg")
+	state.NavigateToExplorer()
+	assert.Equal(t, ViewExplorer, state.CurrentView)
 	assert.Equal(t, "mystorageaccount", state.SelectedStorageAccount)
 
 	// Navigate to blobs
@@ -380,9 +615,9 @@ func TestNavigationFlow_FullJourney(t *testing.T) {
 
 	// Navigate back to storage explorer
 	state.NavigateBackFromBlobFolder()
-	assert.Equal(t, ViewStorageExplorer, state.CurrentView)
+	assert.Equal(t, ViewExplorer, state.CurrentView)
 
 	// Navigate back to blobs
 	state.NavigateBackFromBlobs()
-	assert.Equal(t, ViewStorageExplorer, state.CurrentView)
+	assert.Equal(t, ViewExplorer, state.CurrentView)
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/clipboard"
 	"azure-control-tower/internal/models"
 	"azure-control-tower/internal/navigation"
 	"azure-control-tower/pkg/resource"
@@ -31,15 +33,30 @@ type App struct {
 	detailsView               *DetailsView
 	storageExplorerView       *StorageExplorerView
 	blobsView                 *BlobsView
+	blobSearchView            *BlobSearchView
 	keyVaultExplorerView      *KeyVaultExplorerView
 	keyVaultSecretsView       *KeyVaultSecretsView
+	keyVaultSecretVersionsView *KeyVaultSecretVersionsView
 	keyVaultKeysView          *KeyVaultKeysView
 	keyVaultCertificatesView  *KeyVaultCertificatesView
+	keyVaultDeletedItemsView  *KeyVaultDeletedItemsView
+	keyVaultSecretSyncView    *KeyVaultSecretSyncView
+	keyVaultExpiryView        *KeyVaultExpiryView
+	keyVaultProperties        *models.KeyVault // Soft delete settings of the selected vault; nil when they could not be read
+	explorerView              resource.ExplorerView // Explorer view opened by a resource handler
+	graphQueryView            *GraphQueryView
+	resourceSearchView        *ResourceSearchView
 	menuView                  *MenuView
 	filterMode          *FilterMode
 	mainFlex            *tview.Flex
 	currentView         tview.Primitive
+	dialogs             []dialogLayer
+	blobSearchCancel    context.CancelFunc
 	userInfo            *models.UserInfo
+	screen              tcell.Screen // Captured on every draw for OSC 52 clipboard access
+	clipboard           *clipboard.Clipboard
+	clipboardTimeout    time.Duration // Clear copied secret values after this long; 0 keeps them
+	clipboardPending    int           // Generation of the copied value still waiting to be cleared, 0 for none
 }
 
 // NewApp creates a new application instance
@@ -47,7 +64,7 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 	app := tview.NewApplication()
 
 	navState := navigation.NewState()
-	headerView := NewHeaderView()
+	headerView := NewHeaderView(registry)
 	breadcrumbView := NewBreadcrumbView()
 	viewTitleView := NewViewTitleView()
 	footerView := NewFooterView()
@@ -58,10 +75,17 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 	detailsView := NewDetailsView(registry)
 	storageExplorerView := NewStorageExplorerView()
 	blobsView := NewBlobsView()
+	blobSearchView := NewBlobSearchView()
 	keyVaultExplorerView := NewKeyVaultExplorerView()
 	keyVaultSecretsView := NewKeyVaultSecretsView()
+	keyVaultSecretVersionsView := NewKeyVaultSecretVersionsView()
 	keyVaultKeysView := NewKeyVaultKeysView()
 	keyVaultCertificatesView := NewKeyVaultCertificatesView()
+	keyVaultDeletedItemsView := NewKeyVaultDeletedItemsView()
+	keyVaultSecretSyncView := NewKeyVaultSecretSyncView()
+	keyVaultExpiryView := NewKeyVaultExpiryView()
+	graphQueryView := NewGraphQueryView()
+	resourceSearchView := NewResourceSearchView()
 	menuView := NewMenuView(registry)
 	filterMode := NewFilterMode(app)
 
@@ -84,16 +108,34 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 		detailsView:              detailsView,
 		storageExplorerView:      storageExplorerView,
 		blobsView:                blobsView,
+		blobSearchView:           blobSearchView,
 		keyVaultExplorerView:     keyVaultExplorerView,
 		keyVaultSecretsView:      keyVaultSecretsView,
+		keyVaultSecretVersionsView: keyVaultSecretVersionsView,
 		keyVaultKeysView:         keyVaultKeysView,
 		keyVaultCertificatesView: keyVaultCertificatesView,
+		keyVaultDeletedItemsView: keyVaultDeletedItemsView,
+		keyVaultSecretSyncView:   keyVaultSecretSyncView,
+		keyVaultExpiryView:       keyVaultExpiryView,
+		graphQueryView:           graphQueryView,
+		resourceSearchView:       resourceSearchView,
 		menuView:                 menuView,
 		filterMode:          filterMode,
 		mainFlex:            mainFlex,
 		currentView:         subscriptionsView,
+		clipboardTimeout:    DefaultClipboardTimeout,
 	}
 
+	// The screen is only reachable from draw callbacks; keep it for copying through the terminal
+	app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		a.screen = screen
+	})
+	a.clipboard = clipboard.New(func(data []byte) {
+		if a.screen != nil {
+			a.screen.SetClipboard(data)
+		}
+	})
+
 	// Set up subscriptions view callbacks
 	subscriptionsView.SetOnSelect(func(sub *models.Subscription) {
 		a.navigateToResourceGroups(sub.ID, sub.DisplayName)
@@ -102,6 +144,9 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 	subscriptionsView.SetOnShowDetails(func(sub *models.Subscription) {
 		a.showSubscriptionDetails(sub)
 	})
+	subscriptionsView.SetOnExpiryReport(func(sub *models.Subscription) {
+		a.showExpiryReportForm(sub)
+	})
 
 	// Set up resource groups view callbacks
 	resourceGroupsView.SetOnSelect(func(rg *models.ResourceGroup) {
@@ -128,31 +173,40 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 	resourcesView.SetOnShowDetails(func(resource *models.Resource) {
 		a.showResourceDetails(resource)
 	})
-	resourcesView.SetOnExploreStorage(func(resource *models.Resource) {
-		// Route to appropriate explorer based on resource type
-		switch resource.Type {
-		case "Microsoft.Storage/storageAccounts":
-			a.navigateToStorageExplorer(resource)
-		case "Microsoft.KeyVault/vaults":
-			a.navigateToKeyVaultExplorer(resource)
-		default:
-			// Try to determine if it's an explorable resource type
-			handler := registry.GetHandlerOrDefault(resource.Type)
-			if handler != nil && handler.CanExplore() {
-				// For now, just navigate to storage explorer as fallback
-				// In the future, we can add more handlers
-				a.navigateToStorageExplorer(resource)
-			}
-		}
+	resourcesView.SetOnShowJSON(func(resource *models.Resource) {
+		a.showResourceJSON(resource, a.resourcesView.GetSubscriptionID())
 	})
+	resourcesView.SetOnTag(func(resources []*models.Resource) {
+		a.tagResources(resources)
+	})
+	resourcesView.SetOnMarksChanged(func() {
+		a.updateFooterForTableView(resourcesView.TableView)
+	})
+	// Handler actions explore resources and run commands through the app
+	resourcesView.SetHost(a)
 
 	// Set up storage explorer view callbacks
+	storageExplorerView.SetOnOpen(func(account resource.StorageAccount) {
+		a.navState.SelectStorageAccount(account.Name, account.ResourceGroup)
+	})
 	storageExplorerView.SetOnSelect(func(container *models.Container) {
 		a.navigateToBlobs(container.Name)
 	})
 	storageExplorerView.SetOnShowDetails(func(container *models.Container) {
 		a.showContainerDetails(container)
 	})
+	storageExplorerView.SetOnCreate(func() {
+		a.createContainer()
+	})
+	storageExplorerView.SetOnDelete(func(container *models.Container) {
+		a.deleteContainer(container)
+	})
+	storageExplorerView.SetOnSetAccess(func(container *models.Container) {
+		a.setContainerPublicAccess(container)
+	})
+	storageExplorerView.SetOnEditMetadata(func(container *models.Container) {
+		a.editContainerMetadata(container)
+	})
 
 	// Set up blobs view callbacks
 	blobsView.SetOnShowDetails(func(blob *models.Blob) {
@@ -161,11 +215,59 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 	blobsView.SetOnNavigateFolder(func(folderPath string) {
 		a.navigateIntoBlobFolder(folderPath)
 	})
+	blobsView.SetOnEditProperties(func(blob *models.Blob) {
+		a.editBlobProperties(blob)
+	})
+	blobsView.SetOnSetTier(func(blobs []*models.Blob) {
+		a.setBlobTier(blobs)
+	})
+	blobsView.SetOnDelete(func(blobs []*models.Blob) {
+		a.deleteBlobs(blobs)
+	})
+	blobsView.SetOnDownload(func(blobs []*models.Blob) {
+		a.downloadBlobs(blobs)
+	})
+	blobsView.SetOnCopy(func(items []*models.Blob, move bool) {
+		a.copyBlobs(items, move)
+	})
+	blobsView.SetOnMarksChanged(func() {
+		a.updateFooterForTableView(blobsView.TableView)
+	})
+	blobsView.SetOnSearch(func() {
+		a.searchBlobs()
+	})
+
+	// Set up blob search view callbacks
+	blobSearchView.SetOnOpen(func(blob *models.Blob) {
+		a.openBlobSearchResult(blob)
+	})
+	blobSearchView.SetOnShowDetails(func(blob *models.Blob) {
+		a.showBlobDetails(blob)
+	})
 
 	// Set up Key Vault explorer view callbacks
+	keyVaultExplorerView.SetOnOpen(func(vault resource.KeyVault) {
+		a.navState.SelectKeyVault(vault.Name, vault.URL, vault.ResourceGroup)
+		a.loadKeyVaultProperties()
+	})
+	keyVaultExplorerView.SetOnDetails(func(vault resource.KeyVault) {
+		a.renderKeyVaultDetails(vault)
+	})
 	keyVaultExplorerView.SetOnSelect(func(itemType string) {
 		a.navigateToKeyVaultItemType(itemType)
 	})
+	keyVaultExplorerView.SetOnBackup(func() {
+		a.backupVaultItems(nil)
+	})
+	keyVaultExplorerView.SetOnRestore(func() {
+		a.restoreVaultBackup()
+	})
+	keyVaultExplorerView.SetOnSync(func() {
+		a.syncVaultSecrets()
+	})
+	keyVaultExplorerView.SetOnAccess(func() {
+		a.showVaultAccess()
+	})
 
 	// Set up Key Vault secrets view callbacks
 	keyVaultSecretsView.SetOnShowDetails(func(secret *models.Secret) {
@@ -174,16 +276,181 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 	keyVaultSecretsView.SetOnViewValue(func(secret *models.Secret) {
 		a.viewSecretValue(secret)
 	})
+	keyVaultSecretsView.SetOnCopyValue(func(secret *models.Secret) {
+		a.copySecretValue(secret)
+	})
+	keyVaultSecretsView.SetOnCreate(func() {
+		a.editSecret(nil)
+	})
+	keyVaultSecretsView.SetOnNewVersion(func(secret *models.Secret) {
+		a.editSecret(secret)
+	})
+	keyVaultSecretsView.SetOnToggle(func(secret *models.Secret) {
+		a.toggleSecretEnabled(secret)
+	})
+	keyVaultSecretsView.SetOnVersions(func(secret *models.Secret) {
+		a.navigateToSecretVersions(secret)
+	})
+	keyVaultSecretsView.SetOnHistory(func(secret *models.Secret) {
+		a.showSecretHistory(secret.Name)
+	})
+	keyVaultSecretsView.SetOnDelete(func(items []models.VaultItemRef) {
+		a.deleteVaultItems(items)
+	})
+	keyVaultSecretsView.SetOnBackup(func(items []models.VaultItemRef) {
+		a.backupVaultItems(items)
+	})
+	keyVaultSecretsView.SetOnCopy(func(items []models.VaultItemRef) {
+		a.copySecretsToVault(items)
+	})
+	keyVaultSecretsView.SetOnExport(func(items []models.VaultItemRef) {
+		a.exportSecrets(items)
+	})
+	keyVaultSecretsView.SetOnMarksChanged(func() {
+		a.updateFooterForTableView(keyVaultSecretsView.TableView)
+	})
+
+	// Set up secret versions view callbacks
+	keyVaultSecretVersionsView.SetOnShowDetails(func(version *models.Secret) {
+		a.showSecretDetails(version)
+	})
+	keyVaultSecretVersionsView.SetOnViewValue(func(version *models.Secret) {
+		a.viewSecretValue(version)
+	})
+	keyVaultSecretVersionsView.SetOnCopyValue(func(version *models.Secret) {
+		a.copySecretValue(version)
+	})
+	keyVaultSecretVersionsView.SetOnNewVersion(func(version *models.Secret) {
+		a.editSecret(version)
+	})
+	keyVaultSecretVersionsView.SetOnToggle(func(version *models.Secret) {
+		a.toggleSecretEnabled(version)
+	})
+	keyVaultSecretVersionsView.SetOnHistory(func(version *models.Secret) {
+		a.showSecretHistory(version.Name)
+	})
+	keyVaultSecretVersionsView.SetOnCompare(func(version *models.Secret) {
+		a.compareSecretVersion(version)
+	})
 
 	// Set up Key Vault keys view callbacks
 	keyVaultKeysView.SetOnShowDetails(func(key *models.Key) {
 		a.showKeyDetails(key)
 	})
+	keyVaultKeysView.SetOnDelete(func(items []models.VaultItemRef) {
+		a.deleteVaultItems(items)
+	})
+	keyVaultKeysView.SetOnBackup(func(items []models.VaultItemRef) {
+		a.backupVaultItems(items)
+	})
+	keyVaultKeysView.SetOnCrypto(func(key *models.Key) {
+		a.showKeyCryptoConsole(key)
+	})
+	keyVaultKeysView.SetOnRotate(func(key *models.Key) {
+		a.rotateKey(key)
+	})
+	keyVaultKeysView.SetOnRotationPolicy(func(key *models.Key) {
+		a.editKeyRotationPolicy(key)
+	})
+	keyVaultKeysView.SetOnCreate(func() {
+		a.createKey()
+	})
+	keyVaultKeysView.SetOnImport(func() {
+		a.importKey()
+	})
+	keyVaultKeysView.SetOnMarksChanged(func() {
+		a.updateFooterForTableView(keyVaultKeysView.TableView)
+	})
 
 	// Set up Key Vault certificates view callbacks
 	keyVaultCertificatesView.SetOnShowDetails(func(cert *models.Certificate) {
 		a.showCertificateDetails(cert)
 	})
+	keyVaultCertificatesView.SetOnDelete(func(items []models.VaultItemRef) {
+		a.deleteVaultItems(items)
+	})
+	keyVaultCertificatesView.SetOnBackup(func(items []models.VaultItemRef) {
+		a.backupVaultItems(items)
+	})
+	keyVaultCertificatesView.SetOnDownload(func(cert *models.Certificate) {
+		a.downloadCertificate(cert)
+	})
+	keyVaultCertificatesView.SetOnOperation(func(cert *models.Certificate) {
+		a.showCertificateOperation(cert)
+	})
+	keyVaultCertificatesView.SetOnMerge(func(cert *models.Certificate) {
+		a.mergeCertificate(cert)
+	})
+	keyVaultCertificatesView.SetOnCreate(func() {
+		a.createCertificate()
+	})
+	keyVaultCertificatesView.SetOnImport(func() {
+		a.importCertificate()
+	})
+	keyVaultCertificatesView.SetOnMarksChanged(func() {
+		a.updateFooterForTableView(keyVaultCertificatesView.TableView)
+	})
+
+	// Set up Key Vault deleted items view callbacks
+	keyVaultDeletedItemsView.SetOnShowDetails(func(item *models.DeletedItem) {
+		a.showDeletedItemDetails(item)
+	})
+	keyVaultDeletedItemsView.SetOnRecover(func(item *models.DeletedItem) {
+		a.recoverDeletedItem(item)
+	})
+	keyVaultDeletedItemsView.SetOnPurge(func(item *models.DeletedItem) {
+		a.purgeDeletedItem(item)
+	})
+
+	// Set up Key Vault secret sync view callbacks
+	keyVaultSecretSyncView.SetOnShowDetails(func(diff *models.SecretDiff) {
+		a.showSecretDiff(diff)
+	})
+	keyVaultSecretSyncView.SetOnViewValues(func(diff *models.SecretDiff) {
+		a.viewSecretDiffValues(diff)
+	})
+	keyVaultSecretSyncView.SetOnApply(func(diffs []*models.SecretDiff) {
+		a.applySecretSync(diffs)
+	})
+	keyVaultSecretSyncView.SetOnMarksChanged(func() {
+		a.updateFooterForTableView(keyVaultSecretSyncView.TableView)
+	})
+
+	// Set up Key Vault expiry view callbacks
+	keyVaultExpiryView.SetOnShowDetails(func(item *models.ExpiryItem) {
+		a.showExpiryItem(item)
+	})
+	keyVaultExpiryView.SetOnExport(func() {
+		a.exportExpiryReport()
+	})
+	keyVaultExpiryView.SetOnRescan(func() {
+		a.rescanExpiryReport()
+	})
+
+	// Set up Resource Graph query view callbacks
+	graphQueryView.SetOnShowDetails(func(row []interface{}) {
+		a.showGraphRow(row)
+	})
+	graphQueryView.SetOnEdit(func() {
+		a.showGraphQueryForm()
+	})
+	graphQueryView.SetOnNextPage(func() {
+		a.loadNextGraphPage()
+	})
+
+	// Set up resource search view callbacks
+	resourceSearchView.SetOnOpen(func(result *models.ResourceSearchResult) {
+		a.openSearchResult(result)
+	})
+	resourceSearchView.SetOnShowDetails(func(result *models.ResourceSearchResult) {
+		a.showResourceDetailsIn(result.Resource, result.SubscriptionID)
+	})
+	resourceSearchView.SetOnShowJSON(func(result *models.ResourceSearchResult) {
+		a.showResourceJSON(result.Resource, result.SubscriptionID)
+	})
+	resourceSearchView.SetOnEdit(func() {
+		a.showResourceSearchForm()
+	})
 
 	// Set up details view callback
 	detailsView.SetOnBack(func() {
@@ -195,6 +462,11 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 		a.applyFilter(filterText)
 	})
 
+	filterMode.SetOnCheck(func(filterText string) error {
+		return a.checkFilter(filterText)
+	})
+	filterMode.SetOnHelp(a.showFilterHelp)
+
 	filterMode.SetOnCancel(func() {
 		a.clearFilter()
 		a.updateLayout()
@@ -203,6 +475,11 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 
 	// Set up key bindings
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if a.isDialogOpen() {
+			// Let dialogs handle their own keys
+			return event
+		}
+
 		if filterMode.IsVisible() {
 			// Let filter mode handle its own keys
 			return event
@@ -214,8 +491,8 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 				a.navigateBackFromDetails()
 				return nil
 			}
-			// Let details view handle its own keys
-			return event
+			// Let details view handle its own keys and actions
+			return detailsView.HandleKey(event)
 		}
 
 		// Handle key bindings for table views
@@ -240,22 +517,22 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 			if handled := resourcesView.HandleKey(event); handled != event {
 				return handled
 			}
-		case navigation.ViewStorageExplorer:
-			if handled := storageExplorerView.HandleKey(event); handled != event {
-				return handled
-			}
 		case navigation.ViewBlobs:
 			if handled := blobsView.HandleKey(event); handled != event {
 				return handled
 			}
-		case navigation.ViewKeyVaultExplorer:
-			if handled := keyVaultExplorerView.HandleKey(event); handled != event {
+		case navigation.ViewBlobSearch:
+			if handled := blobSearchView.HandleKey(event); handled != event {
 				return handled
 			}
 		case navigation.ViewKeyVaultSecrets:
 			if handled := keyVaultSecretsView.HandleKey(event); handled != event {
 				return handled
 			}
+		case navigation.ViewKeyVaultSecretVersions:
+			if handled := keyVaultSecretVersionsView.HandleKey(event); handled != event {
+				return handled
+			}
 		case navigation.ViewKeyVaultKeys:
 			if handled := keyVaultKeysView.HandleKey(event); handled != event {
 				return handled
@@ -264,10 +541,39 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 			if handled := keyVaultCertificatesView.HandleKey(event); handled != event {
 				return handled
 			}
+		case navigation.ViewKeyVaultDeletedItems:
+			if handled := keyVaultDeletedItemsView.HandleKey(event); handled != event {
+				return handled
+			}
+		case navigation.ViewKeyVaultSecretSync:
+			if handled := keyVaultSecretSyncView.HandleKey(event); handled != event {
+				return handled
+			}
+		case navigation.ViewKeyVaultExpiry:
+			if handled := keyVaultExpiryView.HandleKey(event); handled != event {
+				return handled
+			}
 		case navigation.ViewMenu:
 			if handled := menuView.HandleKey(event); handled != event {
 				return handled
 			}
+		case navigation.ViewGraphQuery:
+			if handled := graphQueryView.HandleKey(event); handled != event {
+				return handled
+			}
+		case navigation.ViewResourceSearch:
+			if handled := resourceSearchView.HandleKey(event); handled != event {
+				return handled
+			}
+		case navigation.ViewExplorer:
+			// Table explorers such as the storage and Key Vault explorers handle their row actions
+			if view, ok := a.explorerView.(interface {
+				HandleKey(*tcell.EventKey) *tcell.EventKey
+			}); ok {
+				if handled := view.HandleKey(event); handled != event {
+					return handled
+				}
+			}
 		}
 
 		switch event.Key() {
@@ -281,17 +587,45 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 				// Go back to storage explorer
 				a.navigateBackFromBlobs()
 				return nil
+			case navigation.ViewBlobSearch:
+				// Stop the search and go back to the blobs view
+				a.navigateBackFromBlobSearch()
+				return nil
+			case navigation.ViewKeyVaultSecretVersions:
+				// Go back to the secrets list
+				a.navigateBackFromSecretVersions()
+				return nil
+			case navigation.ViewKeyVaultDeletedItems:
+				// Go back to Key Vault explorer
+				a.navigateBackFromDeletedItems()
+				return nil
+			case navigation.ViewKeyVaultSecretSync:
+				// Go back to Key Vault explorer
+				a.navigateBackFromSecretSync()
+				return nil
+			case navigation.ViewKeyVaultExpiry:
+				// Go back to subscriptions
+				a.navigateBackFromExpiry()
+				return nil
 			case navigation.ViewKeyVaultSecrets, navigation.ViewKeyVaultKeys, navigation.ViewKeyVaultCertificates:
 				// Go back to Key Vault explorer
 				a.navigateBackToKeyVaultExplorer()
 				return nil
-			case navigation.ViewKeyVaultExplorer:
+			case navigation.ViewExplorer:
+				// The storage explorer is the root view when opened on a direct endpoint
+				if navState.StorageEndpointMode {
+					return nil
+				}
 				// Go back to resource type view
-				a.navigateBackToResourceType()
+				a.navigateBackFromExplorer()
 				return nil
-			case navigation.ViewStorageExplorer:
-				// Go back to resource type view
-				a.navigateBackToResourceType()
+			case navigation.ViewGraphQuery:
+				// Go back to the view the console was opened from
+				a.navigateBackFromGraphQuery()
+				return nil
+			case navigation.ViewResourceSearch:
+				// Go back to the view the search was opened from
+				a.navigateBackFromResourceSearch()
 				return nil
 			case navigation.ViewResourceType:
 				// Go back to resource types view
@@ -305,6 +639,18 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 				a.navigateToSubscriptions()
 				return nil
 			}
+		case tcell.KeyCtrlG:
+			// Open the Resource Graph query console (only when ARM is available)
+			if !navState.StorageEndpointMode {
+				a.showGraphQueryForm()
+				return nil
+			}
+		case tcell.KeyCtrlF:
+			// Search resources across subscriptions (only when ARM is available)
+			if !navState.StorageEndpointMode {
+				a.showResourceSearchForm()
+				return nil
+			}
 		case tcell.KeyRune:
 			switch event.Rune() {
 			case '/':
@@ -314,13 +660,26 @@ func NewApp(azureClient *azure.Client, registry *resource.Registry) *App {
 					a.updateLayout()
 					return nil
 				}
+			case 'S':
+				// Sort the rows of table views
+				if a.currentTableView() != nil {
+					a.showSortForm()
+					return nil
+				}
+			case 'C':
+				// Choose the columns of table views
+				if a.currentTableView() != nil {
+					a.showColumnChooser()
+					return nil
+				}
 			case 'm', 'M':
-				// Open menu (only when not in details view)
-				if !navState.InDetailsView {
+				// Open menu (only when not in details view and ARM is available)
+				if !navState.InDetailsView && !navState.StorageEndpointMode {
 					a.navigateToMenu()
 					return nil
 				}
 			case 'q':
+				a.clearPendingClipboard()
 				app.Stop()
 				return nil
 			}
@@ -343,7 +702,7 @@ func (a *App) updateLayout() {
 	a.mainFlex.AddItem(a.headerView, 12, 0, false)
 
 	// Update header actions based on navigation state
-	a.headerView.UpdateActions(a.navState)
+	a.headerView.UpdateActions(a.navState, a.explorerView)
 
 	// Update breadcrumb
 	a.breadcrumbView.Update(a.navState)
@@ -359,14 +718,18 @@ func (a *App) updateLayout() {
 
 	// Add filter mode if visible (between view title and content)
 	if a.filterMode.IsVisible() {
-		a.mainFlex.AddItem(a.filterMode.GetInputField(), 1, 0, true)
+		a.mainFlex.AddItem(a.filterMode.GetLayout(), 1, 0, true)
 	}
 
 	// Add main content view (details, subscriptions, resource groups, resources, resource type, storage explorer, or blobs)
 	if a.navState.InDetailsView {
 		a.mainFlex.AddItem(a.detailsView, 0, 1, true)
 		a.currentView = a.detailsView
-		a.updateFooterWithActions(0, 0, false, "ESC: back, q: quit") // No count for details view
+		actions := "ESC: back, q: quit"
+		if detailsActions := a.detailsView.GetActionsText(); detailsActions != "" {
+			actions = detailsActions + ", " + actions
+		}
+		a.updateFooterWithActions(0, 0, false, actions) // No count for details view
 	} else if a.navState.CurrentView == navigation.ViewSubscriptions {
 		a.mainFlex.AddItem(a.subscriptionsView, 0, 1, true)
 		a.currentView = a.subscriptionsView
@@ -383,22 +746,22 @@ func (a *App) updateLayout() {
 		a.mainFlex.AddItem(a.resourcesView, 0, 1, true)
 		a.currentView = a.resourcesView
 		a.updateFooterForTableView(a.resourcesView.TableView)
-	} else if a.navState.CurrentView == navigation.ViewStorageExplorer {
-		a.mainFlex.AddItem(a.storageExplorerView, 0, 1, true)
-		a.currentView = a.storageExplorerView
-		a.updateFooterForTableView(a.storageExplorerView.TableView)
 	} else if a.navState.CurrentView == navigation.ViewBlobs {
 		a.mainFlex.AddItem(a.blobsView, 0, 1, true)
 		a.currentView = a.blobsView
 		a.updateFooterForTableView(a.blobsView.TableView)
-	} else if a.navState.CurrentView == navigation.ViewKeyVaultExplorer {
-		a.mainFlex.AddItem(a.keyVaultExplorerView, 0, 1, true)
-		a.currentView = a.keyVaultExplorerView
-		a.updateFooterForTableView(a.keyVaultExplorerView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewBlobSearch {
+		a.mainFlex.AddItem(a.blobSearchView, 0, 1, true)
+		a.currentView = a.blobSearchView
+		a.updateFooterForTableView(a.blobSearchView.TableView)
 	} else if a.navState.CurrentView == navigation.ViewKeyVaultSecrets {
 		a.mainFlex.AddItem(a.keyVaultSecretsView, 0, 1, true)
 		a.currentView = a.keyVaultSecretsView
 		a.updateFooterForTableView(a.keyVaultSecretsView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewKeyVaultSecretVersions {
+		a.mainFlex.AddItem(a.keyVaultSecretVersionsView, 0, 1, true)
+		a.currentView = a.keyVaultSecretVersionsView
+		a.updateFooterForTableView(a.keyVaultSecretVersionsView.TableView)
 	} else if a.navState.CurrentView == navigation.ViewKeyVaultKeys {
 		a.mainFlex.AddItem(a.keyVaultKeysView, 0, 1, true)
 		a.currentView = a.keyVaultKeysView
@@ -407,10 +770,42 @@ func (a *App) updateLayout() {
 		a.mainFlex.AddItem(a.keyVaultCertificatesView, 0, 1, true)
 		a.currentView = a.keyVaultCertificatesView
 		a.updateFooterForTableView(a.keyVaultCertificatesView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewKeyVaultDeletedItems {
+		a.mainFlex.AddItem(a.keyVaultDeletedItemsView, 0, 1, true)
+		a.currentView = a.keyVaultDeletedItemsView
+		a.updateFooterForTableView(a.keyVaultDeletedItemsView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewKeyVaultSecretSync {
+		a.mainFlex.AddItem(a.keyVaultSecretSyncView, 0, 1, true)
+		a.currentView = a.keyVaultSecretSyncView
+		a.updateFooterForTableView(a.keyVaultSecretSyncView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewKeyVaultExpiry {
+		a.mainFlex.AddItem(a.keyVaultExpiryView, 0, 1, true)
+		a.currentView = a.keyVaultExpiryView
+		a.updateFooterForTableView(a.keyVaultExpiryView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewGraphQuery {
+		a.mainFlex.AddItem(a.graphQueryView, 0, 1, true)
+		a.currentView = a.graphQueryView
+		a.updateFooterForTableView(a.graphQueryView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewResourceSearch {
+		a.mainFlex.AddItem(a.resourceSearchView, 0, 1, true)
+		a.currentView = a.resourceSearchView
+		a.updateFooterForTableView(a.resourceSearchView.TableView)
 	} else if a.navState.CurrentView == navigation.ViewMenu {
 		a.mainFlex.AddItem(a.menuView, 0, 1, true)
 		a.currentView = a.menuView
 		a.updateFooterForTableView(a.menuView.TableView)
+	} else if a.navState.CurrentView == navigation.ViewExplorer && a.explorerView != nil {
+		a.mainFlex.AddItem(a.explorerView, 0, 1, true)
+		a.currentView = a.explorerView
+		if view, ok := a.explorerView.(interface{ GetTableView() *TableView }); ok {
+			a.updateFooterForTableView(view.GetTableView())
+		} else {
+			actions := "ESC: back, /: filter, q: quit"
+			if explorerActions := a.explorerView.Actions(); explorerActions != "" {
+				actions = explorerActions + ", " + actions
+			}
+			a.updateFooterWithActions(0, 0, false, actions)
+		}
 	}
 
 	// Add footer at the bottom
@@ -429,39 +824,71 @@ func (a *App) updateFooterForTableView(tableView *TableView) {
 	var actions string
 	switch a.navState.CurrentView {
 	case navigation.ViewSubscriptions:
-		actions = "Enter: view Resource Groups, d: details, ESC: back, /: filter, q: quit"
+		actions = "Enter: view Resource Groups, d: details, k: Key Vault expiry, ESC: back, /: filter, q: quit"
 	case navigation.ViewResourceGroups:
 		actions = "Enter: view Resource List, d: details, ESC: back, /: filter, q: quit"
 	case navigation.ViewResourceTypes:
 		actions = "Enter: view storage accounts, ESC: back, /: filter, q: quit"
 	case navigation.ViewResources:
-		actions = "E: explore storage, d: details, ESC: back, /: filter, q: quit"
+		actions = "E: explore storage, d: details, j: JSON, g: tags, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
 	case navigation.ViewResourceType:
 		// Get actions from handler
 		handler := a.registry.GetHandlerOrDefault(a.navState.SelectedResourceType)
 		if handler != nil && handler.CanExplore() {
-			actions = "E: explore, d: details, ESC: back, /: filter, q: quit"
+			actions = "E: explore, d: details, j: JSON, g: tags, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
 		} else {
-			actions = "d: details, ESC: back, /: filter, q: quit"
+			actions = "d: details, j: JSON, "
+			// Commands defined by YAML handlers
+			if handler != nil {
+				for _, action := range handler.GetActions() {
+					if action.Key != 'd' {
+						actions += fmt.Sprintf("%c: %s, ", action.Key, strings.ToLower(action.Label))
+					}
+				}
+			}
+			actions += "g: tags, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
 		}
-	case navigation.ViewStorageExplorer:
-		actions = "Enter: open container, d: details, ESC: back, /: filter, q: quit"
 	case navigation.ViewBlobs:
-		actions = "Enter: open folder/details, d: details, ESC: back, /: filter, q: quit"
-	case navigation.ViewKeyVaultExplorer:
-		actions = "Enter: open item type, ESC: back, /: filter, q: quit"
+		actions = "Enter: open folder/details, d: details, e: edit, t: tier, x: delete, w: download, c: copy, v: move, s: search, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
+	case navigation.ViewBlobSearch:
+		actions = "Enter: open in folder, d: details, ESC: back, /: filter, q: quit"
 	case navigation.ViewKeyVaultSecrets:
-		actions = "v: view value, d: details, ESC: back, /: filter, q: quit"
+		actions = "v: view value, y: copy value, d: details, n: new, u: new version, t: enable/disable, h: versions, l: history, x: delete, b: backup, c: copy to vault, e: export, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
+	case navigation.ViewKeyVaultSecretVersions:
+		actions = "v: view value, y: copy value, d: details, u: new version, t: enable/disable, l: history, c: compare, ESC: back, /: filter, q: quit"
 	case navigation.ViewKeyVaultKeys:
-		actions = "d: details, ESC: back, /: filter, q: quit"
+		actions = "d: details, x: delete, b: backup, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
 	case navigation.ViewKeyVaultCertificates:
-		actions = "d: details, ESC: back, /: filter, q: quit"
+		actions = "d: details, x: delete, b: backup, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
+	case navigation.ViewKeyVaultDeletedItems:
+		actions = "d: details, r: recover, p: purge, ESC: back, /: filter, q: quit"
+	case navigation.ViewKeyVaultSecretSync:
+		actions = "Enter/d: compare, v: view values, a: apply to target, space: mark, Ctrl-A: mark all, ESC: back, /: filter, q: quit"
+	case navigation.ViewKeyVaultExpiry:
+		actions = "Enter/d: details, e: export, r: rescan, ESC: back, /: filter, q: quit"
+	case navigation.ViewGraphQuery:
+		actions = "Enter/d: details, e: edit query, n: next page, ESC: back, /: filter, q: quit"
+	case navigation.ViewResourceSearch:
+		actions = "Enter: open in resource group, d: details, j: JSON, e: edit search, ESC: back, /: filter, q: quit"
 	case navigation.ViewMenu:
 		actions = "Enter: select resource type, ESC: back, /: filter, q: quit"
+	case navigation.ViewExplorer:
+		// The storage explorer is the root view when opened on a direct endpoint
+		actions = "ESC: back, /: filter, q: quit"
+		if a.navState.StorageEndpointMode {
+			actions = "/: filter, q: quit"
+		}
+		if a.explorerView != nil && a.explorerView.Actions() != "" {
+			actions = a.explorerView.Actions() + ", " + actions
+		}
 	default:
 		actions = "ESC: back, /: filter, q: quit"
 	}
 
+	if marked := tableView.GetMarkedCount(); marked > 0 {
+		actions = fmt.Sprintf("%d marked", marked) + ", " + actions
+	}
+
 	a.updateFooterWithActions(totalCount, filteredCount, hasFilter, actions)
 }
 
@@ -490,24 +917,54 @@ func (a *App) updateViewTitle() {
 			}
 			viewName = fmt.Sprintf("Resources - %s (%s)", a.navState.SelectedResourceGroupName, resourceTypeDisplay)
 		}
-	case navigation.ViewStorageExplorer:
-		viewName = fmt.Sprintf("Storage Explorer - %s", a.navState.SelectedStorageAccount)
 	case navigation.ViewBlobs:
 		pathDisplay := ""
 		if a.navState.BlobPathPrefix != "" {
 			pathDisplay = fmt.Sprintf(" - %s", a.navState.BlobPathPrefix)
 		}
 		viewName = fmt.Sprintf("Blobs - %s/%s%s", a.navState.SelectedStorageAccount, a.navState.SelectedContainer, pathDisplay)
-	case navigation.ViewKeyVaultExplorer:
-		viewName = fmt.Sprintf("Key Vault Explorer - %s", a.navState.SelectedKeyVault)
+	case navigation.ViewBlobSearch:
+		viewName = fmt.Sprintf("Blob Search - %s/%s (%s)", a.navState.SelectedStorageAccount, a.navState.SelectedContainer, a.blobSearchView.GetStatus())
 	case navigation.ViewKeyVaultSecrets:
 		viewName = fmt.Sprintf("Secrets - %s", a.navState.SelectedKeyVault)
+	case navigation.ViewKeyVaultSecretVersions:
+		viewName = fmt.Sprintf("Secret Versions - %s/%s", a.navState.SelectedKeyVault, a.navState.SelectedSecret)
 	case navigation.ViewKeyVaultKeys:
 		viewName = fmt.Sprintf("Keys - %s", a.navState.SelectedKeyVault)
 	case navigation.ViewKeyVaultCertificates:
 		viewName = fmt.Sprintf("Certificates - %s", a.navState.SelectedKeyVault)
+	case navigation.ViewKeyVaultDeletedItems:
+		viewName = fmt.Sprintf("Deleted Items - %s", a.navState.SelectedKeyVault)
+		if vault := a.keyVaultProperties; vault != nil {
+			if vault.PurgeProtectionEnabled {
+				viewName += fmt.Sprintf(" (%d day retention, purge protected)", vault.SoftDeleteRetentionDays)
+			} else {
+				viewName += fmt.Sprintf(" (%d day retention)", vault.SoftDeleteRetentionDays)
+			}
+		}
+	case navigation.ViewKeyVaultSecretSync:
+		viewName = fmt.Sprintf("Secret Sync - %s → %s", a.navState.SelectedKeyVault, a.keyVaultSecretSyncView.GetTargetVault())
+		if a.keyVaultSecretSyncView.ComparesValues() {
+			viewName += " (with values)"
+		}
+	case navigation.ViewKeyVaultExpiry:
+		viewName = "Key Vault Expiry"
+		if report := a.keyVaultExpiryView.GetReport(); report != nil {
+			viewName += fmt.Sprintf(" - %d item(s) within %d days, %d vault(s) scanned", len(report.Items), report.WithinDays, report.VaultsScanned)
+			if len(report.Errors) > 0 {
+				viewName += fmt.Sprintf(", %d skipped", len(report.Errors))
+			}
+		}
+	case navigation.ViewGraphQuery:
+		viewName = fmt.Sprintf("Resource Graph - %s", a.graphQueryView.GetStatus())
+	case navigation.ViewResourceSearch:
+		viewName = fmt.Sprintf("Resource Search - %s", a.resourceSearchView.GetStatus())
 	case navigation.ViewMenu:
 		viewName = "Resource Types Menu"
+	case navigation.ViewExplorer:
+		if a.explorerView != nil {
+			viewName = a.explorerView.Title()
+		}
 	default:
 		viewName = "Unknown View"
 	}
@@ -534,6 +991,30 @@ func (a *App) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to load subscriptions: %w", err)
 	}
 
+	err = a.Run()
+	// After Ctrl-C the terminal is gone, so only a native clipboard tool can still be cleared
+	a.screen = nil
+	a.clearPendingClipboard()
+	return err
+}
+
+// StartWithStorageEndpoint runs the application directly in the explorer of the storage handler
+// for the client's storage endpoint, bypassing subscriptions and ARM entirely
+func (a *App) StartWithStorageEndpoint(ctx context.Context, storage resource.ResourceHandler) error {
+	endpoint := a.azureClient.GetStorageEndpoint()
+	if endpoint == nil {
+		return fmt.Errorf("no storage endpoint configured")
+	}
+
+	a.headerView.UpdateStorageEndpoint(endpoint.ServiceURL, endpoint.AccountName)
+	a.navState.SelectStorageEndpoint(endpoint.AccountName)
+
+	// The storage handler opens its explorer on the endpoint account like on any storage account
+	account := &models.Resource{Name: endpoint.AccountName, Type: storage.GetResourceType()}
+	if err := storage.Explore(a, account); err != nil {
+		return err
+	}
+
 	return a.Run()
 }
 
@@ -600,6 +1081,7 @@ func (a *App) showResourceGroupDetails(rg *models.ResourceGroup) {
 // navigateBackFromDetails returns from details view to previous view
 func (a *App) navigateBackFromDetails() {
 	a.navState.NavigateBackFromDetails()
+	a.detailsView.SetActions(nil)
 	a.updateLayout()
 	switch a.navState.CurrentView {
 	case navigation.ViewSubscriptions:
@@ -610,18 +1092,30 @@ func (a *App) navigateBackFromDetails() {
 		a.SetFocus(a.resourceTypesView)
 	case navigation.ViewResources, navigation.ViewResourceType:
 		a.SetFocus(a.resourcesView)
-	case navigation.ViewStorageExplorer:
-		a.SetFocus(a.storageExplorerView)
 	case navigation.ViewBlobs:
 		a.SetFocus(a.blobsView)
+	case navigation.ViewBlobSearch:
+		a.SetFocus(a.blobSearchView)
 	case navigation.ViewKeyVaultSecrets:
 		a.SetFocus(a.keyVaultSecretsView)
+	case navigation.ViewKeyVaultSecretVersions:
+		a.SetFocus(a.keyVaultSecretVersionsView)
 	case navigation.ViewKeyVaultKeys:
 		a.SetFocus(a.keyVaultKeysView)
 	case navigation.ViewKeyVaultCertificates:
 		a.SetFocus(a.keyVaultCertificatesView)
+	case navigation.ViewKeyVaultDeletedItems:
+		a.SetFocus(a.keyVaultDeletedItemsView)
+	case navigation.ViewKeyVaultSecretSync:
+		a.SetFocus(a.keyVaultSecretSyncView)
+	case navigation.ViewKeyVaultExpiry:
+		a.SetFocus(a.keyVaultExpiryView)
+	case navigation.ViewResourceSearch:
+		a.SetFocus(a.resourceSearchView)
 	case navigation.ViewMenu:
 		a.SetFocus(a.menuView)
+	case navigation.ViewExplorer:
+		a.SetFocus(a.explorerView)
 	}
 }
 
@@ -657,15 +1151,12 @@ func (a *App) navigateToResourceType(resourceType string) {
 	subscriptionName := a.navState.SelectedSubscriptionName
 	resourceGroupName := a.navState.SelectedResourceGroupName
 
-	var resources []*models.Resource
-	var err error
-	if resourceGroupName != "" {
-		// Filter by resource type within the resource group
-		resources, err = a.azureClient.ListResourcesByResourceGroup(ctx, subscriptionID, resourceGroupName, resourceType)
-	} else {
-		// Filter by resource type across the subscription
-		resources, err = a.azureClient.ListResources(ctx, subscriptionID, resourceType)
-	}
+	// The fetcher of the type loads them, within the resource group or across the subscription
+	resources, err := a.registry.FetchResources(ctx, resource.Scope{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resourceGroupName,
+		ResourceType:   resourceType,
+	})
 
 	if err != nil {
 		// TODO: Show error in UI
@@ -699,22 +1190,9 @@ func (a *App) navigateBackToResourceType() {
 
 // navigateBackToKeyVaultExplorer returns from Key Vault item views to Key Vault explorer
 func (a *App) navigateBackToKeyVaultExplorer() {
-	keyVaultName := a.navState.SelectedKeyVault
-	vaultURL := a.navState.SelectedKeyVaultURL
-	
-	// Navigate back to Key Vault explorer view
-	a.navState.CurrentView = navigation.ViewKeyVaultExplorer
-	
-	// Reload Key Vault explorer
-	ctx := context.Background()
-	err := a.keyVaultExplorerView.LoadKeyVault(ctx, keyVaultName, vaultURL)
-	if err != nil {
-		// TODO: Show error in UI
-		return
-	}
-	
+	a.navState.NavigateToExplorer()
 	a.updateLayout()
-	a.SetFocus(a.keyVaultExplorerView)
+	a.SetFocus(a.explorerView)
 }
 
 // navigateBackToResourceGroups returns from resources view to resource groups view
@@ -724,38 +1202,21 @@ func (a *App) navigateBackToResourceGroups() {
 	a.navigateToResourceGroups(subscriptionID, subscriptionName)
 }
 
-// showResourceDetails shows the details view for a resource
+// showResourceDetails shows the details view for a resource of the resources view
 func (a *App) showResourceDetails(resource *models.Resource) {
+	a.showResourceDetailsIn(resource, a.resourcesView.GetSubscriptionID())
+}
+
+// showResourceDetailsIn shows the details view for a resource of a subscription
+func (a *App) showResourceDetailsIn(resource *models.Resource, subscriptionID string) {
 	a.navState.NavigateToDetails()
-	subscriptionID := a.resourcesView.GetSubscriptionID()
 	a.detailsView.ShowResourceDetails(resource, subscriptionID)
+	a.detailsView.SetActions([]DetailsAction{a.resourceJSONAction(resource, subscriptionID)})
+	a.showHandlerDetails(resource, subscriptionID)
 	a.updateLayout()
 	a.SetFocus(a.detailsView)
 }
 
-// navigateToStorageExplorer navigates to the storage explorer view for a storage account
-func (a *App) navigateToStorageExplorer(resource *models.Resource) {
-	storageAccountName := resource.Name
-	a.navState.NavigateToStorageExplorer(storageAccountName)
-
-	// Load containers
-	ctx := context.Background()
-	subscriptionID := a.navState.SelectedSubscriptionID
-	resourceGroupName := resource.ResourceGroup
-	containers, err := a.azureClient.ListContainers(ctx, subscriptionID, resourceGroupName, storageAccountName)
-	if err != nil {
-		// TODO: Show error in UI
-		return
-	}
-
-	err = a.storageExplorerView.LoadContainers(ctx, containers, storageAccountName)
-	if err == nil {
-		a.updateFooterForTableView(a.storageExplorerView.TableView)
-	}
-	a.updateLayout()
-	a.SetFocus(a.storageExplorerView)
-}
-
 // navigateToBlobs navigates to the blobs view for a container
 func (a *App) navigateToBlobs(containerName string) {
 	a.navState.NavigateToBlobs(containerName)
@@ -767,13 +1228,11 @@ func (a *App) navigateToBlobs(containerName string) {
 // loadBlobsForCurrentPath loads blobs for the current path prefix
 func (a *App) loadBlobsForCurrentPath() {
 	ctx := context.Background()
-	subscriptionID := a.navState.SelectedSubscriptionID
-	resourceGroupName := a.navState.SelectedResourceGroupName
-	storageAccountName := a.navState.SelectedStorageAccount
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
 	containerName := a.navState.SelectedContainer
 	pathPrefix := a.navState.BlobPathPrefix
 
-	blobs, err := a.azureClient.ListBlobs(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, pathPrefix)
+	blobs, err := a.storageExplorerView.GetFetcher().ListBlobs(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, pathPrefix)
 	if err != nil {
 		// TODO: Show error in UI
 		return
@@ -803,33 +1262,15 @@ func (a *App) navigateBackFromBlobs() {
 		return
 	}
 
-	// Go back to storage explorer
-	storageAccountName := a.navState.SelectedStorageAccount
+	// Go back to storage explorer and reload containers
 	a.navState.NavigateBackFromBlobs()
-
-	// Reload containers
-	ctx := context.Background()
-	subscriptionID := a.navState.SelectedSubscriptionID
-	resourceGroupName := a.navState.SelectedResourceGroupName
-	containers, err := a.azureClient.ListContainers(ctx, subscriptionID, resourceGroupName, storageAccountName)
-	if err != nil {
-		// TODO: Show error in UI
-		return
-	}
-
-	err = a.storageExplorerView.LoadContainers(ctx, containers, storageAccountName)
-	if err == nil {
-		a.updateFooterForTableView(a.storageExplorerView.TableView)
-	}
-	a.updateLayout()
-	a.SetFocus(a.storageExplorerView)
+	a.refreshContainers()
 }
 
 // showContainerDetails shows the details view for a container
 func (a *App) showContainerDetails(container *models.Container) {
 	a.navState.NavigateToDetails()
-	storageAccountName := a.navState.SelectedStorageAccount
-	a.detailsView.ShowContainerDetails(container, storageAccountName)
+	a.renderContainerDetails(container)
 	a.updateLayout()
 	a.SetFocus(a.detailsView)
 }
@@ -837,71 +1278,38 @@ func (a *App) showContainerDetails(container *models.Container) {
 // showBlobDetails shows the details view for a blob
 func (a *App) showBlobDetails(blob *models.Blob) {
 	a.navState.NavigateToDetails()
-	subscriptionID := a.navState.SelectedSubscriptionID
-	resourceGroupName := a.navState.SelectedResourceGroupName
-	storageAccountName := a.navState.SelectedStorageAccount
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
 	containerName := a.navState.SelectedContainer
 
 	// Get full blob details
 	ctx := context.Background()
-	fullBlob, err := a.azureClient.GetBlobDetails(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, blob.Name)
+	fullBlob, err := a.storageExplorerView.GetFetcher().GetBlobDetails(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, blob.Name)
 	if err != nil {
 		// TODO: Show error in UI
 		return
 	}
 
 	a.detailsView.ShowBlobDetails(fullBlob, storageAccountName, containerName)
+	a.detailsView.SetActions(a.blobDetailsActions(fullBlob))
 	a.updateLayout()
 	a.SetFocus(a.detailsView)
 }
 
-// navigateToKeyVaultExplorer navigates to the Key Vault explorer view for a Key Vault
-func (a *App) navigateToKeyVaultExplorer(resource *models.Resource) {
-	keyVaultName := resource.Name
-	
-	// Extract vault URL from resource properties
-	vaultURL := ""
-	if resource.Properties != nil {
-		if vaultURIVal, ok := resource.Properties["vaultUri"]; ok {
-			if vaultURIStr, ok := vaultURIVal.(string); ok {
-				vaultURL = vaultURIStr
-			}
-		}
-	}
-	
-	// If not in properties, construct it
-	if vaultURL == "" {
-		vaultURL = fmt.Sprintf("https://%s.vault.azure.net/", keyVaultName)
-	}
-	
-	a.navState.NavigateToKeyVaultExplorer(keyVaultName, vaultURL)
-	
-	// Load Key Vault explorer
-	ctx := context.Background()
-	err := a.keyVaultExplorerView.LoadKeyVault(ctx, keyVaultName, vaultURL)
-	if err != nil {
-		// TODO: Show error in UI
-		return
-	}
-	
-	a.updateLayout()
-	a.SetFocus(a.keyVaultExplorerView)
-}
-
 // navigateToKeyVaultItemType navigates to the selected Key Vault item type (secrets, keys, or certificates)
 func (a *App) navigateToKeyVaultItemType(itemType string) {
 	ctx := context.Background()
 	vaultURL := a.navState.SelectedKeyVaultURL
 	keyVaultName := a.navState.SelectedKeyVault
+	fetcher := a.keyVaultExplorerView.GetFetcher()
 	
 	switch itemType {
 	case "secrets":
 		a.navState.NavigateToKeyVaultSecrets()
 		
 		// Load secrets
-		secrets, err := a.azureClient.ListSecrets(ctx, vaultURL)
+		secrets, err := fetcher.ListSecrets(ctx, vaultURL)
 		if err != nil {
-			// TODO: Show error in UI
+			a.showVaultListError("secrets", err)
 			return
 		}
 		
@@ -916,9 +1324,9 @@ func (a *App) navigateToKeyVaultItemType(itemType string) {
 		a.navState.NavigateToKeyVaultKeys()
 		
 		// Load keys
-		keys, err := a.azureClient.ListKeys(ctx, vaultURL)
+		keys, err := fetcher.ListKeys(ctx, vaultURL)
 		if err != nil {
-			// TODO: Show error in UI
+			a.showVaultListError("keys", err)
 			return
 		}
 		
@@ -933,9 +1341,9 @@ func (a *App) navigateToKeyVaultItemType(itemType string) {
 		a.navState.NavigateToKeyVaultCertificates()
 		
 		// Load certificates
-		certificates, err := a.azureClient.ListCertificates(ctx, vaultURL)
+		certificates, err := fetcher.ListCertificates(ctx, vaultURL)
 		if err != nil {
-			// TODO: Show error in UI
+			a.showVaultListError("certificates", err)
 			return
 		}
 		
@@ -945,6 +1353,9 @@ func (a *App) navigateToKeyVaultItemType(itemType string) {
 		}
 		a.updateLayout()
 		a.SetFocus(a.keyVaultCertificatesView)
+
+	case "deleted":
+		a.navigateToDeletedItems()
 	}
 }
 
@@ -957,40 +1368,49 @@ func (a *App) showSecretDetails(secret *models.Secret) {
 	a.SetFocus(a.detailsView)
 }
 
-// viewSecretValue shows the secret value with a confirmation dialog
+// viewSecretValue shows a secret value, or the value of one version, masked. Reveal shows the full
+// value after a confirmation; Copy puts it in the clipboard without displaying it.
 func (a *App) viewSecretValue(secret *models.Secret) {
-	// Create a modal for confirmation
-	modal := tview.NewModal().
-		SetText(fmt.Sprintf("Are you sure you want to view the value of secret '%s'?\n\n⚠️ This will display sensitive information on screen.", secret.Name)).
-		AddButtons([]string{"View", "Cancel"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			if buttonLabel == "View" {
-				// Fetch and show the secret value
-				ctx := context.Background()
-				vaultURL := a.navState.SelectedKeyVaultURL
-				value, err := a.azureClient.GetSecretValue(ctx, vaultURL, secret.Name)
-				if err != nil {
-					// TODO: Show error in UI
-					a.SetRoot(a.mainFlex, true)
-					return
-				}
-				
-				// Create a modal to display the value
-				valueModal := tview.NewModal().
-					SetText(fmt.Sprintf("Secret: %s\n\nValue:\n%s\n\nPress any key to close.", secret.Name, value)).
-					AddButtons([]string{"Close"}).
-					SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-						a.SetRoot(a.mainFlex, true)
-						a.SetFocus(a.keyVaultSecretsView)
-					})
-				a.SetRoot(valueModal, true)
-			} else {
-				a.SetRoot(a.mainFlex, true)
-				a.SetFocus(a.keyVaultSecretsView)
-			}
-		})
-	
-	a.SetRoot(modal, true)
+	label := fmt.Sprintf("secret '%s'", secret.Name)
+	if secret.Version != "" {
+		label = fmt.Sprintf("version %s of secret '%s'", secret.Version, secret.Name)
+	}
+
+	ctx := context.Background()
+	vaultURL := a.navState.SelectedKeyVaultURL
+	value, err := a.azureClient.GetSecretValue(ctx, vaultURL, secret.Name, secret.Version)
+	if err != nil {
+		a.showError("Failed to get secret value", err)
+		return
+	}
+
+	valueModal := tview.NewModal()
+	showMasked := func() {
+		valueModal.SetText(fmt.Sprintf("Secret: %s\n\nValue (masked):\n%s", secret.Name, maskSecretValue(value))).
+			ClearButtons().
+			AddButtons([]string{"Reveal", "Copy", "Close"})
+	}
+	valueModal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		switch buttonLabel {
+		case "Reveal":
+			a.confirm(fmt.Sprintf("Are you sure you want to reveal the value of %s?\n\n⚠️ This will display sensitive information on screen.", label), "Reveal", func() {
+				valueModal.SetText(fmt.Sprintf("Secret: %s\n\nValue:\n%s", secret.Name, tview.Escape(value))).
+					ClearButtons().
+					AddButtons([]string{"Hide", "Copy", "Close"})
+				a.SetFocus(valueModal)
+			})
+		case "Hide":
+			showMasked()
+			a.SetFocus(valueModal)
+		case "Copy":
+			a.closeDialog()
+			a.copyToClipboard(label, value)
+		default:
+			a.closeDialog()
+		}
+	})
+	showMasked()
+	a.showModal(valueModal)
 }
 
 // showKeyDetails shows the details view for a key
@@ -1001,7 +1421,7 @@ func (a *App) showKeyDetails(key *models.Key) {
 	// Get full key details
 	ctx := context.Background()
 	vaultURL := a.navState.SelectedKeyVaultURL
-	fullKey, err := a.azureClient.GetKeyDetails(ctx, vaultURL, key.Name)
+	fullKey, err := a.keyVaultExplorerView.GetFetcher().GetKeyDetails(ctx, vaultURL, key.Name)
 	if err != nil {
 		// TODO: Show error in UI
 		return
@@ -1020,7 +1440,7 @@ func (a *App) showCertificateDetails(cert *models.Certificate) {
 	// Get full certificate details
 	ctx := context.Background()
 	vaultURL := a.navState.SelectedKeyVaultURL
-	fullCert, err := a.azureClient.GetCertificateDetails(ctx, vaultURL, cert.Name)
+	fullCert, err := a.keyVaultExplorerView.GetFetcher().GetCertificateDetails(ctx, vaultURL, cert.Name)
 	if err != nil {
 		// TODO: Show error in UI
 		return
@@ -1076,15 +1496,12 @@ func (a *App) navigateToResourceTypeFromMenu(resourceType string) {
 	subscriptionName := a.navState.SelectedSubscriptionName
 	resourceGroupName := a.navState.SelectedResourceGroupName
 
-	var resources []*models.Resource
-	var err error
-	if resourceGroupName != "" {
-		// Filter by resource type within the resource group
-		resources, err = a.azureClient.ListResourcesByResourceGroup(ctx, subscriptionID, resourceGroupName, resourceType)
-	} else {
-		// Filter by resource type across the subscription
-		resources, err = a.azureClient.ListResources(ctx, subscriptionID, resourceType)
-	}
+	// The fetcher of the type loads them, within the resource group or across the subscription
+	resources, err := a.registry.FetchResources(ctx, resource.Scope{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resourceGroupName,
+		ResourceType:   resourceType,
+	})
 
 	if err != nil {
 		// TODO: Show error in UI
@@ -1121,15 +1538,37 @@ func (a *App) applyFilter(filterText string) {
 	case navigation.ViewResources, navigation.ViewResourceType:
 		a.resourcesView.SetFilter(filterText)
 		a.updateFooterForTableView(a.resourcesView.TableView)
-	case navigation.ViewStorageExplorer:
-		a.storageExplorerView.SetFilter(filterText)
-		a.updateFooterForTableView(a.storageExplorerView.TableView)
 	case navigation.ViewBlobs:
 		a.blobsView.SetFilter(filterText)
 		a.updateFooterForTableView(a.blobsView.TableView)
+	case navigation.ViewBlobSearch:
+		a.blobSearchView.SetFilter(filterText)
+		a.updateFooterForTableView(a.blobSearchView.TableView)
+	case navigation.ViewKeyVaultSecretVersions:
+		a.keyVaultSecretVersionsView.SetFilter(filterText)
+		a.updateFooterForTableView(a.keyVaultSecretVersionsView.TableView)
+	case navigation.ViewKeyVaultDeletedItems:
+		a.keyVaultDeletedItemsView.SetFilter(filterText)
+		a.updateFooterForTableView(a.keyVaultDeletedItemsView.TableView)
+	case navigation.ViewKeyVaultSecretSync:
+		a.keyVaultSecretSyncView.SetFilter(filterText)
+		a.updateFooterForTableView(a.keyVaultSecretSyncView.TableView)
+	case navigation.ViewKeyVaultExpiry:
+		a.keyVaultExpiryView.SetFilter(filterText)
+		a.updateFooterForTableView(a.keyVaultExpiryView.TableView)
+	case navigation.ViewGraphQuery:
+		a.graphQueryView.SetFilter(filterText)
+		a.updateFooterForTableView(a.graphQueryView.TableView)
+	case navigation.ViewResourceSearch:
+		a.resourceSearchView.SetFilter(filterText)
+		a.updateFooterForTableView(a.resourceSearchView.TableView)
 	case navigation.ViewMenu:
 		a.menuView.SetFilter(filterText)
 		a.updateFooterForTableView(a.menuView.TableView)
+	case navigation.ViewExplorer:
+		if view, ok := a.explorerView.(resource.FilterableView); ok {
+			view.SetFilter(filterText)
+		}
 	}
 
 	a.updateLayout()
@@ -1155,14 +1594,39 @@ func (a *App) clearFilter() {
 	case navigation.ViewResources, navigation.ViewResourceType:
 		a.resourcesView.ClearFilter()
 		a.updateFooterForTableView(a.resourcesView.TableView)
-	case navigation.ViewStorageExplorer:
-		a.storageExplorerView.ClearFilter()
-		a.updateFooterForTableView(a.storageExplorerView.TableView)
 	case navigation.ViewBlobs:
 		a.blobsView.ClearFilter()
 		a.updateFooterForTableView(a.blobsView.TableView)
+	case navigation.ViewBlobSearch:
+		a.blobSearchView.ClearFilter()
+		a.updateFooterForTableView(a.blobSearchView.TableView)
+	case navigation.ViewKeyVaultSecretVersions:
+		a.keyVaultSecretVersionsView.ClearFilter()
+		a.updateFooterForTableView(a.keyVaultSecretVersionsView.TableView)
+	case navigation.ViewKeyVaultDeletedItems:
+		a.keyVaultDeletedItemsView.ClearFilter()
+		a.updateFooterForTableView(a.keyVaultDeletedItemsView.TableView)
+	case navigation.ViewKeyVaultSecretSync:
+		a.keyVaultSecretSyncView.ClearFilter()
+		a.updateFooterForTableView(a.keyVaultSecretSyncView.TableView)
+	case navigation.ViewKeyVaultExpiry:
+		a.keyVaultExpiryView.ClearFilter()
+		a.updateFooterForTableView(a.keyVaultExpiryView.TableView)
+	case navigation.ViewGraphQuery:
+		a.graphQueryView.ClearFilter()
+		a.updateFooterForTableView(a.graphQueryView.TableView)
+	case navigation.ViewResourceSearch:
+		a.resourceSearchView.ClearFilter()
+		a.updateFooterForTableView(a.resourceSearchView.TableView)
 	case navigation.ViewMenu:
 		a.menuView.ClearFilter()
 		a.updateFooterForTableView(a.menuView.TableView)
+	case navigation.ViewExplorer:
+		if view, ok := a.explorerView.(resource.FilterableView); ok {
+			view.SetFilter("")
+		}
+		if view, ok := a.explorerView.(interface{ GetTableView() *TableView }); ok {
+			a.updateFooterForTableView(view.GetTableView())
+		}
 	}
 }
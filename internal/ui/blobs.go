@@ -0,0 +1,371 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/bulk"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// blobDetailsActions returns the details view actions for a blob
+func (a *App) blobDetailsActions(blob *models.Blob) []DetailsAction {
+	return []DetailsAction{
+		{Rune: 'e', Label: "edit properties", Callback: func() { a.editBlobProperties(blob) }},
+		{Rune: 't', Label: "access tier", Callback: func() { a.setBlobTier([]*models.Blob{blob}) }},
+	}
+}
+
+// refreshAfterBlobChange reloads whatever currently shows the changed blob: its details or the blob list
+func (a *App) refreshAfterBlobChange(blob *models.Blob) {
+	if !a.navState.InDetailsView {
+		a.loadBlobsForCurrentPath()
+		return
+	}
+
+	ctx := context.Background()
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+	containerName := a.navState.SelectedContainer
+	fullBlob, err := a.storageExplorerView.GetFetcher().GetBlobDetails(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, blob.Name)
+	if err != nil {
+		a.showError("Failed to reload blob", err)
+		return
+	}
+
+	a.detailsView.ShowBlobDetails(fullBlob, storageAccountName, containerName)
+	a.detailsView.SetActions(a.blobDetailsActions(fullBlob))
+	a.updateLayout()
+	a.SetFocus(a.detailsView)
+}
+
+// editBlobProperties shows the form for editing blob HTTP headers and metadata
+func (a *App) editBlobProperties(blob *models.Blob) {
+	ctx := context.Background()
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+	containerName := a.navState.SelectedContainer
+
+	// The list view does not carry every header, so start from the full properties
+	current, err := a.azureClient.GetBlobDetails(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, blob.Name)
+	if err != nil {
+		a.showError("Failed to get blob properties", err)
+		return
+	}
+
+	form := tview.NewForm().
+		AddInputField("Content Type", current.ContentType, 0, nil, nil).
+		AddInputField("Cache Control", current.CacheControl, 0, nil, nil).
+		AddInputField("Content Disposition", current.ContentDisposition, 0, nil, nil).
+		AddTextArea("Metadata", azure.FormatMetadata(current.Metadata), 0, 6, 0, nil)
+
+	form.AddButton("Save", func() {
+		metadata, err := azure.ParseMetadata(formText(form, "Metadata"))
+		if err != nil {
+			a.showError("Invalid metadata", err)
+			return
+		}
+
+		updated := *current
+		updated.ContentType = strings.TrimSpace(formText(form, "Content Type"))
+		updated.CacheControl = strings.TrimSpace(formText(form, "Cache Control"))
+		updated.ContentDisposition = strings.TrimSpace(formText(form, "Content Disposition"))
+		updated.Metadata = metadata
+
+		a.closeDialog()
+		if err := a.azureClient.UpdateBlobProperties(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, &updated); err != nil {
+			a.showError("Failed to update blob properties", err)
+			return
+		}
+		a.refreshAfterBlobChange(current)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Properties - %s", current.DisplayName), 80, 18)
+}
+
+// setBlobTier shows the access tier form for one or more blobs
+func (a *App) setBlobTier(blobs []*models.Blob) {
+	if len(blobs) == 0 {
+		return
+	}
+
+	archived := 0
+	for _, blob := range blobs {
+		if strings.EqualFold(blob.AccessTier, "Archive") {
+			archived++
+		}
+	}
+
+	title := fmt.Sprintf("Access Tier - %s", blobs[0].DisplayName)
+	if len(blobs) > 1 {
+		title = fmt.Sprintf("Access Tier - %d blobs", len(blobs))
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Tier", azure.AccessTiers, indexOf(azure.AccessTiers, blobs[0].AccessTier), nil)
+	if archived > 0 {
+		// Rehydration priority only matters when moving blobs out of the archive tier
+		form.AddDropDown("Rehydrate Priority", azure.RehydratePriorities, 0, nil)
+	}
+
+	form.AddButton("Save", func() {
+		tier := formOption(form, "Tier")
+		priority := formOption(form, "Rehydrate Priority")
+		apply := func() {
+			title := fmt.Sprintf("Setting the access tier of %d blob(s) to %s", len(blobs), tier)
+			a.runBlobAction(title, blobs, func(ctx context.Context, container *azure.BlobContainer, blob *models.Blob) error {
+				return container.SetTier(ctx, blob, tier, priority)
+			}, func() {
+				a.blobsView.ClearMarks()
+				a.refreshAfterBlobChange(blobs[0])
+				if archived > 0 && tier != "Archive" {
+					a.showInfo(fmt.Sprintf("Rehydration of archived blobs started with %s priority. It can take up to 15 hours; the archive status shows progress.", priority))
+				}
+			})
+		}
+
+		a.closeDialog()
+		if tier == "Archive" {
+			a.confirm(fmt.Sprintf("Move %d blob(s) to the archive tier? Archived blobs are offline until rehydrated.", len(blobs)), "Archive", apply)
+			return
+		}
+		apply()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, title, 70, 9)
+}
+
+// deleteBlobs deletes blobs with their snapshots after a confirmation listing them
+func (a *App) deleteBlobs(blobs []*models.Blob) {
+	names := make([]string, len(blobs))
+	for i, blob := range blobs {
+		names[i] = blob.Name
+	}
+
+	question := fmt.Sprintf("Delete %d blob(s) and their snapshots from %s?", len(blobs), a.navState.SelectedContainer)
+	a.confirmBulkAction(question, names, "Delete", func() {
+		title := fmt.Sprintf("Deleting %d blob(s)", len(blobs))
+		a.runBlobAction(title, blobs, func(ctx context.Context, container *azure.BlobContainer, blob *models.Blob) error {
+			return container.Delete(ctx, blob.Name)
+		}, func() {
+			a.blobsView.ClearMarks()
+			a.loadBlobsForCurrentPath()
+		})
+	})
+}
+
+// downloadBlobs asks for a local directory and downloads blobs into it, keeping their paths below the
+// current folder
+func (a *App) downloadBlobs(blobs []*models.Blob) {
+	defaultDir := fmt.Sprintf("%s-%s", a.navState.SelectedContainer, time.Now().Format("20060102-150405"))
+	form := tview.NewForm().
+		AddInputField("Directory", defaultDir, 0, nil, nil)
+
+	form.AddButton("Download", func() {
+		dir := strings.TrimSpace(formText(form, "Directory"))
+		if dir == "" {
+			a.showError("Invalid directory", fmt.Errorf("enter the directory to download to"))
+			return
+		}
+
+		a.closeDialog()
+		prefix := a.navState.BlobPathPrefix
+		title := fmt.Sprintf("Downloading %d blob(s) to %s", len(blobs), dir)
+		a.runBlobAction(title, blobs, func(ctx context.Context, container *azure.BlobContainer, blob *models.Blob) error {
+			path, err := azure.BlobDownloadPath(dir, prefix, blob.Name)
+			if err != nil {
+				return err
+			}
+			return container.Download(ctx, blob.Name, path)
+		}, a.blobsView.ClearMarks)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Download %d blob(s) - existing files are not overwritten", len(blobs)), 70, 9)
+}
+
+// runBlobAction runs an action on blobs of the selected container as a bulk action, with the container
+// client resolved once for all of them
+func (a *App) runBlobAction(title string, blobs []*models.Blob, action func(ctx context.Context, container *azure.BlobContainer, blob *models.Blob) error, onClose func()) {
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+	containerName := a.navState.SelectedContainer
+
+	prepare := func(ctx context.Context) ([]bulk.Item, error) {
+		container, err := a.azureClient.OpenBlobContainer(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]bulk.Item, 0, len(blobs))
+		for _, blob := range blobs {
+			blob := blob
+			items = append(items, bulk.Item{
+				Label: blob.Name,
+				Run: func(ctx context.Context) error {
+					return action(ctx, container, blob)
+				},
+			})
+		}
+		return items, nil
+	}
+	a.runBulkAction(title, prepare, nil, onClose)
+}
+
+// copyBlobs shows the destination form for copying or moving blobs and folders, then runs the copy
+func (a *App) copyBlobs(items []*models.Blob, move bool) {
+	verb := "Copy"
+	if move {
+		verb = "Move"
+	}
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+	source := models.BlobLocation{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resourceGroupName,
+		StorageAccount: storageAccountName,
+		Container:      a.navState.SelectedContainer,
+		Path:           a.navState.BlobPathPrefix,
+	}
+
+	form := tview.NewForm().
+		AddInputField("Storage Account", source.StorageAccount, 0, nil, nil).
+		AddInputField("Container", source.Container, 0, nil, nil).
+		AddInputField("Folder", source.Path, 0, nil, nil)
+
+	form.AddButton(verb, func() {
+		destination := models.BlobLocation{
+			SubscriptionID: source.SubscriptionID,
+			ResourceGroup:  source.ResourceGroup,
+			StorageAccount: strings.TrimSpace(formText(form, "Storage Account")),
+			Container:      strings.TrimSpace(formText(form, "Container")),
+			Path:           azure.NormalizeBlobPrefix(formText(form, "Folder")),
+		}
+		if err := azure.ValidateContainerName(destination.Container); err != nil {
+			a.showError("Invalid destination container", err)
+			return
+		}
+		if !strings.EqualFold(destination.StorageAccount, source.StorageAccount) && a.navState.StorageEndpointMode {
+			a.showError("Invalid destination", fmt.Errorf("only the connected storage account can be used on a direct endpoint"))
+			return
+		}
+
+		a.closeDialog()
+		run := func() { a.runBlobCopy(source, items, destination, move) }
+		if move {
+			verification := "Sources are deleted after each copy is verified by length and Content-MD5."
+			if strings.EqualFold(destination.StorageAccount, source.StorageAccount) {
+				verification = "Sources are deleted after each copy is verified; block blobs within an account are only checked by size."
+			}
+			a.confirm(fmt.Sprintf("Move %d item(s) to %s/%s/%s? %s",
+				len(items), destination.StorageAccount, destination.Container, destination.Path, verification), "Move", run)
+			return
+		}
+		run()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	title := fmt.Sprintf("%s %d item(s) - destination", verb, len(items))
+	a.showForm(form, title, 70, 11)
+}
+
+// runBlobCopy resolves folders and the destination account, then copies in the background with a progress dialog
+func (a *App) runBlobCopy(source models.BlobLocation, items []*models.Blob, destination models.BlobLocation, move bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := false
+
+	verb := "Copying"
+	if move {
+		verb = "Moving"
+	}
+	target := fmt.Sprintf("%s/%s/%s", destination.StorageAccount, destination.Container, destination.Path)
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s to %s\n\nPreparing...", verb, target)).
+		AddButtons([]string{"Stop"})
+	modal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if !done {
+			// Copies already started keep running on the service; only waiting (and move deletes) stop
+			cancel()
+			return
+		}
+		a.closeDialog()
+		a.blobsView.ClearMarks()
+		a.loadBlobsForCurrentPath()
+	})
+	a.showModal(modal)
+
+	update := func(text string) {
+		a.QueueUpdateDraw(func() {
+			modal.SetText(text)
+		})
+	}
+	finish := func(text string) {
+		a.QueueUpdateDraw(func() {
+			done = true
+			modal.SetText(text)
+			modal.ClearButtons().AddButtons([]string{"Close"})
+			a.SetFocus(modal)
+		})
+	}
+
+	go func() {
+		defer cancel()
+
+		if !strings.EqualFold(destination.StorageAccount, source.StorageAccount) {
+			// The destination account may be in any subscription
+			subscriptionID, resourceGroup, err := a.azureClient.FindStorageAccount(ctx, destination.StorageAccount)
+			if err != nil {
+				finish(fmt.Sprintf("%s to %s failed\n\n%v", verb, target, err))
+				return
+			}
+			destination.SubscriptionID = subscriptionID
+			destination.ResourceGroup = resourceGroup
+		}
+
+		// Folders are copied recursively
+		var names []string
+		for _, item := range items {
+			if !item.IsDirectory {
+				names = append(names, item.Name)
+				continue
+			}
+			folderNames, err := a.azureClient.ListBlobNames(ctx, source.SubscriptionID, source.ResourceGroup, source.StorageAccount, source.Container, item.Name)
+			if err != nil {
+				finish(fmt.Sprintf("%s to %s failed\n\n%v", verb, target, err))
+				return
+			}
+			names = append(names, folderNames...)
+		}
+
+		progress, err := a.azureClient.CopyBlobs(ctx, source, names, destination, move, func(p models.CopyProgress) {
+			update(fmt.Sprintf("%s to %s\n\n%s", verb, target, formatCopyProgress(p, move)))
+		})
+
+		summary := fmt.Sprintf("%s to %s finished\n\n%s", verb, target, formatCopyProgress(progress, move))
+		if err != nil {
+			summary += fmt.Sprintf("\n\n%v", err)
+		}
+		if len(progress.Errors) > 0 {
+			errs := progress.Errors
+			if len(errs) > 5 {
+				errs = append(errs[:5:5], fmt.Sprintf("... and %d more", len(progress.Errors)-5))
+			}
+			summary += "\n\n" + strings.Join(errs, "\n")
+		}
+		finish(summary)
+	}()
+}
+
+// formatCopyProgress renders copy progress for the progress dialog
+func formatCopyProgress(progress models.CopyProgress, move bool) string {
+	text := fmt.Sprintf("%d of %d succeeded, %d failed, %d pending\n%s of %s copied",
+		progress.Succeeded, progress.Total, progress.Failed, progress.Pending,
+		formatSize(progress.BytesCopied), formatSize(progress.BytesTotal))
+	if move {
+		text += fmt.Sprintf("\n%d source blob(s) deleted", progress.Deleted)
+	}
+	return text
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+	"azure-control-tower/internal/navigation"
+
+	"github.com/rivo/tview"
+)
+
+// searchBlobs shows the container-wide search form, starting in the current folder
+func (a *App) searchBlobs() {
+	previous := a.blobSearchView.GetQuery()
+	folder := a.navState.BlobPathPrefix
+
+	form := tview.NewForm().
+		AddInputField("Name Pattern", previous.Pattern, 0, nil, nil).
+		AddCheckbox("Regular Expression", previous.UseRegex, nil).
+		AddInputField("Folder", folder, 0, nil, nil).
+		AddInputField("Min Size", "", 0, nil, nil).
+		AddInputField("Max Size", "", 0, nil, nil).
+		AddInputField("Modified After", "", 0, nil, nil).
+		AddInputField("Modified Before", "", 0, nil, nil).
+		AddInputField("Index Tags", previous.TagFilter, 0, nil, nil)
+
+	form.AddButton("Search", func() {
+		query, err := parseBlobSearchForm(form)
+		if err != nil {
+			a.showError("Invalid search", err)
+			return
+		}
+		a.closeDialog()
+		a.startBlobSearch(query)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Search - %s", a.navState.SelectedContainer), 80, 21)
+}
+
+// parseBlobSearchForm builds a search query from the search form
+func parseBlobSearchForm(form *tview.Form) (models.BlobSearchQuery, error) {
+	query := models.BlobSearchQuery{
+		Prefix:    azure.NormalizeBlobPrefix(formText(form, "Folder")),
+		Pattern:   strings.TrimSpace(formText(form, "Name Pattern")),
+		UseRegex:  formChecked(form, "Regular Expression"),
+		TagFilter: strings.TrimSpace(formText(form, "Index Tags")),
+	}
+
+	for _, field := range []struct {
+		label  string
+		target **int64
+	}{
+		{"Min Size", &query.MinSize},
+		{"Max Size", &query.MaxSize},
+	} {
+		value := strings.TrimSpace(formText(form, field.label))
+		if value == "" {
+			continue
+		}
+		size, err := azure.ParseSize(value)
+		if err != nil {
+			return query, fmt.Errorf("%s: %w", field.label, err)
+		}
+		*field.target = &size
+	}
+
+	var err error
+	if query.ModifiedAfter, err = formTime(form, "Modified After"); err != nil {
+		return query, err
+	}
+	if query.ModifiedBefore, err = formTime(form, "Modified Before"); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// startBlobSearch runs a search in the background, streaming matches into the search results view
+func (a *App) startBlobSearch(query models.BlobSearchQuery) {
+	a.cancelBlobSearch()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.blobSearchCancel = cancel
+
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+	containerName := a.navState.SelectedContainer
+
+	a.navState.NavigateToBlobSearch()
+	a.blobSearchView.StartSearch(query, containerName)
+	a.updateLayout()
+	a.SetFocus(a.blobSearchView)
+
+	refresh := func() {
+		if a.navState.CurrentView == navigation.ViewBlobSearch && !a.navState.InDetailsView {
+			a.updateViewTitle()
+			a.updateFooterForTableView(a.blobSearchView.TableView)
+		}
+	}
+
+	// Updates from a cancelled search are dropped so they cannot leak into a newer one
+	go func() {
+		err := a.azureClient.SearchBlobs(ctx, subscriptionID, resourceGroupName, storageAccountName, containerName, query, func(results []*models.Blob, scanned int) {
+			a.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				a.blobSearchView.AddResults(results, scanned)
+				refresh()
+			})
+		})
+
+		a.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			cancel()
+			a.blobSearchView.FinishSearch()
+			refresh()
+			if err != nil && !errors.Is(err, context.Canceled) {
+				a.showError("Blob search failed", err)
+			}
+		})
+	}()
+}
+
+// cancelBlobSearch stops a running search, if any
+func (a *App) cancelBlobSearch() {
+	if a.blobSearchCancel != nil {
+		a.blobSearchCancel()
+		a.blobSearchCancel = nil
+	}
+	a.blobSearchView.FinishSearch()
+}
+
+// navigateBackFromBlobSearch stops the search and returns to the folder it started from
+func (a *App) navigateBackFromBlobSearch() {
+	a.cancelBlobSearch()
+	a.navState.NavigateBackFromBlobSearch()
+	a.loadBlobsForCurrentPath()
+}
+
+// openBlobSearchResult opens the folder of a search result in the blobs view and selects the blob
+func (a *App) openBlobSearchResult(blob *models.Blob) {
+	a.cancelBlobSearch()
+	a.navState.NavigateToBlobSearchResult(blob.Name)
+	a.loadBlobsForCurrentPath()
+	a.blobsView.SelectBlob(blob.Name)
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/bulk"
+
+	"github.com/rivo/tview"
+)
+
+// bulkSummaryLimit is the number of items a bulk confirmation lists by name
+const bulkSummaryLimit = 200
+
+// confirmBulkAction asks to confirm an action on several items, listing them under the question
+func (a *App) confirmBulkAction(question string, labels []string, confirmLabel string, onConfirm func()) {
+	var summary strings.Builder
+	summary.WriteString(tview.Escape(question) + "\n")
+	for i, label := range labels {
+		if i == bulkSummaryLimit {
+			summary.WriteString(fmt.Sprintf("\n... and %d more", len(labels)-bulkSummaryLimit))
+			break
+		}
+		summary.WriteString("\n  " + tview.Escape(label))
+	}
+	a.confirmChanges(fmt.Sprintf("%s %d item(s)", confirmLabel, len(labels)), summary.String(), confirmLabel, onConfirm)
+}
+
+// runBulkAction runs an action on several items in the background, bulk.Concurrency at a time, with a
+// progress dialog. prepare resolves the items, along with anything they share such as a client. Stop
+// stops starting items; once the run has ended, onDone receives the progress on the UI goroutine,
+// Results lists the outcome of every item and Close runs onClose. onDone and onClose may be nil.
+func (a *App) runBulkAction(title string, prepare func(ctx context.Context) ([]bulk.Item, error), onDone func(bulk.Progress), onClose func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := false
+	var final bulk.Progress
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s\n\nPreparing...", title)).
+		AddButtons([]string{"Stop"})
+	modal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if !done {
+			cancel()
+			return
+		}
+		if buttonLabel == "Results" {
+			a.showTextDialog(title, formatBulkResults(final))
+			return
+		}
+		a.closeDialog()
+		if onClose != nil {
+			onClose()
+		}
+	})
+	a.showModal(modal)
+
+	finish := func(status, detail string, progress bulk.Progress) {
+		a.QueueUpdateDraw(func() {
+			done = true
+			final = progress
+			if onDone != nil {
+				onDone(progress)
+			}
+			text := fmt.Sprintf("%s\n\n%s", status, formatBulkProgress(progress))
+			if detail != "" {
+				text += "\n\n" + detail
+			}
+			modal.SetText(text)
+			modal.ClearButtons().AddButtons([]string{"Results", "Close"})
+			a.SetFocus(modal)
+		})
+	}
+
+	go func() {
+		defer cancel()
+
+		items, err := prepare(ctx)
+		if err != nil {
+			finish(fmt.Sprintf("%s failed", title), fmt.Sprintf("%v", err), bulk.Progress{})
+			return
+		}
+
+		progress, err := bulk.Run(ctx, items, bulk.Concurrency, func(p bulk.Progress) {
+			a.QueueUpdateDraw(func() {
+				if !done {
+					modal.SetText(fmt.Sprintf("%s\n\n%s", title, formatBulkProgress(p)))
+				}
+			})
+		})
+		if err != nil {
+			finish(fmt.Sprintf("%s stopped", title), fmt.Sprintf("%d item(s) were not started", progress.Total-progress.Succeeded-progress.Failed), progress)
+			return
+		}
+		finish(fmt.Sprintf("%s finished", title), "", progress)
+	}()
+}
+
+// formatBulkProgress renders the progress of a bulk action for the progress dialog
+func formatBulkProgress(progress bulk.Progress) string {
+	return fmt.Sprintf("%d of %d succeeded, %d failed", progress.Succeeded, progress.Total, progress.Failed)
+}
+
+// formatBulkResults lists the outcome of every item of a bulk action
+func formatBulkResults(progress bulk.Progress) string {
+	if len(progress.Results) == 0 {
+		return "No items were processed."
+	}
+
+	var text strings.Builder
+	for _, result := range progress.Results {
+		if result.Error == "" {
+			text.WriteString(fmt.Sprintf("[green]OK[white]     %s\n", tview.Escape(result.Label)))
+		} else {
+			text.WriteString(fmt.Sprintf("[red]FAILED[white] %s: %s\n", tview.Escape(result.Label), tview.Escape(result.Error)))
+		}
+	}
+	if skipped := progress.Total - progress.Succeeded - progress.Failed; skipped > 0 {
+		text.WriteString(fmt.Sprintf("\n%d item(s) were not started\n", skipped))
+	}
+	return text.String()
+}
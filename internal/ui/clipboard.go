@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/models"
+)
+
+// DefaultClipboardTimeout is how long a copied secret value stays in the clipboard
+const DefaultClipboardTimeout = 30 * time.Second
+
+// SetClipboardTimeout sets how long copied secret values stay in the clipboard; 0 keeps them
+func (a *App) SetClipboardTimeout(timeout time.Duration) {
+	a.clipboardTimeout = timeout
+}
+
+// copySecretValue copies a secret value, or the value of one version, to the clipboard without displaying it
+func (a *App) copySecretValue(secret *models.Secret) {
+	if !a.clipboard.Available() {
+		a.showError("Failed to copy secret value", fmt.Errorf("no clipboard available: install pbcopy, wl-copy, xclip or xsel, or use a terminal with OSC 52 support"))
+		return
+	}
+
+	ctx := context.Background()
+	value, err := a.azureClient.GetSecretValue(ctx, a.navState.SelectedKeyVaultURL, secret.Name, secret.Version)
+	if err != nil {
+		a.showError("Failed to get secret value", err)
+		return
+	}
+	a.copyToClipboard(fmt.Sprintf("secret '%s'", secret.Name), value)
+}
+
+// copyToClipboard copies a sensitive value and schedules clearing it
+func (a *App) copyToClipboard(label, value string) {
+	method, generation, err := a.clipboard.Copy(value)
+	if err != nil {
+		a.showError("Failed to copy to clipboard", err)
+		return
+	}
+
+	message := fmt.Sprintf("Copied the value of %s to the clipboard (%s).", label, method)
+	if a.clipboardTimeout > 0 {
+		a.clipboardPending = generation
+		time.AfterFunc(a.clipboardTimeout, func() {
+			a.QueueUpdate(func() {
+				if a.clipboardPending == generation {
+					a.clearPendingClipboard()
+				}
+			})
+		})
+		message += fmt.Sprintf("\n\nIt will be cleared in %s or when you quit.", a.clipboardTimeout)
+	}
+	a.showInfo(message)
+}
+
+// clearPendingClipboard clears a copied value that has not been cleared yet
+func (a *App) clearPendingClipboard() {
+	if a.clipboardPending == 0 {
+		return
+	}
+	_ = a.clipboard.ClearIfUnchanged(a.clipboardPending)
+	a.clipboardPending = 0
+}
+
+// maskSecretValue hides a value except for its first and last two characters. Short values are hidden
+// completely, and the mask has a fixed length so that it does not reveal the length of the value.
+func maskSecretValue(value string) string {
+	runes := []rune(value)
+	if len(runes) < 12 {
+		return strings.Repeat("•", 8)
+	}
+	return string(runes[:2]) + strings.Repeat("•", 8) + string(runes[len(runes)-2:])
+}
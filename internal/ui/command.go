@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// runExternalCommand suspends the UI and runs a command of a resource handler action in the terminal.
+// When the command fails, or wait is set, it waits for Enter so that the output can be read.
+func (a *App) runExternalCommand(command []string, wait bool) {
+	if len(command) == 0 || command[0] == "" {
+		a.showError("Failed to run command", fmt.Errorf("the action has no command"))
+		return
+	}
+
+	a.Suspend(func() {
+		fmt.Printf("$ %s\n", strings.Join(command, " "))
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Printf("\nCommand failed: %v\n", err)
+			wait = true
+		}
+		if wait {
+			fmt.Print("\nPress Enter to return to Azure Command Tower...")
+			_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// publicAccessOptions are the container public access levels offered in forms
+var publicAccessOptions = []string{"Private", "Blob", "Container"}
+
+// storageScope returns the subscription, resource group and account of the storage account being explored
+func (a *App) storageScope() (string, string, string) {
+	account := a.storageExplorerView.GetAccount()
+	return account.SubscriptionID, account.ResourceGroup, account.Name
+}
+
+// refreshContainers reloads the container list of the current storage account
+func (a *App) refreshContainers() {
+	if err := a.storageExplorerView.Refresh(context.Background()); err != nil {
+		a.showError("Failed to list containers", err)
+		return
+	}
+	a.updateLayout()
+	a.SetFocus(a.storageExplorerView)
+}
+
+// createContainer shows the new container form
+func (a *App) createContainer() {
+	form := tview.NewForm().
+		AddInputField("Name", "", 0, nil, nil).
+		AddDropDown("Public Access", publicAccessOptions, 0, nil).
+		AddTextArea("Metadata", "", 0, 5, 0, nil)
+
+	form.AddButton("Create", func() {
+		name := strings.TrimSpace(formText(form, "Name"))
+		if err := azure.ValidateContainerName(name); err != nil {
+			a.showError("Invalid container name", err)
+			return
+		}
+		metadata, err := azure.ParseMetadata(formText(form, "Metadata"))
+		if err != nil {
+			a.showError("Invalid metadata", err)
+			return
+		}
+
+		a.closeDialog()
+		subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+		err = a.azureClient.CreateContainer(context.Background(), subscriptionID, resourceGroupName, storageAccountName, name, formOption(form, "Public Access"), metadata)
+		if err != nil {
+			a.showError("Failed to create container", err)
+			return
+		}
+		a.refreshContainers()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "New Container (metadata: one key=value per line)", 70, 15)
+}
+
+// deleteContainer deletes a container after the user types its name
+func (a *App) deleteContainer(container *models.Container) {
+	message := fmt.Sprintf("Delete container '%s' and all of its blobs? This cannot be undone.", container.Name)
+	a.confirmTypedName(message, container.Name, func() {
+		subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+		err := a.azureClient.DeleteContainer(context.Background(), subscriptionID, resourceGroupName, storageAccountName, container.Name)
+		if err != nil {
+			a.showError("Failed to delete container", err)
+			return
+		}
+		a.refreshContainers()
+	})
+}
+
+// setContainerPublicAccess shows the public access level form for a container
+func (a *App) setContainerPublicAccess(container *models.Container) {
+	form := tview.NewForm().
+		AddDropDown("Public Access", publicAccessOptions, indexOf(publicAccessOptions, getPublicAccessDisplay(container.PublicAccess)), nil)
+
+	form.AddButton("Save", func() {
+		access := formOption(form, "Public Access")
+		apply := func() {
+			subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+			err := a.azureClient.SetContainerPublicAccess(context.Background(), subscriptionID, resourceGroupName, storageAccountName, container.Name, access)
+			if err != nil {
+				a.showError("Failed to set public access", err)
+				return
+			}
+			a.refreshContainers()
+		}
+
+		a.closeDialog()
+		if access == "Private" {
+			apply()
+			return
+		}
+		a.confirm(fmt.Sprintf("Allow anonymous %s read access to container '%s'?", strings.ToLower(access), container.Name), "Allow", apply)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Public Access - %s", container.Name), 60, 7)
+}
+
+// editContainerMetadata shows the metadata editor for a container
+func (a *App) editContainerMetadata(container *models.Container) {
+	form := tview.NewForm().
+		AddTextArea("Metadata", azure.FormatMetadata(container.Metadata), 0, 10, 0, nil)
+
+	form.AddButton("Save", func() {
+		metadata, err := azure.ParseMetadata(formText(form, "Metadata"))
+		if err != nil {
+			a.showError("Invalid metadata", err)
+			return
+		}
+
+		a.closeDialog()
+		subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+		err = a.azureClient.SetContainerMetadata(context.Background(), subscriptionID, resourceGroupName, storageAccountName, container.Name, metadata)
+		if err != nil {
+			a.showError("Failed to set metadata", err)
+			return
+		}
+		a.refreshContainers()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Metadata - %s (one key=value per line)", container.Name), 70, 16)
+}
+
+// renderContainerDetails loads protection settings and access policies and shows them in the details view
+func (a *App) renderContainerDetails(container *models.Container) {
+	ctx := context.Background()
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+
+	// Protection settings come from ARM and are unavailable on direct endpoints
+	var protection *models.ContainerProtection
+	if !a.navState.StorageEndpointMode {
+		protection, _ = a.azureClient.GetContainerProtection(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name)
+	}
+	policies, _ := a.azureClient.GetContainerAccessPolicies(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name)
+
+	a.detailsView.ShowContainerDetails(container, storageAccountName, protection, policies)
+
+	actions := []DetailsAction{
+		{Rune: 'p', Label: "access policies", Callback: func() { a.editContainerAccessPolicies(container, policies) }},
+	}
+	if protection != nil {
+		actions = append(actions,
+			DetailsAction{Rune: 'l', Label: "legal hold", Callback: func() { a.editContainerLegalHold(container, protection) }},
+			DetailsAction{Rune: 'i', Label: "immutability", Callback: func() { a.editContainerImmutability(container, protection) }},
+		)
+	}
+	a.detailsView.SetActions(actions)
+}
+
+// editContainerAccessPolicies shows the stored access policy editor for a container
+func (a *App) editContainerAccessPolicies(container *models.Container, policies []*models.StoredAccessPolicy) {
+	form := tview.NewForm().
+		AddTextArea("Policies", azure.FormatStoredAccessPolicies(policies), 0, 7, 0, nil)
+
+	form.AddButton("Save", func() {
+		updated, err := azure.ParseStoredAccessPolicies(formText(form, "Policies"))
+		if err != nil {
+			a.showError("Invalid access policies", err)
+			return
+		}
+
+		a.closeDialog()
+		subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+		err = a.azureClient.SetContainerAccessPolicies(context.Background(), subscriptionID, resourceGroupName, storageAccountName, container.Name, updated)
+		if err != nil {
+			a.showError("Failed to set access policies", err)
+			return
+		}
+		a.refreshContainerDetails(container)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Stored Access Policies (id permissions [start] [expiry], up to 5)", 90, 13)
+}
+
+// editContainerLegalHold shows the legal hold form for a container
+func (a *App) editContainerLegalHold(container *models.Container, protection *models.ContainerProtection) {
+	form := tview.NewForm().
+		AddInputField("Tags", strings.Join(protection.LegalHoldTags, ", "), 0, nil, nil)
+
+	update := func(set bool) {
+		tags, err := azure.ParseLegalHoldTags(formText(form, "Tags"))
+		if err != nil {
+			a.showError("Invalid legal hold tags", err)
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+		if set {
+			err = a.azureClient.SetContainerLegalHold(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name, tags)
+		} else {
+			err = a.azureClient.ClearContainerLegalHold(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name, tags)
+		}
+		if err != nil {
+			a.showError("Failed to update legal hold", err)
+			return
+		}
+		a.refreshContainerDetails(container)
+	}
+
+	form.AddButton("Add Tags", func() { update(true) })
+	form.AddButton("Clear Tags", func() { update(false) })
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Legal Hold - %s", container.Name), 70, 7)
+}
+
+// editContainerImmutability shows the time-based retention policy form for a container
+func (a *App) editContainerImmutability(container *models.Container, protection *models.ContainerProtection) {
+	days := ""
+	if protection.ImmutabilityPeriodDays > 0 {
+		days = strconv.Itoa(int(protection.ImmutabilityPeriodDays))
+	}
+	locked := protection.ImmutabilityState == "Locked"
+
+	form := tview.NewForm().
+		AddInputField("Retention (days)", days, 10, tview.InputFieldInteger, nil).
+		AddCheckbox("Allow protected append writes", protection.AllowProtectedAppendWrites, nil)
+
+	ctx := context.Background()
+	subscriptionID, resourceGroupName, storageAccountName := a.storageScope()
+	etag := protection.ImmutabilityETag
+
+	form.AddButton("Save", func() {
+		period, err := strconv.Atoi(formText(form, "Retention (days)"))
+		if err != nil {
+			a.showError("Invalid retention period", fmt.Errorf("retention must be a number of days"))
+			return
+		}
+
+		a.closeDialog()
+		err = a.azureClient.SetContainerImmutabilityPolicy(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name, int32(period), formChecked(form, "Allow protected append writes"), etag)
+		if err != nil {
+			a.showError("Failed to set immutability policy", err)
+			return
+		}
+		a.refreshContainerDetails(container)
+	})
+
+	if protection.HasImmutabilityPolicy && !locked {
+		form.AddButton("Lock", func() {
+			a.closeDialog()
+			message := fmt.Sprintf("Lock the immutability policy of '%s'? A locked policy can only be extended, never shortened or deleted.", container.Name)
+			a.confirmTypedName(message, container.Name, func() {
+				err := a.azureClient.LockContainerImmutabilityPolicy(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name, etag)
+				if err != nil {
+					a.showError("Failed to lock immutability policy", err)
+					return
+				}
+				a.refreshContainerDetails(container)
+			})
+		})
+		form.AddButton("Delete", func() {
+			a.closeDialog()
+			a.confirm(fmt.Sprintf("Delete the immutability policy of '%s'?", container.Name), "Delete", func() {
+				err := a.azureClient.DeleteContainerImmutabilityPolicy(ctx, subscriptionID, resourceGroupName, storageAccountName, container.Name, etag)
+				if err != nil {
+					a.showError("Failed to delete immutability policy", err)
+					return
+				}
+				a.refreshContainerDetails(container)
+			})
+		})
+	}
+	form.AddButton("Cancel", a.closeDialog)
+
+	title := fmt.Sprintf("Immutability Policy - %s", container.Name)
+	if locked {
+		title += " (locked: retention can only be extended)"
+	}
+	a.showForm(form, title, 80, 9)
+}
+
+// refreshContainerDetails re-renders the details view after a container change
+func (a *App) refreshContainerDetails(container *models.Container) {
+	a.renderContainerDetails(container)
+	a.updateLayout()
+	a.SetFocus(a.detailsView)
+}
@@ -12,10 +12,18 @@ import (
 	"github.com/rivo/tview"
 )
 
+// DetailsAction is a key-triggered action available while a details view is shown
+type DetailsAction struct {
+	Rune     rune
+	Label    string
+	Callback func()
+}
+
 // DetailsView displays detailed information about a resource
 type DetailsView struct {
 	*tview.TextView
 	registry *resource.Registry
+	actions  []DetailsAction
 	onBack   func()
 	theme    *Theme
 }
@@ -122,8 +130,37 @@ func (dv *DetailsView) ShowResourceDetails(resource *models.Resource, subscripti
 	dv.SetText(content.String())
 }
 
-// ShowContainerDetails displays container details
-func (dv *DetailsView) ShowContainerDetails(container *models.Container, storageAccountName string) {
+// SetActions sets the actions available for the currently shown item
+func (dv *DetailsView) SetActions(actions []DetailsAction) {
+	dv.actions = actions
+}
+
+// GetActionsText returns the actions formatted for the footer, e.g. "l: legal hold, i: immutability"
+func (dv *DetailsView) GetActionsText() string {
+	labels := make([]string, len(dv.actions))
+	for i, action := range dv.actions {
+		labels[i] = fmt.Sprintf("%c: %s", action.Rune, action.Label)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// HandleKey runs the action bound to the pressed key, if any
+func (dv *DetailsView) HandleKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+	for _, action := range dv.actions {
+		if action.Rune == event.Rune() && action.Callback != nil {
+			action.Callback()
+			return nil
+		}
+	}
+	return event
+}
+
+// ShowContainerDetails displays container details.
+// protection and policies are optional; nil values are rendered as unavailable.
+func (dv *DetailsView) ShowContainerDetails(container *models.Container, storageAccountName string, protection *models.ContainerProtection, policies []*models.StoredAccessPolicy) {
 	var content strings.Builder
 	content.WriteString("[lightblue::b]Container Details[white]\n\n")
 	content.WriteString(fmt.Sprintf("[lightblue::b]Storage Account:[white] %s\n", storageAccountName))
@@ -141,6 +178,43 @@ func (dv *DetailsView) ShowContainerDetails(container *models.Container, storage
 		content.WriteString("\n[lightblue::b]Metadata:[white] None\n")
 	}
 
+	content.WriteString("\n[lightblue::b]Data Protection:[white]\n")
+	if protection == nil {
+		content.WriteString(fmt.Sprintf("  [lightblue::b]Legal Hold:[white] %v\n", container.HasLegalHold))
+		content.WriteString(fmt.Sprintf("  [lightblue::b]Immutability Policy:[white] %v\n", container.HasImmutabilityPolicy))
+	} else {
+		if protection.HasLegalHold {
+			content.WriteString(fmt.Sprintf("  [lightblue::b]Legal Hold:[white] %s\n", strings.Join(protection.LegalHoldTags, ", ")))
+		} else {
+			content.WriteString("  [lightblue::b]Legal Hold:[white] None\n")
+		}
+		if protection.ImmutabilityState != "" {
+			content.WriteString(fmt.Sprintf("  [lightblue::b]Immutability Policy:[white] %s, %d days", protection.ImmutabilityState, protection.ImmutabilityPeriodDays))
+			if protection.AllowProtectedAppendWrites {
+				content.WriteString(", protected append writes allowed")
+			}
+			content.WriteString("\n")
+		} else {
+			content.WriteString("  [lightblue::b]Immutability Policy:[white] None\n")
+		}
+	}
+
+	if len(policies) > 0 {
+		content.WriteString("\n[lightblue::b]Stored Access Policies:[white]\n")
+		for _, policy := range policies {
+			start, expiry := "-", "-"
+			if policy.Start != nil {
+				start = policy.Start.Format("2006-01-02 15:04:05")
+			}
+			if policy.Expiry != nil {
+				expiry = policy.Expiry.Format("2006-01-02 15:04:05")
+			}
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s (start: %s, expiry: %s)\n", policy.ID, policy.Permission, start, expiry))
+		}
+	} else {
+		content.WriteString("\n[lightblue::b]Stored Access Policies:[white] None\n")
+	}
+
 	dv.SetText(content.String())
 }
 
@@ -161,6 +235,21 @@ func (dv *DetailsView) ShowBlobDetails(blob *models.Blob, storageAccountName, co
 	content.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", blob.Name))
 	content.WriteString(fmt.Sprintf("[lightblue::b]Size:[white] %s\n", formatBlobSize(blob.Size)))
 	content.WriteString(fmt.Sprintf("[lightblue::b]Content Type:[white] %s\n", blob.ContentType))
+	if blob.CacheControl != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Cache Control:[white] %s\n", blob.CacheControl))
+	}
+	if blob.ContentDisposition != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Content Disposition:[white] %s\n", blob.ContentDisposition))
+	}
+	if blob.AccessTier != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Access Tier:[white] %s\n", blob.AccessTier))
+	}
+	if blob.ArchiveStatus != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Archive Status:[white] %s\n", blob.ArchiveStatus))
+	}
+	if blob.RehydratePriority != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Rehydrate Priority:[white] %s\n", blob.RehydratePriority))
+	}
 	content.WriteString(fmt.Sprintf("[lightblue::b]Last Modified:[white] %s\n", blob.LastModified.Format("2006-01-02 15:04:05")))
 	content.WriteString(fmt.Sprintf("[lightblue::b]ETag:[white] %s\n", blob.ETag))
 
@@ -182,6 +271,9 @@ func (dv *DetailsView) ShowSecretDetails(secret *models.Secret, keyVaultName str
 	content.WriteString("[lightblue::b]Secret Details[white]\n\n")
 	content.WriteString(fmt.Sprintf("[lightblue::b]Key Vault:[white] %s\n", keyVaultName))
 	content.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", secret.Name))
+	if secret.Version != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Version:[white] %s\n", secret.Version))
+	}
 	content.WriteString(fmt.Sprintf("[lightblue::b]Enabled:[white] %v\n", secret.Enabled))
 	
 	if secret.ContentType != "" {
@@ -213,6 +305,43 @@ func (dv *DetailsView) ShowSecretDetails(secret *models.Secret, keyVaultName str
 	dv.SetText(content.String())
 }
 
+// ShowDeletedItemDetails shows details for a soft-deleted Key Vault item
+func (dv *DetailsView) ShowDeletedItemDetails(item *models.DeletedItem, keyVault *models.KeyVault, keyVaultName string) {
+	var content strings.Builder
+	content.WriteString("[lightblue::b]Deleted Item Details[white]\n\n")
+	content.WriteString(fmt.Sprintf("[lightblue::b]Key Vault:[white] %s\n", keyVaultName))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Type:[white] %s\n", item.Type))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", item.Name))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Enabled:[white] %v\n", item.Enabled))
+	if item.DeletedDate != nil {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Deleted:[white] %s\n", item.DeletedDate.Local().Format("2006-01-02 15:04:05")))
+	}
+	if item.ScheduledPurgeDate != nil {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Scheduled Purge:[white] %s\n", item.ScheduledPurgeDate.Local().Format("2006-01-02 15:04:05")))
+	}
+	if item.RecoveryID != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Recovery ID:[white] %s\n", item.RecoveryID))
+	}
+
+	if keyVault != nil {
+		content.WriteString("\n[lightblue::b]Vault Deletion Settings:[white]\n")
+		content.WriteString(fmt.Sprintf("  [lightblue::b]Soft Delete:[white] %v\n", keyVault.SoftDeleteEnabled))
+		content.WriteString(fmt.Sprintf("  [lightblue::b]Retention:[white] %d days\n", keyVault.SoftDeleteRetentionDays))
+		content.WriteString(fmt.Sprintf("  [lightblue::b]Purge Protection:[white] %v\n", keyVault.PurgeProtectionEnabled))
+	}
+
+	if len(item.Tags) > 0 {
+		content.WriteString("\n[lightblue::b]Tags:[white]\n")
+		for key, value := range item.Tags {
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s\n", key, value))
+		}
+	} else {
+		content.WriteString("\n[lightblue::b]Tags:[white] None\n")
+	}
+
+	dv.SetText(content.String())
+}
+
 // ShowKeyDetails shows details for a Key Vault key
 func (dv *DetailsView) ShowKeyDetails(key *models.Key, keyVaultName string) {
 	var content strings.Builder
@@ -220,6 +349,15 @@ func (dv *DetailsView) ShowKeyDetails(key *models.Key, keyVaultName string) {
 	content.WriteString(fmt.Sprintf("[lightblue::b]Key Vault:[white] %s\n", keyVaultName))
 	content.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", key.Name))
 	content.WriteString(fmt.Sprintf("[lightblue::b]Type:[white] %s\n", key.KeyType))
+	if key.KeySize > 0 {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Key Size:[white] %d\n", key.KeySize))
+	}
+	if key.Curve != "" {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Curve:[white] %s\n", key.Curve))
+	}
+	if len(key.KeyOps) > 0 {
+		content.WriteString(fmt.Sprintf("[lightblue::b]Operations:[white] %s\n", strings.Join(key.KeyOps, ", ")))
+	}
 	content.WriteString(fmt.Sprintf("[lightblue::b]Enabled:[white] %v\n", key.Enabled))
 	
 	if key.Version != "" {
@@ -330,3 +468,235 @@ func (dv *DetailsView) GetKeyBindings() map[tcell.Key]func() {
 		},
 	}
 }
+
+// ShowSecretHistory shows the timeline of a secret: its versions and who changed or read it
+func (dv *DetailsView) ShowSecretHistory(history *models.SecretHistory, keyVaultName string) {
+	audited := 0
+	for _, entry := range history.Timeline {
+		if entry.Source != models.AuditSourceVault {
+			audited++
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString("[lightblue::b]Secret History[white]\n\n")
+	content.WriteString(fmt.Sprintf("[lightblue::b]Key Vault:[white] %s\n", keyVaultName))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Secret:[white] %s\n", history.Name))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Versions:[white] %d\n", len(history.Versions)))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Audited Events:[white] %d\n", audited))
+
+	content.WriteString("\n[lightblue::b]Timeline (newest first):[white]\n")
+	if len(history.Timeline) == 0 {
+		content.WriteString("  No versions or events found\n")
+	}
+	for _, entry := range history.Timeline {
+		content.WriteString(fmt.Sprintf("  [yellow]%s[white] [lightblue::b]%s[white]", entry.Time.Local().Format("2006-01-02 15:04:05"), tview.Escape(entry.Operation)))
+		if entry.Version != "" {
+			content.WriteString(fmt.Sprintf(" %s", entry.Version))
+		}
+		content.WriteString("\n")
+
+		caller := "[gray]caller unknown[white]"
+		if entry.Caller != "" {
+			caller = "by " + tview.Escape(entry.Caller)
+		}
+		if entry.CallerIP != "" {
+			caller += " from " + tview.Escape(entry.CallerIP)
+		}
+		result := ""
+		switch entry.Result {
+		case "":
+		case "OK", "Succeeded":
+			result = fmt.Sprintf(", [green]%s[white]", entry.Result)
+		default:
+			result = fmt.Sprintf(", [red]%s[white]", tview.Escape(entry.Result))
+		}
+		content.WriteString(fmt.Sprintf("    %s%s [gray](%s)[white]\n", caller, result, entry.Source))
+	}
+
+	if len(history.Notes) > 0 {
+		content.WriteString("\n[yellow::b]Notes:[white]\n")
+		for _, note := range history.Notes {
+			content.WriteString(fmt.Sprintf("  [yellow]•[white] %s\n", tview.Escape(note)))
+		}
+	}
+
+	dv.SetText(content.String())
+	dv.ScrollToBeginning()
+}
+
+// ShowKeyVaultDetails shows a Key Vault with its configuration, firewall and private endpoint connections
+func (dv *DetailsView) ShowKeyVaultDetails(vault *models.KeyVault, subscriptionID string) {
+	var content strings.Builder
+	content.WriteString("[lightblue::b]Key Vault Details[white]\n\n")
+	content.WriteString(fmt.Sprintf("[lightblue::b]ID:[white] %s\n", vault.ID))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", vault.Name))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Location:[white] %s\n", vault.Location))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Resource Group:[white] %s\n", vault.ResourceGroup))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Subscription ID:[white] %s\n", subscriptionID))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Vault URI:[white] %s\n", vault.VaultURI))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Tenant ID:[white] %s\n", vault.TenantID))
+	content.WriteString(fmt.Sprintf("[lightblue::b]SKU:[white] %s\n", vault.SKU))
+	if vault.RBACAuthorization {
+		content.WriteString("[lightblue::b]Access Model:[white] Azure RBAC\n")
+	} else {
+		content.WriteString("[lightblue::b]Access Model:[white] Access policies\n")
+	}
+
+	content.WriteString("\n[lightblue::b]Configuration:[white]\n")
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Soft Delete:[white] %v\n", vault.SoftDeleteEnabled))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Retention:[white] %d days\n", vault.SoftDeleteRetentionDays))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Purge Protection:[white] %v\n", vault.PurgeProtectionEnabled))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Enabled For Deployment:[white] %v\n", vault.EnabledForDeploy))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Enabled For Disk Encryption:[white] %v\n", vault.EnabledForDisk))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Enabled For Template Deployment:[white] %v\n", vault.EnabledForTemplate))
+
+	content.WriteString("\n[lightblue::b]Networking:[white]\n")
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Public Network Access:[white] %s\n", vault.PublicNetworkAccess))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Default Action:[white] %s\n", vault.NetworkDefaultAction))
+	content.WriteString(fmt.Sprintf("  [lightblue::b]Bypass:[white] %s\n", vault.NetworkBypass))
+	writeRules := func(label string, rules []string) {
+		if len(rules) == 0 {
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] None\n", label))
+			return
+		}
+		content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white]\n", label))
+		for _, rule := range rules {
+			content.WriteString(fmt.Sprintf("    %s\n", tview.Escape(rule)))
+		}
+	}
+	writeRules("IP Rules", vault.IPRules)
+	writeRules("Virtual Network Rules", vault.VirtualNetworkRules)
+
+	content.WriteString(fmt.Sprintf("\n[lightblue::b]Private Endpoint Connections (%d):[white]\n", len(vault.PrivateEndpointConnections)))
+	for _, connection := range vault.PrivateEndpointConnections {
+		statusColor := "white"
+		switch connection.Status {
+		case "Approved":
+			statusColor = "green"
+		case "Pending":
+			statusColor = "yellow"
+		case "Rejected", "Disconnected":
+			statusColor = "red"
+		}
+		content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[%s] %s[white]\n", tview.Escape(connection.Name), statusColor, connection.Status))
+		if connection.PrivateEndpointID != "" {
+			content.WriteString(fmt.Sprintf("    [lightblue::b]Endpoint:[white] %s\n", tview.Escape(connection.PrivateEndpointID)))
+		}
+		if connection.Description != "" {
+			content.WriteString(fmt.Sprintf("    [lightblue::b]Description:[white] %s\n", tview.Escape(connection.Description)))
+		}
+		if connection.ProvisioningState != "" {
+			content.WriteString(fmt.Sprintf("    [lightblue::b]Provisioning:[white] %s\n", connection.ProvisioningState))
+		}
+	}
+
+	if len(vault.Tags) > 0 {
+		content.WriteString("\n[lightblue::b]Tags:[white]\n")
+		for key, value := range vault.Tags {
+			val := ""
+			if value != nil {
+				val = *value
+			}
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s\n", key, val))
+		}
+	} else {
+		content.WriteString("\n[lightblue::b]Tags:[white] None\n")
+	}
+
+	dv.SetText(content.String())
+	dv.ScrollToBeginning()
+}
+
+// ShowKeyVaultAccess shows how access to a Key Vault is granted and what the signed-in identity can do
+func (dv *DetailsView) ShowKeyVaultAccess(access *models.VaultAccess, keyVaultName string) {
+	orDash := func(values []string) string {
+		if len(values) == 0 {
+			return "-"
+		}
+		return strings.Join(values, ", ")
+	}
+	principal := func(name, id, principalType string) string {
+		label := tview.Escape(id)
+		if name != "" {
+			label = fmt.Sprintf("%s [gray](%s)[white]", tview.Escape(name), tview.Escape(id))
+		}
+		if principalType != "" {
+			label += fmt.Sprintf(" [gray]%s[white]", principalType)
+		}
+		return label
+	}
+	isIdentity := func(objectID string) bool {
+		return access.Identity != nil && strings.EqualFold(objectID, access.Identity.ObjectID)
+	}
+
+	var content strings.Builder
+	content.WriteString("[lightblue::b]Key Vault Access[white]\n\n")
+	content.WriteString(fmt.Sprintf("[lightblue::b]Key Vault:[white] %s\n", keyVaultName))
+	if access.RBAC {
+		content.WriteString("[lightblue::b]Access Model:[white] Azure RBAC\n")
+		content.WriteString("  Secrets, keys and certificates are accessed through role assignments; access policies are ignored.\n")
+	} else {
+		content.WriteString("[lightblue::b]Access Model:[white] Access policies\n")
+		content.WriteString("  Secrets, keys and certificates are accessed through access policies; role assignments only manage the vault resource.\n")
+	}
+	if access.Identity != nil {
+		name := access.Identity.Name
+		if name == "" {
+			name = access.Identity.Email
+		}
+		content.WriteString(fmt.Sprintf("[lightblue::b]Signed In As:[white] %s\n", principal(name, access.Identity.ObjectID, "")))
+	}
+
+	content.WriteString("\n[lightblue::b]What You Can Do:[white]\n")
+	if access.Capabilities == nil {
+		content.WriteString("  Unknown, see the notes below\n")
+	}
+	for _, capability := range access.Capabilities {
+		if capability.Allowed {
+			content.WriteString(fmt.Sprintf("  [green]✓[white] %s\n", capability.Name))
+		} else {
+			content.WriteString(fmt.Sprintf("  [red]✗[white] [gray]%s[white]\n", capability.Name))
+		}
+	}
+
+	if !access.RBAC {
+		content.WriteString(fmt.Sprintf("\n[lightblue::b]Access Policies (%d):[white]\n", len(access.AccessPolicies)))
+		for _, policy := range access.AccessPolicies {
+			you := ""
+			if isIdentity(policy.ObjectID) {
+				you = " [yellow](you)[white]"
+			}
+			content.WriteString(fmt.Sprintf("  %s%s\n", principal(policy.DisplayName, policy.ObjectID, policy.PrincipalType), you))
+			if policy.ApplicationID != "" {
+				content.WriteString(fmt.Sprintf("    [lightblue::b]Application:[white] %s\n", tview.Escape(policy.ApplicationID)))
+			}
+			content.WriteString(fmt.Sprintf("    [lightblue::b]Secrets:[white] %s\n", orDash(policy.Secrets)))
+			content.WriteString(fmt.Sprintf("    [lightblue::b]Keys:[white] %s\n", orDash(policy.Keys)))
+			content.WriteString(fmt.Sprintf("    [lightblue::b]Certificates:[white] %s\n", orDash(policy.Certificates)))
+		}
+	}
+
+	content.WriteString(fmt.Sprintf("\n[lightblue::b]Role Assignments (%d):[white]\n", len(access.RoleAssignments)))
+	for _, assignment := range access.RoleAssignments {
+		you := ""
+		if isIdentity(assignment.PrincipalID) {
+			you = " [yellow](you)[white]"
+		}
+		content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s%s\n", tview.Escape(assignment.RoleName),
+			principal(assignment.DisplayName, assignment.PrincipalID, assignment.PrincipalType), you))
+		if assignment.Inherited {
+			content.WriteString(fmt.Sprintf("    [gray]inherited from %s[white]\n", tview.Escape(assignment.Scope)))
+		}
+	}
+
+	if len(access.Notes) > 0 {
+		content.WriteString("\n[yellow::b]Notes:[white]\n")
+		for _, note := range access.Notes {
+			content.WriteString(fmt.Sprintf("  [yellow]•[white] %s\n", tview.Escape(note)))
+		}
+	}
+
+	dv.SetText(content.String())
+	dv.ScrollToBeginning()
+}
@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/azure"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// showDialog displays a primitive centered on top of the main layout.
+// Dialogs stack, so an error raised from a form returns to the form when dismissed.
+// While a dialog is open, global key bindings are suspended so forms can receive every key.
+func (a *App) showDialog(dialog tview.Primitive, width, height int) {
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(dialog, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	a.dialogs = append(a.dialogs, dialogLayer{page: centered, focus: dialog})
+	a.renderDialogs()
+}
+
+// showFullScreenDialog displays a primitive over the whole main layout; focus receives input
+func (a *App) showFullScreenDialog(page, focus tview.Primitive) {
+	a.dialogs = append(a.dialogs, dialogLayer{page: page, focus: focus})
+	a.renderDialogs()
+}
+
+// showModal displays a tview modal on top of the main layout
+func (a *App) showModal(modal *tview.Modal) {
+	a.dialogs = append(a.dialogs, dialogLayer{page: modal, focus: modal})
+	a.renderDialogs()
+}
+
+// closeDialog removes the topmost dialog and focuses the one below it, or the current view
+func (a *App) closeDialog() {
+	if len(a.dialogs) > 0 {
+		a.dialogs = a.dialogs[:len(a.dialogs)-1]
+	}
+	if len(a.dialogs) > 0 {
+		a.renderDialogs()
+		return
+	}
+	a.SetRoot(a.mainFlex, true)
+	a.SetFocus(a.currentView)
+}
+
+// dialogLayer is one open dialog; focus is the primitive that receives input
+type dialogLayer struct {
+	page  tview.Primitive
+	focus tview.Primitive
+}
+
+// isDialogOpen reports whether any dialog is shown
+func (a *App) isDialogOpen() bool {
+	return len(a.dialogs) > 0
+}
+
+// renderDialogs stacks the open dialogs over the main layout
+func (a *App) renderDialogs() {
+	pages := tview.NewPages().
+		AddPage("main", a.mainFlex, true, true)
+	for i, layer := range a.dialogs {
+		pages.AddPage(fmt.Sprintf("dialog-%d", i), layer.page, true, true)
+	}
+
+	a.SetRoot(pages, true)
+	a.SetFocus(a.dialogs[len(a.dialogs)-1].focus)
+}
+
+// showError displays an error message in a modal
+func (a *App) showError(action string, err error) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s\n\n%v", action, err)).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.closeDialog()
+		})
+	modal.SetBackgroundColor(a.detailsView.theme.Error)
+	a.showModal(modal)
+}
+
+// showInfo displays an informational message in a modal
+func (a *App) showInfo(message string) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.closeDialog()
+		})
+	a.showModal(modal)
+}
+
+// showTextDialog displays long, scrollable text such as per-item results; Enter or ESC closes it
+func (a *App) showTextDialog(title, text string) {
+	theme := a.detailsView.theme
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(text)
+	textView.SetBorder(true).
+		SetBorderColor(theme.Border).
+		SetTitle(fmt.Sprintf(" %s - Enter/ESC to close ", title))
+	textView.SetDoneFunc(func(key tcell.Key) {
+		a.closeDialog()
+	})
+	a.showDialog(textView, 90, 24)
+}
+
+// confirm asks a yes/no question and runs onConfirm after the dialog is closed
+func (a *App) confirm(message, confirmLabel string, onConfirm func()) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{confirmLabel, "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.closeDialog()
+			if buttonLabel == confirmLabel {
+				onConfirm()
+			}
+		})
+	a.showModal(modal)
+}
+
+// confirmChanges previews changes in a scrollable view, Tab moves to the buttons; onConfirm runs after the dialog is closed
+func (a *App) confirmChanges(title, changes, confirmLabel string, onConfirm func()) {
+	form := tview.NewForm()
+	form.AddTextView("", changes, 0, 16, true, true)
+	form.AddButton(confirmLabel, func() {
+		a.closeDialog()
+		onConfirm()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, title, 90, 22)
+}
+
+// confirmTypedName guards destructive actions by requiring the user to type the target name
+func (a *App) confirmTypedName(message, name string, onConfirm func()) {
+	form := tview.NewForm()
+	form.AddTextView("", message, 0, 2, true, false)
+	form.AddInputField(fmt.Sprintf("Type %q to confirm", name), "", 0, nil, nil)
+	form.AddButton("Confirm", func() {
+		typed := form.GetFormItem(1).(*tview.InputField).GetText()
+		if typed != name {
+			a.closeDialog()
+			a.showError("Confirmation failed", fmt.Errorf("the typed name does not match %q", name))
+			return
+		}
+		a.closeDialog()
+		onConfirm()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	form.SetFocus(1)
+	a.showForm(form, "Confirm", 70, 11)
+}
+
+// showForm displays a form as a bordered dialog; ESC cancels it
+func (a *App) showForm(form *tview.Form, title string, width, height int) {
+	theme := a.detailsView.theme
+	form.SetBorder(true).
+		SetBorderColor(theme.Border).
+		SetTitle(fmt.Sprintf(" %s ", title))
+	form.SetLabelColor(theme.Label).
+		SetFieldTextColor(theme.Text).
+		SetButtonsAlign(tview.AlignCenter)
+	form.SetCancelFunc(a.closeDialog)
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Ctrl-S submits the form from anywhere, which is handy in multi-line text areas
+		if event.Key() == tcell.KeyCtrlS && form.GetButtonCount() > 0 {
+			form.GetButton(0).InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+			return nil
+		}
+		return event
+	})
+
+	a.showDialog(form, width, height)
+}
+
+// formText returns the text of a form input field or text area by label
+func formText(form *tview.Form, label string) string {
+	switch item := form.GetFormItemByLabel(label).(type) {
+	case *tview.InputField:
+		return item.GetText()
+	case *tview.TextArea:
+		return item.GetText()
+	}
+	return ""
+}
+
+// formTime parses an optional date or date and time field of a form by label with azure.ParseSearchTime;
+// an empty field returns nil
+func formTime(form *tview.Form, label string) (*time.Time, error) {
+	value := strings.TrimSpace(formText(form, label))
+	if value == "" {
+		return nil, nil
+	}
+	t, err := azure.ParseSearchTime(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", label, err)
+	}
+	return &t, nil
+}
+
+// formatFormTime formats an optional time for a form field in local time, the inverse of formTime
+func formatFormTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// formOption returns the selected option of a form drop down by label
+func formOption(form *tview.Form, label string) string {
+	if dropDown, ok := form.GetFormItemByLabel(label).(*tview.DropDown); ok {
+		_, option := dropDown.GetCurrentOption()
+		return option
+	}
+	return ""
+}
+
+// formChecked returns the state of a form checkbox by label
+func formChecked(form *tview.Form, label string) bool {
+	if checkbox, ok := form.GetFormItemByLabel(label).(*tview.Checkbox); ok {
+		return checkbox.IsChecked()
+	}
+	return false
+}
+
+// indexOf returns the index of value in options, or 0 when absent
+func indexOf(options []string, value string) int {
+	for i, option := range options {
+		if option == value {
+			return i
+		}
+	}
+	return 0
+}
@@ -1,17 +1,64 @@
 package ui
 
 import (
+	"fmt"
+
+	"azure-control-tower/internal/navigation"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// Labels of the filter input, showing whether the filter parses
+const (
+	filterLabel      = "[lightblue::b]/[white]"
+	filterErrorLabel = "[red::b]/[white]"
+)
+
+// filterHint is shown next to the filter input while the filter parses
+const filterHint = "[gray]F1: filter syntax"
+
+// filterHelp describes the filter syntax
+const filterHelp = `[yellow::b]Terms[-::-]
+  [green]prod[white]                a shown cell contains prod, ignoring case
+  [green]"web app"[white]           a shown cell contains the phrase
+  [green]/^prod-.*$/[white]         a shown cell matches the regular expression, ignoring case;
+                      write \/ for a slash
+  [green]~kvprd[white]              a shown cell contains these letters in order, such as kv-prod
+
+[yellow::b]Columns[-::-]
+  [green]location:westeurope[white] the Location column contains westeurope
+  [green]type:/vaults$/[white]      the value can be any of the terms above
+  Column names ignore case, spaces, dashes and underscores, so resourcegroup: names
+  Resource Group. Hidden columns can be named too.
+
+[yellow::b]Tags[-::-] (resource and resource group views)
+  [green]tag:env[white]             the row has the env tag
+  [green]tag:env=prod[white]        the env tag is prod, ignoring case; the value can also be a
+                      phrase, a regular expression or a fuzzy term
+
+[yellow::b]Combining[-::-]
+  [green]prod web[white]            both terms match; AND and & can be written between them
+  [green]dev OR test[white]         either term matches; | does the same
+  [green]!tag:owner[white]          the term does not match; NOT does the same
+  [green]prod (web | kv)[white]     parentheses group terms; NOT binds tightest, then AND, then OR
+
+Keywords are uppercase, so "and" and "or" are words. A word such as 12:30 or https://... whose
+part before the colon is not a column is searched as text. Quote a word starting with /, ~ or !,
+or a phrase such as "location:west" to search it as text. Problems are shown next to the filter
+while typing, and Enter only applies a filter without any.`
+
 // FilterMode handles the filter mode (/) input
 type FilterMode struct {
 	app        *tview.Application
 	inputField *tview.InputField
+	statusView *tview.TextView
+	layout     *tview.Flex
 	visible    bool
 	onFilter   func(filterText string)
 	onCancel   func()
+	onCheck    func(filterText string) error
+	onHelp     func()
 	theme      *Theme
 }
 
@@ -20,22 +67,45 @@ func NewFilterMode(app *tview.Application) *FilterMode {
 	theme := DefaultTheme()
 
 	inputField := tview.NewInputField().
-		SetLabel("[lightblue::b]/[white]").
+		SetLabel(filterLabel).
 		SetFieldWidth(0).
 		SetFieldTextColor(theme.Text).
 		SetLabelColor(theme.Label)
 
+	statusView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(filterHint)
+
 	fm := &FilterMode{
 		app:        app,
 		inputField: inputField,
-		visible:    false,
-		theme:      theme,
+		statusView: statusView,
+		layout: tview.NewFlex().
+			AddItem(inputField, 0, 1, true).
+			AddItem(statusView, 0, 1, false),
+		visible: false,
+		theme:   theme,
 	}
 
+	inputField.SetChangedFunc(func(text string) {
+		fm.check(text)
+	})
+	inputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyF1 && fm.onHelp != nil {
+			fm.onHelp()
+			return nil
+		}
+		return event
+	})
+
 	inputField.SetDoneFunc(func(key tcell.Key) {
 		switch key {
 		case tcell.KeyEnter:
 			filterText := inputField.GetText()
+			if !fm.check(filterText) {
+				// The problem is shown next to the input, which stays open to fix it
+				return
+			}
 			fm.Hide()
 			if fm.onFilter != nil {
 				fm.onFilter(filterText)
@@ -55,9 +125,27 @@ func NewFilterMode(app *tview.Application) *FilterMode {
 func (fm *FilterMode) Show() {
 	fm.visible = true
 	fm.inputField.SetText("")
+	fm.check("")
 	fm.app.SetFocus(fm.inputField)
 }
 
+// check parses a filter text, showing its first problem next to the input, and reports whether it
+// has none
+func (fm *FilterMode) check(filterText string) bool {
+	var err error
+	if fm.onCheck != nil {
+		err = fm.onCheck(filterText)
+	}
+	if err != nil {
+		fm.inputField.SetLabel(filterErrorLabel)
+		fm.statusView.SetText(fmt.Sprintf("[red]%s", tview.Escape(err.Error())))
+		return false
+	}
+	fm.inputField.SetLabel(filterLabel)
+	fm.statusView.SetText(filterHint)
+	return true
+}
+
 // Hide hides the filter input field
 func (fm *FilterMode) Hide() {
 	fm.visible = false
@@ -69,11 +157,16 @@ func (fm *FilterMode) IsVisible() bool {
 	return fm.visible
 }
 
-// GetInputField returns the input field for embedding in layouts
+// GetInputField returns the input field, which receives focus while filter mode is visible
 func (fm *FilterMode) GetInputField() *tview.InputField {
 	return fm.inputField
 }
 
+// GetLayout returns the input field and the problems of its filter for embedding in layouts
+func (fm *FilterMode) GetLayout() *tview.Flex {
+	return fm.layout
+}
+
 // SetOnFilter sets the callback for when a filter is applied
 func (fm *FilterMode) SetOnFilter(callback func(string)) {
 	fm.onFilter = callback
@@ -83,3 +176,47 @@ func (fm *FilterMode) SetOnFilter(callback func(string)) {
 func (fm *FilterMode) SetOnCancel(callback func()) {
 	fm.onCancel = callback
 }
+
+// SetOnCheck sets the callback that parses the filter text while it is typed, returning its first problem
+func (fm *FilterMode) SetOnCheck(callback func(string) error) {
+	fm.onCheck = callback
+}
+
+// SetOnHelp sets the callback for showing the filter syntax (F1 key)
+func (fm *FilterMode) SetOnHelp(callback func()) {
+	fm.onHelp = callback
+}
+
+// checkFilter parses a filter text against the current view, which can only refer to its own columns
+func (a *App) checkFilter(filterText string) error {
+	if a.navState.CurrentView == navigation.ViewMenu {
+		return a.menuView.CheckFilter(filterText)
+	}
+	if a.navState.CurrentView == navigation.ViewExplorer {
+		if view, ok := a.explorerView.(interface{ CheckFilter(string) error }); ok {
+			return view.CheckFilter(filterText)
+		}
+		return nil
+	}
+	if table := a.currentTableView(); table != nil {
+		return table.CheckFilter(filterText)
+	}
+	return nil
+}
+
+// showFilterHelp describes the filter syntax over the filter input, which has the focus again once
+// the help is closed
+func (a *App) showFilterHelp() {
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(filterHelp)
+	textView.SetBorder(true).
+		SetBorderColor(a.detailsView.theme.Border).
+		SetTitle(" Filter Syntax - Enter/ESC to close ")
+	textView.SetDoneFunc(func(key tcell.Key) {
+		a.closeDialog()
+		a.SetFocus(a.filterMode.GetInputField())
+	})
+	a.showDialog(textView, 96, 36)
+}
@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// graphScopeAll is the scope option that queries every subscription the user can read
+const graphScopeAll = "All subscriptions"
+
+// defaultGraphQuery is the query offered when the console is first opened
+const defaultGraphQuery = "Resources\n| summarize count() by type\n| order by count_ desc"
+
+// GraphQueryView displays the result of a Resource Graph query, with a column for each column of the result
+type GraphQueryView struct {
+	*TableView
+	query           string
+	subscriptionIDs []string                 // Subscriptions of the query; nil for every subscription
+	scope           string                   // Label of the subscriptions of the query
+	result          *models.GraphQueryResult // Last page read, with the skip token of the next one
+	onShowDetails   func(row []interface{})
+	onEdit          func()
+	onNextPage      func()
+}
+
+// NewGraphQueryView creates a new Resource Graph query view
+func NewGraphQueryView() *GraphQueryView {
+	gv := &GraphQueryView{}
+	gv.TableView = NewTableView(gv.config(nil))
+	return gv
+}
+
+// config returns the table configuration for the columns of a result
+func (gv *GraphQueryView) config(columns []models.GraphColumn) *TableConfig {
+	config := &TableConfig{
+		Title: "",
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if row, ok := data.([]interface{}); ok && gv.onShowDetails != nil {
+						gv.onShowDetails(row)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'e',
+				Label: "Edit Query",
+				Callback: func() bool {
+					if gv.onEdit != nil {
+						gv.onEdit()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'n',
+				Label: "Next Page",
+				Callback: func() bool {
+					if gv.onNextPage != nil {
+						gv.onNextPage()
+						return true
+					}
+					return false
+				},
+			},
+		},
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on a row - show all of its columns
+			if row, ok := data.([]interface{}); ok && gv.onShowDetails != nil {
+				gv.onShowDetails(row)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			row, ok := data.([]interface{})
+			if !ok || columnIndex < 0 || columnIndex >= len(row) {
+				return ""
+			}
+			return tview.Escape(formatGraphValue(row[columnIndex]))
+		},
+	}
+	for _, column := range columns {
+		align := tview.AlignLeft
+		if isNumericGraphColumn(column.Type) {
+			align = tview.AlignRight
+		}
+		config.Columns = append(config.Columns, ColumnConfig{Name: column.Name, Align: align})
+	}
+	return config
+}
+
+// LoadResult shows the first page of the result of a query, replacing the columns of the previous one
+func (gv *GraphQueryView) LoadResult(query string, subscriptionIDs []string, scope string, result *models.GraphQueryResult) {
+	gv.query = query
+	gv.subscriptionIDs = subscriptionIDs
+	gv.scope = scope
+	gv.result = result
+
+	gv.SetConfig(gv.config(result.Columns))
+	gv.LoadData(graphRows(result))
+	gv.Select(1, 0)
+}
+
+// AppendResult adds the next page of the current query to the view
+func (gv *GraphQueryView) AppendResult(result *models.GraphQueryResult) {
+	gv.result = result
+	gv.AppendData(graphRows(result))
+}
+
+// graphRows returns the rows of a result as table data
+func graphRows(result *models.GraphQueryResult) []interface{} {
+	data := make([]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		data[i] = row
+	}
+	return data
+}
+
+// GetQuery returns the query shown in the view, empty before the first one
+func (gv *GraphQueryView) GetQuery() string {
+	return gv.query
+}
+
+// GetSubscriptionIDs returns the subscriptions of the query; nil for every subscription
+func (gv *GraphQueryView) GetSubscriptionIDs() []string {
+	return gv.subscriptionIDs
+}
+
+// GetSkipToken returns the token of the next page of the result, empty when every row is shown
+func (gv *GraphQueryView) GetSkipToken() string {
+	if gv.result == nil {
+		return ""
+	}
+	return gv.result.SkipToken
+}
+
+// GetColumns returns the columns of the result
+func (gv *GraphQueryView) GetColumns() []models.GraphColumn {
+	if gv.result == nil {
+		return nil
+	}
+	return gv.result.Columns
+}
+
+// GetStatus returns the scope and row counts of the result for the view title
+func (gv *GraphQueryView) GetStatus() string {
+	if gv.result == nil {
+		return gv.scope
+	}
+	status := fmt.Sprintf("%s, %d of %d row(s)", gv.scope, len(gv.data), gv.result.TotalRecords)
+	if gv.result.SkipToken != "" {
+		status += ", n: next page"
+	}
+	if gv.result.Truncated {
+		status += ", truncated"
+	}
+	return status
+}
+
+// SetOnShowDetails sets the callback for showing every column of a row (d key or Enter)
+func (gv *GraphQueryView) SetOnShowDetails(callback func(row []interface{})) {
+	gv.onShowDetails = callback
+}
+
+// SetOnEdit sets the callback for editing the query (e key)
+func (gv *GraphQueryView) SetOnEdit(callback func()) {
+	gv.onEdit = callback
+}
+
+// SetOnNextPage sets the callback for loading the next page of the result (n key)
+func (gv *GraphQueryView) SetOnNextPage(callback func()) {
+	gv.onNextPage = callback
+}
+
+// isNumericGraphColumn tells whether a column type of Resource Graph holds numbers
+func isNumericGraphColumn(columnType string) bool {
+	switch columnType {
+	case "integer", "number", "long", "real", "decimal":
+		return true
+	}
+	return false
+}
+
+// formatGraphValue formats a value of a query result on one line. Numbers arrive as float64, so whole
+// numbers are shown without a fraction, and objects and arrays are shown as compact JSON.
+func formatGraphValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// showGraphQueryForm asks for a Resource Graph query and its scope, offering the current query to edit
+func (a *App) showGraphQueryForm() {
+	query := a.graphQueryView.GetQuery()
+	if query == "" {
+		query = defaultGraphQuery
+	}
+
+	// The selected subscription is the default scope, otherwise every subscription is queried
+	scopes := []string{graphScopeAll}
+	if a.navState.SelectedSubscriptionID != "" {
+		scopes = []string{a.navState.SelectedSubscriptionName, graphScopeAll}
+	}
+
+	form := tview.NewForm().
+		AddTextArea("Query", query, 0, 8, 0, nil).
+		AddDropDown("Scope", scopes, 0, nil)
+
+	subscriptionID := a.navState.SelectedSubscriptionID
+	form.AddButton("Run", func() {
+		query := strings.TrimSpace(formText(form, "Query"))
+		if query == "" {
+			a.showError("Invalid query", fmt.Errorf("a KQL query is required"))
+			return
+		}
+		scope := formOption(form, "Scope")
+		var subscriptionIDs []string
+		if scope != graphScopeAll {
+			subscriptionIDs = []string{subscriptionID}
+		}
+
+		a.closeDialog()
+		a.runGraphQuery(query, subscriptionIDs, scope)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Resource Graph Query - Ctrl-S runs the query", 90, 16)
+}
+
+// runGraphQuery runs a Resource Graph query in the background and shows its first page in the query view
+func (a *App) runGraphQuery(query string, subscriptionIDs []string, scope string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Querying Resource Graph (%s)...", scope)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		result, err := a.azureClient.QueryResourceGraph(ctx, query, subscriptionIDs, "")
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to run query", err)
+				}
+				return
+			}
+
+			a.navState.NavigateToGraphQuery()
+			a.graphQueryView.ClearFilter()
+			a.graphQueryView.LoadResult(query, subscriptionIDs, scope, result)
+			a.updateLayout()
+			a.SetFocus(a.graphQueryView)
+		})
+	}()
+}
+
+// loadNextGraphPage reads the next page of the current query and adds it to the query view
+func (a *App) loadNextGraphPage() {
+	skipToken := a.graphQueryView.GetSkipToken()
+	if skipToken == "" {
+		a.showInfo("Every row of the result is shown")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	modal := tview.NewModal().
+		SetText("Reading the next page of the result...").
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	query := a.graphQueryView.GetQuery()
+	subscriptionIDs := a.graphQueryView.GetSubscriptionIDs()
+	go func() {
+		result, err := a.azureClient.QueryResourceGraph(ctx, query, subscriptionIDs, skipToken)
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to read next page", err)
+				}
+				return
+			}
+
+			a.graphQueryView.AppendResult(result)
+			a.updateLayout()
+			a.SetFocus(a.graphQueryView)
+		})
+	}()
+}
+
+// showGraphRow shows every column of a query result row, with objects and arrays as indented JSON
+func (a *App) showGraphRow(row []interface{}) {
+	var text strings.Builder
+	for i, column := range a.graphQueryView.GetColumns() {
+		if i >= len(row) {
+			break
+		}
+		switch value := row[i].(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.MarshalIndent(value, "  ", "  ")
+			if err != nil {
+				text.WriteString(fmt.Sprintf("[lightblue::b]%s:[white] %s\n", tview.Escape(column.Name), tview.Escape(fmt.Sprint(value))))
+				continue
+			}
+			text.WriteString(fmt.Sprintf("[lightblue::b]%s:[white]\n  %s\n", tview.Escape(column.Name), tview.Escape(string(data))))
+		default:
+			text.WriteString(fmt.Sprintf("[lightblue::b]%s:[white] %s\n", tview.Escape(column.Name), tview.Escape(formatGraphValue(value))))
+		}
+	}
+
+	a.showTextDialog("Query Result Row", text.String())
+}
+
+// navigateBackFromGraphQuery returns from the query view to the view the console was opened from
+func (a *App) navigateBackFromGraphQuery() {
+	a.navState.NavigateBackFromGraphQuery()
+	a.updateLayout()
+	a.SetFocus(a.currentView)
+}
@@ -6,6 +6,7 @@ import (
 
 	"azure-control-tower/internal/models"
 	"azure-control-tower/internal/navigation"
+	"azure-control-tower/pkg/resource"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -31,11 +32,14 @@ type HeaderView struct {
 	userInfo               *models.UserInfo
 	selectedSubscription   string
 	selectedSubscriptionID string
+	storageEndpointURL     string
+	storageAccountName     string
 	theme                  *Theme
+	registry               *resource.Registry // Tells which resource types can be explored
 }
 
 // NewHeaderView creates a new header view
-func NewHeaderView() *HeaderView {
+func NewHeaderView(registry *resource.Registry) *HeaderView {
 	// Create user info view (left) - tenant, user, subscriptions
 	userInfoView := tview.NewTextView().
 		SetDynamicColors(true).
@@ -100,21 +104,22 @@ func NewHeaderView() *HeaderView {
 		separator1:   separator1,
 		separator2:   separator2,
 		theme:        theme,
+		registry:     registry,
 	}
 
 	// Initialize actions content with empty state (will be updated when navigation state is available)
-	hv.updateActions(nil)
+	hv.updateActions(nil, nil)
 
 	return hv
 }
 
-// UpdateActions updates the actions based on the current navigation state
-func (hv *HeaderView) UpdateActions(navState *navigation.State) {
-	hv.updateActions(navState)
+// UpdateActions updates the actions based on the current navigation state and the explorer view of a handler
+func (hv *HeaderView) UpdateActions(navState *navigation.State, explorer resource.ExplorerView) {
+	hv.updateActions(navState, explorer)
 }
 
 // updateActions updates the actions/keyboard shortcuts display in 2 columns
-func (hv *HeaderView) updateActions(navState *navigation.State) {
+func (hv *HeaderView) updateActions(navState *navigation.State, explorer resource.ExplorerView) {
 	var actionLines []string
 	actionLines = append(actionLines, "[cyan::b]Actions:[white]")
 
@@ -136,49 +141,146 @@ func (hv *HeaderView) updateActions(navState *navigation.State) {
 		actions = append(actions, "[yellow]/[white] - Filter")
 	}
 
-	// Menu action - available in all table views, not in details view
-	if !navState.InDetailsView {
+	// Sort and column chooser - available in table views, not in the menu or explorers without a table
+	_, explorerHasTable := explorer.(interface{ GetTableView() *TableView })
+	if !navState.InDetailsView && navState.CurrentView != navigation.ViewMenu &&
+		(navState.CurrentView != navigation.ViewExplorer || explorerHasTable) {
+		actions = append(actions, "[yellow]S[white] - Sort", "[yellow]C[white] - Columns")
+	}
+
+	// Menu action - available in all table views, not in details view or without ARM access
+	if !navState.InDetailsView && !navState.StorageEndpointMode {
 		actions = append(actions, "[yellow]M[white] - Menu")
 	}
 
-	// Enter/Select action - available in subscriptions, resource groups, resource types, storage explorer, blobs, key vault views
+	// Resource Graph query console - available in all table views with ARM access
+	if !navState.InDetailsView && !navState.StorageEndpointMode {
+		actions = append(actions, "[yellow]Ctrl-F[white] - Search", "[yellow]Ctrl-G[white] - Graph Query")
+	}
+
+	// Enter/Select action - available in subscriptions, resource groups, resource types, blobs, key vault views
 	if !navState.InDetailsView {
 		switch navState.CurrentView {
 		case navigation.ViewSubscriptions, navigation.ViewResourceGroups, navigation.ViewResourceTypes,
-			navigation.ViewStorageExplorer, navigation.ViewBlobs,
-			navigation.ViewKeyVaultExplorer, navigation.ViewKeyVaultSecrets, navigation.ViewKeyVaultKeys, navigation.ViewKeyVaultCertificates:
+			navigation.ViewBlobs, navigation.ViewBlobSearch, navigation.ViewKeyVaultSecrets, navigation.ViewKeyVaultSecretVersions,
+			navigation.ViewKeyVaultKeys, navigation.ViewKeyVaultCertificates, navigation.ViewKeyVaultDeletedItems,
+			navigation.ViewKeyVaultSecretSync, navigation.ViewKeyVaultExpiry, navigation.ViewGraphQuery,
+			navigation.ViewResourceSearch:
 			actions = append(actions, "[yellow]Enter[white] - Select")
 		}
 	}
 
-	// Explore action (E) - available in resources and resource type views for storage accounts and Key Vaults
+	// Explore action (E) - available in resources view and in resource type views whose handler can explore
 	if !navState.InDetailsView {
 		if navState.CurrentView == navigation.ViewResources ||
-			(navState.CurrentView == navigation.ViewResourceType && 
-				(navState.SelectedResourceType == "Microsoft.Storage/storageAccounts" || 
-				 navState.SelectedResourceType == "Microsoft.KeyVault/vaults")) {
+			(navState.CurrentView == navigation.ViewResourceType && hv.canExplore(navState.SelectedResourceType)) {
 			actions = append(actions, "[yellow]E[white] - Explore")
 		}
 	}
 
+	// JSON action (j) - available in resources, resource type and resource search views
+	if !navState.InDetailsView &&
+		(navState.CurrentView == navigation.ViewResources || navState.CurrentView == navigation.ViewResourceType ||
+			navState.CurrentView == navigation.ViewResourceSearch) {
+		actions = append(actions, "[yellow]j[white] - JSON")
+	}
+
+	// Tags action (g) - available in resources and resource type views
+	if !navState.InDetailsView &&
+		(navState.CurrentView == navigation.ViewResources || navState.CurrentView == navigation.ViewResourceType) {
+		actions = append(actions, "[yellow]g[white] - Tags")
+	}
+
 	// View secret value action (V) - available in Key Vault secrets view
+	if !navState.InDetailsView &&
+		(navState.CurrentView == navigation.ViewKeyVaultSecrets || navState.CurrentView == navigation.ViewKeyVaultSecretVersions) {
+		actions = append(actions, "[yellow]V[white] - View Value", "[yellow]y[white] - Copy Value")
+	}
+
+	// Explorer actions - the keys of the explorer view of a handler, such as the storage and Key Vault explorers
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewExplorer && explorer != nil {
+		actions = append(actions, explorerActions(explorer.Actions())...)
+	}
+
+	// Secret management actions - available in Key Vault secrets view
 	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultSecrets {
-		actions = append(actions, "[yellow]V[white] - View Value")
+		actions = append(actions, "[yellow]n[white] - New", "[yellow]h[white] - Versions", "[yellow]l[white] - History", "[yellow]c[white] - Copy to Vault", "[yellow]e[white] - Export")
+	}
+
+	// Secret version actions - available in Key Vault secret versions view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultSecretVersions {
+		actions = append(actions, "[yellow]l[white] - History", "[yellow]c[white] - Compare")
+	}
+
+	// Key management actions - available in Key Vault keys view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultKeys {
+		actions = append(actions, "[yellow]c[white] - Crypto", "[yellow]r[white] - Rotate", "[yellow]n[white] - New", "[yellow]i[white] - Import")
+	}
+
+	// Certificate management actions - available in Key Vault certificates view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultCertificates {
+		actions = append(actions, "[yellow]n[white] - New", "[yellow]i[white] - Import", "[yellow]w[white] - Download", "[yellow]o[white] - Operation")
+	}
+
+	// Delete action - available in Key Vault item views
+	if !navState.InDetailsView {
+		switch navState.CurrentView {
+		case navigation.ViewKeyVaultSecrets, navigation.ViewKeyVaultKeys, navigation.ViewKeyVaultCertificates:
+			actions = append(actions, "[yellow]x[white] - Delete", "[yellow]b[white] - Backup")
+		}
+	}
+
+	// Apply action - available in Key Vault secret sync view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultSecretSync {
+		actions = append(actions, "[yellow]a[white] - Apply", "[yellow]v[white] - View Values")
 	}
 
-	// Details action (d) - available in subscriptions, resource groups, resources, resource type, storage explorer, blobs, and Key Vault views
-	// Not available in resource types view or details view
+	// Expiry report action - available in subscriptions view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewSubscriptions && !navState.StorageEndpointMode {
+		actions = append(actions, "[yellow]k[white] - Key Vault Expiry")
+	}
+
+	// Export and rescan actions - available in Key Vault expiry view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultExpiry {
+		actions = append(actions, "[yellow]e[white] - Export", "[yellow]r[white] - Rescan")
+	}
+
+	// Edit search action - available in resource search view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewResourceSearch {
+		actions = append(actions, "[yellow]e[white] - Edit Search")
+	}
+
+	// Edit and paging actions - available in Resource Graph query view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewGraphQuery {
+		actions = append(actions, "[yellow]e[white] - Edit Query", "[yellow]n[white] - Next Page")
+	}
+
+	// Recover and purge actions - available in Key Vault deleted items view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewKeyVaultDeletedItems {
+		actions = append(actions, "[yellow]r[white] - Recover", "[yellow]p[white] - Purge")
+	}
+
+	// Search, delete and download actions - available in blobs view
+	if !navState.InDetailsView && navState.CurrentView == navigation.ViewBlobs {
+		actions = append(actions, "[yellow]s[white] - Search", "[yellow]x[white] - Delete", "[yellow]w[white] - Download")
+	}
+
+	// Details action (d) - available in subscriptions, resource groups, resources, resource type, blobs, and Key Vault views
+	// Not available in resource types view or details view; explorers list it with their own keys
 	if !navState.InDetailsView {
 		switch navState.CurrentView {
 		case navigation.ViewSubscriptions, navigation.ViewResourceGroups, navigation.ViewResources,
-			navigation.ViewResourceType, navigation.ViewStorageExplorer, navigation.ViewBlobs,
-			navigation.ViewKeyVaultSecrets, navigation.ViewKeyVaultKeys, navigation.ViewKeyVaultCertificates:
+			navigation.ViewResourceType, navigation.ViewBlobs, navigation.ViewBlobSearch,
+			navigation.ViewKeyVaultSecrets, navigation.ViewKeyVaultSecretVersions, navigation.ViewKeyVaultKeys, navigation.ViewKeyVaultCertificates,
+			navigation.ViewKeyVaultDeletedItems, navigation.ViewKeyVaultSecretSync, navigation.ViewKeyVaultExpiry,
+			navigation.ViewGraphQuery, navigation.ViewResourceSearch:
 			actions = append(actions, "[yellow]d[white] - Details")
 		}
 	}
 
 	// Back action (Esc) - available when not at root (subscriptions view)
-	if navState.CurrentView != navigation.ViewSubscriptions {
+	if navState.CurrentView != navigation.ViewSubscriptions &&
+		!(navState.StorageEndpointMode && navState.CurrentView == navigation.ViewExplorer) {
 		actions = append(actions, "[yellow::b]Esc[white] - Back")
 	}
 
@@ -198,6 +300,28 @@ func (hv *HeaderView) updateActions(navState *navigation.State) {
 	hv.actionsView.SetText(strings.Join(actionLines, "\n"))
 }
 
+// explorerActions formats the footer keys of an explorer view, such as "Enter: open, d: details", for the header
+func explorerActions(footer string) []string {
+	var actions []string
+	for _, action := range strings.Split(footer, ", ") {
+		key, label, ok := strings.Cut(action, ": ")
+		if !ok || label == "" {
+			continue
+		}
+		actions = append(actions, fmt.Sprintf("[yellow]%s[white] - %s", key, strings.ToUpper(label[:1])+label[1:]))
+	}
+	return actions
+}
+
+// canExplore tells whether the handler of a resource type has an exploration view
+func (hv *HeaderView) canExplore(resourceType string) bool {
+	if hv.registry == nil {
+		return false
+	}
+	handler := hv.registry.GetHandlerOrDefault(resourceType)
+	return handler != nil && handler.CanExplore()
+}
+
 // UpdateUserInfo updates the user information displayed in the header
 func (hv *HeaderView) UpdateUserInfo(userInfo *models.UserInfo) {
 	hv.userInfo = userInfo
@@ -211,8 +335,24 @@ func (hv *HeaderView) UpdateSelectedSubscription(subscriptionName, subscriptionI
 	hv.updateContent()
 }
 
+// UpdateStorageEndpoint shows the direct storage endpoint instead of tenant and subscription information
+func (hv *HeaderView) UpdateStorageEndpoint(serviceURL, accountName string) {
+	hv.storageEndpointURL = serviceURL
+	hv.storageAccountName = accountName
+	hv.updateContent()
+}
+
 // updateContent refreshes the header content
 func (hv *HeaderView) updateContent() {
+	if hv.storageEndpointURL != "" {
+		var content strings.Builder
+		content.WriteString("[lightblue::b]Mode:[white] Direct storage endpoint\n")
+		content.WriteString(fmt.Sprintf("[lightblue::b]Account:[white] %s\n", hv.storageAccountName))
+		content.WriteString(fmt.Sprintf("[lightblue::b]Endpoint:[white] %s", hv.storageEndpointURL))
+		hv.userInfoView.SetText(content.String())
+		return
+	}
+
 	if hv.userInfo == nil {
 		hv.userInfoView.SetText("[yellow]Loading user information...[white]")
 		return
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"azure-control-tower/internal/models"
+	"azure-control-tower/pkg/resource"
+)
+
+// The application is the host of the resource handlers, and provides the explorer views of the storage
+// and Key Vault handlers
+var (
+	_ resource.Host                 = (*App)(nil)
+	_ resource.StorageExplorerView  = (*StorageExplorerView)(nil)
+	_ resource.KeyVaultExplorerView = (*KeyVaultExplorerView)(nil)
+)
+
+// ShowExplorer shows the explorer view of a resource handler in place of the resource list
+func (a *App) ShowExplorer(view resource.ExplorerView) {
+	a.explorerView = view
+	a.navState.NavigateToExplorer()
+	a.updateLayout()
+	a.SetFocus(view)
+}
+
+// RunCommand runs an external command for a resource handler
+func (a *App) RunCommand(command []string, wait bool) {
+	a.runExternalCommand(command, wait)
+}
+
+// ShowError shows an error dialog for a resource handler
+func (a *App) ShowError(action string, err error) {
+	a.showError(action, err)
+}
+
+// StorageExplorerView returns the view the storage handler opens storage accounts in
+func (a *App) StorageExplorerView() resource.StorageExplorerView {
+	return a.storageExplorerView
+}
+
+// KeyVaultExplorerView returns the view the Key Vault handler opens vaults in
+func (a *App) KeyVaultExplorerView() resource.KeyVaultExplorerView {
+	return a.keyVaultExplorerView
+}
+
+// showHandlerDetails lets the handler of a resource replace its details with a details view of its own
+func (a *App) showHandlerDetails(item *models.Resource, subscriptionID string) {
+	if provider, ok := a.registry.GetHandlerOrDefault(item.Type).(resource.DetailsProvider); ok {
+		provider.ShowDetails(a, item, subscriptionID)
+	}
+}
+
+// navigateBackFromExplorer returns from the explorer view of a handler to the resource list
+func (a *App) navigateBackFromExplorer() {
+	a.explorerView = nil
+	a.navigateBackToResourceType()
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"azure-control-tower/internal/azure"
+
+	"github.com/rivo/tview"
+)
+
+// showVaultAccess reads the access model of the selected vault in the background and shows it in the details view
+func (a *App) showVaultAccess() {
+	if a.navState.SelectedKeyVaultRG == "" {
+		a.showError("Failed to read vault access", fmt.Errorf("the resource group of %s is unknown", a.navState.SelectedKeyVault))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	subscriptionID := a.navState.SelectedSubscriptionID
+	resourceGroup := a.navState.SelectedKeyVaultRG
+	vaultName := a.navState.SelectedKeyVault
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Reading access policies and role assignments of %s...", vaultName)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		access, err := a.azureClient.GetVaultAccess(ctx, subscriptionID, resourceGroup, vaultName)
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to read vault access", err)
+				}
+				return
+			}
+
+			a.navState.NavigateToDetails()
+			a.detailsView.ShowKeyVaultAccess(access, vaultName)
+			a.updateLayout()
+			a.SetFocus(a.detailsView)
+		})
+	}()
+}
+
+// showVaultListError returns to the Key Vault explorer after the items of a vault could not be listed.
+// A permission error points at the access view, which explains how access to the vault is granted.
+func (a *App) showVaultListError(itemType string, err error) {
+	a.navigateBackToKeyVaultExplorer()
+	if azure.IsForbidden(err) {
+		a.showError(fmt.Sprintf("You are not allowed to list %s in %s. Press a to see how access to the vault is granted.", itemType, a.navState.SelectedKeyVault), err)
+		return
+	}
+	a.showError(fmt.Sprintf("Failed to list %s", itemType), err)
+}
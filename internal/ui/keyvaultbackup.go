@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// backupVaultItems asks for a backup directory and backs up the given items; nil items means the whole vault
+func (a *App) backupVaultItems(items []models.VaultItemRef) {
+	vaultName := a.navState.SelectedKeyVault
+	defaultDir := fmt.Sprintf("%s-backup-%s", vaultName, time.Now().Format("20060102-150405"))
+
+	form := tview.NewForm().
+		AddInputField("Directory", defaultDir, 0, nil, nil).
+		AddTextView("", "A new or empty directory. Files are encrypted by Key Vault only, not again locally.", 0, 2, true, false)
+
+	form.AddButton("Backup", func() {
+		dir := strings.TrimSpace(formText(form, "Directory"))
+		if dir == "" {
+			a.showError("Invalid directory", fmt.Errorf("enter the directory to write the backup to"))
+			return
+		}
+		a.closeDialog()
+		a.runVaultBackup(items, dir)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	title := fmt.Sprintf("Backup %d item(s) from %s", len(items), vaultName)
+	if items == nil {
+		title = fmt.Sprintf("Backup vault %s", vaultName)
+	}
+	a.showForm(form, title, 70, 11)
+}
+
+// runVaultBackup backs up items in the background with a progress dialog
+func (a *App) runVaultBackup(items []models.VaultItemRef, dir string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	vaultName := a.navState.SelectedKeyVault
+	vaultURL := a.navState.SelectedKeyVaultURL
+
+	update, finish := a.showItemProgress(fmt.Sprintf("Backing up %s to %s", vaultName, dir), cancel, func() {
+		a.keyVaultSecretsView.ClearMarks()
+		a.keyVaultKeysView.ClearMarks()
+		a.keyVaultCertificatesView.ClearMarks()
+	})
+
+	go func() {
+		defer cancel()
+
+		if items == nil {
+			resolved, err := a.listVaultBackupItems(ctx, vaultURL)
+			if err != nil {
+				finish("Backup failed", fmt.Sprintf("%v", err), models.ItemProgress{})
+				return
+			}
+			items = resolved
+		}
+
+		progress, err := a.azureClient.BackupVaultItems(ctx, vaultName, vaultURL, items, dir, func(p models.ItemProgress) {
+			update(formatItemProgress(p))
+		})
+		status := "Backup finished"
+		detail := fmt.Sprintf("Written to %s", dir)
+		if err != nil {
+			status = "Backup stopped"
+			detail = fmt.Sprintf("%v", err)
+		}
+		finish(status, detail, progress)
+	}()
+}
+
+// listVaultBackupItems lists every secret, key and certificate of a vault for a full backup
+func (a *App) listVaultBackupItems(ctx context.Context, vaultURL string) ([]models.VaultItemRef, error) {
+	secrets, err := a.azureClient.ListSecrets(ctx, vaultURL)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := a.azureClient.ListKeys(ctx, vaultURL)
+	if err != nil {
+		return nil, err
+	}
+	certificates, err := a.azureClient.ListCertificates(ctx, vaultURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretNames, keyNames, certificateNames []string
+	for _, secret := range secrets {
+		secretNames = append(secretNames, secret.Name)
+	}
+	for _, key := range keys {
+		keyNames = append(keyNames, key.Name)
+	}
+	for _, cert := range certificates {
+		certificateNames = append(certificateNames, cert.Name)
+	}
+	return azure.VaultBackupItems(secretNames, keyNames, certificateNames), nil
+}
+
+// restoreVaultBackup asks for a backup directory and restores its items into the selected vault
+func (a *App) restoreVaultBackup() {
+	form := tview.NewForm().
+		AddInputField("Directory", "", 0, nil, nil)
+
+	form.AddButton("Restore", func() {
+		dir := strings.TrimSpace(formText(form, "Directory"))
+		manifest, err := azure.ReadBackupManifest(dir)
+		if err != nil {
+			a.showError("Invalid backup", err)
+			return
+		}
+		if len(manifest.Items) == 0 {
+			a.showError("Invalid backup", fmt.Errorf("the backup in %s has no items", dir))
+			return
+		}
+
+		a.closeDialog()
+		message := fmt.Sprintf("Restore %d item(s) backed up from %s on %s into %s?\n\nItems that already exist in the vault, including deleted items, are skipped with an error.",
+			len(manifest.Items), manifest.Vault, manifest.Created.Local().Format("2006-01-02 15:04:05"), a.navState.SelectedKeyVault)
+		a.confirm(message, "Restore", func() {
+			a.runVaultRestore(dir, manifest)
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Restore backup into %s", a.navState.SelectedKeyVault), 70, 9)
+}
+
+// runVaultRestore restores backup items in the background with a progress dialog
+func (a *App) runVaultRestore(dir string, manifest *models.BackupManifest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	vaultName := a.navState.SelectedKeyVault
+	vaultURL := a.navState.SelectedKeyVaultURL
+
+	update, finish := a.showItemProgress(fmt.Sprintf("Restoring %s into %s", dir, vaultName), cancel, nil)
+
+	go func() {
+		defer cancel()
+
+		progress, err := a.azureClient.RestoreVaultItems(ctx, vaultURL, dir, manifest.Items, func(p models.ItemProgress) {
+			update(formatItemProgress(p))
+		})
+		status := "Restore finished"
+		detail := fmt.Sprintf("Restored into %s", vaultName)
+		if err != nil {
+			status = "Restore stopped"
+			detail = fmt.Sprintf("%v", err)
+		}
+		finish(status, detail, progress)
+	}()
+}
+
+// showItemProgress shows a progress dialog for an operation on several Key Vault items. Stop cancels the run; once it
+// has finished, Close dismisses the dialog and Results lists the outcome of every item.
+func (a *App) showItemProgress(title string, cancel context.CancelFunc, onClose func()) (func(string), func(string, string, models.ItemProgress)) {
+	done := false
+	var final models.ItemProgress
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s\n\nPreparing...", title)).
+		AddButtons([]string{"Stop"})
+	modal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if !done {
+			cancel()
+			return
+		}
+		if buttonLabel == "Results" {
+			a.showTextDialog(title, formatItemResults(final))
+			return
+		}
+		a.closeDialog()
+		if onClose != nil {
+			onClose()
+		}
+	})
+	a.showModal(modal)
+
+	update := func(text string) {
+		a.QueueUpdateDraw(func() {
+			modal.SetText(fmt.Sprintf("%s\n\n%s", title, text))
+		})
+	}
+	finish := func(status, detail string, progress models.ItemProgress) {
+		a.QueueUpdateDraw(func() {
+			done = true
+			final = progress
+			modal.SetText(fmt.Sprintf("%s\n\n%s\n\n%s", status, formatItemProgress(progress), detail))
+			modal.ClearButtons().AddButtons([]string{"Results", "Close"})
+			a.SetFocus(modal)
+		})
+	}
+	return update, finish
+}
+
+// formatItemProgress renders the progress of an operation on several items for the progress dialog
+func formatItemProgress(progress models.ItemProgress) string {
+	return fmt.Sprintf("%d of %d succeeded, %d failed", progress.Succeeded, progress.Total, progress.Failed)
+}
+
+// formatItemResults lists the outcome of every item of an operation
+func formatItemResults(progress models.ItemProgress) string {
+	if len(progress.Results) == 0 {
+		return "No items were processed."
+	}
+
+	var text strings.Builder
+	for _, result := range progress.Results {
+		if result.Error == "" {
+			text.WriteString(fmt.Sprintf("[green]OK[white]     %s %s\n", result.Item.Type, result.Item.Name))
+		} else {
+			text.WriteString(fmt.Sprintf("[red]FAILED[white] %s %s: %s\n", result.Item.Type, result.Item.Name, tview.Escape(result.Error)))
+		}
+	}
+	return text.String()
+}
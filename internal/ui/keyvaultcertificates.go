@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/export"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// certificateIssuers are the issuers offered in the certificate form; a configured CA issuer is typed in
+var certificateIssuers = []string{azure.CertificateIssuerSelf, azure.CertificateIssuerUnknown, "Configured issuer"}
+
+// certificateContentTypes are the formats of the secret backing a new certificate
+var certificateContentTypes = []string{azure.CertificateContentTypePKCS12, azure.CertificateContentTypePEM}
+
+// createCertificate shows the policy form for a self-signed, CA-issued or CSR-based certificate
+func (a *App) createCertificate() {
+	form := tview.NewForm().
+		AddInputField("Name", "", 0, nil, nil).
+		AddInputField("Subject", "CN=", 0, nil, nil).
+		AddInputField("Alternative Names", "", 0, nil, nil).
+		AddDropDown("Issuer", certificateIssuers, 0, nil).
+		AddInputField("Issuer Name", "", 0, nil, nil).
+		AddDropDown("Key Type", azure.KeyTypes, 0, nil).
+		AddDropDown("Key Size", azure.KeySizes, 0, nil).
+		AddDropDown("Curve", azure.KeyCurves, 0, nil).
+		AddCheckbox("Exportable Key", true, nil).
+		AddDropDown("Content Type", certificateContentTypes, 0, nil).
+		AddInputField("Validity (months)", "12", 0, nil, nil).
+		AddCheckbox("Auto-Renew", true, nil).
+		AddInputField("Renew At (% lifetime)", "80", 0, nil, nil).
+		AddTextArea("Tags", "", 0, 3, 0, nil)
+
+	form.AddButton("Create", func() {
+		cert, err := newCertificateFromForm(form)
+		if err != nil {
+			a.showError("Invalid certificate", err)
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		op, err := a.azureClient.CreateCertificate(ctx, a.navState.SelectedKeyVaultURL, cert)
+		if err != nil {
+			a.showError("Failed to create certificate", err)
+			return
+		}
+		a.refreshCertificates()
+
+		message := fmt.Sprintf("Certificate '%s' is being issued (status: %s). Press o to follow the operation.", cert.Name, op.Status)
+		if cert.Issuer == azure.CertificateIssuerUnknown {
+			message = fmt.Sprintf("Certificate '%s' is pending. Download the CSR with w, have it signed by your CA, then merge the signed certificate with u.", cert.Name)
+		}
+		a.showInfo(message)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "New Certificate - key size applies to RSA, curve to EC", 80, 34)
+}
+
+// newCertificateFromForm validates the certificate policy form
+func newCertificateFromForm(form *tview.Form) (*models.NewCertificate, error) {
+	cert := &models.NewCertificate{
+		Name:        strings.TrimSpace(formText(form, "Name")),
+		Subject:     strings.TrimSpace(formText(form, "Subject")),
+		Issuer:      formOption(form, "Issuer"),
+		KeyType:     formOption(form, "Key Type"),
+		Curve:       formOption(form, "Curve"),
+		Exportable:  formChecked(form, "Exportable Key"),
+		ContentType: formOption(form, "Content Type"),
+		AutoRenew:   formChecked(form, "Auto-Renew"),
+	}
+	if err := azure.ValidateCertificateName(cert.Name); err != nil {
+		return nil, err
+	}
+	if cert.Issuer == "Configured issuer" {
+		cert.Issuer = strings.TrimSpace(formText(form, "Issuer Name"))
+	}
+
+	var err error
+	if cert.DNSNames, cert.Emails, err = azure.ParseSubjectAlternativeNames(formText(form, "Alternative Names")); err != nil {
+		return nil, err
+	}
+	if cert.KeySize, err = strconv.Atoi(formOption(form, "Key Size")); err != nil {
+		return nil, fmt.Errorf("invalid key size: %w", err)
+	}
+	if cert.ValidityMonths, err = strconv.Atoi(strings.TrimSpace(formText(form, "Validity (months)"))); err != nil {
+		return nil, fmt.Errorf("validity must be a number of months")
+	}
+	if cert.RenewPercentage, err = strconv.Atoi(strings.TrimSpace(formText(form, "Renew At (% lifetime)"))); err != nil {
+		return nil, fmt.Errorf("renewal must be a percentage of the lifetime")
+	}
+	if cert.Tags, err = azure.ParseTags(formText(form, "Tags")); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// importCertificate imports a certificate with its private key from a PFX or PEM file
+func (a *App) importCertificate() {
+	form := tview.NewForm().
+		AddInputField("Name", "", 0, nil, nil).
+		AddInputField("File", "", 0, nil, nil).
+		AddPasswordField("PFX Password", "", 0, '*', nil)
+
+	form.AddButton("Import", func() {
+		name := strings.TrimSpace(formText(form, "Name"))
+		if err := azure.ValidateCertificateName(name); err != nil {
+			a.showError("Invalid certificate", err)
+			return
+		}
+		path := strings.TrimSpace(formText(form, "File"))
+		if path == "" {
+			a.showError("Invalid certificate", fmt.Errorf("enter the path of the PFX or PEM file"))
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			a.showError("Invalid certificate", fmt.Errorf("failed to read certificate file: %w", err))
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		if err := a.azureClient.ImportCertificate(ctx, a.navState.SelectedKeyVaultURL, name, data, formText(form, "PFX Password")); err != nil {
+			a.showError("Failed to import certificate", err)
+			return
+		}
+		a.refreshCertificates()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Import Certificate - PFX, or PEM with certificate and private key", 80, 11)
+}
+
+// downloadCertificate saves a certificate as CER or PEM, in full with its private key, or its pending CSR
+func (a *App) downloadCertificate(cert *models.Certificate) {
+	form := tview.NewForm()
+	form.AddDropDown("Format", azure.CertificateDownloadFormats, 0, nil).
+		AddInputField("File", "", 0, nil, nil)
+
+	form.AddButton("Download", func() {
+		format := formOption(form, "Format")
+		path := strings.TrimSpace(formText(form, "File"))
+
+		save := func() {
+			ctx := context.Background()
+			data, ext, err := a.azureClient.DownloadCertificate(ctx, a.navState.SelectedKeyVaultURL, cert.Name, format)
+			if err != nil {
+				a.showError("Failed to download certificate", err)
+				return
+			}
+			if path == "" {
+				path = cert.Name + ext
+			}
+			if format == azure.CertificateDownloadFull {
+				err = export.WriteFile(path, data)
+			} else {
+				err = os.WriteFile(path, data, 0644)
+			}
+			if err != nil {
+				a.showError("Failed to save certificate", err)
+				return
+			}
+			a.showInfo(fmt.Sprintf("Saved certificate '%s' (%s) to %s", cert.Name, format, path))
+		}
+
+		a.closeDialog()
+		if format == azure.CertificateDownloadFull {
+			a.confirm(fmt.Sprintf("Download certificate '%s' with its private key?\n\n⚠️ The file is written unencrypted and readable only by you (mode 0600). Keep it secure and delete it when it is no longer needed.", cert.Name),
+				"Download", save)
+			return
+		}
+		save()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Download %s - an empty file name uses the certificate name", cert.Name), 80, 9)
+}
+
+// showCertificateOperation shows the state of the latest issuance of a certificate
+func (a *App) showCertificateOperation(cert *models.Certificate) {
+	ctx := context.Background()
+	op, err := a.azureClient.GetCertificateOperation(ctx, a.navState.SelectedKeyVaultURL, cert.Name)
+	if err != nil {
+		a.showError("Failed to get certificate operation", err)
+		return
+	}
+
+	orDash := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return tview.Escape(value)
+	}
+	statusColor := "white"
+	switch op.Status {
+	case "completed":
+		statusColor = "green"
+	case "failed", "cancelled":
+		statusColor = "red"
+	case "inProgress":
+		statusColor = "yellow"
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("[lightblue::b]Status:[%s] %s[white]\n", statusColor, orDash(op.Status)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Status Details:[white] %s\n", orDash(op.StatusDetails)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Issuer:[white] %s\n", orDash(op.Issuer)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Request ID:[white] %s\n", orDash(op.RequestID)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Target:[white] %s\n", orDash(op.Target)))
+	if op.CancellationRequested {
+		text.WriteString("[lightblue::b]Cancellation:[white] requested\n")
+	}
+	if op.Error != "" {
+		text.WriteString(fmt.Sprintf("\n[red::b]Error:[white] %s\n", tview.Escape(op.Error)))
+	}
+	if len(op.CSR) > 0 && op.Status == "inProgress" {
+		text.WriteString("\n[lightblue::b]Certificate Signing Request:[white]\n")
+		text.WriteString(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: op.CSR})))
+		if op.Issuer == azure.CertificateIssuerUnknown {
+			text.WriteString("\nHave the CSR signed by your CA, then press u to merge the signed certificate.\n")
+		}
+	}
+
+	a.showTextDialog(fmt.Sprintf("Certificate Operation - %s", cert.Name), text.String())
+}
+
+// mergeCertificate completes a pending certificate with the certificate signed from its CSR
+func (a *App) mergeCertificate(cert *models.Certificate) {
+	form := tview.NewForm().
+		AddInputField("Signed Certificate File", "", 0, nil, nil)
+
+	form.AddButton("Merge", func() {
+		path := strings.TrimSpace(formText(form, "Signed Certificate File"))
+		if path == "" {
+			a.showError("Invalid certificate", fmt.Errorf("enter the path of the signed certificate"))
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			a.showError("Invalid certificate", fmt.Errorf("failed to read certificate file: %w", err))
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		if err := a.azureClient.MergeCertificate(ctx, a.navState.SelectedKeyVaultURL, cert.Name, data); err != nil {
+			a.showError("Failed to merge certificate", err)
+			return
+		}
+		a.refreshCertificates()
+		a.showInfo(fmt.Sprintf("The signed certificate was merged into '%s'", cert.Name))
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Merge %s - PEM, DER or base64, with its chain", cert.Name), 80, 7)
+}
+
+// refreshCertificates reloads the certificates view
+func (a *App) refreshCertificates() {
+	ctx := context.Background()
+	vaultURL := a.navState.SelectedKeyVaultURL
+	certificates, err := a.keyVaultExplorerView.GetFetcher().ListCertificates(ctx, vaultURL)
+	if err != nil {
+		a.showError("Failed to list certificates", err)
+		return
+	}
+
+	a.keyVaultCertificatesView.LoadCertificates(ctx, certificates, a.navState.SelectedKeyVault, vaultURL)
+	a.updateLayout()
+	a.SetFocus(a.keyVaultCertificatesView)
+}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+	"azure-control-tower/pkg/resource"
+
+	"github.com/rivo/tview"
+)
+
+// renderKeyVaultDetails shows a Key Vault with its configuration and the actions to change it
+func (a *App) renderKeyVaultDetails(target resource.KeyVault) {
+	vault, err := a.azureClient.GetKeyVault(context.Background(), target.SubscriptionID, target.ResourceGroup, target.Name)
+	if err != nil {
+		a.showError("Failed to read vault configuration", err)
+		return
+	}
+	a.showKeyVaultDetails(vault, target.SubscriptionID)
+}
+
+// showKeyVaultDetails shows a vault in the details view with its configuration actions
+func (a *App) showKeyVaultDetails(vault *models.KeyVault, subscriptionID string) {
+	a.detailsView.ShowKeyVaultDetails(vault, subscriptionID)
+
+	resource := &models.Resource{ID: vault.ID, Name: vault.Name, Type: "Microsoft.KeyVault/vaults"}
+	actions := []DetailsAction{
+		a.resourceJSONAction(resource, subscriptionID),
+		{Rune: 'c', Label: "configuration", Callback: func() { a.editVaultConfig(vault, subscriptionID) }},
+	}
+	if len(vault.PrivateEndpointConnections) > 0 {
+		actions = append(actions, DetailsAction{Rune: 'p', Label: "private endpoints", Callback: func() { a.managePrivateEndpoints(vault, subscriptionID) }})
+	}
+	a.detailsView.SetActions(actions)
+}
+
+// editVaultConfig shows the network and configuration settings of a vault, and previews the changes before applying them
+func (a *App) editVaultConfig(vault *models.KeyVault, subscriptionID string) {
+	current := azure.VaultConfigOf(vault)
+
+	form := tview.NewForm().
+		AddDropDown("Public Network Access", azure.PublicNetworkAccessOptions, indexOf(azure.PublicNetworkAccessOptions, current.PublicNetworkAccess), nil).
+		AddDropDown("Default Action", azure.NetworkDefaultActions, indexOf(azure.NetworkDefaultActions, current.NetworkDefaultAction), nil).
+		AddDropDown("Bypass", azure.NetworkBypassOptions, indexOf(azure.NetworkBypassOptions, current.NetworkBypass), nil).
+		AddTextArea("IP Rules", strings.Join(current.IPRules, "\n"), 0, 4, 0, nil).
+		AddTextArea("Virtual Network Rules", strings.Join(current.VirtualNetworkRules, "\n"), 0, 4, 0, nil).
+		// Key Vault only sets the soft-delete retention when a vault is created
+		AddTextView("Soft Delete Retention", fmt.Sprintf("%d days, set when the vault was created", vault.SoftDeleteRetentionDays), 0, 1, true, false).
+		AddCheckbox("Purge Protection", current.PurgeProtection, nil).
+		AddCheckbox("Enabled For Deployment", current.EnabledForDeployment, nil).
+		AddCheckbox("Enabled For Disk Encryption", current.EnabledForDiskEncryption, nil).
+		AddCheckbox("Enabled For Template Deployment", current.EnabledForTemplateDeployment, nil)
+
+	form.AddButton("Review", func() {
+		updated, err := vaultConfigFromForm(form)
+		if err != nil {
+			a.showError("Invalid configuration", err)
+			return
+		}
+		if err := azure.ValidateVaultConfig(current, updated); err != nil {
+			a.showError("Invalid configuration", err)
+			return
+		}
+		changes := azure.DiffVaultConfig(current, updated)
+		if len(changes) == 0 {
+			a.showInfo("No changes to apply")
+			return
+		}
+
+		a.closeDialog()
+		a.confirmChanges(fmt.Sprintf("Apply %d change(s) to %s?", len(changes), vault.Name), formatVaultConfigChanges(changes, updated), "Apply", func() {
+			ctx := context.Background()
+			result, err := a.azureClient.UpdateVaultConfig(ctx, subscriptionID, vault.ResourceGroup, vault.Name, current, updated)
+			if err != nil {
+				a.showError("Failed to update vault configuration", err)
+				return
+			}
+			a.showKeyVaultDetails(result, subscriptionID)
+			a.showInfo(fmt.Sprintf("Updated the configuration of %s", vault.Name))
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Configure %s - one rule per line", vault.Name), 100, 30)
+}
+
+// vaultConfigFromForm reads and parses the vault configuration form
+func vaultConfigFromForm(form *tview.Form) (models.VaultConfig, error) {
+	config := models.VaultConfig{
+		PublicNetworkAccess:          formOption(form, "Public Network Access"),
+		NetworkDefaultAction:         formOption(form, "Default Action"),
+		NetworkBypass:                formOption(form, "Bypass"),
+		PurgeProtection:              formChecked(form, "Purge Protection"),
+		EnabledForDeployment:         formChecked(form, "Enabled For Deployment"),
+		EnabledForDiskEncryption:     formChecked(form, "Enabled For Disk Encryption"),
+		EnabledForTemplateDeployment: formChecked(form, "Enabled For Template Deployment"),
+	}
+
+	var err error
+	if config.IPRules, err = azure.ParseIPRules(formText(form, "IP Rules")); err != nil {
+		return config, err
+	}
+	if config.VirtualNetworkRules, err = azure.ParseVirtualNetworkRules(formText(form, "Virtual Network Rules")); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// formatVaultConfigChanges renders the configuration changes as a diff, with warnings for changes that are
+// hard to undo or may lock out the caller
+func formatVaultConfigChanges(changes []models.VaultConfigChange, updated models.VaultConfig) string {
+	var text strings.Builder
+	for _, change := range changes {
+		switch {
+		case change.From == "":
+			text.WriteString(fmt.Sprintf("[green]+ %s:[white] %s\n", change.Setting, tview.Escape(change.To)))
+		case change.To == "":
+			text.WriteString(fmt.Sprintf("[red]- %s:[white] %s\n", change.Setting, tview.Escape(change.From)))
+		default:
+			text.WriteString(fmt.Sprintf("[lightblue::b]%s:[white] %s → [yellow]%s[white]\n", change.Setting, tview.Escape(change.From), tview.Escape(change.To)))
+		}
+	}
+
+	var warnings []string
+	firewallChanged := false
+	for _, change := range changes {
+		switch change.Setting {
+		case "Purge Protection":
+			warnings = append(warnings, "Purge protection cannot be disabled once enabled.")
+		case "Public Network Access":
+			if updated.PublicNetworkAccess == "Disabled" {
+				warnings = append(warnings, "With public network access disabled, the vault is only reachable through private endpoints.")
+			}
+		case "Default Action", "IP Rule", "Virtual Network Rule":
+			firewallChanged = true
+		}
+	}
+	if firewallChanged && updated.NetworkDefaultAction == "Deny" {
+		warnings = append(warnings, "The firewall denies traffic that no rule allows. Make sure your own address is allowed before applying.")
+	}
+	if len(warnings) > 0 {
+		text.WriteString("\n")
+		for _, warning := range warnings {
+			text.WriteString(fmt.Sprintf("[yellow]⚠️ %s[white]\n", warning))
+		}
+	}
+	return text.String()
+}
+
+// managePrivateEndpoints approves, rejects or removes the private endpoint connections of a vault
+func (a *App) managePrivateEndpoints(vault *models.KeyVault, subscriptionID string) {
+	var labels []string
+	for _, connection := range vault.PrivateEndpointConnections {
+		labels = append(labels, fmt.Sprintf("%s (%s)", connection.Name, connection.Status))
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Connection", labels, 0, nil).
+		AddDropDown("Action", azure.PrivateEndpointActions, 0, nil).
+		AddInputField("Description", "", 0, nil, nil)
+
+	form.AddButton("Apply", func() {
+		index, _ := form.GetFormItemByLabel("Connection").(*tview.DropDown).GetCurrentOption()
+		if index < 0 {
+			return
+		}
+		connection := vault.PrivateEndpointConnections[index]
+		action := formOption(form, "Action")
+		description := strings.TrimSpace(formText(form, "Description"))
+
+		a.closeDialog()
+		a.confirm(fmt.Sprintf("%s private endpoint connection '%s' of %s?", action, connection.Name, vault.Name), action, func() {
+			ctx := context.Background()
+			var err error
+			if action == azure.PrivateEndpointRemove {
+				err = a.azureClient.DeletePrivateEndpointConnection(ctx, subscriptionID, vault.ResourceGroup, vault.Name, connection.Name)
+			} else {
+				err = a.azureClient.SetPrivateEndpointConnectionStatus(ctx, subscriptionID, vault.ResourceGroup, vault.Name, connection.Name, action, description)
+			}
+			if err != nil {
+				a.showError("Failed to update private endpoint connection", err)
+				return
+			}
+
+			refreshed, err := a.azureClient.GetKeyVault(ctx, subscriptionID, vault.ResourceGroup, vault.Name)
+			if err != nil {
+				a.showError("Failed to read vault configuration", err)
+				return
+			}
+			a.showKeyVaultDetails(refreshed, subscriptionID)
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Private Endpoints - %s", vault.Name), 90, 11)
+}
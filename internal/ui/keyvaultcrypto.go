@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// cryptoInputSources are the ways the input of a crypto operation can be provided
+var cryptoInputSources = []string{"Text", "File"}
+
+// showKeyCryptoConsole opens the crypto console for a key. The operations and algorithms offered
+// depend on the key type, curve and permitted operations, which only the key details carry.
+func (a *App) showKeyCryptoConsole(key *models.Key) {
+	ctx := context.Background()
+	fullKey, err := a.azureClient.GetKeyDetails(ctx, a.navState.SelectedKeyVaultURL, key.Name)
+	if err != nil {
+		a.showError("Failed to get key details", err)
+		return
+	}
+	operations := azure.KeyOperations(fullKey)
+	if len(operations) == 0 {
+		a.showError("No crypto operations", fmt.Errorf("key '%s' (%s) permits no encrypt, decrypt, sign, verify, wrap or unwrap operations", key.Name, fullKey.KeyType))
+		return
+	}
+
+	form := tview.NewForm()
+	form.AddDropDown("Operation", operations, 0, nil).
+		AddDropDown("Algorithm", nil, 0, nil).
+		AddDropDown("Input Source", cryptoInputSources, 0, nil).
+		AddTextArea("Input", "", 0, 4, 0, nil).
+		AddInputField("File", "", 0, nil, nil).
+		AddDropDown("Input Encoding", azure.CryptoEncodings, 0, nil).
+		AddInputField("Signature", "", 0, nil, nil).
+		AddDropDown("Output Encoding", azure.CryptoEncodings, 0, nil)
+
+	// Follow the operation with its algorithms and the usual encodings: binary data is base64,
+	// plaintext and messages are text
+	form.GetFormItemByLabel("Operation").(*tview.DropDown).SetSelectedFunc(func(operation string, index int) {
+		algorithms := form.GetFormItemByLabel("Algorithm").(*tview.DropDown)
+		algorithms.SetOptions(azure.CryptoAlgorithms(fullKey, operation), nil)
+		algorithms.SetCurrentOption(0)
+
+		inputEncoding, outputEncoding := azure.EncodingBase64, azure.EncodingBase64
+		switch operation {
+		case models.KeyOperationEncrypt, models.KeyOperationSign, models.KeyOperationVerify:
+			inputEncoding = azure.EncodingText
+		case models.KeyOperationDecrypt:
+			outputEncoding = azure.EncodingText
+		}
+		form.GetFormItemByLabel("Input Encoding").(*tview.DropDown).SetCurrentOption(indexOf(azure.CryptoEncodings, inputEncoding))
+		form.GetFormItemByLabel("Output Encoding").(*tview.DropDown).SetCurrentOption(indexOf(azure.CryptoEncodings, outputEncoding))
+	})
+	form.GetFormItemByLabel("Operation").(*tview.DropDown).SetCurrentOption(0)
+
+	form.AddButton("Run", func() {
+		req, err := cryptoRequestFromForm(form)
+		if err != nil {
+			a.showError("Invalid crypto operation", err)
+			return
+		}
+		outputEncoding := formOption(form, "Output Encoding")
+
+		// The form stays open so that the output can be fed into the next operation
+		result, err := a.azureClient.KeyCryptoOperation(ctx, a.navState.SelectedKeyVaultURL, key.Name, "", req)
+		if err != nil {
+			a.showError(fmt.Sprintf("Failed to %s", req.Operation), err)
+			return
+		}
+		a.showKeyCryptoResult(req, result, outputEncoding)
+	})
+	form.AddButton("Close", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Crypto - %s (%s)", key.Name, fullKey.KeyType), 90, 24)
+}
+
+// cryptoRequestFromForm validates the crypto console form and decodes its input
+func cryptoRequestFromForm(form *tview.Form) (models.KeyCryptoRequest, error) {
+	req := models.KeyCryptoRequest{
+		Operation: formOption(form, "Operation"),
+		Algorithm: formOption(form, "Algorithm"),
+	}
+	if req.Algorithm == "" {
+		return req, fmt.Errorf("the key supports no algorithm for %s", req.Operation)
+	}
+
+	input := formText(form, "Input")
+	if formOption(form, "Input Source") == "File" {
+		path := strings.TrimSpace(formText(form, "File"))
+		if path == "" {
+			return req, fmt.Errorf("enter the path of the input file")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return req, fmt.Errorf("failed to read input file: %w", err)
+		}
+		input = string(data)
+	}
+
+	var err error
+	if req.Input, err = azure.DecodeCryptoInput(input, formOption(form, "Input Encoding")); err != nil {
+		return req, err
+	}
+	if len(req.Input) == 0 {
+		return req, fmt.Errorf("enter the input, or choose an input file")
+	}
+
+	if req.Operation == models.KeyOperationVerify {
+		signature := strings.TrimSpace(formText(form, "Signature"))
+		if signature == "" {
+			return req, fmt.Errorf("enter the signature to verify")
+		}
+		// Signatures are read in the output encoding, the encoding sign shows them in
+		if req.Signature, err = azure.DecodeCryptoInput(signature, formOption(form, "Output Encoding")); err != nil {
+			return req, fmt.Errorf("signature: %w", err)
+		}
+	}
+	return req, nil
+}
+
+// showKeyCryptoResult shows the output of a crypto operation, or whether a signature is valid
+func (a *App) showKeyCryptoResult(req models.KeyCryptoRequest, result *models.KeyCryptoResult, outputEncoding string) {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("[lightblue::b]Operation:[white] %s\n", req.Operation))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Algorithm:[white] %s\n", req.Algorithm))
+	if result.KeyID != "" {
+		text.WriteString(fmt.Sprintf("[lightblue::b]Key:[white] %s\n", tview.Escape(result.KeyID)))
+	}
+
+	if req.Operation == models.KeyOperationVerify {
+		if result.Verified {
+			text.WriteString("\n[green::b]The signature is valid.[white]\n")
+		} else {
+			text.WriteString("\n[red::b]The signature is NOT valid.[white]\n")
+		}
+		a.showTextDialog("Verify", text.String())
+		return
+	}
+
+	output, err := azure.EncodeCryptoOutput(result.Output, outputEncoding)
+	if err != nil {
+		a.showError("Failed to encode output", err)
+		return
+	}
+	text.WriteString(fmt.Sprintf("[lightblue::b]Output (%s, %d bytes):[white]\n%s\n", outputEncoding, len(result.Output), tview.Escape(output)))
+	a.showTextDialog(strings.ToUpper(req.Operation[:1])+req.Operation[1:], text.String())
+}
+
+// createKey shows the form for creating a key, or a new version of a key with the same name
+func (a *App) createKey() {
+	form := tview.NewForm().
+		AddInputField("Name", "", 0, nil, nil).
+		AddDropDown("Key Type", azure.KeyTypes, 0, nil).
+		AddDropDown("Key Size", azure.KeySizes, 0, nil).
+		AddDropDown("Curve", azure.KeyCurves, 0, nil).
+		AddInputField("Operations", "", 0, nil, nil).
+		AddInputField("Expires", "", 0, nil, nil).
+		AddTextArea("Tags", "", 0, 4, 0, nil)
+
+	form.AddButton("Create", func() {
+		key, err := newKeyFromForm(form)
+		if err != nil {
+			a.showError("Invalid key", err)
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		if err := a.azureClient.CreateKey(ctx, a.navState.SelectedKeyVaultURL, key); err != nil {
+			a.showError("Failed to create key", err)
+			return
+		}
+		a.refreshKeys()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "New Key - key size applies to RSA, curve to EC; empty operations permit all", 80, 21)
+}
+
+// newKeyFromForm validates the key creation form
+func newKeyFromForm(form *tview.Form) (*models.NewKey, error) {
+	key := &models.NewKey{
+		Name:    strings.TrimSpace(formText(form, "Name")),
+		KeyType: formOption(form, "Key Type"),
+		Curve:   formOption(form, "Curve"),
+	}
+	if err := azure.ValidateKeyName(key.Name); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if key.KeySize, err = strconv.Atoi(formOption(form, "Key Size")); err != nil {
+		return nil, fmt.Errorf("invalid key size: %w", err)
+	}
+	if key.KeyOps, err = azure.ParseKeyOps(formText(form, "Operations")); err != nil {
+		return nil, err
+	}
+	if key.Expires, err = formTime(form, "Expires"); err != nil {
+		return nil, err
+	}
+	if key.Tags, err = azure.ParseTags(formText(form, "Tags")); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// importKey imports a private key from a PEM or JSON Web Key file
+func (a *App) importKey() {
+	form := tview.NewForm().
+		AddInputField("Name", "", 0, nil, nil).
+		AddInputField("File", "", 0, nil, nil).
+		AddCheckbox("HSM Protected", false, nil)
+
+	form.AddButton("Import", func() {
+		name := strings.TrimSpace(formText(form, "Name"))
+		if err := azure.ValidateKeyName(name); err != nil {
+			a.showError("Invalid key", err)
+			return
+		}
+		path := strings.TrimSpace(formText(form, "File"))
+		if path == "" {
+			a.showError("Invalid key", fmt.Errorf("enter the path of the PEM or JWK file"))
+			return
+		}
+		material, err := os.ReadFile(path)
+		if err != nil {
+			a.showError("Invalid key", fmt.Errorf("failed to read key file: %w", err))
+			return
+		}
+		if _, err := azure.ParseKeyMaterial(material); err != nil {
+			a.showError("Invalid key", err)
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		if err := a.azureClient.ImportKey(ctx, a.navState.SelectedKeyVaultURL, name, material, formChecked(form, "HSM Protected")); err != nil {
+			a.showError("Failed to import key", err)
+			return
+		}
+		a.refreshKeys()
+		a.showInfo(fmt.Sprintf("Key '%s' was imported. The key file still holds the private key; delete it if it is no longer needed.", name))
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Import Key - PEM (PKCS #1, PKCS #8, SEC 1) or JWK", 80, 11)
+}
+
+// rotateKey creates a new version of a key after a confirmation
+func (a *App) rotateKey(key *models.Key) {
+	message := fmt.Sprintf("Rotate key '%s'?\n\nA new key version is created and becomes the current version. Data encrypted or wrapped with older versions still needs those versions to decrypt.", key.Name)
+	a.confirm(message, "Rotate", func() {
+		ctx := context.Background()
+		version, err := a.azureClient.RotateKey(ctx, a.navState.SelectedKeyVaultURL, key.Name)
+		if err != nil {
+			a.showError("Failed to rotate key", err)
+			return
+		}
+		a.refreshKeys()
+		a.showInfo(fmt.Sprintf("Key '%s' was rotated to version %s", key.Name, version))
+	})
+}
+
+// editKeyRotationPolicy shows and edits the automatic rotation policy of a key
+func (a *App) editKeyRotationPolicy(key *models.Key) {
+	ctx := context.Background()
+	vaultURL := a.navState.SelectedKeyVaultURL
+	policy, err := a.azureClient.GetKeyRotationPolicy(ctx, vaultURL, key.Name)
+	if err != nil {
+		a.showError("Failed to get rotation policy", err)
+		return
+	}
+
+	updated := "never"
+	if policy.Updated != nil {
+		updated = policy.Updated.Format("2006-01-02 15:04:05")
+	}
+	form := tview.NewForm()
+	form.AddTextView("", fmt.Sprintf("ISO 8601 durations such as P90D, P6M or P1Y; empty fields are not set. Last updated: %s", updated), 0, 2, true, false).
+		AddInputField("Version Expiry", policy.ExpiryTime, 0, nil, nil).
+		AddInputField("Rotate After Create", policy.RotateAfterCreate, 0, nil, nil).
+		AddInputField("Rotate Before Expiry", policy.RotateBeforeExpiry, 0, nil, nil).
+		AddInputField("Notify Before Expiry", policy.NotifyBeforeExpiry, 0, nil, nil)
+
+	form.AddButton("Save", func() {
+		policy := &models.KeyRotationPolicy{
+			ExpiryTime:         strings.ToUpper(strings.TrimSpace(formText(form, "Version Expiry"))),
+			RotateAfterCreate:  strings.ToUpper(strings.TrimSpace(formText(form, "Rotate After Create"))),
+			RotateBeforeExpiry: strings.ToUpper(strings.TrimSpace(formText(form, "Rotate Before Expiry"))),
+			NotifyBeforeExpiry: strings.ToUpper(strings.TrimSpace(formText(form, "Notify Before Expiry"))),
+		}
+		if err := a.azureClient.UpdateKeyRotationPolicy(ctx, vaultURL, key.Name, policy); err != nil {
+			a.showError("Failed to update rotation policy", err)
+			return
+		}
+		a.closeDialog()
+		a.showInfo(fmt.Sprintf("Rotation policy of key '%s' was updated", key.Name))
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Rotation Policy - %s", key.Name), 80, 17)
+}
+
+// refreshKeys reloads the keys view
+func (a *App) refreshKeys() {
+	ctx := context.Background()
+	vaultURL := a.navState.SelectedKeyVaultURL
+	keys, err := a.keyVaultExplorerView.GetFetcher().ListKeys(ctx, vaultURL)
+	if err != nil {
+		a.showError("Failed to list keys", err)
+		return
+	}
+
+	a.keyVaultKeysView.LoadKeys(ctx, keys, a.navState.SelectedKeyVault, vaultURL)
+	a.updateLayout()
+	a.SetFocus(a.keyVaultKeysView)
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/export"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// expiryScopeAll is the scope option that scans every subscription
+const expiryScopeAll = "All subscriptions"
+
+// defaultExpiryDays is the default look-ahead of the expiry report
+const defaultExpiryDays = 30
+
+// showExpiryReportForm asks for the scope and look-ahead of an expiry report, starting from the selected subscription
+func (a *App) showExpiryReportForm(sub *models.Subscription) {
+	scopes := []string{azure.SubscriptionLabel(sub), expiryScopeAll}
+	form := tview.NewForm().
+		AddDropDown("Scope", scopes, 0, nil).
+		AddInputField("Within Days", strconv.Itoa(defaultExpiryDays), 0, nil, nil)
+
+	form.AddButton("Scan", func() {
+		days, err := strconv.Atoi(strings.TrimSpace(formText(form, "Within Days")))
+		if err != nil || days < 0 {
+			a.showError("Invalid expiry report", fmt.Errorf("within days must be a number of days"))
+			return
+		}
+		subscriptions := []*models.Subscription{sub}
+		if formOption(form, "Scope") == expiryScopeAll {
+			subscriptions = a.subscriptionsView.GetSubscriptions()
+		}
+
+		a.closeDialog()
+		a.runExpiryReport(subscriptions, days)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Key Vault Expiry Report", 70, 9)
+}
+
+// runExpiryReport scans the Key Vaults of the given subscriptions in the background and shows the
+// report in the expiry view
+func (a *App) runExpiryReport(subscriptions []*models.Subscription, days int) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Listing the Key Vaults of %d subscription(s)...", len(subscriptions))).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		report, err := a.azureClient.BuildExpiryReport(ctx, subscriptions, days, func(scanned, total int, vault string) {
+			a.QueueUpdateDraw(func() {
+				modal.SetText(fmt.Sprintf("Scanning %s (%d/%d)...", vault, scanned+1, total))
+			})
+		})
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to build expiry report", err)
+				}
+				return
+			}
+
+			a.navState.NavigateToKeyVaultExpiry()
+			a.keyVaultExpiryView.LoadReport(report, subscriptions)
+			a.updateLayout()
+			a.SetFocus(a.keyVaultExpiryView)
+			if len(report.Errors) > 0 {
+				a.showTextDialog(fmt.Sprintf("%d subscription(s), vault(s) or item type(s) could not be scanned", len(report.Errors)),
+					tview.Escape(strings.Join(report.Errors, "\n")))
+			}
+		})
+	}()
+}
+
+// rescanExpiryReport builds the expiry report again with the same scope and look-ahead
+func (a *App) rescanExpiryReport() {
+	report := a.keyVaultExpiryView.GetReport()
+	if report == nil {
+		return
+	}
+	a.runExpiryReport(a.keyVaultExpiryView.GetSubscriptions(), report.WithinDays)
+}
+
+// showExpiryItem shows where an expiring item lives and when it expires
+func (a *App) showExpiryItem(item *models.ExpiryItem) {
+	color := "white"
+	switch item.Status {
+	case models.ExpiryStatusExpired:
+		color = "red"
+	case models.ExpiryStatusCritical:
+		color = "orange"
+	case models.ExpiryStatusWarning:
+		color = "yellow"
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("[lightblue::b]Status:[%s] %s[white]\n", color, item.Status))
+	if item.DaysLeft < 0 {
+		text.WriteString(fmt.Sprintf("[lightblue::b]Expired:[white] %d day(s) ago\n", -item.DaysLeft))
+	} else {
+		text.WriteString(fmt.Sprintf("[lightblue::b]Days Left:[white] %d\n", item.DaysLeft))
+	}
+	text.WriteString(fmt.Sprintf("[lightblue::b]Expires:[white] %s\n", item.Expires.Local().Format("2006-01-02 15:04:05")))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Type:[white] %s\n", item.Type))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", tview.Escape(item.Name)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Enabled:[white] %t\n", item.Enabled))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Vault:[white] %s\n", tview.Escape(item.Vault)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Resource Group:[white] %s\n", tview.Escape(item.ResourceGroup)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Subscription:[white] %s\n", tview.Escape(item.SubscriptionName)))
+	text.WriteString(fmt.Sprintf("[lightblue::b]Subscription ID:[white] %s\n", item.SubscriptionID))
+
+	a.showTextDialog(fmt.Sprintf("%s %s", item.Type, item.Name), text.String())
+}
+
+// exportExpiryReport saves the expiry report as CSV or JSON
+func (a *App) exportExpiryReport() {
+	report := a.keyVaultExpiryView.GetReport()
+	if report == nil {
+		return
+	}
+
+	form := tview.NewForm()
+	form.AddDropDown("Format", export.ReportFormats, 0, nil).
+		AddInputField("File", "", 0, nil, nil)
+
+	form.AddButton("Export", func() {
+		format := formOption(form, "Format")
+		path := strings.TrimSpace(formText(form, "File"))
+		if path == "" {
+			path = export.DefaultReportFileName(format)
+		}
+		data, err := export.RenderExpiryReport(report, format)
+		if err != nil {
+			a.showError("Failed to export report", err)
+			return
+		}
+		a.closeDialog()
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			a.showError("Failed to save report", err)
+			return
+		}
+		a.showInfo(fmt.Sprintf("Saved %d item(s) to %s", len(report.Items), path))
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Export Expiry Report - an empty file name uses keyvault-expiry.<format>", 80, 9)
+}
+
+// navigateBackFromExpiry returns from the expiry view to the subscriptions
+func (a *App) navigateBackFromExpiry() {
+	a.navState.NavigateBackFromKeyVaultExpiry()
+	a.navigateToSubscriptions()
+}
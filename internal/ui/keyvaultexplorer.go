@@ -3,19 +3,30 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"azure-control-tower/internal/models"
+	"azure-control-tower/pkg/resource"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-// KeyVaultExplorerView displays the main navigation for a Key Vault
+// KeyVaultExplorerView displays the main navigation for a Key Vault. The Key Vault handler opens it
+// on a vault with the fetcher that loads the secrets, keys and certificates.
 type KeyVaultExplorerView struct {
 	*TableView
 	keyVaultName string
 	vaultURL     string
+	fetcher      resource.KeyVaultFetcher
+	onOpen       func(vault resource.KeyVault)
+	onDetails    func(vault resource.KeyVault)
 	onSelect     func(itemType string)
+	onBackup     func()
+	onRestore    func()
+	onSync       func()
+	onAccess     func()
 }
 
 // NewKeyVaultExplorerView creates a new Key Vault explorer view
@@ -29,6 +40,52 @@ func NewKeyVaultExplorerView() *KeyVaultExplorerView {
 			{Name: "Item Type", Align: tview.AlignLeft},
 			{Name: "Description", Align: tview.AlignLeft},
 		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'b',
+				Label: "Backup Vault",
+				Callback: func() bool {
+					if kve.onBackup != nil {
+						kve.onBackup()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'r',
+				Label: "Restore Backup",
+				Callback: func() bool {
+					if kve.onRestore != nil {
+						kve.onRestore()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  's',
+				Label: "Sync Secrets",
+				Callback: func() bool {
+					if kve.onSync != nil {
+						kve.onSync()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'a',
+				Label: "Access",
+				Callback: func() bool {
+					if kve.onAccess != nil {
+						kve.onAccess()
+						return true
+					}
+					return false
+				},
+			},
+		},
 		OnSelect: func(rowIndex int, data interface{}) {
 			// Enter key on an item - navigate to that item type
 			if itemData, ok := data.(string); ok && kve.onSelect != nil {
@@ -49,6 +106,8 @@ func NewKeyVaultExplorerView() *KeyVaultExplorerView {
 					return "🔑 Keys"
 				case "certificates":
 					return "📜 Certificates"
+				case "deleted":
+					return "🗑️ Deleted Items"
 				default:
 					return itemType
 				}
@@ -60,6 +119,8 @@ func NewKeyVaultExplorerView() *KeyVaultExplorerView {
 					return "Manage cryptographic keys"
 				case "certificates":
 					return "Manage SSL/TLS certificates"
+				case "deleted":
+					return "Recover or purge deleted secrets, keys and certificates"
 				default:
 					return ""
 				}
@@ -78,22 +139,89 @@ func (kve *KeyVaultExplorerView) LoadKeyVault(ctx context.Context, keyVaultName,
 	kve.keyVaultName = keyVaultName
 	kve.vaultURL = vaultURL
 
-	// Load the three main item types
+	// Load the three main item types and the deleted items
 	data := []interface{}{
 		"secrets",
 		"keys",
 		"certificates",
+		"deleted",
 	}
 
 	kve.LoadData(data)
 	return nil
 }
 
+// Open shows the item types of a vault, opened by the Key Vault handler
+func (kve *KeyVaultExplorerView) Open(vault resource.KeyVault, fetcher resource.KeyVaultFetcher) {
+	kve.fetcher = fetcher
+	kve.LoadKeyVault(context.Background(), vault.Name, vault.URL)
+	if kve.onOpen != nil {
+		kve.onOpen(vault)
+	}
+}
+
+// ShowDetails shows the configuration of a vault in the details view, for the Key Vault handler
+func (kve *KeyVaultExplorerView) ShowDetails(vault resource.KeyVault) {
+	if kve.onDetails != nil {
+		kve.onDetails(vault)
+	}
+}
+
+// Title returns the title of the Key Vault explorer
+func (kve *KeyVaultExplorerView) Title() string {
+	return fmt.Sprintf("Key Vault Explorer - %s", kve.keyVaultName)
+}
+
+// Actions returns the keys of the Key Vault explorer for the footer
+func (kve *KeyVaultExplorerView) Actions() string {
+	return "Enter: open item type, b: backup vault, r: restore backup, s: sync secrets, a: access"
+}
+
+// GetTableView returns the table of the Key Vault explorer
+func (kve *KeyVaultExplorerView) GetTableView() *TableView {
+	return kve.TableView
+}
+
+// GetFetcher returns the fetcher that loads the secrets, keys and certificates of the vault
+func (kve *KeyVaultExplorerView) GetFetcher() resource.KeyVaultFetcher {
+	return kve.fetcher
+}
+
+// SetOnOpen sets the callback for when the Key Vault handler opens the explorer on a vault
+func (kve *KeyVaultExplorerView) SetOnOpen(callback func(resource.KeyVault)) {
+	kve.onOpen = callback
+}
+
+// SetOnDetails sets the callback for showing the configuration of a vault in the details view
+func (kve *KeyVaultExplorerView) SetOnDetails(callback func(resource.KeyVault)) {
+	kve.onDetails = callback
+}
+
 // SetOnSelect sets the callback for when an item type is selected (Enter key)
 func (kve *KeyVaultExplorerView) SetOnSelect(callback func(string)) {
 	kve.onSelect = callback
 }
 
+// SetOnBackup sets the callback for backing up the whole vault (b key)
+func (kve *KeyVaultExplorerView) SetOnBackup(callback func()) {
+	kve.onBackup = callback
+}
+
+// SetOnRestore sets the callback for restoring a backup into the vault (r key)
+func (kve *KeyVaultExplorerView) SetOnRestore(callback func()) {
+	kve.onRestore = callback
+}
+
+// SetOnSync sets the callback for comparing the secrets with another vault (s key)
+func (kve *KeyVaultExplorerView) SetOnSync(callback func()) {
+	kve.onSync = callback
+}
+
+// SetOnAccess sets the callback for showing the access model of the vault (a key)
+func (kve *KeyVaultExplorerView) SetOnAccess(callback func()) {
+	kve.onAccess = callback
+}
+
 // GetKeyVaultName returns the current Key Vault name
 func (kve *KeyVaultExplorerView) GetKeyVaultName() string {
 	return kve.keyVaultName
@@ -117,6 +245,16 @@ type KeyVaultSecretsView struct {
 	vaultURL     string
 	onShowDetails func(secret *models.Secret)
 	onViewValue   func(secret *models.Secret)
+	onCopyValue   func(secret *models.Secret)
+	onCreate      func()
+	onNewVersion  func(secret *models.Secret)
+	onToggle      func(secret *models.Secret)
+	onVersions    func(secret *models.Secret)
+	onHistory     func(secret *models.Secret)
+	onDelete      func(items []models.VaultItemRef)
+	onBackup      func(items []models.VaultItemRef)
+	onCopy        func(items []models.VaultItemRef)
+	onExport      func(items []models.VaultItemRef)
 }
 
 // NewKeyVaultSecretsView creates a new Key Vault secrets view
@@ -144,6 +282,17 @@ func NewKeyVaultSecretsView() *KeyVaultSecretsView {
 					return false
 				},
 			},
+			{
+				Rune:  'y',
+				Label: "Copy Value",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksv.onCopyValue != nil {
+						ksv.onCopyValue(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
 			{
 				Rune:  'd',
 				Label: "Details",
@@ -155,6 +304,112 @@ func NewKeyVaultSecretsView() *KeyVaultSecretsView {
 					return false
 				},
 			},
+			{
+				Rune:  'u',
+				Label: "New Version",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksv.onNewVersion != nil {
+						ksv.onNewVersion(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  't',
+				Label: "Enable/Disable",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksv.onToggle != nil {
+						ksv.onToggle(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'h',
+				Label: "Versions",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksv.onVersions != nil {
+						ksv.onVersions(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'l',
+				Label: "History",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksv.onHistory != nil {
+						ksv.onHistory(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'x',
+				Label: "Delete",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := ksv.GetMarkedItems()
+					if len(items) == 0 || ksv.onDelete == nil {
+						return false
+					}
+					ksv.onDelete(items)
+					return true
+				},
+			},
+			{
+				Rune:  'b',
+				Label: "Backup",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := ksv.GetMarkedItems()
+					if len(items) == 0 || ksv.onBackup == nil {
+						return false
+					}
+					ksv.onBackup(items)
+					return true
+				},
+			},
+			{
+				Rune:  'c',
+				Label: "Copy to Vault",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := ksv.GetMarkedItems()
+					if len(items) == 0 || ksv.onCopy == nil {
+						return false
+					}
+					ksv.onCopy(items)
+					return true
+				},
+			},
+			{
+				Rune:  'e',
+				Label: "Export",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := ksv.GetMarkedItems()
+					if len(items) == 0 || ksv.onExport == nil {
+						return false
+					}
+					ksv.onExport(items)
+					return true
+				},
+			},
+		},
+		MultiSelect: true,
+		ViewActions: []ViewAction{
+			{
+				Rune:  'n',
+				Label: "New Secret",
+				Callback: func() bool {
+					if ksv.onCreate != nil {
+						ksv.onCreate()
+						return true
+					}
+					return false
+				},
+			},
 		},
 		OnSelect: func(rowIndex int, data interface{}) {
 			// Enter key on a secret - show details
@@ -220,6 +475,67 @@ func (ksv *KeyVaultSecretsView) SetOnViewValue(callback func(*models.Secret)) {
 	ksv.onViewValue = callback
 }
 
+// SetOnCopyValue sets the callback for copying a secret value to the clipboard (y key)
+func (ksv *KeyVaultSecretsView) SetOnCopyValue(callback func(*models.Secret)) {
+	ksv.onCopyValue = callback
+}
+
+// SetOnCreate sets the callback for creating a secret (n key)
+func (ksv *KeyVaultSecretsView) SetOnCreate(callback func()) {
+	ksv.onCreate = callback
+}
+
+// SetOnNewVersion sets the callback for setting a new version of a secret (u key)
+func (ksv *KeyVaultSecretsView) SetOnNewVersion(callback func(*models.Secret)) {
+	ksv.onNewVersion = callback
+}
+
+// SetOnToggle sets the callback for enabling or disabling a secret (t key)
+func (ksv *KeyVaultSecretsView) SetOnToggle(callback func(*models.Secret)) {
+	ksv.onToggle = callback
+}
+
+// SetOnVersions sets the callback for listing the versions of a secret (h key)
+func (ksv *KeyVaultSecretsView) SetOnVersions(callback func(*models.Secret)) {
+	ksv.onVersions = callback
+}
+
+// SetOnHistory sets the callback for showing the change history of a secret (l key)
+func (ksv *KeyVaultSecretsView) SetOnHistory(callback func(*models.Secret)) {
+	ksv.onHistory = callback
+}
+
+// SetOnDelete sets the callback for deleting the marked or selected secrets (x key)
+func (ksv *KeyVaultSecretsView) SetOnDelete(callback func([]models.VaultItemRef)) {
+	ksv.onDelete = callback
+}
+
+// SetOnBackup sets the callback for backing up the marked or selected secrets (b key)
+func (ksv *KeyVaultSecretsView) SetOnBackup(callback func([]models.VaultItemRef)) {
+	ksv.onBackup = callback
+}
+
+// SetOnCopy sets the callback for copying the marked or selected secrets to another vault (c key)
+func (ksv *KeyVaultSecretsView) SetOnCopy(callback func([]models.VaultItemRef)) {
+	ksv.onCopy = callback
+}
+
+// SetOnExport sets the callback for exporting the marked or selected secrets to a file (e key)
+func (ksv *KeyVaultSecretsView) SetOnExport(callback func([]models.VaultItemRef)) {
+	ksv.onExport = callback
+}
+
+// GetMarkedItems returns the marked secrets, or the selected secret when nothing is marked
+func (ksv *KeyVaultSecretsView) GetMarkedItems() []models.VaultItemRef {
+	var items []models.VaultItemRef
+	for _, data := range ksv.GetMarkedData() {
+		if rowData, ok := data.(*SecretRowData); ok {
+			items = append(items, models.VaultItemRef{Type: models.VaultItemSecret, Name: rowData.Secret.Name})
+		}
+	}
+	return items
+}
+
 // GetKeyVaultName returns the current Key Vault name
 func (ksv *KeyVaultSecretsView) GetKeyVaultName() string {
 	return ksv.keyVaultName
@@ -230,40 +546,107 @@ func (ksv *KeyVaultSecretsView) GetVaultURL() string {
 	return ksv.vaultURL
 }
 
-// KeyRowData wraps Key with context info for display
-type KeyRowData struct {
-	Key *models.Key
-}
-
-// KeyVaultKeysView displays keys in a Key Vault
-type KeyVaultKeysView struct {
+// KeyVaultSecretVersionsView displays every version of a secret
+type KeyVaultSecretVersionsView struct {
 	*TableView
-	keys         []*models.Key
-	keyVaultName string
-	vaultURL     string
-	onShowDetails func(key *models.Key)
+	versions      []*models.Secret
+	secretName    string
+	onShowDetails func(version *models.Secret)
+	onViewValue   func(version *models.Secret)
+	onCopyValue   func(version *models.Secret)
+	onNewVersion  func(version *models.Secret)
+	onToggle      func(version *models.Secret)
+	onHistory     func(version *models.Secret)
+	onCompare     func(version *models.Secret)
 }
 
-// NewKeyVaultKeysView creates a new Key Vault keys view
-func NewKeyVaultKeysView() *KeyVaultKeysView {
-	kkv := &KeyVaultKeysView{}
+// NewKeyVaultSecretVersionsView creates a new secret versions view
+func NewKeyVaultSecretVersionsView() *KeyVaultSecretVersionsView {
+	ksvv := &KeyVaultSecretVersionsView{}
 
-	// Create table configuration
 	config := &TableConfig{
 		Title: "",
 		Columns: []ColumnConfig{
-			{Name: "Name", Align: tview.AlignLeft},
-			{Name: "Type", Align: tview.AlignLeft},
+			{Name: "Version", Align: tview.AlignLeft},
 			{Name: "Enabled", Align: tview.AlignCenter},
-			{Name: "Updated", Align: tview.AlignLeft},
+			{Name: "Content Type", Align: tview.AlignLeft},
+			{Name: "Created", Align: tview.AlignLeft},
+			{Name: "Not Before", Align: tview.AlignLeft},
+			{Name: "Expires", Align: tview.AlignLeft},
 		},
 		RowActions: []RowAction{
+			{
+				Rune:  'v',
+				Label: "View Value",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onViewValue != nil {
+						ksvv.onViewValue(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'y',
+				Label: "Copy Value",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onCopyValue != nil {
+						ksvv.onCopyValue(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
 			{
 				Rune:  'd',
 				Label: "Details",
 				Callback: func(rowIndex int, data interface{}) bool {
-					if rowData, ok := data.(*KeyRowData); ok && kkv.onShowDetails != nil {
-						kkv.onShowDetails(rowData.Key)
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onShowDetails != nil {
+						ksvv.onShowDetails(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'u',
+				Label: "New Version",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onNewVersion != nil {
+						ksvv.onNewVersion(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  't',
+				Label: "Enable/Disable",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onToggle != nil {
+						ksvv.onToggle(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'l',
+				Label: "History",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onHistory != nil {
+						ksvv.onHistory(rowData.Secret)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'c',
+				Label: "Compare",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*SecretRowData); ok && ksvv.onCompare != nil {
+						ksvv.onCompare(rowData.Secret)
 						return true
 					}
 					return false
@@ -271,90 +654,378 @@ func NewKeyVaultKeysView() *KeyVaultKeysView {
 			},
 		},
 		OnSelect: func(rowIndex int, data interface{}) {
-			// Enter key on a key - show details
-			if rowData, ok := data.(*KeyRowData); ok && kkv.onShowDetails != nil {
-				kkv.onShowDetails(rowData.Key)
+			// Enter key on a version - show details
+			if rowData, ok := data.(*SecretRowData); ok && ksvv.onShowDetails != nil {
+				ksvv.onShowDetails(rowData.Secret)
 			}
 		},
 		GetCellValue: func(data interface{}, columnIndex int) string {
-			rowData, ok := data.(*KeyRowData)
+			rowData, ok := data.(*SecretRowData)
 			if !ok {
 				return ""
 			}
+			formatTime := func(t *time.Time) string {
+				if t == nil {
+					return "-"
+				}
+				return t.Format("2006-01-02 15:04:05")
+			}
 			switch columnIndex {
 			case 0:
-				return rowData.Key.Name
+				return rowData.Secret.Version
 			case 1:
-				return rowData.Key.KeyType
-			case 2:
-				if rowData.Key.Enabled {
+				if rowData.Secret.Enabled {
 					return "✓"
 				}
 				return "✗"
+			case 2:
+				return rowData.Secret.ContentType
 			case 3:
-				if rowData.Key.Updated != nil {
-					return rowData.Key.Updated.Format("2006-01-02 15:04:05")
+				return formatTime(rowData.Secret.Created)
+			case 4:
+				return formatTime(rowData.Secret.NotBefore)
+			case 5:
+				if rowData.Secret.Expires != nil && rowData.Secret.Expires.Before(time.Now()) {
+					return fmt.Sprintf("⚠️ %s (EXPIRED)", formatTime(rowData.Secret.Expires))
 				}
-				return "-"
+				return formatTime(rowData.Secret.Expires)
 			default:
 				return ""
 			}
 		},
 	}
 
-	kkv.TableView = NewTableView(config)
-	return kkv
+	ksvv.TableView = NewTableView(config)
+	return ksvv
 }
 
-// LoadKeys loads keys into the view
-func (kkv *KeyVaultKeysView) LoadKeys(ctx context.Context, keys []*models.Key, keyVaultName, vaultURL string) error {
-	kkv.keys = keys
-	kkv.keyVaultName = keyVaultName
-	kkv.vaultURL = vaultURL
+// LoadVersions loads the versions of a secret into the view
+func (ksvv *KeyVaultSecretVersionsView) LoadVersions(versions []*models.Secret, secretName string) {
+	ksvv.versions = versions
+	ksvv.secretName = secretName
 
-	// Convert to interface{} slice with row data
-	data := make([]interface{}, len(keys))
-	for i, key := range keys {
-		data[i] = &KeyRowData{
-			Key: key,
+	data := make([]interface{}, len(versions))
+	for i, version := range versions {
+		data[i] = &SecretRowData{
+			Secret: version,
 		}
 	}
 
-	kkv.LoadData(data)
-	return nil
+	ksvv.LoadData(data)
 }
 
-// SetOnShowDetails sets the callback for when details are requested
-func (kkv *KeyVaultKeysView) SetOnShowDetails(callback func(*models.Key)) {
-	kkv.onShowDetails = callback
+// GetSecretName returns the secret whose versions are shown
+func (ksvv *KeyVaultSecretVersionsView) GetSecretName() string {
+	return ksvv.secretName
 }
 
-// GetKeyVaultName returns the current Key Vault name
-func (kkv *KeyVaultKeysView) GetKeyVaultName() string {
-	return kkv.keyVaultName
+// SetOnShowDetails sets the callback for when details are requested (d key or Enter)
+func (ksvv *KeyVaultSecretVersionsView) SetOnShowDetails(callback func(*models.Secret)) {
+	ksvv.onShowDetails = callback
 }
 
-// GetVaultURL returns the current vault URL
-func (kkv *KeyVaultKeysView) GetVaultURL() string {
-	return kkv.vaultURL
+// SetOnViewValue sets the callback for viewing the value of a version (v key)
+func (ksvv *KeyVaultSecretVersionsView) SetOnViewValue(callback func(*models.Secret)) {
+	ksvv.onViewValue = callback
 }
 
-// CertificateRowData wraps Certificate with context info for display
-type CertificateRowData struct {
-	Certificate *models.Certificate
+// SetOnCopyValue sets the callback for copying the value of a version to the clipboard (y key)
+func (ksvv *KeyVaultSecretVersionsView) SetOnCopyValue(callback func(*models.Secret)) {
+	ksvv.onCopyValue = callback
 }
 
-// KeyVaultCertificatesView displays certificates in a Key Vault
-type KeyVaultCertificatesView struct {
-	*TableView
-	certificates []*models.Certificate
-	keyVaultName string
-	vaultURL     string
-	onShowDetails func(cert *models.Certificate)
+// SetOnNewVersion sets the callback for setting a new version of the secret (u key)
+func (ksvv *KeyVaultSecretVersionsView) SetOnNewVersion(callback func(*models.Secret)) {
+	ksvv.onNewVersion = callback
 }
 
-// NewKeyVaultCertificatesView creates a new Key Vault certificates view
-func NewKeyVaultCertificatesView() *KeyVaultCertificatesView {
+// SetOnToggle sets the callback for enabling or disabling a version (t key)
+func (ksvv *KeyVaultSecretVersionsView) SetOnToggle(callback func(*models.Secret)) {
+	ksvv.onToggle = callback
+}
+
+// SetOnHistory sets the callback for showing the change history of the secret (l key)
+func (ksvv *KeyVaultSecretVersionsView) SetOnHistory(callback func(*models.Secret)) {
+	ksvv.onHistory = callback
+}
+
+// SetOnCompare sets the callback for comparing the value of a version with another version (c key)
+func (ksvv *KeyVaultSecretVersionsView) SetOnCompare(callback func(*models.Secret)) {
+	ksvv.onCompare = callback
+}
+
+// GetVersions returns the loaded versions, newest first
+func (ksvv *KeyVaultSecretVersionsView) GetVersions() []*models.Secret {
+	return ksvv.versions
+}
+
+// KeyRowData wraps Key with context info for display
+type KeyRowData struct {
+	Key *models.Key
+}
+
+// KeyVaultKeysView displays keys in a Key Vault
+type KeyVaultKeysView struct {
+	*TableView
+	keys         []*models.Key
+	keyVaultName string
+	vaultURL     string
+	onShowDetails func(key *models.Key)
+	onDelete      func(items []models.VaultItemRef)
+	onBackup      func(items []models.VaultItemRef)
+	onCrypto      func(key *models.Key)
+	onRotate      func(key *models.Key)
+	onPolicy      func(key *models.Key)
+	onCreate      func()
+	onImport      func()
+}
+
+// NewKeyVaultKeysView creates a new Key Vault keys view
+func NewKeyVaultKeysView() *KeyVaultKeysView {
+	kkv := &KeyVaultKeysView{}
+
+	// Create table configuration
+	config := &TableConfig{
+		Title: "",
+		Columns: []ColumnConfig{
+			{Name: "Name", Align: tview.AlignLeft},
+			{Name: "Type", Align: tview.AlignLeft},
+			{Name: "Enabled", Align: tview.AlignCenter},
+			{Name: "Updated", Align: tview.AlignLeft},
+		},
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*KeyRowData); ok && kkv.onShowDetails != nil {
+						kkv.onShowDetails(rowData.Key)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'x',
+				Label: "Delete",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := kkv.GetMarkedItems()
+					if len(items) == 0 || kkv.onDelete == nil {
+						return false
+					}
+					kkv.onDelete(items)
+					return true
+				},
+			},
+			{
+				Rune:  'b',
+				Label: "Backup",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := kkv.GetMarkedItems()
+					if len(items) == 0 || kkv.onBackup == nil {
+						return false
+					}
+					kkv.onBackup(items)
+					return true
+				},
+			},
+			{
+				Rune:  'c',
+				Label: "Crypto",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*KeyRowData); ok && kkv.onCrypto != nil {
+						kkv.onCrypto(rowData.Key)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'r',
+				Label: "Rotate",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*KeyRowData); ok && kkv.onRotate != nil {
+						kkv.onRotate(rowData.Key)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'p',
+				Label: "Rotation Policy",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*KeyRowData); ok && kkv.onPolicy != nil {
+						kkv.onPolicy(rowData.Key)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'n',
+				Label: "New Key",
+				Callback: func() bool {
+					if kkv.onCreate != nil {
+						kkv.onCreate()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'i',
+				Label: "Import Key",
+				Callback: func() bool {
+					if kkv.onImport != nil {
+						kkv.onImport()
+						return true
+					}
+					return false
+				},
+			},
+		},
+		MultiSelect: true,
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on a key - show details
+			if rowData, ok := data.(*KeyRowData); ok && kkv.onShowDetails != nil {
+				kkv.onShowDetails(rowData.Key)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			rowData, ok := data.(*KeyRowData)
+			if !ok {
+				return ""
+			}
+			switch columnIndex {
+			case 0:
+				return rowData.Key.Name
+			case 1:
+				return rowData.Key.KeyType
+			case 2:
+				if rowData.Key.Enabled {
+					return "✓"
+				}
+				return "✗"
+			case 3:
+				if rowData.Key.Updated != nil {
+					return rowData.Key.Updated.Format("2006-01-02 15:04:05")
+				}
+				return "-"
+			default:
+				return ""
+			}
+		},
+	}
+
+	kkv.TableView = NewTableView(config)
+	return kkv
+}
+
+// LoadKeys loads keys into the view
+func (kkv *KeyVaultKeysView) LoadKeys(ctx context.Context, keys []*models.Key, keyVaultName, vaultURL string) error {
+	kkv.keys = keys
+	kkv.keyVaultName = keyVaultName
+	kkv.vaultURL = vaultURL
+
+	// Convert to interface{} slice with row data
+	data := make([]interface{}, len(keys))
+	for i, key := range keys {
+		data[i] = &KeyRowData{
+			Key: key,
+		}
+	}
+
+	kkv.LoadData(data)
+	return nil
+}
+
+// SetOnShowDetails sets the callback for when details are requested
+func (kkv *KeyVaultKeysView) SetOnShowDetails(callback func(*models.Key)) {
+	kkv.onShowDetails = callback
+}
+
+// SetOnDelete sets the callback for deleting the marked or selected keys (x key)
+func (kkv *KeyVaultKeysView) SetOnDelete(callback func([]models.VaultItemRef)) {
+	kkv.onDelete = callback
+}
+
+// SetOnBackup sets the callback for backing up the marked or selected keys (b key)
+func (kkv *KeyVaultKeysView) SetOnBackup(callback func([]models.VaultItemRef)) {
+	kkv.onBackup = callback
+}
+
+// SetOnCrypto sets the callback for opening the crypto console of a key (c key)
+func (kkv *KeyVaultKeysView) SetOnCrypto(callback func(*models.Key)) {
+	kkv.onCrypto = callback
+}
+
+// SetOnRotate sets the callback for rotating a key (r key)
+func (kkv *KeyVaultKeysView) SetOnRotate(callback func(*models.Key)) {
+	kkv.onRotate = callback
+}
+
+// SetOnRotationPolicy sets the callback for viewing and editing the rotation policy of a key (p key)
+func (kkv *KeyVaultKeysView) SetOnRotationPolicy(callback func(*models.Key)) {
+	kkv.onPolicy = callback
+}
+
+// SetOnCreate sets the callback for creating a key (n key)
+func (kkv *KeyVaultKeysView) SetOnCreate(callback func()) {
+	kkv.onCreate = callback
+}
+
+// SetOnImport sets the callback for importing a key (i key)
+func (kkv *KeyVaultKeysView) SetOnImport(callback func()) {
+	kkv.onImport = callback
+}
+
+// GetMarkedItems returns the marked keys, or the selected key when nothing is marked
+func (kkv *KeyVaultKeysView) GetMarkedItems() []models.VaultItemRef {
+	var items []models.VaultItemRef
+	for _, data := range kkv.GetMarkedData() {
+		if rowData, ok := data.(*KeyRowData); ok {
+			items = append(items, models.VaultItemRef{Type: models.VaultItemKey, Name: rowData.Key.Name})
+		}
+	}
+	return items
+}
+
+// GetKeyVaultName returns the current Key Vault name
+func (kkv *KeyVaultKeysView) GetKeyVaultName() string {
+	return kkv.keyVaultName
+}
+
+// GetVaultURL returns the current vault URL
+func (kkv *KeyVaultKeysView) GetVaultURL() string {
+	return kkv.vaultURL
+}
+
+// CertificateRowData wraps Certificate with context info for display
+type CertificateRowData struct {
+	Certificate *models.Certificate
+}
+
+// KeyVaultCertificatesView displays certificates in a Key Vault
+type KeyVaultCertificatesView struct {
+	*TableView
+	certificates []*models.Certificate
+	keyVaultName string
+	vaultURL     string
+	onShowDetails func(cert *models.Certificate)
+	onDelete      func(items []models.VaultItemRef)
+	onBackup      func(items []models.VaultItemRef)
+	onDownload    func(cert *models.Certificate)
+	onOperation   func(cert *models.Certificate)
+	onMerge       func(cert *models.Certificate)
+	onCreate      func()
+	onImport      func()
+}
+
+// NewKeyVaultCertificatesView creates a new Key Vault certificates view
+func NewKeyVaultCertificatesView() *KeyVaultCertificatesView {
 	kcv := &KeyVaultCertificatesView{}
 
 	// Create table configuration
@@ -378,7 +1049,89 @@ func NewKeyVaultCertificatesView() *KeyVaultCertificatesView {
 					return false
 				},
 			},
+			{
+				Rune:  'x',
+				Label: "Delete",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := kcv.GetMarkedItems()
+					if len(items) == 0 || kcv.onDelete == nil {
+						return false
+					}
+					kcv.onDelete(items)
+					return true
+				},
+			},
+			{
+				Rune:  'b',
+				Label: "Backup",
+				Callback: func(rowIndex int, data interface{}) bool {
+					items := kcv.GetMarkedItems()
+					if len(items) == 0 || kcv.onBackup == nil {
+						return false
+					}
+					kcv.onBackup(items)
+					return true
+				},
+			},
+			{
+				Rune:  'w',
+				Label: "Download",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*CertificateRowData); ok && kcv.onDownload != nil {
+						kcv.onDownload(rowData.Certificate)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'o',
+				Label: "Operation",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*CertificateRowData); ok && kcv.onOperation != nil {
+						kcv.onOperation(rowData.Certificate)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'u',
+				Label: "Merge Signed",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*CertificateRowData); ok && kcv.onMerge != nil {
+						kcv.onMerge(rowData.Certificate)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'n',
+				Label: "New Certificate",
+				Callback: func() bool {
+					if kcv.onCreate != nil {
+						kcv.onCreate()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'i',
+				Label: "Import Certificate",
+				Callback: func() bool {
+					if kcv.onImport != nil {
+						kcv.onImport()
+						return true
+					}
+					return false
+				},
+			},
 		},
+		MultiSelect: true,
 		OnSelect: func(rowIndex int, data interface{}) {
 			// Enter key on a certificate - show details
 			if rowData, ok := data.(*CertificateRowData); ok && kcv.onShowDetails != nil {
@@ -446,6 +1199,52 @@ func (kcv *KeyVaultCertificatesView) SetOnShowDetails(callback func(*models.Cert
 	kcv.onShowDetails = callback
 }
 
+// SetOnDelete sets the callback for deleting the marked or selected certificates (x key)
+func (kcv *KeyVaultCertificatesView) SetOnDelete(callback func([]models.VaultItemRef)) {
+	kcv.onDelete = callback
+}
+
+// SetOnBackup sets the callback for backing up the marked or selected certificates (b key)
+func (kcv *KeyVaultCertificatesView) SetOnBackup(callback func([]models.VaultItemRef)) {
+	kcv.onBackup = callback
+}
+
+// SetOnDownload sets the callback for downloading a certificate (w key)
+func (kcv *KeyVaultCertificatesView) SetOnDownload(callback func(*models.Certificate)) {
+	kcv.onDownload = callback
+}
+
+// SetOnOperation sets the callback for showing the issuance operation of a certificate (o key)
+func (kcv *KeyVaultCertificatesView) SetOnOperation(callback func(*models.Certificate)) {
+	kcv.onOperation = callback
+}
+
+// SetOnMerge sets the callback for merging a signed CSR into a pending certificate (u key)
+func (kcv *KeyVaultCertificatesView) SetOnMerge(callback func(*models.Certificate)) {
+	kcv.onMerge = callback
+}
+
+// SetOnCreate sets the callback for creating a certificate (n key)
+func (kcv *KeyVaultCertificatesView) SetOnCreate(callback func()) {
+	kcv.onCreate = callback
+}
+
+// SetOnImport sets the callback for importing a certificate (i key)
+func (kcv *KeyVaultCertificatesView) SetOnImport(callback func()) {
+	kcv.onImport = callback
+}
+
+// GetMarkedItems returns the marked certificates, or the selected certificate when nothing is marked
+func (kcv *KeyVaultCertificatesView) GetMarkedItems() []models.VaultItemRef {
+	var items []models.VaultItemRef
+	for _, data := range kcv.GetMarkedData() {
+		if rowData, ok := data.(*CertificateRowData); ok {
+			items = append(items, models.VaultItemRef{Type: models.VaultItemCertificate, Name: rowData.Certificate.Name})
+		}
+	}
+	return items
+}
+
 // GetKeyVaultName returns the current Key Vault name
 func (kcv *KeyVaultCertificatesView) GetKeyVaultName() string {
 	return kcv.keyVaultName
@@ -455,3 +1254,443 @@ func (kcv *KeyVaultCertificatesView) GetKeyVaultName() string {
 func (kcv *KeyVaultCertificatesView) GetVaultURL() string {
 	return kcv.vaultURL
 }
+
+// KeyVaultDeletedItemsView displays the soft-deleted secrets, keys and certificates of a Key Vault
+type KeyVaultDeletedItemsView struct {
+	*TableView
+	items         []*models.DeletedItem
+	onShowDetails func(item *models.DeletedItem)
+	onRecover     func(item *models.DeletedItem)
+	onPurge       func(item *models.DeletedItem)
+}
+
+// NewKeyVaultDeletedItemsView creates a new deleted items view
+func NewKeyVaultDeletedItemsView() *KeyVaultDeletedItemsView {
+	kdv := &KeyVaultDeletedItemsView{}
+
+	config := &TableConfig{
+		Title: "",
+		Columns: []ColumnConfig{
+			{Name: "Type", Align: tview.AlignLeft},
+			{Name: "Name", Align: tview.AlignLeft},
+			{Name: "Deleted", Align: tview.AlignLeft},
+			{Name: "Scheduled Purge", Align: tview.AlignLeft},
+			{Name: "Recovery ID", Align: tview.AlignLeft},
+		},
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if item, ok := data.(*models.DeletedItem); ok && kdv.onShowDetails != nil {
+						kdv.onShowDetails(item)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'r',
+				Label: "Recover",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if item, ok := data.(*models.DeletedItem); ok && kdv.onRecover != nil {
+						kdv.onRecover(item)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'p',
+				Label: "Purge",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if item, ok := data.(*models.DeletedItem); ok && kdv.onPurge != nil {
+						kdv.onPurge(item)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on a deleted item - show details
+			if item, ok := data.(*models.DeletedItem); ok && kdv.onShowDetails != nil {
+				kdv.onShowDetails(item)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			item, ok := data.(*models.DeletedItem)
+			if !ok {
+				return ""
+			}
+			switch columnIndex {
+			case 0:
+				return item.Type
+			case 1:
+				return item.Name
+			case 2:
+				if item.DeletedDate != nil {
+					return item.DeletedDate.Local().Format("2006-01-02 15:04:05")
+				}
+				return "-"
+			case 3:
+				if item.ScheduledPurgeDate != nil {
+					return item.ScheduledPurgeDate.Local().Format("2006-01-02 15:04:05")
+				}
+				return "-"
+			case 4:
+				return item.RecoveryID
+			default:
+				return ""
+			}
+		},
+	}
+
+	kdv.TableView = NewTableView(config)
+	return kdv
+}
+
+// LoadItems loads deleted items into the view
+func (kdv *KeyVaultDeletedItemsView) LoadItems(items []*models.DeletedItem) {
+	kdv.items = items
+
+	data := make([]interface{}, len(items))
+	for i, item := range items {
+		data[i] = item
+	}
+	kdv.LoadData(data)
+}
+
+// SetOnShowDetails sets the callback for when details are requested
+func (kdv *KeyVaultDeletedItemsView) SetOnShowDetails(callback func(*models.DeletedItem)) {
+	kdv.onShowDetails = callback
+}
+
+// SetOnRecover sets the callback for recovering a deleted item (r key)
+func (kdv *KeyVaultDeletedItemsView) SetOnRecover(callback func(*models.DeletedItem)) {
+	kdv.onRecover = callback
+}
+
+// SetOnPurge sets the callback for purging a deleted item (p key)
+func (kdv *KeyVaultDeletedItemsView) SetOnPurge(callback func(*models.DeletedItem)) {
+	kdv.onPurge = callback
+}
+
+// KeyVaultSecretSyncView displays the differences between the secrets of two Key Vaults
+type KeyVaultSecretSyncView struct {
+	*TableView
+	diffs         []*models.SecretDiff
+	targetVault   string
+	targetURL     string
+	compareValues bool
+	onShowDetails func(diff *models.SecretDiff)
+	onViewValues  func(diff *models.SecretDiff)
+	onApply       func(diffs []*models.SecretDiff)
+}
+
+// NewKeyVaultSecretSyncView creates a new secret sync view
+func NewKeyVaultSecretSyncView() *KeyVaultSecretSyncView {
+	ksyv := &KeyVaultSecretSyncView{}
+
+	config := &TableConfig{
+		Title: "",
+		Columns: []ColumnConfig{
+			{Name: "Status", Align: tview.AlignLeft},
+			{Name: "Name", Align: tview.AlignLeft},
+			{Name: "Source Updated", Align: tview.AlignLeft},
+			{Name: "Target Updated", Align: tview.AlignLeft},
+			{Name: "Differences", Align: tview.AlignLeft},
+		},
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if diff, ok := data.(*models.SecretDiff); ok && ksyv.onShowDetails != nil {
+						ksyv.onShowDetails(diff)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'v',
+				Label: "View Values",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if diff, ok := data.(*models.SecretDiff); ok && ksyv.onViewValues != nil {
+						ksyv.onViewValues(diff)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'a',
+				Label: "Apply",
+				Callback: func(rowIndex int, data interface{}) bool {
+					diffs := ksyv.GetMarkedDiffs()
+					if len(diffs) == 0 || ksyv.onApply == nil {
+						return false
+					}
+					ksyv.onApply(diffs)
+					return true
+				},
+			},
+		},
+		MultiSelect: true,
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on a secret - show both sides
+			if diff, ok := data.(*models.SecretDiff); ok && ksyv.onShowDetails != nil {
+				ksyv.onShowDetails(diff)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			diff, ok := data.(*models.SecretDiff)
+			if !ok {
+				return ""
+			}
+			switch columnIndex {
+			case 0:
+				return diff.Status
+			case 1:
+				return diff.Name
+			case 2:
+				return secretUpdated(diff.Source)
+			case 3:
+				return secretUpdated(diff.Target)
+			case 4:
+				return strings.Join(diff.Differences, ", ")
+			default:
+				return ""
+			}
+		},
+	}
+
+	ksyv.TableView = NewTableView(config)
+	return ksyv
+}
+
+// secretUpdated formats the update time of one side of a secret diff
+func secretUpdated(secret *models.Secret) string {
+	if secret == nil {
+		return "-"
+	}
+	if secret.Updated != nil {
+		return secret.Updated.Format("2006-01-02 15:04:05")
+	}
+	return "-"
+}
+
+// LoadDiffs loads the diff against a target vault into the view
+func (ksyv *KeyVaultSecretSyncView) LoadDiffs(diffs []*models.SecretDiff, targetVault, targetURL string, compareValues bool) {
+	ksyv.diffs = diffs
+	ksyv.targetVault = targetVault
+	ksyv.targetURL = targetURL
+	ksyv.compareValues = compareValues
+
+	data := make([]interface{}, len(diffs))
+	for i, diff := range diffs {
+		data[i] = diff
+	}
+	ksyv.LoadData(data)
+}
+
+// GetMarkedDiffs returns the marked secrets, or the selected secret when nothing is marked
+func (ksyv *KeyVaultSecretSyncView) GetMarkedDiffs() []*models.SecretDiff {
+	var diffs []*models.SecretDiff
+	for _, data := range ksyv.GetMarkedData() {
+		if diff, ok := data.(*models.SecretDiff); ok {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+// GetTargetVault returns the name of the vault compared against
+func (ksyv *KeyVaultSecretSyncView) GetTargetVault() string {
+	return ksyv.targetVault
+}
+
+// GetTargetURL returns the URL of the vault compared against
+func (ksyv *KeyVaultSecretSyncView) GetTargetURL() string {
+	return ksyv.targetURL
+}
+
+// ComparesValues reports whether the diff includes secret values
+func (ksyv *KeyVaultSecretSyncView) ComparesValues() bool {
+	return ksyv.compareValues
+}
+
+// SetOnShowDetails sets the callback for showing both sides of a secret (d key)
+func (ksyv *KeyVaultSecretSyncView) SetOnShowDetails(callback func(*models.SecretDiff)) {
+	ksyv.onShowDetails = callback
+}
+
+// SetOnViewValues sets the callback for viewing the values on both sides (v key)
+func (ksyv *KeyVaultSecretSyncView) SetOnViewValues(callback func(*models.SecretDiff)) {
+	ksyv.onViewValues = callback
+}
+
+// SetOnApply sets the callback for copying the marked or selected secrets to the target (a key)
+func (ksyv *KeyVaultSecretSyncView) SetOnApply(callback func([]*models.SecretDiff)) {
+	ksyv.onApply = callback
+}
+
+// KeyVaultExpiryView displays the secrets, keys and certificates that have expired or expire soon
+// across the Key Vaults of one or more subscriptions
+type KeyVaultExpiryView struct {
+	*TableView
+	report        *models.ExpiryReport
+	subscriptions []*models.Subscription
+	onShowDetails func(item *models.ExpiryItem)
+	onExport      func()
+	onRescan      func()
+}
+
+// NewKeyVaultExpiryView creates a new expiry report view
+func NewKeyVaultExpiryView() *KeyVaultExpiryView {
+	kev := &KeyVaultExpiryView{}
+
+	config := &TableConfig{
+		Title: "",
+		Columns: []ColumnConfig{
+			{Name: "Status", Align: tview.AlignLeft},
+			{Name: "Days", Align: tview.AlignRight},
+			{Name: "Expires", Align: tview.AlignLeft},
+			{Name: "Type", Align: tview.AlignLeft},
+			{Name: "Name", Align: tview.AlignLeft},
+			{Name: "Vault", Align: tview.AlignLeft},
+			{Name: "Resource Group", Align: tview.AlignLeft},
+			{Name: "Subscription", Align: tview.AlignLeft},
+		},
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if item, ok := data.(*models.ExpiryItem); ok && kev.onShowDetails != nil {
+						kev.onShowDetails(item)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'e',
+				Label: "Export",
+				Callback: func() bool {
+					if kev.onExport != nil {
+						kev.onExport()
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'r',
+				Label: "Rescan",
+				Callback: func() bool {
+					if kev.onRescan != nil {
+						kev.onRescan()
+						return true
+					}
+					return false
+				},
+			},
+		},
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on an item - show details
+			if item, ok := data.(*models.ExpiryItem); ok && kev.onShowDetails != nil {
+				kev.onShowDetails(item)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			item, ok := data.(*models.ExpiryItem)
+			if !ok {
+				return ""
+			}
+			switch columnIndex {
+			case 0:
+				return item.Status
+			case 1:
+				return fmt.Sprintf("%d", item.DaysLeft)
+			case 2:
+				return item.Expires.Local().Format("2006-01-02 15:04:05")
+			case 3:
+				return item.Type
+			case 4:
+				return item.Name
+			case 5:
+				return item.Vault
+			case 6:
+				return item.ResourceGroup
+			case 7:
+				return item.SubscriptionName
+			default:
+				return ""
+			}
+		},
+		GetRowColor: func(data interface{}) tcell.Color {
+			if item, ok := data.(*models.ExpiryItem); ok {
+				return expiryStatusColor(item.Status)
+			}
+			return tcell.ColorWhite
+		},
+	}
+
+	kev.TableView = NewTableView(config)
+	return kev
+}
+
+// expiryStatusColor returns the colour of an expiry status, from red for expired to white for upcoming
+func expiryStatusColor(status string) tcell.Color {
+	switch status {
+	case models.ExpiryStatusExpired:
+		return tcell.ColorRed
+	case models.ExpiryStatusCritical:
+		return tcell.ColorOrange
+	case models.ExpiryStatusWarning:
+		return tcell.ColorYellow
+	default:
+		return tcell.ColorWhite
+	}
+}
+
+// LoadReport loads an expiry report and the subscriptions it was built from into the view
+func (kev *KeyVaultExpiryView) LoadReport(report *models.ExpiryReport, subscriptions []*models.Subscription) {
+	kev.report = report
+	kev.subscriptions = subscriptions
+
+	data := make([]interface{}, len(report.Items))
+	for i, item := range report.Items {
+		data[i] = item
+	}
+	kev.LoadData(data)
+}
+
+// GetReport returns the report shown in the view
+func (kev *KeyVaultExpiryView) GetReport() *models.ExpiryReport {
+	return kev.report
+}
+
+// GetSubscriptions returns the subscriptions the report was built from
+func (kev *KeyVaultExpiryView) GetSubscriptions() []*models.Subscription {
+	return kev.subscriptions
+}
+
+// SetOnShowDetails sets the callback for when details are requested (d key or Enter)
+func (kev *KeyVaultExpiryView) SetOnShowDetails(callback func(*models.ExpiryItem)) {
+	kev.onShowDetails = callback
+}
+
+// SetOnExport sets the callback for exporting the report as CSV or JSON (e key)
+func (kev *KeyVaultExpiryView) SetOnExport(callback func()) {
+	kev.onExport = callback
+}
+
+// SetOnRescan sets the callback for building the report again (r key)
+func (kev *KeyVaultExpiryView) SetOnRescan(callback func()) {
+	kev.onRescan = callback
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"azure-control-tower/internal/export"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// exportSecrets asks for an export format and file and writes the values of the given secrets to it
+func (a *App) exportSecrets(items []models.VaultItemRef) {
+	vaultName := a.navState.SelectedKeyVault
+
+	form := tview.NewForm()
+	form.AddDropDown("Format", export.Formats, 0, func(option string, optionIndex int) {
+		// Follow the format with the file name until a custom name is entered
+		if field, ok := form.GetFormItemByLabel("File").(*tview.InputField); ok {
+			for _, format := range export.Formats {
+				if field.GetText() == export.DefaultFileName(format) {
+					field.SetText(export.DefaultFileName(option))
+					break
+				}
+			}
+		}
+	})
+	form.AddDropDown("Name Mapping", export.Mappings, 0, nil).
+		AddInputField("Key Prefix", "", 0, nil, nil).
+		AddInputField("File", export.DefaultFileName(export.FormatDotenv), 0, nil, nil).
+		AddInputField("Secret Name", export.KubernetesName(vaultName), 0, nil, nil).
+		AddInputField("Namespace", "", 0, nil, nil)
+
+	form.AddButton("Export", func() {
+		path := strings.TrimSpace(formText(form, "File"))
+		if path == "" {
+			a.showError("Invalid file", fmt.Errorf("enter the file to write the secrets to"))
+			return
+		}
+		opts := export.Options{
+			Format:     formOption(form, "Format"),
+			Mapping:    formOption(form, "Name Mapping"),
+			Prefix:     strings.TrimSpace(formText(form, "Key Prefix")),
+			SecretName: strings.TrimSpace(formText(form, "Secret Name")),
+			Namespace:  strings.TrimSpace(formText(form, "Namespace")),
+		}
+		if err := opts.Validate(); err != nil {
+			a.showError("Invalid export options", err)
+			return
+		}
+		a.closeDialog()
+
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i] = item.Name
+		}
+
+		message := fmt.Sprintf("Write the values of %d secret(s) from %s in plain text to %s?\n\nThe file is readable only by you (mode 0600). Keep it out of version control.",
+			len(names), vaultName, path)
+		if _, err := os.Stat(path); err == nil {
+			message += fmt.Sprintf("\n\n⚠️ %s already exists and will be overwritten.", path)
+		}
+		a.confirm(message, "Export", func() {
+			a.runSecretExport(names, path, opts)
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Export %d secret(s) from %s", len(items), vaultName), 70, 17)
+}
+
+// runSecretExport reads the secret values in the background and writes the export file
+func (a *App) runSecretExport(names []string, path string, opts export.Options) {
+	ctx, cancel := context.WithCancel(context.Background())
+	vaultURL := a.navState.SelectedKeyVaultURL
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Reading %d secret(s) from %s...", len(names), a.navState.SelectedKeyVault)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		defer cancel()
+
+		err := func() error {
+			entries, err := export.Fetch(ctx, names, opts, func(ctx context.Context, name string) (string, error) {
+				return a.azureClient.GetSecretValue(ctx, vaultURL, name, "")
+			})
+			if err != nil {
+				return err
+			}
+			data, err := export.Render(entries, opts)
+			if err != nil {
+				return err
+			}
+			return export.WriteFile(path, data)
+		}()
+
+		a.QueueUpdateDraw(func() {
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to export secrets", err)
+				}
+				return
+			}
+			a.keyVaultSecretsView.ClearMarks()
+			a.showInfo(fmt.Sprintf("Exported %d secret(s) to %s", len(names), path))
+		})
+	}()
+}
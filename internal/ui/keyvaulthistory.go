@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// showSecretHistory reads the versions and audit events of a secret in the background and shows its timeline
+func (a *App) showSecretHistory(secretName string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	subscriptionID := a.navState.SelectedSubscriptionID
+	resourceGroup := a.navState.SelectedKeyVaultRG
+	vaultName := a.navState.SelectedKeyVault
+	vaultURL := a.navState.SelectedKeyVaultURL
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Reading the versions and audit logs of secret '%s'...", secretName)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		history, err := a.azureClient.GetSecretHistory(ctx, subscriptionID, resourceGroup, vaultName, vaultURL, secretName)
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to read secret history", err)
+				}
+				return
+			}
+
+			a.navState.NavigateToDetails()
+			a.detailsView.ShowSecretHistory(history, vaultName)
+			a.updateLayout()
+			a.SetFocus(a.detailsView)
+		})
+	}()
+}
+
+// compareSecretVersion asks for another version of the secret and tells whether their values differ,
+// comparing hashes so that no value is shown
+func (a *App) compareSecretVersion(version *models.Secret) {
+	var others []*models.Secret
+	var labels []string
+	selected := 0
+	for i, other := range a.keyVaultSecretVersionsView.GetVersions() {
+		if other.Version == version.Version {
+			// Versions are newest first, so the version before the selected one takes its place in the list
+			selected = i
+			continue
+		}
+		others = append(others, other)
+		labels = append(labels, fmt.Sprintf("%s (%s)", other.Version, formatFormTime(other.Created)))
+	}
+	if len(others) == 0 {
+		a.showInfo(fmt.Sprintf("Secret '%s' has a single version", version.Name))
+		return
+	}
+	selected = min(selected, len(others)-1)
+
+	form := tview.NewForm().
+		AddDropDown("Compare With", labels, selected, nil)
+
+	form.AddButton("Compare", func() {
+		index, _ := form.GetFormItemByLabel("Compare With").(*tview.DropDown).GetCurrentOption()
+		if index < 0 {
+			return
+		}
+		older, newer := others[index], version
+		if newer.Created != nil && older.Created != nil && older.Created.After(*newer.Created) {
+			older, newer = newer, older
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		comparison, err := a.azureClient.CompareSecretVersions(ctx, a.navState.SelectedKeyVaultURL, version.Name, older.Version, newer.Version)
+		if err != nil {
+			a.showError("Failed to compare secret versions", err)
+			return
+		}
+
+		result := "hold the same value"
+		if comparison.Changed {
+			result = "hold different values"
+		}
+		modal := tview.NewModal().
+			SetText(fmt.Sprintf("Versions %s and %s of secret '%s' %s.\n\nThe values were compared by their SHA-256 hashes and not displayed.",
+				older.Version, newer.Version, version.Name, result)).
+			AddButtons([]string{"Show Diff", "Close"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				a.closeDialog()
+				if buttonLabel == "Show Diff" {
+					a.showSecretVersionDiff(version.Name, older, newer)
+				}
+			})
+		a.showModal(modal)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Compare %s", version.Version), 90, 7)
+}
+
+// showSecretVersionDiff shows two versions of a secret side by side, line by line. Lines are masked until
+// revealed with r after a confirmation.
+func (a *App) showSecretVersionDiff(secretName string, older, newer *models.Secret) {
+	ctx := context.Background()
+	vaultURL := a.navState.SelectedKeyVaultURL
+	olderValue, err := a.azureClient.GetSecretValue(ctx, vaultURL, secretName, older.Version)
+	if err != nil {
+		a.showError("Failed to get secret value", err)
+		return
+	}
+	newerValue, err := a.azureClient.GetSecretValue(ctx, vaultURL, secretName, newer.Version)
+	if err != nil {
+		a.showError("Failed to get secret value", err)
+		return
+	}
+	rows := azure.DiffLines(olderValue, newerValue)
+
+	theme := a.detailsView.theme
+	table := tview.NewTable().
+		SetFixed(1, 0).
+		SetSelectable(false, false)
+	table.SetBorder(true).
+		SetBorderColor(theme.Border)
+
+	revealed := false
+	render := func() {
+		table.Clear()
+		table.SetTitle(fmt.Sprintf(" %s - r: reveal/hide, Enter/ESC: close ", secretName))
+		table.SetCell(0, 1, tview.NewTableCell(fmt.Sprintf("%s (older)", older.Version)).SetTextColor(theme.Label).SetExpansion(1))
+		table.SetCell(0, 2, tview.NewTableCell(newer.Version).SetTextColor(theme.Label).SetExpansion(1))
+
+		show := func(line string) string {
+			if revealed || line == "" {
+				return line
+			}
+			return maskSecretValue(line)
+		}
+		for i, row := range rows {
+			marker, left, right := " ", tcell.ColorWhite, tcell.ColorWhite
+			if row.Changed {
+				marker, left, right = "~", tcell.ColorRed, tcell.ColorGreen
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(marker).SetTextColor(tcell.ColorYellow))
+			table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(show(row.Left))).SetTextColor(left).SetExpansion(1))
+			table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(show(row.Right))).SetTextColor(right).SetExpansion(1))
+		}
+	}
+	render()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter:
+			a.closeDialog()
+			return nil
+		case event.Rune() == 'r':
+			if revealed {
+				revealed = false
+				render()
+				return nil
+			}
+			a.confirm(fmt.Sprintf("Are you sure you want to reveal versions %s and %s of secret '%s'?\n\n⚠️ This will display sensitive information on screen.",
+				older.Version, newer.Version, secretName), "Reveal", func() {
+				revealed = true
+				render()
+			})
+			return nil
+		}
+		return event
+	})
+
+	a.showDialog(table, 110, 24)
+}
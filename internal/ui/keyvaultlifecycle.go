@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/bulk"
+	"azure-control-tower/internal/models"
+)
+
+// loadKeyVaultProperties reads the soft delete and purge protection settings of the selected vault.
+// They only guide the confirmations; if they cannot be read the service still enforces them.
+func (a *App) loadKeyVaultProperties() {
+	a.keyVaultProperties = nil
+	if a.navState.SelectedKeyVaultRG == "" {
+		return
+	}
+
+	ctx := context.Background()
+	vault, err := a.azureClient.GetKeyVault(ctx, a.navState.SelectedSubscriptionID, a.navState.SelectedKeyVaultRG, a.navState.SelectedKeyVault)
+	if err != nil {
+		return
+	}
+	a.keyVaultProperties = vault
+}
+
+// deleteVaultItem deletes a secret, key or certificate after a confirmation matching the vault's soft delete settings.
+// Without soft delete the deletion is permanent, so the name has to be typed.
+func (a *App) deleteVaultItem(itemType, name string) {
+	apply := func() {
+		ctx := context.Background()
+		if err := a.azureClient.DeleteVaultItem(ctx, a.navState.SelectedKeyVaultURL, itemType, name); err != nil {
+			a.showError(fmt.Sprintf("Failed to delete %s", itemType), err)
+			return
+		}
+		a.navigateToKeyVaultItemType(itemType + "s")
+	}
+
+	message := azure.DeleteWarning(a.keyVaultProperties, itemType, name)
+	if a.keyVaultProperties == nil || !a.keyVaultProperties.SoftDeleteEnabled {
+		a.confirmTypedName(message, name, apply)
+		return
+	}
+	a.confirm(message, "Delete", apply)
+}
+
+// deleteVaultItems deletes the marked secrets, keys or certificates. A single item gets the confirmation of
+// deleteVaultItem; several are listed in a summary, and without soft delete the vault name has to be typed.
+func (a *App) deleteVaultItems(items []models.VaultItemRef) {
+	if len(items) == 1 {
+		a.deleteVaultItem(items[0].Type, items[0].Name)
+		return
+	}
+
+	itemType := items[0].Type
+	vaultName := a.navState.SelectedKeyVault
+	vaultURL := a.navState.SelectedKeyVaultURL
+	run := func() {
+		prepare := func(ctx context.Context) ([]bulk.Item, error) {
+			bulkItems := make([]bulk.Item, 0, len(items))
+			for _, item := range items {
+				item := item
+				bulkItems = append(bulkItems, bulk.Item{
+					Label: item.Name,
+					Run: func(ctx context.Context) error {
+						return a.azureClient.DeleteVaultItem(ctx, vaultURL, item.Type, item.Name)
+					},
+				})
+			}
+			return bulkItems, nil
+		}
+		a.runBulkAction(fmt.Sprintf("Deleting %d %ss from %s", len(items), itemType, vaultName), prepare, nil, func() {
+			a.navigateToKeyVaultItemType(itemType + "s")
+		})
+	}
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	a.confirmBulkAction(azure.DeleteItemsWarning(a.keyVaultProperties, itemType, len(items)), names, "Delete", func() {
+		if a.keyVaultProperties == nil || !a.keyVaultProperties.SoftDeleteEnabled {
+			a.confirmTypedName(fmt.Sprintf("The %d %ss cannot be recovered once deleted.", len(items), itemType), vaultName, run)
+			return
+		}
+		run()
+	})
+}
+
+// navigateToDeletedItems shows the soft-deleted items of the selected vault
+func (a *App) navigateToDeletedItems() {
+	a.navState.NavigateToKeyVaultDeletedItems()
+	a.loadDeletedItems()
+}
+
+// loadDeletedItems loads the soft-deleted items of the selected vault
+func (a *App) loadDeletedItems() {
+	ctx := context.Background()
+	items, err := a.azureClient.ListDeletedItems(ctx, a.navState.SelectedKeyVaultURL)
+	if err != nil {
+		a.showError("Failed to list deleted items", err)
+		return
+	}
+
+	a.keyVaultDeletedItemsView.LoadItems(items)
+	a.updateLayout()
+	a.SetFocus(a.keyVaultDeletedItemsView)
+}
+
+// showDeletedItemDetails shows the details view for a deleted item
+func (a *App) showDeletedItemDetails(item *models.DeletedItem) {
+	a.navState.NavigateToDetails()
+	a.detailsView.ShowDeletedItemDetails(item, a.keyVaultProperties, a.navState.SelectedKeyVault)
+	a.updateLayout()
+	a.SetFocus(a.detailsView)
+}
+
+// recoverDeletedItem restores a deleted item to its latest version
+func (a *App) recoverDeletedItem(item *models.DeletedItem) {
+	message := fmt.Sprintf("Recover %s '%s'? It becomes available again with all its versions.", item.Type, item.Name)
+	a.confirm(message, "Recover", func() {
+		ctx := context.Background()
+		if err := a.azureClient.RecoverDeletedItem(ctx, a.navState.SelectedKeyVaultURL, item.Type, item.Name); err != nil {
+			a.showError(fmt.Sprintf("Failed to recover %s", item.Type), err)
+			return
+		}
+		a.loadDeletedItems()
+		a.showInfo(fmt.Sprintf("Recovery of %s '%s' started. It can take a few seconds before it is listed again.", item.Type, item.Name))
+	})
+}
+
+// purgeDeletedItem permanently deletes a deleted item; the name has to be typed to confirm.
+// Purging is refused up front when the vault has purge protection.
+func (a *App) purgeDeletedItem(item *models.DeletedItem) {
+	if err := azure.CheckPurgeAllowed(a.keyVaultProperties, item); err != nil {
+		a.showError(fmt.Sprintf("Cannot purge %s", item.Type), err)
+		return
+	}
+
+	message := fmt.Sprintf("Permanently purge %s '%s'? This cannot be undone.", item.Type, item.Name)
+	a.confirmTypedName(message, item.Name, func() {
+		ctx := context.Background()
+		if err := a.azureClient.PurgeDeletedItem(ctx, a.navState.SelectedKeyVaultURL, item.Type, item.Name); err != nil {
+			a.showError(fmt.Sprintf("Failed to purge %s", item.Type), err)
+			return
+		}
+		a.loadDeletedItems()
+	})
+}
+
+// navigateBackFromDeletedItems returns from the deleted items view to the Key Vault explorer
+func (a *App) navigateBackFromDeletedItems() {
+	a.navState.NavigateBackFromKeyVaultDeletedItems()
+	a.navigateBackToKeyVaultExplorer()
+}
@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// resolveTargetVault reads the target vault from a form and refuses the selected vault itself
+func (a *App) resolveTargetVault(form *tview.Form) (string, string, error) {
+	targetURL, err := azure.ResolveVaultURL(formText(form, "Target Vault"))
+	if err != nil {
+		return "", "", err
+	}
+	if strings.EqualFold(strings.TrimSuffix(targetURL, "/"), strings.TrimSuffix(a.navState.SelectedKeyVaultURL, "/")) {
+		return "", "", fmt.Errorf("the target vault is the vault being browsed")
+	}
+	return vaultNameFromURL(targetURL), targetURL, nil
+}
+
+// vaultNameFromURL returns the vault name, the first label of the vault host name
+func vaultNameFromURL(vaultURL string) string {
+	host := strings.TrimSuffix(strings.TrimPrefix(vaultURL, "https://"), "/")
+	if idx := strings.Index(host, "."); idx > 0 {
+		return host[:idx]
+	}
+	return host
+}
+
+// copySecretsToVault asks for a target vault and copies the latest version of the given secrets to it
+func (a *App) copySecretsToVault(items []models.VaultItemRef) {
+	form := tview.NewForm().
+		AddInputField("Target Vault", "", 0, nil, nil).
+		AddCheckbox("Overwrite Existing", false, nil)
+
+	form.AddButton("Copy", func() {
+		targetVault, targetURL, err := a.resolveTargetVault(form)
+		if err != nil {
+			a.showError("Invalid target vault", err)
+			return
+		}
+		overwrite := formChecked(form, "Overwrite Existing")
+		a.closeDialog()
+
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i] = item.Name
+		}
+		run := func() {
+			a.runSecretCopy(names, targetVault, targetURL, overwrite, func() {
+				a.keyVaultSecretsView.ClearMarks()
+			})
+		}
+		if overwrite {
+			a.confirm(fmt.Sprintf("Copy %d secret(s) to %s?\n\nSecrets that already exist there get a new version with the value from %s.",
+				len(names), targetVault, a.navState.SelectedKeyVault), "Copy", run)
+			return
+		}
+		run()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Copy %d secret(s) from %s", len(items), a.navState.SelectedKeyVault), 70, 11)
+}
+
+// runSecretCopy copies secrets to the target vault in the background with a progress dialog
+func (a *App) runSecretCopy(names []string, targetVault, targetURL string, overwrite bool, onClose func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sourceURL := a.navState.SelectedKeyVaultURL
+
+	update, finish := a.showItemProgress(fmt.Sprintf("Copying secrets from %s to %s", a.navState.SelectedKeyVault, targetVault), cancel, onClose)
+
+	go func() {
+		defer cancel()
+
+		progress, err := a.azureClient.CopySecrets(ctx, sourceURL, targetURL, names, overwrite, func(p models.ItemProgress) {
+			update(formatItemProgress(p))
+		})
+		status := "Copy finished"
+		detail := fmt.Sprintf("Copied to %s", targetVault)
+		if err != nil {
+			status = "Copy stopped"
+			detail = fmt.Sprintf("%v", err)
+		}
+		finish(status, detail, progress)
+	}()
+}
+
+// syncVaultSecrets asks for a target vault and compares the secrets of the selected vault with it
+func (a *App) syncVaultSecrets() {
+	form := tview.NewForm().
+		AddInputField("Target Vault", "", 0, nil, nil).
+		AddCheckbox("Compare Values", false, nil)
+
+	form.AddButton("Compare", func() {
+		targetVault, targetURL, err := a.resolveTargetVault(form)
+		if err != nil {
+			a.showError("Invalid target vault", err)
+			return
+		}
+		compareValues := formChecked(form, "Compare Values")
+		a.closeDialog()
+
+		if compareValues {
+			message := fmt.Sprintf("Read the value of every secret in %s and %s to compare them?\n\nValues are compared by hash and are not displayed.",
+				a.navState.SelectedKeyVault, targetVault)
+			a.confirm(message, "Compare", func() {
+				a.compareVaultSecrets(targetVault, targetURL, true)
+			})
+			return
+		}
+		a.compareVaultSecrets(targetVault, targetURL, false)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Sync secrets from %s", a.navState.SelectedKeyVault), 70, 11)
+}
+
+// compareVaultSecrets computes the secret diff in the background and shows it in the secret sync view
+func (a *App) compareVaultSecrets(targetVault, targetURL string, compareValues bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sourceURL := a.navState.SelectedKeyVaultURL
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Comparing secrets of %s and %s...", a.navState.SelectedKeyVault, targetVault)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		defer cancel()
+
+		diffs, err := a.azureClient.CompareVaultSecrets(ctx, sourceURL, targetURL, compareValues)
+		a.QueueUpdateDraw(func() {
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to compare secrets", err)
+				}
+				return
+			}
+
+			a.navState.NavigateToKeyVaultSecretSync()
+			a.keyVaultSecretSyncView.LoadDiffs(diffs, targetVault, targetURL, compareValues)
+			a.updateLayout()
+			a.SetFocus(a.keyVaultSecretSyncView)
+		})
+	}()
+}
+
+// showSecretDiff shows the properties of a secret in both vaults side by side; values are never shown here
+func (a *App) showSecretDiff(diff *models.SecretDiff) {
+	differs := make(map[string]bool)
+	for _, difference := range diff.Differences {
+		differs[difference] = true
+	}
+
+	orDash := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	}
+	side := func(secret *models.Secret, field string) string {
+		if secret == nil {
+			return "-"
+		}
+		switch field {
+		case "version":
+			return secret.Version
+		case "updated":
+			return secretUpdated(secret)
+		case "content type":
+			return orDash(secret.ContentType)
+		case "enabled":
+			return fmt.Sprintf("%t", secret.Enabled)
+		case "expires":
+			return orDash(formatFormTime(secret.Expires))
+		case "not before":
+			return orDash(formatFormTime(secret.NotBefore))
+		case "tags":
+			var tags []string
+			for k, v := range secret.Tags {
+				tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(tags)
+			return orDash(strings.Join(tags, ", "))
+		}
+		return ""
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("[lightblue::b]Status:[white] %s\n\n", diff.Status))
+	text.WriteString(fmt.Sprintf("[lightblue::b]%-14s[white] %-36s %s\n", "", tview.Escape(a.navState.SelectedKeyVault), tview.Escape(a.keyVaultSecretSyncView.GetTargetVault())))
+	for _, field := range []string{"version", "updated", "content type", "enabled", "expires", "not before", "tags"} {
+		color := "white"
+		if differs[field] {
+			color = "yellow"
+		}
+		text.WriteString(fmt.Sprintf("[lightblue::b]%-14s[%s] %-36s %s\n", field+":", color,
+			tview.Escape(side(diff.Source, field)), tview.Escape(side(diff.Target, field))))
+	}
+	if differs["value"] {
+		text.WriteString("\n[yellow]The values differ.[white] Press v in the list to view them.\n")
+	} else if !a.keyVaultSecretSyncView.ComparesValues() {
+		text.WriteString("\nValues were not compared.\n")
+	}
+
+	a.showTextDialog(fmt.Sprintf("Secret %s", diff.Name), text.String())
+}
+
+// viewSecretDiffValues shows the latest value of a secret in both vaults after a confirmation
+func (a *App) viewSecretDiffValues(diff *models.SecretDiff) {
+	targetVault := a.keyVaultSecretSyncView.GetTargetVault()
+	message := fmt.Sprintf("Are you sure you want to view the values of secret '%s' in %s and %s?\n\n⚠️ This will display sensitive information on screen.",
+		diff.Name, a.navState.SelectedKeyVault, targetVault)
+
+	a.confirm(message, "View", func() {
+		ctx := context.Background()
+		value := func(secret *models.Secret, vaultURL string) string {
+			if secret == nil {
+				return "[gray](missing)[white]"
+			}
+			v, err := a.azureClient.GetSecretValue(ctx, vaultURL, diff.Name, "")
+			if err != nil {
+				return fmt.Sprintf("[red]%s[white]", tview.Escape(err.Error()))
+			}
+			return tview.Escape(v)
+		}
+
+		var text strings.Builder
+		text.WriteString(fmt.Sprintf("[lightblue::b]%s:[white]\n%s\n\n", tview.Escape(a.navState.SelectedKeyVault), value(diff.Source, a.navState.SelectedKeyVaultURL)))
+		text.WriteString(fmt.Sprintf("[lightblue::b]%s:[white]\n%s\n", tview.Escape(targetVault), value(diff.Target, a.keyVaultSecretSyncView.GetTargetURL())))
+		a.showTextDialog(fmt.Sprintf("Secret %s", diff.Name), text.String())
+	})
+}
+
+// applySecretSync copies the missing and different secrets among the given ones to the target vault,
+// then compares the vaults again. Identical secrets and secrets that only exist in the target are left alone.
+func (a *App) applySecretSync(diffs []*models.SecretDiff) {
+	var names []string
+	for _, diff := range diffs {
+		if diff.Status == models.SyncStatusMissing || diff.Status == models.SyncStatusDifferent {
+			names = append(names, diff.Name)
+		}
+	}
+	if len(names) == 0 {
+		a.showInfo("Nothing to apply: the selected secrets are identical or only exist in the target vault.")
+		return
+	}
+
+	targetVault := a.keyVaultSecretSyncView.GetTargetVault()
+	targetURL := a.keyVaultSecretSyncView.GetTargetURL()
+	compareValues := a.keyVaultSecretSyncView.ComparesValues()
+	message := fmt.Sprintf("Copy %d secret(s) from %s to %s?\n\nSecrets that differ get a new version in %s with the latest value and properties from %s.",
+		len(names), a.navState.SelectedKeyVault, targetVault, targetVault, a.navState.SelectedKeyVault)
+
+	a.confirm(message, "Apply", func() {
+		a.runSecretCopy(names, targetVault, targetURL, true, func() {
+			a.keyVaultSecretSyncView.ClearMarks()
+			a.compareVaultSecrets(targetVault, targetURL, compareValues)
+		})
+	})
+}
+
+// navigateBackFromSecretSync returns from the secret sync view to the Key Vault explorer
+func (a *App) navigateBackFromSecretSync() {
+	a.navState.NavigateBackFromKeyVaultSecretSync()
+	a.navigateBackToKeyVaultExplorer()
+}
@@ -72,6 +72,12 @@ func NewResourceGroupsView() *ResourceGroupsView {
 				return ""
 			}
 		},
+		GetTags: func(data interface{}) map[string]*string {
+			if rowData, ok := data.(*ResourceGroupRowData); ok {
+				return rowData.ResourceGroup.Tags
+			}
+			return nil
+		},
 	}
 
 	rgv.TableView = NewTableView(config)
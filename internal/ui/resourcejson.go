@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/jsontree"
+	"azure-control-tower/internal/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// JSON viewer display modes, cycled with r
+const (
+	jsonModeTree = iota
+	jsonModeRaw
+	jsonModeYAML
+)
+
+var jsonModeNames = []string{"Tree", "JSON", "YAML"}
+
+// resourceJSONViewer shows the full ARM representation of a resource as a collapsible tree, as indented
+// JSON or as YAML
+type resourceJSONViewer struct {
+	app      *App
+	document *models.ResourceDocument
+	root     *jsontree.Node
+	title    string
+
+	layout *tview.Flex
+	tree   *tview.TreeView
+	text   *tview.TextView
+	status *tview.TextView
+	search *tview.InputField
+
+	mode    int
+	nodes   map[*jsontree.Node]*tview.TreeNode
+	parents map[*tview.TreeNode]*tview.TreeNode
+	matches []*jsontree.Node
+	match   int
+}
+
+// showResourceJSON reads the full ARM representation of a resource in the background and shows it in the JSON viewer
+func (a *App) showResourceJSON(resource *models.Resource, subscriptionID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Reading '%s' from Azure Resource Manager...", resource.Name)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		document, err := a.azureClient.GetResourceJSON(ctx, subscriptionID, resource.ID, resource.Type)
+		var root *jsontree.Node
+		if err == nil {
+			root, err = jsontree.Parse(document.JSON)
+		}
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to read resource JSON", err)
+				}
+				return
+			}
+			a.showJSONViewer(resource.Name, document, root)
+		})
+	}()
+}
+
+// showJSONViewer opens the JSON viewer over the main layout
+func (a *App) showJSONViewer(title string, document *models.ResourceDocument, root *jsontree.Node) {
+	theme := a.detailsView.theme
+	v := &resourceJSONViewer{
+		app:      a,
+		document: document,
+		root:     root,
+		title:    title,
+		tree:     tview.NewTreeView(),
+		text:     tview.NewTextView().SetWrap(false),
+		status:   tview.NewTextView().SetDynamicColors(true),
+		search:   tview.NewInputField().SetLabel("/"),
+		nodes:    make(map[*jsontree.Node]*tview.TreeNode),
+		parents:  make(map[*tview.TreeNode]*tview.TreeNode),
+	}
+
+	rootNode := v.buildNode(root, nil)
+	rootNode.SetText(fmt.Sprintf("[white]%s", tview.Escape(title)))
+	rootNode.Expand()
+	// Open the properties, where most of the resource configuration lives
+	for _, child := range root.Children {
+		if child.Key == "properties" {
+			v.nodes[child].Expand()
+		}
+	}
+	v.tree.SetRoot(rootNode).
+		SetCurrentNode(rootNode).
+		SetChangedFunc(func(node *tview.TreeNode) { v.showPath() })
+	v.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+	v.tree.SetBorder(true).SetBorderColor(theme.Border)
+	v.text.SetBorder(true).SetBorderColor(theme.Border)
+
+	v.search.SetFieldBackgroundColor(tcell.ColorDefault).
+		SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				v.find(v.search.GetText())
+			}
+			v.layout.RemoveItem(v.search)
+			v.layout.AddItem(v.status, 1, 0, false)
+			a.SetFocus(v.layout)
+		})
+
+	v.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.tree, 0, 1, true).
+		AddItem(v.status, 1, 0, false)
+
+	v.tree.SetInputCapture(v.handleKey)
+	v.text.SetInputCapture(v.handleKey)
+
+	v.updateTitle()
+	v.showPath()
+	a.showFullScreenDialog(v.layout, v.layout)
+}
+
+// resourceJSONAction is the details action that opens the JSON viewer for a resource
+func (a *App) resourceJSONAction(resource *models.Resource, subscriptionID string) DetailsAction {
+	return DetailsAction{Rune: 'j', Label: "JSON", Callback: func() { a.showResourceJSON(resource, subscriptionID) }}
+}
+
+// buildNode creates the tree node of a JSON node and its descendants. Containers start collapsed.
+func (v *resourceJSONViewer) buildNode(node *jsontree.Node, parent *tview.TreeNode) *tview.TreeNode {
+	treeNode := tview.NewTreeNode(formatJSONNode(node)).
+		SetReference(node).
+		SetSelectable(true).
+		SetExpanded(false)
+	v.nodes[node] = treeNode
+	if parent != nil {
+		v.parents[treeNode] = parent
+	}
+	for _, child := range node.Children {
+		treeNode.AddChild(v.buildNode(child, treeNode))
+	}
+	return treeNode
+}
+
+// formatJSONNode renders the key of a node with its value, or the size of objects and arrays
+func formatJSONNode(node *jsontree.Node) string {
+	key := fmt.Sprintf("[lightblue]%s[white]", tview.Escape(node.Key))
+	switch node.Kind {
+	case jsontree.Object:
+		return fmt.Sprintf("%s {%d}", key, len(node.Children))
+	case jsontree.Array:
+		return fmt.Sprintf("%s [%d[]", key, len(node.Children))
+	case jsontree.String:
+		quoted, _ := json.Marshal(node.Value)
+		return fmt.Sprintf("%s: [green]%s[white]", key, tview.Escape(string(quoted)))
+	case jsontree.Number:
+		return fmt.Sprintf("%s: [yellow]%s[white]", key, node.Value)
+	default:
+		return fmt.Sprintf("%s: [fuchsia]%s[white]", key, node.Value)
+	}
+}
+
+// handleKey handles the keys of the viewer in every mode
+func (v *resourceJSONViewer) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEscape {
+		v.app.closeDialog()
+		return nil
+	}
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+
+	switch event.Rune() {
+	case 'r':
+		v.setMode((v.mode + 1) % len(jsonModeNames))
+		return nil
+	case 'y':
+		v.copy()
+		return nil
+	}
+
+	if v.mode != jsonModeTree {
+		return event
+	}
+	switch event.Rune() {
+	case '/':
+		v.search.SetText("")
+		v.layout.RemoveItem(v.status)
+		v.layout.AddItem(v.search, 1, 0, false)
+		v.app.SetFocus(v.search)
+		return nil
+	case 'n':
+		v.nextMatch(1)
+		return nil
+	case 'N':
+		v.nextMatch(-1)
+		return nil
+	case '+':
+		if node := v.tree.GetCurrentNode(); node != nil {
+			node.ExpandAll()
+		}
+		return nil
+	case '-':
+		if node := v.tree.GetCurrentNode(); node != nil {
+			node.CollapseAll()
+		}
+		return nil
+	}
+	return event
+}
+
+// setMode switches between the tree, indented JSON and YAML
+func (v *resourceJSONViewer) setMode(mode int) {
+	switch mode {
+	case jsonModeRaw:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, v.document.JSON, "", "  "); err != nil {
+			v.app.showError("Failed to format JSON", err)
+			return
+		}
+		v.text.SetText(indented.String()).ScrollToBeginning()
+	case jsonModeYAML:
+		text, err := v.root.YAML()
+		if err != nil {
+			v.app.showError("Failed to format YAML", err)
+			return
+		}
+		v.text.SetText(text).ScrollToBeginning()
+	}
+
+	v.mode = mode
+	v.layout.Clear()
+	if mode == jsonModeTree {
+		v.layout.AddItem(v.tree, 0, 1, true)
+	} else {
+		v.layout.AddItem(v.text, 0, 1, true)
+	}
+	v.layout.AddItem(v.status, 1, 0, false)
+	v.updateTitle()
+	v.showPath()
+	v.app.SetFocus(v.layout)
+}
+
+// updateTitle shows the mode and the keys available in it
+func (v *resourceJSONViewer) updateTitle() {
+	keys := "r: tree/JSON/YAML, y: copy, ESC: close"
+	if v.mode == jsonModeTree {
+		keys = "Enter: expand/collapse, +/-: expand/collapse all, /: search, n/N: next/previous match, y: copy path, r: tree/JSON/YAML, ESC: close"
+	}
+	title := fmt.Sprintf(" %s (%s, API version %s) - %s ", v.title, jsonModeNames[v.mode], v.document.APIVersion, keys)
+	v.tree.SetTitle(title)
+	v.text.SetTitle(title)
+}
+
+// showPath shows the path of the selected node in the status line
+func (v *resourceJSONViewer) showPath() {
+	if v.mode != jsonModeTree {
+		v.status.SetText(fmt.Sprintf("[lightblue::b]ID:[white] %s", tview.Escape(v.document.ID)))
+		return
+	}
+	text := fmt.Sprintf("[lightblue::b]Path:[white] %s", tview.Escape(v.currentPath()))
+	if len(v.matches) > 0 {
+		text += fmt.Sprintf("  [yellow]match %d of %d[white]", v.match+1, len(v.matches))
+	}
+	v.status.SetText(text)
+}
+
+// currentPath returns the jq path of the selected node
+func (v *resourceJSONViewer) currentPath() string {
+	if node := v.tree.GetCurrentNode(); node != nil {
+		if jsonNode, ok := node.GetReference().(*jsontree.Node); ok {
+			return jsonNode.Path
+		}
+	}
+	return jsontree.RootPath
+}
+
+// find selects the first node whose key or value contains the query
+func (v *resourceJSONViewer) find(query string) {
+	v.matches = jsontree.Search(v.root, strings.TrimSpace(query))
+	v.match = 0
+	if len(v.matches) == 0 {
+		v.showPath()
+		if query != "" {
+			v.status.SetText(fmt.Sprintf("[yellow]No match for '%s'[white]", tview.Escape(query)))
+		}
+		return
+	}
+	v.selectMatch()
+}
+
+// nextMatch selects the next or previous search match
+func (v *resourceJSONViewer) nextMatch(step int) {
+	if len(v.matches) == 0 {
+		return
+	}
+	v.match = (v.match + step + len(v.matches)) % len(v.matches)
+	v.selectMatch()
+}
+
+// selectMatch expands the ancestors of the current match and selects it
+func (v *resourceJSONViewer) selectMatch() {
+	node := v.nodes[v.matches[v.match]]
+	for parent := v.parents[node]; parent != nil; parent = v.parents[parent] {
+		parent.Expand()
+	}
+	v.tree.SetCurrentNode(node)
+	v.showPath()
+}
+
+// copy copies the path of the selected node in tree mode, or the displayed document otherwise
+func (v *resourceJSONViewer) copy() {
+	text, label := v.currentPath(), "path"
+	if v.mode != jsonModeTree {
+		text, label = v.text.GetText(false), jsonModeNames[v.mode]
+	}
+	method, _, err := v.app.clipboard.Copy(text)
+	if err != nil {
+		v.app.showError("Failed to copy to clipboard", err)
+		return
+	}
+	v.status.SetText(fmt.Sprintf("[green]Copied the %s to the clipboard (%s)[white]", label, method))
+}
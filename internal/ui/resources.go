@@ -29,7 +29,10 @@ type ResourcesView struct {
 	registry           *resource.Registry
 	onShowResourceType func(resourceType string)
 	onShowDetails      func(resource *models.Resource)
-	onExploreStorage   func(resource *models.Resource)
+	onShowJSON         func(resource *models.Resource)
+	onTag              func(resources []*models.Resource)
+	host               resource.Host // Application that handler actions run in
+	handler            resource.ResourceHandler // Handler that defines the columns of the list
 }
 
 // NewResourcesView creates a new resources view
@@ -47,7 +50,8 @@ func NewResourcesView(registry *resource.Registry) *ResourcesView {
 			{Name: "Name", Align: tview.AlignLeft},
 			{Name: "Location", Align: tview.AlignLeft},
 		},
-		RowActions: []RowAction{},
+		RowActions:  []RowAction{},
+		MultiSelect: true,
 		OnSelect: func(rowIndex int, data interface{}) {
 			// Enter key on a resource - could show details or do nothing
 		},
@@ -56,8 +60,8 @@ func NewResourcesView(registry *resource.Registry) *ResourcesView {
 			if !ok {
 				return ""
 			}
-			// Get handler for this resource type
-			handler := rv.registry.GetHandlerOrDefault(rowData.Resource.Type)
+			// Use the handler that defined the columns
+			handler := rv.handler
 			if handler != nil {
 				return handler.GetCellValue(rowData.Resource, columnIndex)
 			}
@@ -77,6 +81,12 @@ func NewResourcesView(registry *resource.Registry) *ResourcesView {
 				return ""
 			}
 		},
+		GetTags: func(data interface{}) map[string]*string {
+			if rowData, ok := data.(*ResourceRowData); ok {
+				return rowData.Resource.Tags
+			}
+			return nil
+		},
 	}
 
 	rv.TableView = NewTableView(config)
@@ -94,13 +104,18 @@ func (rv *ResourcesView) LoadResources(ctx context.Context, resources []*models.
 	rv.SetTitle("")
 
 	// Determine columns and actions based on resource types in the list
-	// Use the first resource's handler, or default if empty
-	var handler resource.ResourceHandler
-	if len(resources) > 0 {
-		handler = rv.registry.GetHandlerOrDefault(resources[0].Type)
-	} else {
-		handler = rv.registry.GetHandlerOrDefault("")
+	// Use the handler of the type when all resources share it, or default otherwise, since handlers
+	// may define different columns
+	resourceType := ""
+	for i, res := range resources {
+		if i > 0 && res.Type != resourceType {
+			resourceType = ""
+			break
+		}
+		resourceType = res.Type
 	}
+	handler := rv.registry.GetHandlerOrDefault(resourceType)
+	rv.handler = handler
 
 	if handler != nil {
 		// Update columns from handler - convert resource.ColumnConfig to ui.ColumnConfig
@@ -118,7 +133,7 @@ func (rv *ResourcesView) LoadResources(ctx context.Context, resources []*models.
 
 		// Build row actions from handler actions
 		actions := handler.GetActions()
-		rowActions := make([]RowAction, 0, len(actions)+1) // +1 for filter by type
+		rowActions := make([]RowAction, 0, len(actions)+3) // +3 for filter by type, JSON and tags
 
 		// Add filter by type action
 		rowActions = append(rowActions, RowAction{
@@ -133,6 +148,33 @@ func (rv *ResourcesView) LoadResources(ctx context.Context, resources []*models.
 			},
 		})
 
+		// Add JSON action, available for every resource type
+		rowActions = append(rowActions, RowAction{
+			Rune:  'j',
+			Label: "JSON",
+			Callback: func(rowIndex int, data interface{}) bool {
+				if rowData, ok := data.(*ResourceRowData); ok && rv.onShowJSON != nil {
+					rv.onShowJSON(rowData.Resource)
+					return true
+				}
+				return false
+			},
+		})
+
+		// Add tags action, applying to the marked or selected resources
+		rowActions = append(rowActions, RowAction{
+			Rune:  'g',
+			Label: "Tags",
+			Callback: func(rowIndex int, data interface{}) bool {
+				resources := rv.GetMarkedResources()
+				if len(resources) == 0 || rv.onTag == nil {
+					return false
+				}
+				rv.onTag(resources)
+				return true
+			},
+		})
+
 		// Add handler actions
 		for _, action := range actions {
 			actionCopy := action // Capture loop variable
@@ -145,19 +187,13 @@ func (rv *ResourcesView) LoadResources(ctx context.Context, resources []*models.
 							SubscriptionID:    subscriptionID,
 							SubscriptionName:  subscriptionName,
 							ResourceGroupName: resourceGroupName,
+							Host:              rv.host,
 						}
 						if actionCopy.Callback != nil {
 							if actionCopy.Callback(rowData.Resource, actionContext) {
-								// Route to appropriate callback based on action key
-								switch actionCopy.Key {
-								case 'e', 'E':
-									if rv.onExploreStorage != nil && handler.CanExplore() {
-										rv.onExploreStorage(rowData.Resource)
-									}
-								case 'd', 'D':
-									if rv.onShowDetails != nil {
-										rv.onShowDetails(rowData.Resource)
-									}
+								// Details are shown by the UI layer; other actions run through the host
+								if (actionCopy.Key == 'd' || actionCopy.Key == 'D') && rv.onShowDetails != nil {
+									rv.onShowDetails(rowData.Resource)
 								}
 								return true
 							}
@@ -197,9 +233,30 @@ func (rv *ResourcesView) SetOnShowDetails(callback func(*models.Resource)) {
 	rv.onShowDetails = callback
 }
 
-// SetOnExploreStorage sets the callback for when storage exploration is requested (e key)
-func (rv *ResourcesView) SetOnExploreStorage(callback func(*models.Resource)) {
-	rv.onExploreStorage = callback
+// SetOnShowJSON sets the callback for when the full JSON of a resource is requested (j key)
+func (rv *ResourcesView) SetOnShowJSON(callback func(*models.Resource)) {
+	rv.onShowJSON = callback
+}
+
+// SetOnTag sets the callback for setting or removing a tag on the marked or selected resources (g key)
+func (rv *ResourcesView) SetOnTag(callback func([]*models.Resource)) {
+	rv.onTag = callback
+}
+
+// GetMarkedResources returns the marked resources, or the selected resource when nothing is marked
+func (rv *ResourcesView) GetMarkedResources() []*models.Resource {
+	var resources []*models.Resource
+	for _, data := range rv.GetMarkedData() {
+		if rowData, ok := data.(*ResourceRowData); ok {
+			resources = append(resources, rowData.Resource)
+		}
+	}
+	return resources
+}
+
+// SetHost sets the application that handler actions run in, to explore resources and run commands
+func (rv *ResourcesView) SetHost(host resource.Host) {
+	rv.host = host
 }
 
 // GetSubscriptionID returns the current subscription ID
@@ -212,6 +269,16 @@ func (rv *ResourcesView) GetResourceGroupName() string {
 	return rv.resourceGroupName
 }
 
+// SelectResource selects the row of a resource by ID, if it is not filtered out
+func (rv *ResourcesView) SelectResource(resourceID string) bool {
+	for i, res := range rv.resources {
+		if strings.EqualFold(res.ID, resourceID) {
+			return rv.SelectDataIndex(i)
+		}
+	}
+	return false
+}
+
 // HandleKey handles key events for this view
 func (rv *ResourcesView) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	// Handle 't' and 'T' for resource type filter
@@ -244,18 +311,12 @@ func (rv *ResourcesView) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 									SubscriptionID:    rv.subscriptionID,
 									SubscriptionName:  rv.subscriptionName,
 									ResourceGroupName: rv.resourceGroupName,
+									Host:              rv.host,
 								}
 								if action.Callback != nil && action.Callback(rowData.Resource, actionContext) {
-									// Route to appropriate callback
-									switch action.Key {
-									case 'e', 'E':
-										if rv.onExploreStorage != nil && handler.CanExplore() {
-											rv.onExploreStorage(rowData.Resource)
-										}
-									case 'd', 'D':
-										if rv.onShowDetails != nil {
-											rv.onShowDetails(rowData.Resource)
-										}
+									// Details are shown by the UI layer; other actions run through the host
+									if (action.Key == 'd' || action.Key == 'D') && rv.onShowDetails != nil {
+										rv.onShowDetails(rowData.Resource)
 									}
 									return nil
 								}
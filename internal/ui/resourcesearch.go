@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+	"azure-control-tower/pkg/resource"
+
+	"github.com/rivo/tview"
+)
+
+// ResourceSearchView displays the resources found by a search across subscriptions
+type ResourceSearchView struct {
+	*TableView
+	criteria      models.ResourceSearch
+	results       []*models.ResourceSearchResult
+	onOpen        func(result *models.ResourceSearchResult)
+	onShowDetails func(result *models.ResourceSearchResult)
+	onShowJSON    func(result *models.ResourceSearchResult)
+	onEdit        func()
+}
+
+// NewResourceSearchView creates a new resource search view
+func NewResourceSearchView() *ResourceSearchView {
+	rsv := &ResourceSearchView{}
+
+	config := &TableConfig{
+		Title: "",
+		Columns: []ColumnConfig{
+			{Name: "Name", Align: tview.AlignLeft},
+			{Name: "Type", Align: tview.AlignLeft},
+			{Name: "Resource Group", Align: tview.AlignLeft},
+			{Name: "Subscription", Align: tview.AlignLeft},
+			{Name: "Location", Align: tview.AlignLeft},
+		},
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if result, ok := data.(*models.ResourceSearchResult); ok && rsv.onShowDetails != nil {
+						rsv.onShowDetails(result)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'j',
+				Label: "JSON",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if result, ok := data.(*models.ResourceSearchResult); ok && rsv.onShowJSON != nil {
+						rsv.onShowJSON(result)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'e',
+				Label: "Edit Search",
+				Callback: func() bool {
+					if rsv.onEdit != nil {
+						rsv.onEdit()
+						return true
+					}
+					return false
+				},
+			},
+		},
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on a result - open it in its resource group
+			if result, ok := data.(*models.ResourceSearchResult); ok && rsv.onOpen != nil {
+				rsv.onOpen(result)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			result, ok := data.(*models.ResourceSearchResult)
+			if !ok {
+				return ""
+			}
+			switch columnIndex {
+			case 0:
+				return result.Resource.Name
+			case 1:
+				return stripProviderPrefix(result.Resource.Type)
+			case 2:
+				return result.Resource.ResourceGroup
+			case 3:
+				if result.SubscriptionName != "" {
+					return result.SubscriptionName
+				}
+				return result.SubscriptionID
+			case 4:
+				return result.Resource.Location
+			default:
+				return ""
+			}
+		},
+	}
+
+	rsv.TableView = NewTableView(config)
+	return rsv
+}
+
+// LoadResults loads the results of a search and the criteria they were found with into the view
+func (rsv *ResourceSearchView) LoadResults(criteria models.ResourceSearch, results []*models.ResourceSearchResult) {
+	rsv.criteria = criteria
+	rsv.results = results
+
+	data := make([]interface{}, len(results))
+	for i, result := range results {
+		data[i] = result
+	}
+	rsv.LoadData(data)
+}
+
+// GetCriteria returns the criteria of the search shown in the view
+func (rsv *ResourceSearchView) GetCriteria() models.ResourceSearch {
+	return rsv.criteria
+}
+
+// GetStatus returns the criteria and result count of the search for the view title
+func (rsv *ResourceSearchView) GetStatus() string {
+	var criteria []string
+	for _, criterion := range []struct{ label, value string }{
+		{"name", rsv.criteria.Name},
+		{"type", rsv.criteria.Type},
+		{"tag", rsv.criteria.Tag},
+		{"location", rsv.criteria.Location},
+	} {
+		if value := strings.TrimSpace(criterion.value); value != "" {
+			criteria = append(criteria, fmt.Sprintf("%s: %s", criterion.label, value))
+		}
+	}
+
+	status := fmt.Sprintf("%s (%d result(s)", strings.Join(criteria, ", "), len(rsv.results))
+	if len(rsv.results) >= azure.ResourceSearchLimit {
+		status += ", refine the search to see more"
+	}
+	return status + ")"
+}
+
+// SetOnOpen sets the callback for opening a result in its resource group (Enter)
+func (rsv *ResourceSearchView) SetOnOpen(callback func(*models.ResourceSearchResult)) {
+	rsv.onOpen = callback
+}
+
+// SetOnShowDetails sets the callback for when details are requested (d key)
+func (rsv *ResourceSearchView) SetOnShowDetails(callback func(*models.ResourceSearchResult)) {
+	rsv.onShowDetails = callback
+}
+
+// SetOnShowJSON sets the callback for when the full JSON of a result is requested (j key)
+func (rsv *ResourceSearchView) SetOnShowJSON(callback func(*models.ResourceSearchResult)) {
+	rsv.onShowJSON = callback
+}
+
+// SetOnEdit sets the callback for changing the criteria and searching again (e key)
+func (rsv *ResourceSearchView) SetOnEdit(callback func()) {
+	rsv.onEdit = callback
+}
+
+// showResourceSearchForm asks for the criteria of a search across subscriptions, offering the last ones
+func (a *App) showResourceSearchForm() {
+	criteria := a.resourceSearchView.GetCriteria()
+	form := tview.NewForm().
+		AddInputField("Name", criteria.Name, 0, nil, nil).
+		AddInputField("Type", criteria.Type, 0, nil, nil).
+		AddInputField("Tag", criteria.Tag, 0, nil, nil).
+		AddInputField("Location", criteria.Location, 0, nil, nil)
+
+	form.AddButton("Search", func() {
+		criteria := models.ResourceSearch{
+			Name:     strings.TrimSpace(formText(form, "Name")),
+			Type:     strings.TrimSpace(formText(form, "Type")),
+			Tag:      strings.TrimSpace(formText(form, "Tag")),
+			Location: strings.TrimSpace(formText(form, "Location")),
+		}
+		if criteria == (models.ResourceSearch{}) {
+			a.showError("Invalid search", fmt.Errorf("enter a name, type, tag or location to search for"))
+			return
+		}
+
+		a.closeDialog()
+		a.runResourceSearch(criteria)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Search All Subscriptions - parts of names and types, tags as name or name=value", 90, 13)
+}
+
+// runResourceSearch searches every subscription in the background and shows the results in the search view
+func (a *App) runResourceSearch(criteria models.ResourceSearch) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	modal := tview.NewModal().
+		SetText("Searching all subscriptions...").
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	go func() {
+		results, err := a.azureClient.SearchResources(ctx, criteria)
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to search resources", err)
+				}
+				return
+			}
+
+			// Results only carry subscription IDs; the names come from the subscriptions list
+			names := make(map[string]string)
+			for _, sub := range a.subscriptionsView.GetSubscriptions() {
+				names[strings.ToLower(sub.ID)] = azure.SubscriptionLabel(sub)
+			}
+			for _, result := range results {
+				result.SubscriptionName = names[strings.ToLower(result.SubscriptionID)]
+			}
+
+			a.navState.NavigateToResourceSearch()
+			a.resourceSearchView.ClearFilter()
+			a.resourceSearchView.LoadResults(criteria, results)
+			a.updateLayout()
+			a.SetFocus(a.resourceSearchView)
+		})
+	}()
+}
+
+// openSearchResult opens the resource list of a result's type in its resource group, with the result
+// selected and the subscription and resource group set as if the user had browsed to it
+func (a *App) openSearchResult(result *models.ResourceSearchResult) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Opening '%s'...", result.Resource.Name)).
+		AddButtons([]string{"Stop"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			cancel()
+		})
+	a.showModal(modal)
+
+	scope := resource.Scope{
+		SubscriptionID: result.SubscriptionID,
+		ResourceGroup:  result.Resource.ResourceGroup,
+		ResourceType:   result.Resource.Type,
+	}
+	go func() {
+		resources, err := a.registry.FetchResources(ctx, scope)
+		a.QueueUpdateDraw(func() {
+			cancel()
+			a.closeDialog()
+			if err != nil {
+				if ctx.Err() == nil {
+					a.showError("Failed to open resource", err)
+				}
+				return
+			}
+
+			subscriptionName := result.SubscriptionName
+			if subscriptionName == "" {
+				subscriptionName = result.SubscriptionID
+			}
+			a.navState.NavigateToSearchResult(result.SubscriptionID, subscriptionName, scope.ResourceGroup, scope.ResourceType)
+			a.headerView.UpdateSelectedSubscription(subscriptionName, result.SubscriptionID)
+
+			a.resourcesView.ClearFilter()
+			a.resourcesView.SetTitle("")
+			if err := a.resourcesView.LoadResources(context.Background(), resources, result.SubscriptionID, subscriptionName, scope.ResourceGroup); err == nil {
+				a.resourcesView.SelectResource(result.Resource.ID)
+			}
+			a.updateLayout()
+			a.SetFocus(a.resourcesView)
+		})
+	}()
+}
+
+// navigateBackFromResourceSearch returns from the search results to the view the search was opened from
+func (a *App) navigateBackFromResourceSearch() {
+	a.navState.NavigateBackFromResourceSearch()
+	a.updateLayout()
+	a.SetFocus(a.currentView)
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/bulk"
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// Tag actions offered by the tag form
+const (
+	tagActionSet    = "Set"
+	tagActionRemove = "Remove"
+)
+
+// tagResources asks for a tag to set on or remove from resources, then applies it to each of them after a
+// confirmation listing them
+func (a *App) tagResources(resources []*models.Resource) {
+	form := tview.NewForm().
+		AddDropDown("Action", []string{tagActionSet, tagActionRemove}, 0, nil).
+		AddInputField("Name", "", 0, nil, nil).
+		AddInputField("Value", "", 0, nil, nil)
+
+	form.AddButton("Apply", func() {
+		action := formOption(form, "Action")
+		name := strings.TrimSpace(formText(form, "Name"))
+		value := strings.TrimSpace(formText(form, "Value"))
+		if action == tagActionRemove {
+			value = ""
+		}
+		if err := azure.ValidateTag(name, value); err != nil {
+			a.showError("Invalid tag", err)
+			return
+		}
+
+		a.closeDialog()
+		question := fmt.Sprintf("Set tag %s=%s on %d resource(s)?", name, value, len(resources))
+		if action == tagActionRemove {
+			question = fmt.Sprintf("Remove tag %s from %d resource(s)? Resources without it are left unchanged.", name, len(resources))
+		}
+		labels := make([]string, len(resources))
+		for i, res := range resources {
+			labels[i] = fmt.Sprintf("%s (%s)", res.Name, res.ResourceGroup)
+		}
+		a.confirmBulkAction(question, labels, "Apply", func() {
+			a.runResourceTagging(resources, action == tagActionRemove, name, value)
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, fmt.Sprintf("Tags - %d resource(s), the value is ignored when removing", len(resources)), 70, 11)
+}
+
+// runResourceTagging sets or removes a tag on each resource as a bulk action. The tags of the resources
+// that succeeded are updated in the list, since listings can take a while to show the change.
+func (a *App) runResourceTagging(resources []*models.Resource, remove bool, name, value string) {
+	succeeded := make([]bool, len(resources))
+	prepare := func(ctx context.Context) ([]bulk.Item, error) {
+		items := make([]bulk.Item, 0, len(resources))
+		for i, res := range resources {
+			i, res := i, res
+			items = append(items, bulk.Item{
+				Label: fmt.Sprintf("%s (%s)", res.Name, res.ResourceGroup),
+				Run: func(ctx context.Context) error {
+					var err error
+					if !remove {
+						err = a.azureClient.SetResourceTag(ctx, res.ID, name, value)
+					} else if key, current := findTag(res.Tags, name); key != "" {
+						// ARM removes tags by name and value, so the current value is given
+						err = a.azureClient.RemoveResourceTag(ctx, res.ID, key, current)
+					}
+					succeeded[i] = err == nil
+					return err
+				},
+			})
+		}
+		return items, nil
+	}
+
+	verb := fmt.Sprintf("Setting tag %s", name)
+	if remove {
+		verb = fmt.Sprintf("Removing tag %s", name)
+	}
+	onDone := func(bulk.Progress) {
+		for i, res := range resources {
+			if !succeeded[i] {
+				continue
+			}
+			// Tag names are case-insensitive, so a tag with another casing is replaced
+			if key, _ := findTag(res.Tags, name); key != "" {
+				delete(res.Tags, key)
+			}
+			if !remove {
+				if res.Tags == nil {
+					res.Tags = make(map[string]*string)
+				}
+				tagValue := value
+				res.Tags[name] = &tagValue
+			}
+		}
+		a.resourcesView.RenderData()
+	}
+	a.runBulkAction(fmt.Sprintf("%s on %d resource(s)", verb, len(resources)), prepare, onDone, a.resourcesView.ClearMarks)
+}
+
+// findTag returns the name and value of a tag, matching its name without regard to case; an empty name
+// when the tag is not set
+func findTag(tags map[string]*string, name string) (string, string) {
+	for key, value := range tags {
+		if strings.EqualFold(key, name) {
+			if value == nil {
+				return key, ""
+			}
+			return key, *value
+		}
+	}
+	return "", ""
+}
@@ -65,6 +65,16 @@ func NewResourceTypesView(registry *resource.Registry) *ResourceTypesView {
 				return ""
 			}
 		},
+		GetRowColor: func(data interface{}) tcell.Color {
+			// Gray out types that cannot be listed
+			if summary, ok := data.(*models.ResourceTypeSummary); ok {
+				handler := rtv.registry.GetHandlerOrDefault(summary.Type)
+				if handler == nil || !handler.CanNavigateToList() {
+					return tcell.ColorGray
+				}
+			}
+			return tcell.ColorWhite
+		},
 	}
 
 	rtv.TableView = NewTableView(config)
@@ -92,45 +102,6 @@ func (rtv *ResourceTypesView) LoadResourceTypes(ctx context.Context, resourceTyp
 	return nil
 }
 
-// RenderData overrides TableView's RenderData to apply styling after rendering
-func (rtv *ResourceTypesView) RenderData() {
-	rtv.TableView.RenderData()
-	rtv.applyStyling()
-}
-
-// SetFilter overrides TableView's SetFilter to reapply styling after filtering
-func (rtv *ResourceTypesView) SetFilter(filterText string) {
-	rtv.TableView.SetFilter(filterText)
-	rtv.applyStyling()
-}
-
-// applyStyling applies visual styling to indicate which types are navigable
-func (rtv *ResourceTypesView) applyStyling() {
-	// Iterate through all rows and style based on navigability
-	for row := 1; row < rtv.GetRowCount(); row++ {
-		// Get the data index for this display row
-		displayRowIndex := row - 1
-		if displayRowIndex >= 0 && displayRowIndex < len(rtv.filteredIndices) {
-			dataIndex := rtv.filteredIndices[displayRowIndex]
-			if dataIndex >= 0 && dataIndex < len(rtv.data) {
-				if summary, ok := rtv.data[dataIndex].(*models.ResourceTypeSummary); ok {
-					// Check if handler supports navigation
-					handler := rtv.registry.GetHandlerOrDefault(summary.Type)
-					canNavigate := handler != nil && handler.CanNavigateToList()
-					// Gray out non-navigable types
-					if !canNavigate {
-						for col := 0; col < len(rtv.config.Columns); col++ {
-							if cell := rtv.GetCell(row, col); cell != nil {
-								cell.SetTextColor(tcell.ColorGray)
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-}
-
 // SetOnSelect sets the callback for when a resource type is selected
 func (rtv *ResourceTypesView) SetOnSelect(callback func(*models.ResourceTypeSummary)) {
 	rtv.onSelect = callback
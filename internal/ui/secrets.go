@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/azure"
+	"azure-control-tower/internal/models"
+	"azure-control-tower/internal/navigation"
+
+	"github.com/rivo/tview"
+)
+
+// secretValueSources are the ways a secret value can be provided in the secret form
+var secretValueSources = []string{"Typed", "File", "Generated"}
+
+// editSecret shows the form for creating a secret, or a new version of an existing one.
+// A new version starts from the properties of the version it is based on; the value is never prefilled.
+func (a *App) editSecret(existing *models.Secret) {
+	current := &models.Secret{Enabled: true}
+	title := "New Secret"
+	if existing != nil {
+		current = existing
+		title = fmt.Sprintf("New Version - %s", existing.Name)
+	}
+
+	form := tview.NewForm()
+	if existing == nil {
+		form.AddInputField("Name", "", 0, nil, nil)
+	}
+	form.AddDropDown("Value Source", secretValueSources, 0, nil).
+		AddPasswordField("Value", "", 0, '*', nil).
+		AddInputField("File", "", 0, nil, nil).
+		AddInputField("Generated Length", "32", 0, nil, nil).
+		AddInputField("Content Type", current.ContentType, 0, nil, nil).
+		AddTextArea("Tags", azure.FormatMetadata(current.Tags), 0, 4, 0, nil).
+		AddInputField("Not Before", formatFormTime(current.NotBefore), 0, nil, nil).
+		AddInputField("Expires", formatFormTime(current.Expires), 0, nil, nil).
+		AddCheckbox("Enabled", current.Enabled, nil)
+
+	form.AddButton("Save", func() {
+		secret, err := a.secretFromForm(form, existing)
+		if err != nil {
+			a.showError("Invalid secret", err)
+			return
+		}
+
+		a.closeDialog()
+		ctx := context.Background()
+		saved, err := a.azureClient.SetSecret(ctx, a.navState.SelectedKeyVaultURL, secret)
+		if err != nil {
+			a.showError("Failed to save secret", err)
+			return
+		}
+		a.refreshSecrets()
+		if formOption(form, "Value Source") == "Generated" {
+			// The generated value is never shown unless asked for
+			a.showInfo(fmt.Sprintf("Secret '%s' was saved with a generated value (version %s). Use v to view it.", saved.Name, saved.Version))
+		}
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, title, 80, 25)
+}
+
+// secretFromForm validates the secret form and resolves the value from the chosen source
+func (a *App) secretFromForm(form *tview.Form, existing *models.Secret) (*models.Secret, error) {
+	secret := &models.Secret{
+		ContentType: strings.TrimSpace(formText(form, "Content Type")),
+		Enabled:     formChecked(form, "Enabled"),
+	}
+
+	if existing != nil {
+		secret.Name = existing.Name
+	} else {
+		secret.Name = strings.TrimSpace(formText(form, "Name"))
+		if err := azure.ValidateSecretName(secret.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	switch formOption(form, "Value Source") {
+	case "File":
+		path := strings.TrimSpace(formText(form, "File"))
+		if path == "" {
+			return nil, fmt.Errorf("enter the path of the file holding the value")
+		}
+		secret.Value, err = azure.ReadSecretFile(path)
+	case "Generated":
+		length, convErr := strconv.Atoi(strings.TrimSpace(formText(form, "Generated Length")))
+		if convErr != nil {
+			return nil, fmt.Errorf("generated length must be a number")
+		}
+		secret.Value, err = azure.GenerateSecretValue(length)
+	default:
+		secret.Value = formText(form, "Value")
+		if secret.Value == "" {
+			return nil, fmt.Errorf("enter a value, or choose a file or generated value")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.Tags, err = azure.ParseTags(formText(form, "Tags")); err != nil {
+		return nil, err
+	}
+	if secret.NotBefore, err = formTime(form, "Not Before"); err != nil {
+		return nil, err
+	}
+	if secret.Expires, err = formTime(form, "Expires"); err != nil {
+		return nil, err
+	}
+	if secret.NotBefore != nil && secret.Expires != nil && !secret.Expires.After(*secret.NotBefore) {
+		return nil, fmt.Errorf("expiry must be after not before")
+	}
+	return secret, nil
+}
+
+// toggleSecretEnabled enables or disables a secret, or one version of it, confirming before disabling
+func (a *App) toggleSecretEnabled(secret *models.Secret) {
+	apply := func() {
+		ctx := context.Background()
+		if err := a.azureClient.SetSecretEnabled(ctx, a.navState.SelectedKeyVaultURL, secret.Name, secret.Version, !secret.Enabled); err != nil {
+			a.showError("Failed to update secret", err)
+			return
+		}
+		a.refreshSecrets()
+	}
+
+	if !secret.Enabled {
+		apply()
+		return
+	}
+
+	target := fmt.Sprintf("secret '%s'", secret.Name)
+	if secret.Version != "" {
+		target = fmt.Sprintf("version %s of secret '%s'", secret.Version, secret.Name)
+	}
+	a.confirm(fmt.Sprintf("Disable %s? Applications reading it will get an error until it is enabled again.", target), "Disable", apply)
+}
+
+// refreshSecrets reloads the secrets view, or the versions view when it is shown
+func (a *App) refreshSecrets() {
+	if a.navState.CurrentView == navigation.ViewKeyVaultSecretVersions {
+		a.loadSecretVersions()
+		return
+	}
+
+	ctx := context.Background()
+	vaultURL := a.navState.SelectedKeyVaultURL
+	secrets, err := a.keyVaultExplorerView.GetFetcher().ListSecrets(ctx, vaultURL)
+	if err != nil {
+		a.showError("Failed to list secrets", err)
+		return
+	}
+
+	a.keyVaultSecretsView.LoadSecrets(ctx, secrets, a.navState.SelectedKeyVault, vaultURL)
+	a.updateLayout()
+	a.SetFocus(a.keyVaultSecretsView)
+}
+
+// navigateToSecretVersions shows every version of a secret
+func (a *App) navigateToSecretVersions(secret *models.Secret) {
+	a.navState.NavigateToKeyVaultSecretVersions(secret.Name)
+	a.loadSecretVersions()
+}
+
+// loadSecretVersions loads the versions of the selected secret
+func (a *App) loadSecretVersions() {
+	ctx := context.Background()
+	secretName := a.navState.SelectedSecret
+	versions, err := a.azureClient.ListSecretVersions(ctx, a.navState.SelectedKeyVaultURL, secretName)
+	if err != nil {
+		a.showError("Failed to list secret versions", err)
+		return
+	}
+
+	a.keyVaultSecretVersionsView.LoadVersions(versions, secretName)
+	a.updateLayout()
+	a.SetFocus(a.keyVaultSecretVersionsView)
+}
+
+// navigateBackFromSecretVersions returns from the versions view to the secrets view
+func (a *App) navigateBackFromSecretVersions() {
+	a.navState.NavigateBackFromKeyVaultSecretVersions()
+	a.refreshSecrets()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"azure-control-tower/internal/models"
+	"azure-control-tower/pkg/resource"
 
 	"github.com/rivo/tview"
 )
@@ -14,13 +15,21 @@ type ContainerRowData struct {
 	Container *models.Container
 }
 
-// StorageExplorerView displays containers in a storage account
+// StorageExplorerView displays containers in a storage account. The storage handler opens it on an
+// account with the fetcher that loads the containers and blobs.
 type StorageExplorerView struct {
 	*TableView
 	containers      []*models.Container
 	storageAccount  string
+	account         resource.StorageAccount
+	fetcher         resource.StorageFetcher
+	onOpen          func(account resource.StorageAccount)
 	onSelect        func(container *models.Container)
 	onShowDetails   func(container *models.Container)
+	onCreate        func()
+	onDelete        func(container *models.Container)
+	onSetAccess     func(container *models.Container)
+	onEditMetadata  func(container *models.Container)
 }
 
 // NewStorageExplorerView creates a new storage explorer view
@@ -47,6 +56,52 @@ func NewStorageExplorerView() *StorageExplorerView {
 					return false
 				},
 			},
+			{
+				Rune:  'x',
+				Label: "Delete",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*ContainerRowData); ok && sev.onDelete != nil {
+						sev.onDelete(rowData.Container)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'a',
+				Label: "Public Access",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*ContainerRowData); ok && sev.onSetAccess != nil {
+						sev.onSetAccess(rowData.Container)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  'e',
+				Label: "Edit Metadata",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*ContainerRowData); ok && sev.onEditMetadata != nil {
+						sev.onEditMetadata(rowData.Container)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  'n',
+				Label: "New Container",
+				Callback: func() bool {
+					if sev.onCreate != nil {
+						sev.onCreate()
+						return true
+					}
+					return false
+				},
+			},
 		},
 		OnSelect: func(rowIndex int, data interface{}) {
 			// Enter key on a container - navigate to blobs
@@ -99,6 +154,55 @@ func (sev *StorageExplorerView) LoadContainers(ctx context.Context, containers [
 	return nil
 }
 
+// Open shows the containers of a storage account, loaded by the storage handler
+func (sev *StorageExplorerView) Open(account resource.StorageAccount, containers []*models.Container, fetcher resource.StorageFetcher) {
+	sev.account = account
+	sev.fetcher = fetcher
+	sev.LoadContainers(context.Background(), containers, account.Name)
+	if sev.onOpen != nil {
+		sev.onOpen(account)
+	}
+}
+
+// Refresh reloads the containers of the storage account with the fetcher of the storage handler
+func (sev *StorageExplorerView) Refresh(ctx context.Context) error {
+	containers, err := sev.fetcher.ListContainers(ctx, sev.account.SubscriptionID, sev.account.ResourceGroup, sev.account.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	return sev.LoadContainers(ctx, containers, sev.account.Name)
+}
+
+// Title returns the title of the storage explorer
+func (sev *StorageExplorerView) Title() string {
+	return fmt.Sprintf("Storage Explorer - %s", sev.storageAccount)
+}
+
+// Actions returns the keys of the storage explorer for the footer
+func (sev *StorageExplorerView) Actions() string {
+	return "Enter: open container, d: details, n: new, x: delete, a: access, e: metadata"
+}
+
+// GetTableView returns the table of the storage explorer
+func (sev *StorageExplorerView) GetTableView() *TableView {
+	return sev.TableView
+}
+
+// GetAccount returns the storage account the explorer is opened on
+func (sev *StorageExplorerView) GetAccount() resource.StorageAccount {
+	return sev.account
+}
+
+// GetFetcher returns the fetcher that loads the containers and blobs of the storage account
+func (sev *StorageExplorerView) GetFetcher() resource.StorageFetcher {
+	return sev.fetcher
+}
+
+// SetOnOpen sets the callback for when the storage handler opens the explorer on an account
+func (sev *StorageExplorerView) SetOnOpen(callback func(resource.StorageAccount)) {
+	sev.onOpen = callback
+}
+
 // SetOnSelect sets the callback for when a container is selected (Enter key)
 func (sev *StorageExplorerView) SetOnSelect(callback func(*models.Container)) {
 	sev.onSelect = callback
@@ -109,6 +213,26 @@ func (sev *StorageExplorerView) SetOnShowDetails(callback func(*models.Container
 	sev.onShowDetails = callback
 }
 
+// SetOnCreate sets the callback for creating a container (n key)
+func (sev *StorageExplorerView) SetOnCreate(callback func()) {
+	sev.onCreate = callback
+}
+
+// SetOnDelete sets the callback for deleting a container (x key)
+func (sev *StorageExplorerView) SetOnDelete(callback func(*models.Container)) {
+	sev.onDelete = callback
+}
+
+// SetOnSetAccess sets the callback for changing the public access level (a key)
+func (sev *StorageExplorerView) SetOnSetAccess(callback func(*models.Container)) {
+	sev.onSetAccess = callback
+}
+
+// SetOnEditMetadata sets the callback for editing container metadata (e key)
+func (sev *StorageExplorerView) SetOnEditMetadata(callback func(*models.Container)) {
+	sev.onEditMetadata = callback
+}
+
 // GetStorageAccount returns the current storage account name
 func (sev *StorageExplorerView) GetStorageAccount() string {
 	return sev.storageAccount
@@ -128,6 +252,12 @@ type BlobsView struct {
 	pathPrefix      string // Current folder path prefix
 	onShowDetails   func(blob *models.Blob)
 	onNavigateFolder func(folderPath string) // Callback for folder navigation
+	onEditProperties func(blob *models.Blob)
+	onSetTier        func(blobs []*models.Blob)
+	onDelete         func(blobs []*models.Blob)
+	onDownload       func(blobs []*models.Blob)
+	onCopy           func(items []*models.Blob, move bool)
+	onSearch         func()
 }
 
 // NewBlobsView creates a new blobs view
@@ -141,6 +271,7 @@ func NewBlobsView() *BlobsView {
 			{Name: "Name", Align: tview.AlignLeft},
 			{Name: "Size", Align: tview.AlignRight},
 			{Name: "Content Type", Align: tview.AlignLeft},
+			{Name: "Tier", Align: tview.AlignLeft},
 			{Name: "Last Modified", Align: tview.AlignLeft},
 		},
 		RowActions: []RowAction{
@@ -155,7 +286,82 @@ func NewBlobsView() *BlobsView {
 					return false
 				},
 			},
+			{
+				Rune:  'e',
+				Label: "Edit Properties",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*BlobRowData); ok && !rowData.Blob.IsDirectory && bv.onEditProperties != nil {
+						bv.onEditProperties(rowData.Blob)
+						return true
+					}
+					return false
+				},
+			},
+			{
+				Rune:  't',
+				Label: "Access Tier",
+				Callback: func(rowIndex int, data interface{}) bool {
+					blobs := bv.GetMarkedBlobs()
+					if len(blobs) == 0 || bv.onSetTier == nil {
+						return false
+					}
+					bv.onSetTier(blobs)
+					return true
+				},
+			},
+			{
+				Rune:  'x',
+				Label: "Delete",
+				Callback: func(rowIndex int, data interface{}) bool {
+					blobs := bv.GetMarkedBlobs()
+					if len(blobs) == 0 || bv.onDelete == nil {
+						return false
+					}
+					bv.onDelete(blobs)
+					return true
+				},
+			},
+			{
+				Rune:  'w',
+				Label: "Download",
+				Callback: func(rowIndex int, data interface{}) bool {
+					blobs := bv.GetMarkedBlobs()
+					if len(blobs) == 0 || bv.onDownload == nil {
+						return false
+					}
+					bv.onDownload(blobs)
+					return true
+				},
+			},
+			{
+				Rune:  'c',
+				Label: "Copy",
+				Callback: func(rowIndex int, data interface{}) bool {
+					return bv.startCopy(false)
+				},
+			},
+			{
+				Rune:  'v',
+				Label: "Move",
+				Callback: func(rowIndex int, data interface{}) bool {
+					return bv.startCopy(true)
+				},
+			},
 		},
+		ViewActions: []ViewAction{
+			{
+				Rune:  's',
+				Label: "Search",
+				Callback: func() bool {
+					if bv.onSearch != nil {
+						bv.onSearch()
+						return true
+					}
+					return false
+				},
+			},
+		},
+		MultiSelect: true,
 		OnSelect: func(rowIndex int, data interface{}) {
 			// Enter key on a blob - navigate into folder or show details
 			if rowData, ok := data.(*BlobRowData); ok {
@@ -196,6 +402,14 @@ func NewBlobsView() *BlobsView {
 				}
 				return rowData.Blob.ContentType
 			case 3:
+				if rowData.Blob.IsDirectory {
+					return "-"
+				}
+				if rowData.Blob.ArchiveStatus != "" {
+					return fmt.Sprintf("%s (%s)", rowData.Blob.AccessTier, rowData.Blob.ArchiveStatus)
+				}
+				return rowData.Blob.AccessTier
+			case 4:
 				if rowData.Blob.IsDirectory {
 					return "-"
 				}
@@ -242,6 +456,73 @@ func (bv *BlobsView) SetOnNavigateFolder(callback func(string)) {
 	bv.onNavigateFolder = callback
 }
 
+// SetOnEditProperties sets the callback for editing blob properties and metadata (e key)
+func (bv *BlobsView) SetOnEditProperties(callback func(*models.Blob)) {
+	bv.onEditProperties = callback
+}
+
+// SetOnSetTier sets the callback for changing the access tier of the marked or selected blobs (t key)
+func (bv *BlobsView) SetOnSetTier(callback func([]*models.Blob)) {
+	bv.onSetTier = callback
+}
+
+// SetOnDelete sets the callback for deleting the marked or selected blobs (x key)
+func (bv *BlobsView) SetOnDelete(callback func([]*models.Blob)) {
+	bv.onDelete = callback
+}
+
+// SetOnDownload sets the callback for downloading the marked or selected blobs (w key)
+func (bv *BlobsView) SetOnDownload(callback func([]*models.Blob)) {
+	bv.onDownload = callback
+}
+
+// SetOnCopy sets the callback for copying (c key) or moving (v key) the marked or selected blobs and folders
+func (bv *BlobsView) SetOnCopy(callback func(items []*models.Blob, move bool)) {
+	bv.onCopy = callback
+}
+
+// SetOnSearch sets the callback for searching the whole container (s key)
+func (bv *BlobsView) SetOnSearch(callback func()) {
+	bv.onSearch = callback
+}
+
+// SelectBlob selects the row of a blob by full name
+func (bv *BlobsView) SelectBlob(name string) bool {
+	for i, blob := range bv.blobs {
+		if blob.Name == name {
+			return bv.SelectDataIndex(i)
+		}
+	}
+	return false
+}
+
+// startCopy passes the marked or selected blobs and folders to the copy callback
+func (bv *BlobsView) startCopy(move bool) bool {
+	var items []*models.Blob
+	for _, data := range bv.GetMarkedData() {
+		if rowData, ok := data.(*BlobRowData); ok {
+			items = append(items, rowData.Blob)
+		}
+	}
+	if len(items) == 0 || bv.onCopy == nil {
+		return false
+	}
+	bv.onCopy(items, move)
+	return true
+}
+
+// GetMarkedBlobs returns the marked blobs, or the selected blob when nothing is marked.
+// Folders are excluded.
+func (bv *BlobsView) GetMarkedBlobs() []*models.Blob {
+	var blobs []*models.Blob
+	for _, data := range bv.GetMarkedData() {
+		if rowData, ok := data.(*BlobRowData); ok && !rowData.Blob.IsDirectory {
+			blobs = append(blobs, rowData.Blob)
+		}
+	}
+	return blobs
+}
+
 // GetContainerName returns the current container name
 func (bv *BlobsView) GetContainerName() string {
 	return bv.containerName
@@ -252,6 +533,130 @@ func (bv *BlobsView) GetPathPrefix() string {
 	return bv.pathPrefix
 }
 
+// BlobSearchView displays the results of a container-wide blob search as a flat list
+type BlobSearchView struct {
+	*TableView
+	query         models.BlobSearchQuery
+	containerName string
+	scanned       int
+	searching     bool
+	onOpen        func(blob *models.Blob)
+	onShowDetails func(blob *models.Blob)
+}
+
+// NewBlobSearchView creates a new blob search results view
+func NewBlobSearchView() *BlobSearchView {
+	bsv := &BlobSearchView{}
+
+	config := &TableConfig{
+		Title: "",
+		Columns: []ColumnConfig{
+			{Name: "Path", Align: tview.AlignLeft},
+			{Name: "Size", Align: tview.AlignRight},
+			{Name: "Tier", Align: tview.AlignLeft},
+			{Name: "Last Modified", Align: tview.AlignLeft},
+		},
+		RowActions: []RowAction{
+			{
+				Rune:  'd',
+				Label: "Details",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if rowData, ok := data.(*BlobRowData); ok && bsv.onShowDetails != nil {
+						bsv.onShowDetails(rowData.Blob)
+						return true
+					}
+					return false
+				},
+			},
+		},
+		OnSelect: func(rowIndex int, data interface{}) {
+			// Enter key on a result - open its folder in the blobs view
+			if rowData, ok := data.(*BlobRowData); ok && bsv.onOpen != nil {
+				bsv.onOpen(rowData.Blob)
+			}
+		},
+		GetCellValue: func(data interface{}, columnIndex int) string {
+			rowData, ok := data.(*BlobRowData)
+			if !ok {
+				return ""
+			}
+			switch columnIndex {
+			case 0:
+				return rowData.Blob.Name
+			case 1:
+				return formatSize(rowData.Blob.Size)
+			case 2:
+				if rowData.Blob.ArchiveStatus != "" {
+					return fmt.Sprintf("%s (%s)", rowData.Blob.AccessTier, rowData.Blob.ArchiveStatus)
+				}
+				return rowData.Blob.AccessTier
+			case 3:
+				return rowData.Blob.LastModified.Format("2006-01-02 15:04:05")
+			default:
+				return ""
+			}
+		},
+	}
+
+	bsv.TableView = NewTableView(config)
+	return bsv
+}
+
+// StartSearch clears previous results for a new search
+func (bsv *BlobSearchView) StartSearch(query models.BlobSearchQuery, containerName string) {
+	bsv.query = query
+	bsv.containerName = containerName
+	bsv.scanned = 0
+	bsv.searching = true
+	bsv.LoadData(nil)
+}
+
+// AddResults appends a page of matches and records how many blobs have been scanned
+func (bsv *BlobSearchView) AddResults(blobs []*models.Blob, scanned int) {
+	bsv.scanned = scanned
+	data := make([]interface{}, len(blobs))
+	for i, blob := range blobs {
+		data[i] = &BlobRowData{
+			Blob: blob,
+		}
+	}
+	bsv.AppendData(data)
+}
+
+// FinishSearch marks the search as complete
+func (bsv *BlobSearchView) FinishSearch() {
+	bsv.searching = false
+}
+
+// IsSearching reports whether results are still arriving
+func (bsv *BlobSearchView) IsSearching() bool {
+	return bsv.searching
+}
+
+// GetStatus returns a short description of the search progress for the view title
+func (bsv *BlobSearchView) GetStatus() string {
+	status := fmt.Sprintf("%d matches, %d scanned", len(bsv.data), bsv.scanned)
+	if bsv.searching {
+		status += ", searching..."
+	}
+	return status
+}
+
+// GetQuery returns the query of the current search
+func (bsv *BlobSearchView) GetQuery() models.BlobSearchQuery {
+	return bsv.query
+}
+
+// SetOnOpen sets the callback for opening a result in its folder (Enter key)
+func (bsv *BlobSearchView) SetOnOpen(callback func(*models.Blob)) {
+	bsv.onOpen = callback
+}
+
+// SetOnShowDetails sets the callback for when details are requested (d key)
+func (bsv *BlobSearchView) SetOnShowDetails(callback func(*models.Blob)) {
+	bsv.onShowDetails = callback
+}
+
 // formatSize formats a size in bytes to a human-readable string
 func formatSize(size int64) string {
 	const unit = 1024
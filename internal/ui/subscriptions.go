@@ -15,6 +15,7 @@ type SubscriptionsView struct {
 	subscriptions []*models.Subscription
 	onSelect      func(subscription *models.Subscription)
 	onShowDetails func(subscription *models.Subscription)
+	onExpiry      func(subscription *models.Subscription)
 }
 
 // NewSubscriptionsView creates a new subscriptions view
@@ -52,6 +53,17 @@ func NewSubscriptionsView() *SubscriptionsView {
 					return false
 				},
 			},
+			{
+				Rune:  'k',
+				Label: "Key Vault Expiry",
+				Callback: func(rowIndex int, data interface{}) bool {
+					if sub, ok := data.(*models.Subscription); ok && sv.onExpiry != nil {
+						sv.onExpiry(sub)
+						return true
+					}
+					return false
+				},
+			},
 		},
 		OnSelect: func(rowIndex int, data interface{}) {
 			if sub, ok := data.(*models.Subscription); ok && sv.onSelect != nil {
@@ -111,6 +123,16 @@ func (sv *SubscriptionsView) SetOnShowDetails(callback func(*models.Subscription
 	sv.onShowDetails = callback
 }
 
+// SetOnExpiryReport sets the callback for building a Key Vault expiry report (k key)
+func (sv *SubscriptionsView) SetOnExpiryReport(callback func(*models.Subscription)) {
+	sv.onExpiry = callback
+}
+
+// GetSubscriptions returns the loaded subscriptions
+func (sv *SubscriptionsView) GetSubscriptions() []*models.Subscription {
+	return sv.subscriptions
+}
+
 // HandleKey handles key events for this view
 func (sv *SubscriptionsView) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	// Let TableView handle row actions first
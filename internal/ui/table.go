@@ -1,6 +1,12 @@
 package ui
 
 import (
+	"sort"
+	"strings"
+
+	"azure-control-tower/internal/collate"
+	"azure-control-tower/internal/filter"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -21,23 +27,58 @@ type RowAction struct {
 	Callback func(rowIndex int, data interface{}) bool // Returns true if event was handled
 }
 
+// ViewAction represents an action on the view itself that does not need a selected row
+type ViewAction struct {
+	Key      tcell.Key
+	Rune     rune
+	Label    string
+	Callback func() bool // Returns true if event was handled
+}
+
 // TableConfig holds the configuration for a table view
 type TableConfig struct {
 	Title        string
 	Columns      []ColumnConfig
 	RowActions   []RowAction
+	ViewActions  []ViewAction
+	MultiSelect  bool // Space marks rows for actions that apply to several rows, Ctrl-A every shown row
 	OnSelect     func(rowIndex int, data interface{})
 	GetRowData   func(rowIndex int) interface{}                 // Function to get row data by index
 	GetCellValue func(data interface{}, columnIndex int) string // Function to extract cell value from row data
+	GetRowColor  func(data interface{}) tcell.Color             // Optional text colour of an unmarked row
+	GetTags      func(data interface{}) map[string]*string      // Optional tags of a row, offered as columns
+}
+
+// tagColumnPrefix starts the names of the columns that show the value of a tag
+const tagColumnPrefix = "Tag: "
+
+// ColumnChoice is a column offered by the column chooser and whether it is shown
+type ColumnChoice struct {
+	Name    string
+	Visible bool
+}
+
+// tableColumn is a column as displayed: a configured column, or a tag when index is -1
+type tableColumn struct {
+	ColumnConfig
+	index int
+	tag   string
 }
 
 // TableView is a reusable table component that accepts header configuration and row actions
 type TableView struct {
 	*tview.Table
 	config          *TableConfig
-	data            []interface{} // Store row data
-	filterText      string        // Current filter text
-	filteredIndices []int         // Indices of filtered rows
+	data            []interface{}  // Store row data
+	filterText      string         // Current filter text
+	filter          *filter.Filter // Parsed filter text; nil when it does not parse, so it matches as text
+	filterColumns   []tableColumn  // Columns the filter refers to by index
+	filteredIndices []int          // Indices of filtered rows
+	marked          map[int]bool   // Data indices of rows marked with space
+	onMarksChanged  func()
+	sortColumn      string         // Name of the column rows are sorted by; empty for the order of the data
+	sortDescending  bool           // Sort from the largest value
+	columnLayout    []ColumnChoice // Columns chosen with the column chooser; nil for the configured ones
 	theme           *Theme
 }
 
@@ -53,6 +94,7 @@ func NewTableView(config *TableConfig) *TableView {
 		Table:           table,
 		config:          config,
 		filteredIndices: []int{},
+		marked:          make(map[int]bool),
 		theme:           theme,
 	}
 
@@ -76,7 +118,7 @@ func NewTableView(config *TableConfig) *TableView {
 	})
 
 	// Render headers
-	tv.renderHeaders()
+	tv.renderHeaders(tv.visibleColumns())
 
 	return tv
 }
@@ -92,28 +134,49 @@ func (tv *TableView) SetConfig(config *TableConfig) {
 	if config.Title != "" {
 		tv.SetTitle(config.Title)
 	}
-	tv.renderHeaders()
+	// Sort and columns are kept by name, so they apply to the new columns wherever the names match
+	tv.sortRows()
 	tv.RenderData()
-	// expandColumns is already called in RenderData
+	// Headers and expandColumns are rendered by RenderData
 }
 
 // LoadData loads data into the table
 func (tv *TableView) LoadData(data []interface{}) {
 	tv.data = data
+	tv.marked = make(map[int]bool)
 	tv.filteredIndices = make([]int, len(data))
 	for i := range data {
 		tv.filteredIndices[i] = i
 	}
+	tv.sortRows()
 	tv.RenderData()
 }
 
-// RenderData renders all data rows
-func (tv *TableView) RenderData() {
-	// Clear existing data rows (keep header)
-	rowCount := tv.GetRowCount()
-	for i := rowCount - 1; i > 0; i-- {
-		tv.RemoveRow(i)
+// AppendData adds rows to the table, keeping the current filter, marks and selection.
+// Used for results that arrive in pages.
+func (tv *TableView) AppendData(data []interface{}) {
+	if len(data) == 0 {
+		return
 	}
+	tv.keepSelection(func() {
+		start := len(tv.data)
+		tv.data = append(tv.data, data...)
+		for i, rowData := range data {
+			if tv.matchesFilter(rowData) {
+				tv.filteredIndices = append(tv.filteredIndices, start+i)
+			}
+		}
+		tv.sortRows()
+		tv.RenderData()
+	})
+}
+
+// RenderData renders the header and all data rows
+func (tv *TableView) RenderData() {
+	// Columns depend on the data when tags are shown, so the header is rendered again too
+	tv.Clear()
+	columns := tv.visibleColumns()
+	tv.renderHeaders(columns)
 
 	// Render filtered rows
 	for i, dataIndex := range tv.filteredIndices {
@@ -121,10 +184,16 @@ func (tv *TableView) RenderData() {
 			continue
 		}
 		data := tv.data[dataIndex]
-		for colIndex, col := range tv.config.Columns {
-			cellValue := tv.config.GetCellValue(data, colIndex)
+		textColor := tv.theme.Text
+		if tv.marked[dataIndex] {
+			textColor = tv.theme.Warning
+		} else if tv.config.GetRowColor != nil {
+			textColor = tv.config.GetRowColor(data)
+		}
+		for colIndex, col := range columns {
+			cellValue := tv.cellValue(data, col)
 			cell := tview.NewTableCell(cellValue).
-				SetTextColor(tv.theme.Text).
+				SetTextColor(textColor).
 				SetExpansion(1) // Make cells expand to fill available space
 			if col.Align != 0 {
 				cell.SetAlign(col.Align)
@@ -134,13 +203,21 @@ func (tv *TableView) RenderData() {
 	}
 
 	// Expand columns to use full width
-	tv.expandColumns()
+	tv.expandColumns(len(columns))
 }
 
-// renderHeaders renders the header row
-func (tv *TableView) renderHeaders() {
-	for i, col := range tv.config.Columns {
-		cell := tview.NewTableCell(col.Name).
+// renderHeaders renders the header row, marking the sort column with its order
+func (tv *TableView) renderHeaders(columns []tableColumn) {
+	for i, col := range columns {
+		name := col.Name
+		if tv.sortColumn != "" && col.Name == tv.sortColumn {
+			if tv.sortDescending {
+				name += " ▼"
+			} else {
+				name += " ▲"
+			}
+		}
+		cell := tview.NewTableCell(tview.Escape(name)).
 			SetSelectable(false).
 			SetAttributes(tcell.AttrBold).
 			SetTextColor(tv.theme.Label).
@@ -153,8 +230,7 @@ func (tv *TableView) renderHeaders() {
 }
 
 // expandColumns expands all columns to use the full available width
-func (tv *TableView) expandColumns() {
-	numColumns := len(tv.config.Columns)
+func (tv *TableView) expandColumns(numColumns int) {
 	if numColumns == 0 {
 		return
 	}
@@ -189,24 +265,284 @@ func (tv *TableView) SetFilter(filterText string) {
 	} else {
 		// Filter rows based on cell values
 		tv.filteredIndices = []int{}
+		tv.filter, tv.filterColumns, _ = tv.parseFilter(filterText)
 		for i, data := range tv.data {
-			matches := false
-			for colIndex := range tv.config.Columns {
-				cellValue := tv.config.GetCellValue(data, colIndex)
-				if containsIgnoreCase(cellValue, filterText) {
-					matches = true
-					break
-				}
-			}
-			if matches {
+			if tv.matchesFilter(data) {
 				tv.filteredIndices = append(tv.filteredIndices, i)
 			}
 		}
 	}
+	tv.sortRows()
 	tv.RenderData()
 	// Note: Selection will be maintained by tview automatically
 }
 
+// CheckFilter parses a filter text against the columns and tags of the view, returning a
+// *filter.SyntaxError describing the first problem
+func (tv *TableView) CheckFilter(filterText string) error {
+	_, _, err := tv.parseFilter(filterText)
+	return err
+}
+
+// parseFilter parses a filter text. Qualifiers can name any column the view can show, while unqualified
+// terms search the shown columns only.
+func (tv *TableView) parseFilter(filterText string) (*filter.Filter, []tableColumn, error) {
+	columns := tv.availableColumns()
+	fields := filter.Fields{
+		Columns: make([]string, len(columns)),
+		Tags:    tv.config.GetTags != nil,
+	}
+	indexes := make(map[string]int, len(columns))
+	for i, col := range columns {
+		fields.Columns[i] = col.Name
+		indexes[col.Name] = i
+	}
+	for _, col := range tv.visibleColumns() {
+		fields.Shown = append(fields.Shown, indexes[col.Name])
+	}
+
+	parsed, err := filter.Parse(filterText, fields)
+	return parsed, columns, err
+}
+
+// matchesFilter reports whether a row matches the filter. A filter text that does not parse, such as
+// one naming a column of data that is no longer shown, matches rows with a shown cell containing it.
+func (tv *TableView) matchesFilter(data interface{}) bool {
+	if tv.filterText == "" {
+		return true
+	}
+	if tv.filter != nil {
+		return tv.filter.Match(filterRow{tv: tv, data: data, columns: tv.filterColumns})
+	}
+	for _, col := range tv.visibleColumns() {
+		if containsIgnoreCase(tv.cellValue(data, col), tv.filterText) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterRow gives a filter the cells and tags of a row
+type filterRow struct {
+	tv      *TableView
+	data    interface{}
+	columns []tableColumn
+}
+
+// Cell returns the text of a column of the filter
+func (r filterRow) Cell(column int) string {
+	return r.tv.cellValue(r.data, r.columns[column])
+}
+
+// Tag returns the value of a tag, matching its name without regard to case
+func (r filterRow) Tag(name string) (string, bool) {
+	if r.tv.config.GetTags == nil {
+		return "", false
+	}
+	for key, value := range r.tv.config.GetTags(r.data) {
+		if strings.EqualFold(key, name) {
+			if value == nil {
+				return "", true
+			}
+			return *value, true
+		}
+	}
+	return "", false
+}
+
+// cellValue returns the text of a row in a column
+func (tv *TableView) cellValue(data interface{}, col tableColumn) string {
+	if col.index >= 0 {
+		return tv.config.GetCellValue(data, col.index)
+	}
+	if tv.config.GetTags != nil {
+		if value := tv.config.GetTags(data)[col.tag]; value != nil {
+			return tview.Escape(*value)
+		}
+	}
+	return ""
+}
+
+// availableColumns returns the configured columns followed by a column for each tag of the data
+func (tv *TableView) availableColumns() []tableColumn {
+	columns := make([]tableColumn, 0, len(tv.config.Columns))
+	for i, col := range tv.config.Columns {
+		columns = append(columns, tableColumn{ColumnConfig: col, index: i})
+	}
+	if tv.config.GetTags == nil {
+		return columns
+	}
+
+	keys := make(map[string]bool)
+	for _, data := range tv.data {
+		for key := range tv.config.GetTags(data) {
+			keys[key] = true
+		}
+	}
+	tags := make([]string, 0, len(keys))
+	for key := range keys {
+		tags = append(tags, key)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+	for _, tag := range tags {
+		columns = append(columns, tableColumn{
+			ColumnConfig: ColumnConfig{Name: tagColumnPrefix + tag, Align: tview.AlignLeft},
+			index:        -1,
+			tag:          tag,
+		})
+	}
+	return columns
+}
+
+// visibleColumns returns the columns shown: those of the column layout in its order, then configured
+// columns the layout does not mention, such as those of a new query. Tags are only shown when chosen.
+func (tv *TableView) visibleColumns() []tableColumn {
+	available := tv.availableColumns()
+	if tv.columnLayout == nil {
+		return available[:len(tv.config.Columns)]
+	}
+
+	byName := make(map[string]tableColumn, len(available))
+	for _, col := range available {
+		byName[col.Name] = col
+	}
+	mentioned := make(map[string]bool, len(tv.columnLayout))
+	var columns []tableColumn
+	for _, choice := range tv.columnLayout {
+		mentioned[choice.Name] = true
+		if col, ok := byName[choice.Name]; ok && choice.Visible {
+			columns = append(columns, col)
+		}
+	}
+	for _, col := range available[:len(tv.config.Columns)] {
+		if !mentioned[col.Name] {
+			columns = append(columns, col)
+		}
+	}
+	if len(columns) == 0 {
+		// The chosen columns are not in this data, so the configured ones are shown instead
+		return available[:len(tv.config.Columns)]
+	}
+	return columns
+}
+
+// sortRows orders the filtered rows by the sort column. Values compare by what they show, so sizes,
+// dates and counts sort by value, and empty values are last in either order.
+func (tv *TableView) sortRows() {
+	if tv.sortColumn == "" {
+		return
+	}
+	var column *tableColumn
+	for _, col := range tv.availableColumns() {
+		if col.Name == tv.sortColumn {
+			column = &col
+			break
+		}
+	}
+	if column == nil {
+		return
+	}
+
+	values := make(map[int]string, len(tv.filteredIndices))
+	for _, dataIndex := range tv.filteredIndices {
+		values[dataIndex] = tv.cellValue(tv.data[dataIndex], *column)
+	}
+	sort.SliceStable(tv.filteredIndices, func(i, j int) bool {
+		a, b := values[tv.filteredIndices[i]], values[tv.filteredIndices[j]]
+		if collate.Empty(a) || collate.Empty(b) {
+			return !collate.Empty(a) && collate.Empty(b)
+		}
+		if tv.sortDescending {
+			return collate.Compare(a, b) > 0
+		}
+		return collate.Compare(a, b) < 0
+	})
+}
+
+// keepSelection runs a change that reorders the rows and selects the row that was selected before it
+func (tv *TableView) keepSelection(change func()) {
+	selected := -1
+	if row, _ := tv.GetSelection(); row > 0 {
+		selected = tv.getDataIndex(row - 1)
+	}
+	change()
+	if selected >= 0 {
+		tv.SelectDataIndex(selected)
+	}
+}
+
+// SetSort sorts the rows by the named column, from the largest value when descending is set. An empty
+// name restores the order of the data. The sort is kept when new data is loaded.
+func (tv *TableView) SetSort(column string, descending bool) {
+	tv.keepSelection(func() {
+		tv.sortColumn = column
+		tv.sortDescending = descending
+		if column == "" {
+			tv.SetFilter(tv.filterText)
+			return
+		}
+		tv.sortRows()
+		tv.RenderData()
+	})
+}
+
+// GetSort returns the name of the sort column, empty when rows are in data order, and the sort order
+func (tv *TableView) GetSort() (string, bool) {
+	return tv.sortColumn, tv.sortDescending
+}
+
+// GetColumnNames returns the names of the shown columns in display order
+func (tv *TableView) GetColumnNames() []string {
+	columns := tv.visibleColumns()
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// GetColumnChoices returns every column the view can show: the shown ones in display order, then the
+// hidden ones, including a column for each tag of the data when the view has tags
+func (tv *TableView) GetColumnChoices() []ColumnChoice {
+	visible := tv.visibleColumns()
+	shown := make(map[string]bool, len(visible))
+	choices := make([]ColumnChoice, 0, len(visible))
+	for _, col := range visible {
+		shown[col.Name] = true
+		choices = append(choices, ColumnChoice{Name: col.Name, Visible: true})
+	}
+	for _, col := range tv.availableColumns() {
+		if !shown[col.Name] {
+			choices = append(choices, ColumnChoice{Name: col.Name})
+		}
+	}
+	return choices
+}
+
+// SetColumnChoices shows the visible columns of choices in their order and hides the others. Nil restores
+// the configured columns. The choice is kept when new data is loaded.
+func (tv *TableView) SetColumnChoices(choices []ColumnChoice) {
+	// The chooser keeps editing its choices, so the view keeps a copy
+	tv.columnLayout = append([]ColumnChoice(nil), choices...)
+	// Unqualified filter terms match the shown columns only, so the filter is applied again
+	tv.keepSelection(func() {
+		tv.SetFilter(tv.filterText)
+	})
+}
+
+// SelectDataIndex selects the row showing a data index, if it is not filtered out
+func (tv *TableView) SelectDataIndex(dataIndex int) bool {
+	for row, index := range tv.filteredIndices {
+		if index == dataIndex {
+			tv.Select(row+1, 0)
+			return true
+		}
+	}
+	return false
+}
+
 // GetFilter returns the current filter text
 func (tv *TableView) GetFilter() string {
 	return tv.filterText
@@ -220,6 +556,14 @@ func (tv *TableView) ClearFilter() {
 // HandleKey handles key events for the table
 func (tv *TableView) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 	row, _ := tv.GetSelection()
+	if tv.config.MultiSelect && event.Key() == tcell.KeyRune && event.Rune() == ' ' {
+		tv.toggleMark(row)
+		return nil
+	}
+	if tv.config.MultiSelect && event.Key() == tcell.KeyCtrlA {
+		tv.toggleMarkAll()
+		return nil
+	}
 	if row > 0 {
 		dataIndex := tv.getDataIndex(row - 1)
 		if dataIndex >= 0 && dataIndex < len(tv.data) {
@@ -238,9 +582,98 @@ func (tv *TableView) HandleKey(event *tcell.EventKey) *tcell.EventKey {
 			}
 		}
 	}
+
+	// View actions apply even when the table is empty
+	for _, action := range tv.config.ViewActions {
+		matches := (action.Key != 0 && event.Key() == action.Key) ||
+			(action.Rune != 0 && event.Key() == tcell.KeyRune && event.Rune() == action.Rune)
+		if matches && action.Callback != nil && action.Callback() {
+			return nil
+		}
+	}
 	return event
 }
 
+// toggleMark marks or unmarks the row at a display index and moves the selection down
+func (tv *TableView) toggleMark(row int) {
+	dataIndex := tv.getDataIndex(row - 1)
+	if dataIndex < 0 {
+		return
+	}
+	if tv.marked[dataIndex] {
+		delete(tv.marked, dataIndex)
+	} else {
+		tv.marked[dataIndex] = true
+	}
+	tv.RenderData()
+	if row < len(tv.filteredIndices) {
+		tv.Select(row+1, 0)
+	}
+	if tv.onMarksChanged != nil {
+		tv.onMarksChanged()
+	}
+}
+
+// toggleMarkAll marks every shown row, which are the rows matching the filter when there is one, or
+// unmarks them when they are all marked already
+func (tv *TableView) toggleMarkAll() {
+	allMarked := len(tv.filteredIndices) > 0
+	for _, dataIndex := range tv.filteredIndices {
+		if !tv.marked[dataIndex] {
+			allMarked = false
+			break
+		}
+	}
+	for _, dataIndex := range tv.filteredIndices {
+		if allMarked {
+			delete(tv.marked, dataIndex)
+		} else {
+			tv.marked[dataIndex] = true
+		}
+	}
+	tv.RenderData()
+	if tv.onMarksChanged != nil {
+		tv.onMarksChanged()
+	}
+}
+
+// SetOnMarksChanged sets the callback for when rows are marked or unmarked
+func (tv *TableView) SetOnMarksChanged(callback func()) {
+	tv.onMarksChanged = callback
+}
+
+// GetMarkedData returns the marked rows in data order, or the selected row when nothing is marked
+func (tv *TableView) GetMarkedData() []interface{} {
+	if len(tv.marked) == 0 {
+		if data := tv.GetSelectedData(); data != nil {
+			return []interface{}{data}
+		}
+		return nil
+	}
+
+	var result []interface{}
+	for i, data := range tv.data {
+		if tv.marked[i] {
+			result = append(result, data)
+		}
+	}
+	return result
+}
+
+// GetMarkedCount returns the number of marked rows
+func (tv *TableView) GetMarkedCount() int {
+	return len(tv.marked)
+}
+
+// ClearMarks unmarks all rows
+func (tv *TableView) ClearMarks() {
+	tv.marked = make(map[int]bool)
+	tv.RenderData()
+	if tv.onMarksChanged != nil {
+		tv.onMarksChanged()
+	}
+}
+
 // getDataIndex converts a display row index to a data index
 func (tv *TableView) getDataIndex(displayRowIndex int) int {
 	if displayRowIndex < 0 || displayRowIndex >= len(tv.filteredIndices) {
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"azure-control-tower/internal/navigation"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// sortNone is the sort column option that restores the order of the data
+const sortNone = "None"
+
+// Sort orders offered by the sort form
+const (
+	sortAscending  = "Ascending"
+	sortDescending = "Descending"
+)
+
+// currentTableView returns the table of the current view, or nil when the view has no sortable table
+func (a *App) currentTableView() *TableView {
+	if a.navState.InDetailsView {
+		return nil
+	}
+
+	switch a.navState.CurrentView {
+	case navigation.ViewSubscriptions:
+		return a.subscriptionsView.TableView
+	case navigation.ViewResourceGroups:
+		return a.resourceGroupsView.TableView
+	case navigation.ViewResourceTypes:
+		return a.resourceTypesView.TableView
+	case navigation.ViewResources, navigation.ViewResourceType:
+		return a.resourcesView.TableView
+	case navigation.ViewBlobs:
+		return a.blobsView.TableView
+	case navigation.ViewBlobSearch:
+		return a.blobSearchView.TableView
+	case navigation.ViewKeyVaultSecrets:
+		return a.keyVaultSecretsView.TableView
+	case navigation.ViewKeyVaultSecretVersions:
+		return a.keyVaultSecretVersionsView.TableView
+	case navigation.ViewKeyVaultKeys:
+		return a.keyVaultKeysView.TableView
+	case navigation.ViewKeyVaultCertificates:
+		return a.keyVaultCertificatesView.TableView
+	case navigation.ViewKeyVaultDeletedItems:
+		return a.keyVaultDeletedItemsView.TableView
+	case navigation.ViewKeyVaultSecretSync:
+		return a.keyVaultSecretSyncView.TableView
+	case navigation.ViewKeyVaultExpiry:
+		return a.keyVaultExpiryView.TableView
+	case navigation.ViewGraphQuery:
+		return a.graphQueryView.TableView
+	case navigation.ViewResourceSearch:
+		return a.resourceSearchView.TableView
+	case navigation.ViewExplorer:
+		if view, ok := a.explorerView.(interface{ GetTableView() *TableView }); ok {
+			return view.GetTableView()
+		}
+	}
+	return nil
+}
+
+// showSortForm asks for the column and order to sort the current table view by, offering the current sort
+func (a *App) showSortForm() {
+	table := a.currentTableView()
+	if table == nil {
+		return
+	}
+
+	column, descending := table.GetSort()
+	options := append([]string{sortNone}, table.GetColumnNames()...)
+	selected := 0
+	for i, option := range options {
+		if i > 0 && option == column {
+			selected = i
+		}
+	}
+	order := 0
+	if descending {
+		order = 1
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Column", options, selected, nil).
+		AddDropDown("Order", []string{sortAscending, sortDescending}, order, nil)
+
+	form.AddButton("Sort", func() {
+		column := formOption(form, "Column")
+		if column == sortNone {
+			column = ""
+		}
+		descending := formOption(form, "Order") == sortDescending
+
+		a.closeDialog()
+		table.SetSort(column, descending)
+		a.updateLayout()
+		a.SetFocus(a.currentView)
+	})
+	form.AddButton("Cancel", a.closeDialog)
+
+	a.showForm(form, "Sort Rows - kept when the view is reloaded", 60, 9)
+}
+
+// showColumnChooser shows, hides and reorders the columns of the current table view, including a column
+// for each tag in views of tagged resources. Changes apply as they are made.
+func (a *App) showColumnChooser() {
+	table := a.currentTableView()
+	if table == nil {
+		return
+	}
+
+	theme := a.detailsView.theme
+	choices := table.GetColumnChoices()
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+	list.SetBorder(true).
+		SetBorderColor(theme.Border).
+		SetTitle(" Columns - Space: show/hide, -/+: move up/down, r: reset, Enter/ESC: close ")
+
+	render := func(current int) {
+		list.Clear()
+		for _, choice := range choices {
+			mark := "[ ] "
+			if choice.Visible {
+				mark = "[x] "
+			}
+			list.AddItem(tview.Escape(mark+choice.Name), "", 0, nil)
+		}
+		list.SetCurrentItem(current)
+	}
+	apply := func(current int) {
+		table.SetColumnChoices(choices)
+		render(current)
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		current := list.GetCurrentItem()
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyEnter:
+			a.closeDialog()
+			// Filtered row counts follow the shown columns, so the footer is updated
+			a.updateLayout()
+			a.SetFocus(a.currentView)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				if choices[current].Visible && visibleChoiceCount(choices) == 1 {
+					// At least one column stays shown
+					return nil
+				}
+				choices[current].Visible = !choices[current].Visible
+				apply(current)
+			case '-':
+				if current > 0 {
+					choices[current-1], choices[current] = choices[current], choices[current-1]
+					apply(current - 1)
+				}
+			case '+':
+				if current < len(choices)-1 {
+					choices[current+1], choices[current] = choices[current], choices[current+1]
+					apply(current + 1)
+				}
+			case 'r':
+				table.SetColumnChoices(nil)
+				choices = table.GetColumnChoices()
+				render(0)
+			}
+			return nil
+		}
+		return event
+	})
+
+	render(0)
+	a.showDialog(list, 60, 20)
+}
+
+// visibleChoiceCount returns the number of shown columns of a column choice
+func visibleChoiceCount(choices []ColumnChoice) int {
+	count := 0
+	for _, choice := range choices {
+		if choice.Visible {
+			count++
+		}
+	}
+	return count
+}
@@ -9,7 +9,7 @@ type ActionContext struct {
 	SubscriptionID    string
 	SubscriptionName  string
 	ResourceGroupName string
-	App               interface{} // *ui.App - using interface{} to avoid circular dependency
+	Host              Host // Application the action runs in
 }
 
 // Action represents a resource-specific action that can be performed
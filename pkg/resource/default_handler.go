@@ -1,10 +1,14 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
 )
 
 const (
@@ -113,15 +117,34 @@ func (h *DefaultHandler) RenderDetails(resource *models.Resource, subscriptionID
 
 	if len(resource.Properties) > 0 {
 		content.WriteString("\n[lightblue::b]Properties:[white]\n")
-		for key, value := range resource.Properties {
-			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %v\n", key, value))
+		keys := make([]string, 0, len(resource.Properties))
+		for key := range resource.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s\n", key, tview.Escape(formatPropertyValue(resource.Properties[key]))))
 		}
 	}
 
 	return content.String()
 }
 
-// NavigateToExplore is a no-op for default handler
-func (h *DefaultHandler) NavigateToExplore(app interface{}, resource *models.Resource) {
-	// Default handler doesn't support exploration
+// formatPropertyValue renders a property value; nested objects and arrays are rendered as JSON
+func formatPropertyValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Explore returns ErrNotExplorable as default handler doesn't support exploration
+func (h *DefaultHandler) Explore(host Host, resource *models.Resource) error {
+	return ErrNotExplorable
 }
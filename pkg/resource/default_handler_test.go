@@ -199,6 +199,21 @@ func TestDefaultHandler_RenderDetails(t *testing.T) {
 		assert.Contains(t, result, "Succeeded")
 	})
 
+	t.Run("Nested properties are rendered as JSON", func(t *testing.T) {
+		resource := &models.Resource{
+			ID:   "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.KeyVault/vaults/kv-1",
+			Name: "kv-1",
+			Properties: map[string]interface{}{
+				"networkAcls": map[string]interface{}{"defaultAction": "Deny", "ipRules": []interface{}{"203.0.113.10"}},
+			},
+		}
+
+		result := handler.RenderDetails(resource, "sub-123")
+
+		assert.Contains(t, result, `networkAcls:[white] {"defaultAction":"Deny","ipRules":["203.0.113.10"[]}`)
+		assert.NotContains(t, result, "map[")
+	})
+
 	t.Run("Resource without tags", func(t *testing.T) {
 		resource := &models.Resource{
 			ID:            "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/myaccount",
@@ -237,17 +252,16 @@ func TestDefaultHandler_RenderDetails(t *testing.T) {
 	})
 }
 
-func TestDefaultHandler_NavigateToExplore(t *testing.T) {
+func TestDefaultHandler_Explore(t *testing.T) {
 	handler := NewDefaultHandler()
 	resource := &models.Resource{
 		Name: "test-resource",
 		Type: "Microsoft.Storage/storageAccounts",
 	}
+	host := &mockHost{}
 
-	// Should not panic
-	assert.NotPanics(t, func() {
-		handler.NavigateToExplore(nil, resource)
-	})
+	assert.ErrorIs(t, handler.Explore(host, resource), ErrNotExplorable)
+	assert.Empty(t, host.views)
 }
 
 func TestAlignmentConstants(t *testing.T) {
@@ -2,10 +2,42 @@ package resource
 
 import (
 	"context"
+	"strings"
+
+	"azure-control-tower/internal/models"
 )
 
+// Scope selects the resources to fetch
+type Scope struct {
+	SubscriptionID string
+	ResourceGroup  string // Empty for the whole subscription
+	ResourceType   string // Empty for every type
+}
+
 // Fetcher defines the interface for fetching resources
 type Fetcher interface {
-	FetchResources(ctx context.Context, subscriptionID string, resourceType string) ([]interface{}, error)
+	FetchResources(ctx context.Context, scope Scope) ([]*models.Resource, error)
 }
 
+// FetcherFunc adapts a function to the Fetcher interface
+type FetcherFunc func(ctx context.Context, scope Scope) ([]*models.Resource, error)
+
+// FetchResources calls the function
+func (f FetcherFunc) FetchResources(ctx context.Context, scope Scope) ([]*models.Resource, error) {
+	return f(ctx, scope)
+}
+
+// FetcherProvider is implemented by handlers that load their resources with their own fetcher, which
+// RegisterHandler registers for their type
+type FetcherProvider interface {
+	Fetcher() Fetcher
+}
+
+// subscriptionFromID returns the subscription of a resource ID, or an empty string when it has none
+func subscriptionFromID(id string) string {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "subscriptions") {
+		return parts[1]
+	}
+	return ""
+}
@@ -31,6 +31,6 @@ type ResourceHandler interface {
 	RenderDetails(resource *models.Resource, subscriptionID string) string
 
 	// Navigation
-	NavigateToExplore(app interface{}, resource *models.Resource) // For special views (using interface{} to avoid circular dependency)
+	Explore(host Host, resource *models.Resource) error // Opens the exploration view; ErrNotExplorable when there is none
 }
 
@@ -0,0 +1,58 @@
+package resource
+
+import (
+	"errors"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+)
+
+// ErrNotExplorable is returned by handlers whose resources have no explorer
+var ErrNotExplorable = errors.New("resource type has no explorer")
+
+// Host is the application as seen by handlers. The UI implements it, so that handlers can open their
+// own views and run their actions without the UI knowing about each resource type.
+type Host interface {
+	// ShowExplorer shows an explorer view in place of the resource list, until ESC goes back to it
+	ShowExplorer(view ExplorerView)
+	// RunCommand runs an external command in the terminal, suspending the UI while it runs
+	RunCommand(command []string, wait bool)
+	// ShowError shows an error dialog for a failed action
+	ShowError(action string, err error)
+}
+
+// ExplorerView is a view opened by a handler to explore the contents of a resource. It receives the keys
+// that the application does not use itself: ESC, /, m and q are taken.
+type ExplorerView interface {
+	tview.Primitive
+	// Title returns the title shown above the view
+	Title() string
+	// Actions returns the keys of the view for the footer, such as "Enter: open, d: details"
+	Actions() string
+}
+
+// DetailsProvider is implemented by handlers that show their resources in a details view of their own,
+// in place of the details rendered from RenderDetails
+type DetailsProvider interface {
+	ShowDetails(host Host, resource *models.Resource, subscriptionID string)
+}
+
+// FilterableView is implemented by explorer views that can be filtered with /
+type FilterableView interface {
+	SetFilter(filterText string)
+}
+
+// exploreAction is the callback of explore actions: it opens the explorer of the handler and reports
+// failures to the host
+func exploreAction(handler ResourceHandler) func(resource *models.Resource, context *ActionContext) bool {
+	return func(resource *models.Resource, context *ActionContext) bool {
+		if context == nil || context.Host == nil {
+			return false
+		}
+		if err := handler.Explore(context.Host, resource); err != nil {
+			context.Host.ShowError("Failed to explore resource", err)
+		}
+		return true
+	}
+}
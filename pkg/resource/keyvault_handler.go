@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,18 +12,54 @@ const (
 	keyVaultType = "Microsoft.KeyVault/vaults"
 )
 
+// KeyVaultFetcher lists Key Vaults and loads the secrets, keys and certificates the Key Vault explorer shows
+type KeyVaultFetcher interface {
+	Fetcher
+	ListSecrets(ctx context.Context, vaultURL string) ([]*models.Secret, error)
+	ListKeys(ctx context.Context, vaultURL string) ([]*models.Key, error)
+	GetKeyDetails(ctx context.Context, vaultURL, keyName string) (*models.Key, error)
+	ListCertificates(ctx context.Context, vaultURL string) ([]*models.Certificate, error)
+	GetCertificateDetails(ctx context.Context, vaultURL, certName string) (*models.Certificate, error)
+}
+
+// KeyVault identifies the vault a Key Vault explorer is opened on
+type KeyVault struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Name           string
+	URL            string // Data plane URL, such as https://myvault.vault.azure.net/
+}
+
+// KeyVaultExplorerView is the explorer view of a Key Vault, provided by the UI. It loads the items of the
+// vault with the fetcher it is given, and shows the configuration of vaults in the details view.
+type KeyVaultExplorerView interface {
+	ExplorerView
+	Open(vault KeyVault, fetcher KeyVaultFetcher)
+	ShowDetails(vault KeyVault)
+}
+
 // KeyVaultHandler provides behavior for Key Vault resources
 type KeyVaultHandler struct {
 	resourceType string
+	fetcher      KeyVaultFetcher
+	view         KeyVaultExplorerView
 }
 
-// NewKeyVaultHandler creates a new Key Vault handler
-func NewKeyVaultHandler() *KeyVaultHandler {
+// NewKeyVaultHandler creates a new Key Vault handler that loads vaults with a fetcher and explores them
+// in a view. Without them the vaults cannot be explored.
+func NewKeyVaultHandler(fetcher KeyVaultFetcher, view KeyVaultExplorerView) *KeyVaultHandler {
 	return &KeyVaultHandler{
 		resourceType: keyVaultType,
+		fetcher:      fetcher,
+		view:         view,
 	}
 }
 
+// Fetcher returns the fetcher that lists Key Vaults, registered with the handler
+func (h *KeyVaultHandler) Fetcher() Fetcher {
+	return h.fetcher
+}
+
 // GetResourceType returns the resource type
 func (h *KeyVaultHandler) GetResourceType() string {
 	return h.resourceType
@@ -65,12 +102,9 @@ func (h *KeyVaultHandler) GetCellValue(resource *models.Resource, columnIndex in
 func (h *KeyVaultHandler) GetActions() []Action {
 	return []Action{
 		{
-			Key:   'e',
-			Label: "Explore Key Vault",
-			Callback: func(resource *models.Resource, context *ActionContext) bool {
-				// Action will be handled by the UI layer
-				return true
-			},
+			Key:      'e',
+			Label:    "Explore Key Vault",
+			Callback: exploreAction(h),
 		},
 		{
 			Key:   'd',
@@ -127,8 +161,41 @@ func (h *KeyVaultHandler) RenderDetails(resource *models.Resource, subscriptionI
 	return content.String()
 }
 
-// NavigateToExplore navigates to the Key Vault explorer view
-func (h *KeyVaultHandler) NavigateToExplore(app interface{}, resource *models.Resource) {
-	// This will be called by the UI layer to navigate to Key Vault explorer
-	// The actual navigation logic is in app.go
+// Explore shows the Key Vault explorer view on the vault
+func (h *KeyVaultHandler) Explore(host Host, resource *models.Resource) error {
+	if h.fetcher == nil || h.view == nil {
+		return ErrNotExplorable
+	}
+
+	vault := KeyVault{
+		SubscriptionID: subscriptionFromID(resource.ID),
+		ResourceGroup:  resource.ResourceGroup,
+		Name:           resource.Name,
+		URL:            vaultURL(resource),
+	}
+	h.view.Open(vault, h.fetcher)
+	host.ShowExplorer(h.view)
+	return nil
+}
+
+// ShowDetails shows the configuration of the vault, with the actions to change it, in place of its details
+func (h *KeyVaultHandler) ShowDetails(host Host, resource *models.Resource, subscriptionID string) {
+	if h.view == nil {
+		return
+	}
+	h.view.ShowDetails(KeyVault{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resource.ResourceGroup,
+		Name:           resource.Name,
+		URL:            vaultURL(resource),
+	})
+}
+
+// vaultURL returns the data plane URL of a vault from its vaultUri property, or the URL of the public
+// cloud when the property is missing
+func vaultURL(resource *models.Resource) string {
+	if uri, ok := resource.Properties["vaultUri"].(string); ok && uri != "" {
+		return uri
+	}
+	return fmt.Sprintf("https://%s.vault.azure.net/", resource.Name)
 }
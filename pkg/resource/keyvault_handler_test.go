@@ -1,22 +1,25 @@
 package resource
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"azure-control-tower/internal/models"
 
+	"github.com/rivo/tview"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewKeyVaultHandler(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	assert.NotNil(t, handler)
 	assert.Equal(t, "Microsoft.KeyVault/vaults", handler.GetResourceType())
 }
 
 func TestKeyVaultHandler_GetColumns(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	columns := handler.GetColumns()
 	
 	assert.Len(t, columns, 3)
@@ -26,7 +29,7 @@ func TestKeyVaultHandler_GetColumns(t *testing.T) {
 }
 
 func TestKeyVaultHandler_GetCellValue(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	
 	testValue := "test-value"
 	resource := &models.Resource{
@@ -72,7 +75,7 @@ func TestKeyVaultHandler_GetCellValue(t *testing.T) {
 }
 
 func TestKeyVaultHandler_GetActions(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	actions := handler.GetActions()
 	
 	assert.Len(t, actions, 2)
@@ -83,17 +86,17 @@ func TestKeyVaultHandler_GetActions(t *testing.T) {
 }
 
 func TestKeyVaultHandler_CanNavigateToList(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	assert.True(t, handler.CanNavigateToList())
 }
 
 func TestKeyVaultHandler_CanExplore(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	assert.True(t, handler.CanExplore())
 }
 
 func TestKeyVaultHandler_RenderDetails(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	
 	tests := []struct {
 		name     string
@@ -167,19 +170,103 @@ func TestKeyVaultHandler_RenderDetails(t *testing.T) {
 	}
 }
 
-func TestKeyVaultHandler_NavigateToExplore(t *testing.T) {
-	handler := NewKeyVaultHandler()
+// Fake Key Vault fetcher for testing
+type fakeKeyVaultFetcher struct {
+	mockFetcher
+}
+
+func (f *fakeKeyVaultFetcher) ListSecrets(ctx context.Context, vaultURL string) ([]*models.Secret, error) {
+	return nil, nil
+}
+
+func (f *fakeKeyVaultFetcher) ListKeys(ctx context.Context, vaultURL string) ([]*models.Key, error) {
+	return nil, nil
+}
+
+func (f *fakeKeyVaultFetcher) GetKeyDetails(ctx context.Context, vaultURL, keyName string) (*models.Key, error) {
+	return nil, nil
+}
+
+func (f *fakeKeyVaultFetcher) ListCertificates(ctx context.Context, vaultURL string) ([]*models.Certificate, error) {
+	return nil, nil
+}
+
+func (f *fakeKeyVaultFetcher) GetCertificateDetails(ctx context.Context, vaultURL, certName string) (*models.Certificate, error) {
+	return nil, nil
+}
+
+// Fake Key Vault explorer view for testing
+type fakeKeyVaultView struct {
+	*tview.Box
+	vaults  []KeyVault
+	fetcher KeyVaultFetcher
+	details []KeyVault
+}
+
+func (f *fakeKeyVaultView) Title() string { return "Key Vault" }
+
+func (f *fakeKeyVaultView) Actions() string { return "" }
+
+func (f *fakeKeyVaultView) Open(vault KeyVault, fetcher KeyVaultFetcher) {
+	f.vaults = append(f.vaults, vault)
+	f.fetcher = fetcher
+}
+
+func (f *fakeKeyVaultView) ShowDetails(vault KeyVault) {
+	f.details = append(f.details, vault)
+}
+
+func TestKeyVaultHandler_Explore(t *testing.T) {
+	fetcher := &fakeKeyVaultFetcher{}
+	view := &fakeKeyVaultView{Box: tview.NewBox()}
+	handler := NewKeyVaultHandler(fetcher, view)
 	resource := &models.Resource{
-		Name: "test-kv",
-		Type: "Microsoft.KeyVault/vaults",
+		ID:            "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.KeyVault/vaults/test-kv",
+		Name:          "test-kv",
+		Type:          "Microsoft.KeyVault/vaults",
+		ResourceGroup: "rg1",
+		Properties:    map[string]interface{}{"vaultUri": "https://test-kv.vault.azure.cn/"},
 	}
-	
-	// Should not panic
-	handler.NavigateToExplore(nil, resource)
+
+	host := &mockHost{}
+	require.NoError(t, handler.Explore(host, resource))
+	assert.Equal(t, []KeyVault{{SubscriptionID: "sub1", ResourceGroup: "rg1", Name: "test-kv", URL: "https://test-kv.vault.azure.cn/"}}, view.vaults)
+	assert.Equal(t, fetcher, view.fetcher)
+	assert.Equal(t, []ExplorerView{view}, host.views)
+
+	// The explore action goes through the host of the action context
+	assert.True(t, handler.GetActions()[0].Callback(resource, &ActionContext{Host: host}))
+	assert.Len(t, host.views, 2)
+
+	// Vaults without a vaultUri are on the public cloud
+	resource.Properties = map[string]interface{}{}
+	require.NoError(t, handler.Explore(host, resource))
+	assert.Equal(t, "https://test-kv.vault.azure.net/", view.vaults[2].URL)
+
+	assert.ErrorIs(t, NewKeyVaultHandler(nil, nil).Explore(host, resource), ErrNotExplorable)
+}
+
+func TestKeyVaultHandler_ShowDetails(t *testing.T) {
+	view := &fakeKeyVaultView{Box: tview.NewBox()}
+	var handler ResourceHandler = NewKeyVaultHandler(&fakeKeyVaultFetcher{}, view)
+	resource := &models.Resource{
+		ID:            "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.KeyVault/vaults/test-kv",
+		Name:          "test-kv",
+		Type:          "Microsoft.KeyVault/vaults",
+		ResourceGroup: "rg1",
+	}
+
+	provider, ok := handler.(DetailsProvider)
+	require.True(t, ok)
+	provider.ShowDetails(&mockHost{}, resource, "sub1")
+	assert.Equal(t, []KeyVault{{SubscriptionID: "sub1", ResourceGroup: "rg1", Name: "test-kv", URL: "https://test-kv.vault.azure.net/"}}, view.details)
+
+	// Without a view the generic details stay
+	assert.NotPanics(t, func() { NewKeyVaultHandler(nil, nil).ShowDetails(&mockHost{}, resource, "sub1") })
 }
 
 func TestKeyVaultHandler_RenderDetails_Formatting(t *testing.T) {
-	handler := NewKeyVaultHandler()
+	handler := NewKeyVaultHandler(nil, nil)
 	resource := &models.Resource{
 		ID:            "/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.KeyVault/vaults/test-kv",
 		Name:          "test-kv",
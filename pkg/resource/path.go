@@ -0,0 +1,181 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"azure-control-tower/internal/models"
+)
+
+// pathSegment is one step of a path: an object key or an array index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Path is a parsed JSONPath-style expression over a resource, such as properties.provisioningState,
+// sku.name, tags.env, properties.ipRules[0].value or tags['cost-center']. A leading $ is optional.
+type Path struct {
+	expression string
+	segments   []pathSegment
+}
+
+// ParsePath parses a path expression
+func ParsePath(expression string) (Path, error) {
+	rest := strings.TrimSpace(expression)
+	rest = strings.TrimPrefix(rest, "$")
+	path := Path{expression: expression}
+
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return Path{}, fmt.Errorf("invalid path %q: empty key", expression)
+			}
+			path.segments = append(path.segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return Path{}, fmt.Errorf("invalid path %q: missing ]", expression)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path.segments = append(path.segments, pathSegment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return Path{}, fmt.Errorf("invalid path %q: %q is not an array index or quoted key", expression, inner)
+			}
+			path.segments = append(path.segments, pathSegment{index: index, isIndex: true})
+		case len(path.segments) == 0:
+			// The first key needs no leading dot
+			rest = "." + rest
+		default:
+			return Path{}, fmt.Errorf("invalid path %q: unexpected %q", expression, rest)
+		}
+	}
+
+	if len(path.segments) == 0 {
+		return Path{}, fmt.Errorf("invalid path %q: empty path", expression)
+	}
+	return path, nil
+}
+
+// String returns the expression the path was parsed from
+func (p Path) String() string {
+	return p.expression
+}
+
+// Lookup returns the value at the path, or false when the document has no value there. Keys are
+// matched exactly first and then ignoring case, as Azure treats tag names.
+func (p Path) Lookup(document map[string]interface{}) (interface{}, bool) {
+	var current interface{} = document
+	for _, segment := range p.segments {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			if segment.isIndex {
+				return nil, false
+			}
+			next, ok := value[segment.key]
+			if !ok {
+				for key, candidate := range value {
+					if strings.EqualFold(key, segment.key) {
+						next, ok = candidate, true
+						break
+					}
+				}
+			}
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			if !segment.isIndex || segment.index >= len(value) {
+				return nil, false
+			}
+			current = value[segment.index]
+		default:
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// Format returns the value at the path as text, or an empty string when there is none
+func (p Path) Format(document map[string]interface{}) string {
+	value, ok := p.Lookup(document)
+	if !ok {
+		return ""
+	}
+	return formatPropertyValue(value)
+}
+
+// ResourceDocument returns a resource as a JSON-like document with the ARM field names, for path lookups
+func ResourceDocument(resource *models.Resource) map[string]interface{} {
+	tags := make(map[string]interface{}, len(resource.Tags))
+	for key, value := range resource.Tags {
+		if value != nil {
+			tags[key] = *value
+		}
+	}
+
+	document := map[string]interface{}{
+		"id":            resource.ID,
+		"name":          resource.Name,
+		"type":          resource.Type,
+		"location":      resource.Location,
+		"resourceGroup": resource.ResourceGroup,
+		"tags":          tags,
+		"properties":    normalizeJSON(resource.Properties),
+	}
+	if resource.Kind != "" {
+		document["kind"] = resource.Kind
+	}
+	if resource.SKU != nil {
+		document["sku"] = normalizeJSON(resource.SKU)
+	}
+	return document
+}
+
+// normalizeJSON converts a map holding values of other Go types, such as SDK structs, to plain JSON
+// values so that paths can walk into them
+func normalizeJSON(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return map[string]interface{}{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return values
+	}
+	// Numbers stay as written, so that large integers are not shown in exponent form
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var normalized map[string]interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return values
+	}
+	return normalized
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
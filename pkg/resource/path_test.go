@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"testing"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPathResource() *models.Resource {
+	env := "prod"
+	costCenter := "42"
+	return &models.Resource{
+		ID:            "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Web/sites/app-1",
+		Name:          "app-1",
+		Type:          "Microsoft.Web/sites",
+		Location:      "westeurope",
+		ResourceGroup: "my-rg",
+		Kind:          "app,linux",
+		SKU:           map[string]interface{}{"name": "P1v3", "capacity": int64(2)},
+		Tags:          map[string]*string{"Env": &env, "cost-center": &costCenter},
+		Properties: map[string]interface{}{
+			"provisioningState": "Succeeded",
+			"hostNames":         []interface{}{"app-1.azurewebsites.net", "www.example.com"},
+			"siteConfig":        map[string]interface{}{"ipSecurityRestrictions": []interface{}{map[string]interface{}{"ipAddress": "203.0.113.10/32"}}},
+			"dailyMemoryTimeQuota": 1234567890,
+		},
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{expression: "name"},
+		{expression: "$.properties.provisioningState"},
+		{expression: "properties.hostNames[1]"},
+		{expression: "tags['cost-center']"},
+		{expression: `tags["cost-center"]`},
+		{expression: "", wantErr: true},
+		{expression: "$", wantErr: true},
+		{expression: "properties..state", wantErr: true},
+		{expression: "properties.hostNames[x]", wantErr: true},
+		{expression: "properties.hostNames[1", wantErr: true},
+		{expression: "properties.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := ParsePath(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPath_Format(t *testing.T) {
+	document := ResourceDocument(testPathResource())
+
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{expression: "name", expected: "app-1"},
+		{expression: "kind", expected: "app,linux"},
+		{expression: "sku.name", expected: "P1v3"},
+		{expression: "sku.capacity", expected: "2"},
+		{expression: "tags.env", expected: "prod"},
+		{expression: "tags['cost-center']", expected: "42"},
+		{expression: "properties.provisioningState", expected: "Succeeded"},
+		{expression: "properties.hostNames[1]", expected: "www.example.com"},
+		{expression: "properties.hostNames", expected: `["app-1.azurewebsites.net","www.example.com"]`},
+		{expression: "properties.siteConfig.ipSecurityRestrictions[0].ipAddress", expected: "203.0.113.10/32"},
+		{expression: "properties.dailyMemoryTimeQuota", expected: "1234567890"},
+		{expression: "properties.hostNames[5]", expected: ""},
+		{expression: "properties.missing.value", expected: ""},
+		{expression: "name.first", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			path, err := ParsePath(tt.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path.Format(document))
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 	"sync"
+
+	"azure-control-tower/internal/models"
 )
 
 // Registry manages resource type handlers
@@ -20,7 +23,8 @@ func NewRegistry() *Registry {
 	}
 }
 
-// Register registers a resource type fetcher
+// Register registers a resource type fetcher. The fetcher of the empty type is the default for types
+// without their own.
 func (r *Registry) Register(resourceType string, fetcher Fetcher) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -38,6 +42,25 @@ func (r *Registry) GetFetcher(resourceType string) (Fetcher, error) {
 	return fetcher, nil
 }
 
+// GetFetcherOrDefault returns the fetcher for a resource type, or the default fetcher if not found
+func (r *Registry) GetFetcherOrDefault(resourceType string) Fetcher {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if fetcher, ok := r.fetchers[resourceType]; ok {
+		return fetcher
+	}
+	return r.fetchers[""]
+}
+
+// FetchResources loads the resources of a scope with the fetcher of its resource type
+func (r *Registry) FetchResources(ctx context.Context, scope Scope) ([]*models.Resource, error) {
+	fetcher := r.GetFetcherOrDefault(scope.ResourceType)
+	if fetcher == nil {
+		return nil, fmt.Errorf("no fetcher registered for resource type %s", scope.ResourceType)
+	}
+	return fetcher.FetchResources(ctx, scope)
+}
+
 // ListResourceTypes returns all registered resource types
 func (r *Registry) ListResourceTypes() []string {
 	r.mu.RLock()
@@ -49,12 +72,17 @@ func (r *Registry) ListResourceTypes() []string {
 	return types
 }
 
-// RegisterHandler registers a resource type handler
+// RegisterHandler registers a resource type handler, and the fetcher of handlers that provide one
 func (r *Registry) RegisterHandler(handler ResourceHandler) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	resourceType := handler.GetResourceType()
 	r.handlers[resourceType] = handler
+	if provider, ok := handler.(FetcherProvider); ok {
+		if fetcher := provider.Fetcher(); fetcher != nil {
+			r.fetchers[resourceType] = fetcher
+		}
+	}
 }
 
 // GetHandler returns the handler for a resource type
@@ -96,3 +124,25 @@ func (r *Registry) GetSupportedResourceTypes() []string {
 	return types
 }
 
+// LoadHandlers registers the YAML handlers of a directory and returns them. Types that already have a
+// handler cannot be redefined.
+func (r *Registry) LoadHandlers(dir string) ([]*YAMLHandler, error) {
+	handlers, err := LoadHandlers(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	loaded := make(map[string]bool)
+	for _, handler := range handlers {
+		if _, ok := r.handlers[handler.GetResourceType()]; ok || loaded[handler.GetResourceType()] {
+			return nil, fmt.Errorf("invalid handler %s: %s already has a handler", handler.GetSource(), handler.GetResourceType())
+		}
+		loaded[handler.GetResourceType()] = true
+	}
+	for _, handler := range handlers {
+		r.handlers[handler.GetResourceType()] = handler
+	}
+	return handlers, nil
+}
@@ -49,18 +49,43 @@ func (m *mockHandler) RenderDetails(resource *models.Resource, subscriptionID st
 	return ""
 }
 
-func (m *mockHandler) NavigateToExplore(app interface{}, resource *models.Resource) {
+func (m *mockHandler) Explore(host Host, resource *models.Resource) error {
+	return ErrNotExplorable
 }
 
 // Mock fetcher for testing
 type mockFetcher struct {
-	name string
+	name   string
+	scopes []Scope
 }
 
-func (m *mockFetcher) FetchResources(ctx context.Context, subscriptionID string, resourceType string) ([]interface{}, error) {
-	return nil, nil
+func (m *mockFetcher) FetchResources(ctx context.Context, scope Scope) ([]*models.Resource, error) {
+	m.scopes = append(m.scopes, scope)
+	return []*models.Resource{{Name: m.name}}, nil
 }
 
+// Mock host for testing
+type mockHost struct {
+	commands [][]string
+	waits    []bool
+	errors   []error
+	views    []ExplorerView
+}
+
+func (m *mockHost) ShowExplorer(view ExplorerView) {
+	m.views = append(m.views, view)
+}
+
+func (m *mockHost) RunCommand(command []string, wait bool) {
+	m.commands = append(m.commands, command)
+	m.waits = append(m.waits, wait)
+}
+
+func (m *mockHost) ShowError(action string, err error) {
+	m.errors = append(m.errors, err)
+}
+
+
 func TestNewRegistry(t *testing.T) {
 	registry := NewRegistry()
 
@@ -94,6 +119,31 @@ func TestGetFetcher_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "not registered")
 }
 
+func TestFetchResources(t *testing.T) {
+	registry := NewRegistry()
+	ctx := context.Background()
+
+	// Without fetchers nothing can be loaded
+	_, err := registry.FetchResources(ctx, Scope{SubscriptionID: "sub-1"})
+	assert.Error(t, err)
+
+	defaultFetcher := &mockFetcher{name: "default"}
+	storageFetcher := &mockFetcher{name: "storage"}
+	registry.Register("", defaultFetcher)
+	registry.Register("Microsoft.Storage/storageAccounts", storageFetcher)
+
+	resources, err := registry.FetchResources(ctx, Scope{SubscriptionID: "sub-1", ResourceGroup: "rg-1", ResourceType: "Microsoft.Storage/storageAccounts"})
+	require.NoError(t, err)
+	assert.Equal(t, "storage", resources[0].Name)
+	assert.Equal(t, []Scope{{SubscriptionID: "sub-1", ResourceGroup: "rg-1", ResourceType: "Microsoft.Storage/storageAccounts"}}, storageFetcher.scopes)
+
+	resources, err = registry.FetchResources(ctx, Scope{SubscriptionID: "sub-1", ResourceType: "Microsoft.Web/sites"})
+	require.NoError(t, err)
+	assert.Equal(t, "default", resources[0].Name)
+
+	assert.Same(t, defaultFetcher, registry.GetFetcherOrDefault(""))
+}
+
 func TestListResourceTypes(t *testing.T) {
 	registry := NewRegistry()
 
@@ -128,6 +178,22 @@ func TestRegisterHandler(t *testing.T) {
 	assert.Equal(t, handler, retrieved)
 }
 
+func TestRegisterHandler_RegistersHandlerFetcher(t *testing.T) {
+	registry := NewRegistry()
+	fetcher := &fakeStorageFetcher{}
+
+	registry.RegisterHandler(NewStorageHandler(fetcher, nil))
+
+	retrieved, err := registry.GetFetcher("Microsoft.Storage/storageAccounts")
+	require.NoError(t, err)
+	assert.Equal(t, fetcher, retrieved)
+
+	// Handlers without a fetcher leave the type to the default fetcher
+	registry.RegisterHandler(NewKeyVaultHandler(nil, nil))
+	_, err = registry.GetFetcher("Microsoft.KeyVault/vaults")
+	assert.Error(t, err)
+}
+
 func TestGetHandler_NotFound(t *testing.T) {
 	registry := NewRegistry()
 
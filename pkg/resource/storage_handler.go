@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,18 +12,50 @@ const (
 	storageAccountType = "Microsoft.Storage/storageAccounts"
 )
 
+// StorageFetcher lists storage accounts and loads the containers and blobs the storage explorer shows
+type StorageFetcher interface {
+	Fetcher
+	ListContainers(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName string) ([]*models.Container, error)
+	ListBlobs(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, prefix string) ([]*models.Blob, error)
+	GetBlobDetails(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, blobName string) (*models.Blob, error)
+}
+
+// StorageAccount identifies the storage account a storage explorer is opened on
+type StorageAccount struct {
+	SubscriptionID string // Empty on a direct storage endpoint
+	ResourceGroup  string // Empty on a direct storage endpoint
+	Name           string
+}
+
+// StorageExplorerView is the explorer view of a storage account, provided by the UI. It lists the
+// containers it is opened with and loads blobs with the fetcher it is given.
+type StorageExplorerView interface {
+	ExplorerView
+	Open(account StorageAccount, containers []*models.Container, fetcher StorageFetcher)
+}
+
 // StorageHandler provides behavior for storage account resources
 type StorageHandler struct {
 	resourceType string
+	fetcher      StorageFetcher
+	view         StorageExplorerView
 }
 
-// NewStorageHandler creates a new storage account handler
-func NewStorageHandler() *StorageHandler {
+// NewStorageHandler creates a new storage account handler that loads storage accounts with a fetcher
+// and explores them in a view. Without them the storage accounts cannot be explored.
+func NewStorageHandler(fetcher StorageFetcher, view StorageExplorerView) *StorageHandler {
 	return &StorageHandler{
 		resourceType: storageAccountType,
+		fetcher:      fetcher,
+		view:         view,
 	}
 }
 
+// Fetcher returns the fetcher that lists storage accounts, registered with the handler
+func (h *StorageHandler) Fetcher() Fetcher {
+	return h.fetcher
+}
+
 // GetResourceType returns the resource type
 func (h *StorageHandler) GetResourceType() string {
 	return h.resourceType
@@ -65,12 +98,9 @@ func (h *StorageHandler) GetCellValue(resource *models.Resource, columnIndex int
 func (h *StorageHandler) GetActions() []Action {
 	return []Action{
 		{
-			Key:   'e',
-			Label: "Explore Storage",
-			Callback: func(resource *models.Resource, context *ActionContext) bool {
-				// Action will be handled by the UI layer
-				return true
-			},
+			Key:      'e',
+			Label:    "Explore Storage",
+			Callback: exploreAction(h),
 		},
 		{
 			Key:   'd',
@@ -129,9 +159,24 @@ func (h *StorageHandler) RenderDetails(resource *models.Resource, subscriptionID
 	return content.String()
 }
 
-// NavigateToExplore navigates to the storage explorer view
-func (h *StorageHandler) NavigateToExplore(app interface{}, resource *models.Resource) {
-	// This will be called by the UI layer to navigate to storage explorer
-	// The actual navigation logic is in app.go
+// Explore lists the containers of the storage account and shows them in the storage explorer view
+func (h *StorageHandler) Explore(host Host, resource *models.Resource) error {
+	if h.fetcher == nil || h.view == nil {
+		return ErrNotExplorable
+	}
+
+	account := StorageAccount{
+		SubscriptionID: subscriptionFromID(resource.ID),
+		ResourceGroup:  resource.ResourceGroup,
+		Name:           resource.Name,
+	}
+	containers, err := h.fetcher.ListContainers(context.Background(), account.SubscriptionID, account.ResourceGroup, account.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	h.view.Open(account, containers, h.fetcher)
+	host.ShowExplorer(h.view)
+	return nil
 }
 
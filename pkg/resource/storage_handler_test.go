@@ -2,14 +2,19 @@ package resource
 
 import (
 	"azure-control-tower/internal/models"
+	"context"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/rivo/tview"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewStorageHandler(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, "Microsoft.Storage/storageAccounts", handler.GetResourceType())
@@ -17,7 +22,7 @@ func TestNewStorageHandler(t *testing.T) {
 }
 
 func TestStorageHandler_GetColumns(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	columns := handler.GetColumns()
 
@@ -28,7 +33,7 @@ func TestStorageHandler_GetColumns(t *testing.T) {
 }
 
 func TestStorageHandler_GetCellValue(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	tests := []struct {
 		name        string
@@ -87,7 +92,7 @@ func TestStorageHandler_GetCellValue(t *testing.T) {
 }
 
 func TestStorageHandler_GetActions(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	actions := handler.GetActions()
 
@@ -105,19 +110,19 @@ func TestStorageHandler_GetActions(t *testing.T) {
 }
 
 func TestStorageHandler_CanNavigateToList(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	assert.True(t, handler.CanNavigateToList())
 }
 
 func TestStorageHandler_CanExplore(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	assert.True(t, handler.CanExplore())
 }
 
 func TestStorageHandler_RenderDetails(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 
 	t.Run("Storage account with tags and properties", func(t *testing.T) {
 		tag1 := "production"
@@ -237,21 +242,87 @@ func TestStorageHandler_RenderDetails(t *testing.T) {
 	})
 }
 
-func TestStorageHandler_NavigateToExplore(t *testing.T) {
-	handler := NewStorageHandler()
+// Fake storage fetcher for testing
+type fakeStorageFetcher struct {
+	mockFetcher
+	containers []*models.Container
+	err        error
+	listed     []StorageAccount
+}
+
+func (f *fakeStorageFetcher) ListContainers(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName string) ([]*models.Container, error) {
+	f.listed = append(f.listed, StorageAccount{SubscriptionID: subscriptionID, ResourceGroup: resourceGroupName, Name: storageAccountName})
+	return f.containers, f.err
+}
+
+func (f *fakeStorageFetcher) ListBlobs(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, prefix string) ([]*models.Blob, error) {
+	return nil, nil
+}
+
+func (f *fakeStorageFetcher) GetBlobDetails(ctx context.Context, subscriptionID, resourceGroupName, storageAccountName, containerName, blobName string) (*models.Blob, error) {
+	return nil, nil
+}
+
+// Fake storage explorer view for testing
+type fakeStorageView struct {
+	*tview.Box
+	account    StorageAccount
+	containers []*models.Container
+	fetcher    StorageFetcher
+}
+
+func (f *fakeStorageView) Title() string { return "Storage" }
+
+func (f *fakeStorageView) Actions() string { return "" }
+
+func (f *fakeStorageView) Open(account StorageAccount, containers []*models.Container, fetcher StorageFetcher) {
+	f.account = account
+	f.containers = containers
+	f.fetcher = fetcher
+}
+
+func TestStorageHandler_Explore(t *testing.T) {
+	fetcher := &fakeStorageFetcher{containers: []*models.Container{{Name: "logs"}}}
+	view := &fakeStorageView{Box: tview.NewBox()}
+	handler := NewStorageHandler(fetcher, view)
 	resource := &models.Resource{
-		Name: "mystorageaccount",
-		Type: "Microsoft.Storage/storageAccounts",
+		ID:            "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorageaccount",
+		Name:          "mystorageaccount",
+		Type:          "Microsoft.Storage/storageAccounts",
+		ResourceGroup: "my-rg",
 	}
 
-	// Should not panic
-	assert.NotPanics(t, func() {
-		handler.NavigateToExplore(nil, resource)
-	})
+	host := &mockHost{}
+	require.NoError(t, handler.Explore(host, resource))
+	account := StorageAccount{SubscriptionID: "sub-123", ResourceGroup: "my-rg", Name: "mystorageaccount"}
+	assert.Equal(t, []StorageAccount{account}, fetcher.listed)
+	assert.Equal(t, account, view.account)
+	assert.Equal(t, fetcher.containers, view.containers)
+	assert.Equal(t, fetcher, view.fetcher)
+	assert.Equal(t, []ExplorerView{view}, host.views)
+
+	// Without a host the explore action is not handled
+	assert.False(t, handler.GetActions()[0].Callback(resource, &ActionContext{}))
+
+	// Failures to list the containers are reported and show no view
+	fetcher.err = errors.New("forbidden")
+	failing := &mockHost{}
+	assert.ErrorIs(t, handler.Explore(failing, resource), fetcher.err)
+	assert.True(t, handler.GetActions()[0].Callback(resource, &ActionContext{Host: failing}))
+	assert.Len(t, failing.errors, 1)
+	assert.Empty(t, failing.views)
+}
+
+func TestStorageHandler_ExploreWithoutView(t *testing.T) {
+	handler := NewStorageHandler(&fakeStorageFetcher{}, nil)
+	host := &mockHost{}
+
+	assert.ErrorIs(t, handler.Explore(host, &models.Resource{Name: "mystorageaccount"}), ErrNotExplorable)
+	assert.Empty(t, host.views)
 }
 
 func TestStorageHandler_RenderDetails_Formatting(t *testing.T) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 	resource := &models.Resource{
 		ID:            "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorageaccount",
 		Name:          "mystorageaccount",
@@ -273,7 +344,7 @@ func TestStorageHandler_RenderDetails_Formatting(t *testing.T) {
 }
 
 func TestStorageHandler_CompareWithDefault(t *testing.T) {
-	storageHandler := NewStorageHandler()
+	storageHandler := NewStorageHandler(nil, nil)
 	defaultHandler := NewDefaultHandler()
 
 	// Both should have same column structure
@@ -288,7 +359,7 @@ func TestStorageHandler_CompareWithDefault(t *testing.T) {
 }
 
 func BenchmarkStorageHandler_GetCellValue(b *testing.B) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 	resource := &models.Resource{
 		Type:     "Microsoft.Storage/storageAccounts",
 		Name:     "mystorageaccount",
@@ -304,7 +375,7 @@ func BenchmarkStorageHandler_GetCellValue(b *testing.B) {
 }
 
 func BenchmarkStorageHandler_RenderDetails(b *testing.B) {
-	handler := NewStorageHandler()
+	handler := NewStorageHandler(nil, nil)
 	tag1 := "production"
 	resource := &models.Resource{
 		ID:            "/subscriptions/sub-123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/mystorageaccount",
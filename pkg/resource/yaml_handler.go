@@ -0,0 +1,340 @@
+package resource
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"azure-control-tower/internal/models"
+
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+)
+
+// reservedActionKeys are used by the resources view and the global shortcuts, so YAML actions cannot take them
+const reservedActionKeys = "dejtqmg"
+
+// reservedTableKeys sort the rows and choose the columns of every table, so YAML actions cannot take them
+const reservedTableKeys = "SC"
+
+// commandPlaceholder matches the {path} placeholders of action commands
+var commandPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// HandlerDefinition is a resource handler defined in a YAML file
+type HandlerDefinition struct {
+	ResourceType string              `yaml:"resourceType"`
+	DisplayName  string              `yaml:"displayName"`
+	Columns      []ColumnDefinition  `yaml:"columns"`
+	Details      []SectionDefinition `yaml:"details"`
+	Actions      []ActionDefinition  `yaml:"actions"`
+}
+
+// ColumnDefinition is a list column whose value is read with a path over the resource
+type ColumnDefinition struct {
+	Name  string `yaml:"name"`
+	Path  string `yaml:"path"`
+	Width int    `yaml:"width"`
+	Align string `yaml:"align"` // left, center or right; left when empty
+}
+
+// SectionDefinition is a titled group of fields in the details view
+type SectionDefinition struct {
+	Title  string            `yaml:"title"`
+	Fields []FieldDefinition `yaml:"fields"`
+}
+
+// FieldDefinition is a labelled value of a details section
+type FieldDefinition struct {
+	Label string `yaml:"label"`
+	Path  string `yaml:"path"`
+}
+
+// ActionDefinition runs an external command on the selected resource. Each argument may contain {path}
+// placeholders, replaced by the value at the path, and {subscriptionId}.
+type ActionDefinition struct {
+	Key     string   `yaml:"key"`
+	Label   string   `yaml:"label"`
+	Command []string `yaml:"command"`
+	Wait    bool     `yaml:"wait"` // Wait for Enter after the command exits, to read its output
+}
+
+// YAMLHandler is a resource handler built from a HandlerDefinition
+type YAMLHandler struct {
+	definition HandlerDefinition
+	source     string // File the definition was loaded from
+	columns    []Path
+	sections   [][]Path
+}
+
+// NewYAMLHandler validates a definition and creates its handler
+func NewYAMLHandler(definition HandlerDefinition) (*YAMLHandler, error) {
+	if _, _, ok := strings.Cut(definition.ResourceType, "/"); !ok {
+		return nil, fmt.Errorf("resourceType must be a type such as Microsoft.Web/sites, got %q", definition.ResourceType)
+	}
+	if definition.DisplayName == "" {
+		return nil, fmt.Errorf("displayName is required")
+	}
+	if len(definition.Columns) == 0 {
+		return nil, fmt.Errorf("at least one column is required")
+	}
+
+	h := &YAMLHandler{definition: definition}
+	for _, column := range definition.Columns {
+		if column.Name == "" {
+			return nil, fmt.Errorf("column %q: name is required", column.Path)
+		}
+		if _, ok := alignments[column.Align]; !ok {
+			return nil, fmt.Errorf("column %s: align must be left, center or right", column.Name)
+		}
+		path, err := ParsePath(column.Path)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.Name, err)
+		}
+		h.columns = append(h.columns, path)
+	}
+
+	for _, section := range definition.Details {
+		if section.Title == "" {
+			return nil, fmt.Errorf("details sections need a title")
+		}
+		var paths []Path
+		for _, field := range section.Fields {
+			if field.Label == "" {
+				return nil, fmt.Errorf("details section %s: field %q has no label", section.Title, field.Path)
+			}
+			path, err := ParsePath(field.Path)
+			if err != nil {
+				return nil, fmt.Errorf("details field %s: %w", field.Label, err)
+			}
+			paths = append(paths, path)
+		}
+		h.sections = append(h.sections, paths)
+	}
+
+	keys := make(map[rune]bool)
+	for _, action := range definition.Actions {
+		key, size := utf8.DecodeRuneInString(action.Key)
+		if size == 0 || size != len(action.Key) || !(unicode.IsLetter(key) || unicode.IsDigit(key)) {
+			return nil, fmt.Errorf("action %s: key must be a single letter or digit", action.Label)
+		}
+		if strings.ContainsRune(reservedActionKeys, key) || strings.ContainsRune(strings.ToUpper(reservedActionKeys), key) ||
+			strings.ContainsRune(reservedTableKeys, key) {
+			return nil, fmt.Errorf("action %s: key %q is reserved", action.Label, action.Key)
+		}
+		if keys[key] {
+			return nil, fmt.Errorf("action %s: key %q is used twice", action.Label, action.Key)
+		}
+		keys[key] = true
+		if action.Label == "" {
+			return nil, fmt.Errorf("action %q: label is required", action.Key)
+		}
+		if len(action.Command) == 0 {
+			return nil, fmt.Errorf("action %s: command is required", action.Label)
+		}
+		for _, argument := range action.Command {
+			for _, match := range commandPlaceholder.FindAllStringSubmatch(argument, -1) {
+				if match[1] == "subscriptionId" {
+					continue
+				}
+				if _, err := ParsePath(match[1]); err != nil {
+					return nil, fmt.Errorf("action %s: %w", action.Label, err)
+				}
+			}
+		}
+	}
+
+	return h, nil
+}
+
+var alignments = map[string]int{"": AlignLeft, "left": AlignLeft, "center": AlignCenter, "right": AlignRight}
+
+// LoadHandlers loads the handler definitions of the .yaml and .yml files of a directory, in name order.
+// A missing directory holds no handlers.
+func LoadHandlers(dir string) ([]*YAMLHandler, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handlers directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (extension == ".yaml" || extension == ".yml") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var handlers []*YAMLHandler
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read handler %s: %w", file, err)
+		}
+
+		var definition HandlerDefinition
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&definition); err != nil {
+			return nil, fmt.Errorf("failed to parse handler %s: %w", file, err)
+		}
+		handler, err := NewYAMLHandler(definition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid handler %s: %w", file, err)
+		}
+		handler.source = file
+		handlers = append(handlers, handler)
+	}
+	return handlers, nil
+}
+
+// GetResourceType returns the resource type
+func (h *YAMLHandler) GetResourceType() string {
+	return h.definition.ResourceType
+}
+
+// GetDisplayName returns a display name for the resource type
+func (h *YAMLHandler) GetDisplayName() string {
+	return h.definition.DisplayName
+}
+
+// GetSource returns the file the handler was loaded from
+func (h *YAMLHandler) GetSource() string {
+	return h.source
+}
+
+// GetColumns returns the configured columns
+func (h *YAMLHandler) GetColumns() []ColumnConfig {
+	columns := make([]ColumnConfig, len(h.definition.Columns))
+	for i, column := range h.definition.Columns {
+		columns[i] = ColumnConfig{
+			Name:  column.Name,
+			Width: column.Width,
+			Align: alignments[column.Align],
+		}
+	}
+	return columns
+}
+
+// GetCellValue reads the path of a column from the resource
+func (h *YAMLHandler) GetCellValue(resource *models.Resource, columnIndex int) string {
+	if columnIndex < 0 || columnIndex >= len(h.columns) {
+		return ""
+	}
+	return h.columns[columnIndex].Format(ResourceDocument(resource))
+}
+
+// GetActions returns the details action followed by the configured commands
+func (h *YAMLHandler) GetActions() []Action {
+	actions := []Action{
+		{
+			Key:   'd',
+			Label: "Details",
+			Callback: func(resource *models.Resource, context *ActionContext) bool {
+				// Action will be handled by the UI layer
+				return true
+			},
+		},
+	}
+	for _, definition := range h.definition.Actions {
+		definition := definition
+		key, _ := utf8.DecodeRuneInString(definition.Key)
+		actions = append(actions, Action{
+			Key:   key,
+			Label: definition.Label,
+			Callback: func(resource *models.Resource, context *ActionContext) bool {
+				if context == nil || context.Host == nil {
+					return false
+				}
+				context.Host.RunCommand(ExpandCommand(definition.Command, resource, context.SubscriptionID), definition.Wait)
+				return true
+			},
+		})
+	}
+	return actions
+}
+
+// ExpandCommand replaces the placeholders of a command with the values of the resource. Placeholders
+// whose path has no value become empty.
+func ExpandCommand(command []string, resource *models.Resource, subscriptionID string) []string {
+	document := ResourceDocument(resource)
+	expanded := make([]string, len(command))
+	for i, argument := range command {
+		expanded[i] = commandPlaceholder.ReplaceAllStringFunc(argument, func(placeholder string) string {
+			expression := placeholder[1 : len(placeholder)-1]
+			if expression == "subscriptionId" {
+				return subscriptionID
+			}
+			path, err := ParsePath(expression)
+			if err != nil {
+				return ""
+			}
+			return path.Format(document)
+		})
+	}
+	return expanded
+}
+
+// CanNavigateToList returns true so that the type can be listed from the resource types view
+func (h *YAMLHandler) CanNavigateToList() bool {
+	return true
+}
+
+// CanExplore returns false as YAML handlers have no exploration view
+func (h *YAMLHandler) CanExplore() bool {
+	return false
+}
+
+// RenderDetails renders the resource with the configured details sections
+func (h *YAMLHandler) RenderDetails(resource *models.Resource, subscriptionID string) string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("[lightblue::b]%s Details[white]\n\n", tview.Escape(h.definition.DisplayName)))
+	content.WriteString(fmt.Sprintf("[lightblue::b]ID:[white] %s\n", resource.ID))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Name:[white] %s\n", resource.Name))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Type:[white] %s\n", resource.Type))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Location:[white] %s\n", resource.Location))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Resource Group:[white] %s\n", resource.ResourceGroup))
+	content.WriteString(fmt.Sprintf("[lightblue::b]Subscription ID:[white] %s\n", subscriptionID))
+
+	document := ResourceDocument(resource)
+	for i, section := range h.definition.Details {
+		content.WriteString(fmt.Sprintf("\n[lightblue::b]%s:[white]\n", tview.Escape(section.Title)))
+		for j, field := range section.Fields {
+			value := h.sections[i][j].Format(document)
+			if value == "" {
+				value = "-"
+			}
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s\n", tview.Escape(field.Label), tview.Escape(value)))
+		}
+	}
+
+	if len(resource.Tags) > 0 {
+		content.WriteString("\n[lightblue::b]Tags:[white]\n")
+		for _, key := range sortedKeys(resource.Tags) {
+			val := ""
+			if value := resource.Tags[key]; value != nil {
+				val = *value
+			}
+			content.WriteString(fmt.Sprintf("  [lightblue::b]%s:[white] %s\n", key, val))
+		}
+	} else {
+		content.WriteString("\n[lightblue::b]Tags:[white] None\n")
+	}
+
+	return content.String()
+}
+
+// Explore returns ErrNotExplorable as YAML handlers have no exploration view
+func (h *YAMLHandler) Explore(host Host, resource *models.Resource) error {
+	return ErrNotExplorable
+}
@@ -0,0 +1,182 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHandlerYAML = `resourceType: Microsoft.Web/sites
+displayName: Web Apps
+columns:
+  - name: Name
+    path: name
+  - name: SKU
+    path: sku.name
+  - name: State
+    path: properties.provisioningState
+    width: 12
+    align: right
+details:
+  - title: Hosting
+    fields:
+      - label: Host Names
+        path: properties.hostNames
+      - label: Owner
+        path: tags.owner
+actions:
+  - key: b
+    label: Browse
+    command: ["open", "https://{properties.hostNames[0]}"]
+  - key: l
+    label: Logs
+    command: ["az", "webapp", "log", "tail", "--subscription", "{subscriptionId}", "-g", "{resourceGroup}", "-n", "{name}"]
+    wait: true
+`
+
+// writeHandler writes a handler definition to a directory
+func writeHandler(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func TestLoadHandlers(t *testing.T) {
+	dir := t.TempDir()
+	writeHandler(t, dir, "webapps.yaml", testHandlerYAML)
+	writeHandler(t, dir, "notes.txt", "not a handler")
+
+	handlers, err := LoadHandlers(dir)
+	require.NoError(t, err)
+	require.Len(t, handlers, 1)
+
+	handler := handlers[0]
+	assert.Equal(t, "Microsoft.Web/sites", handler.GetResourceType())
+	assert.Equal(t, "Web Apps", handler.GetDisplayName())
+	assert.Equal(t, filepath.Join(dir, "webapps.yaml"), handler.GetSource())
+	assert.Equal(t, []ColumnConfig{
+		{Name: "Name", Align: AlignLeft},
+		{Name: "SKU", Align: AlignLeft},
+		{Name: "State", Width: 12, Align: AlignRight},
+	}, handler.GetColumns())
+	assert.True(t, handler.CanNavigateToList())
+	assert.False(t, handler.CanExplore())
+
+	resource := testPathResource()
+	assert.Equal(t, "app-1", handler.GetCellValue(resource, 0))
+	assert.Equal(t, "P1v3", handler.GetCellValue(resource, 1))
+	assert.Equal(t, "Succeeded", handler.GetCellValue(resource, 2))
+	assert.Equal(t, "", handler.GetCellValue(resource, 3))
+
+	details := handler.RenderDetails(resource, "sub-123")
+	assert.Contains(t, details, "Web Apps Details")
+	assert.Contains(t, details, "[lightblue::b]Hosting:[white]")
+	assert.Contains(t, details, `Host Names:[white] ["app-1.azurewebsites.net","www.example.com"[]`)
+	assert.Contains(t, details, "Owner:[white] -")
+	assert.Contains(t, details, "Subscription ID:[white] sub-123")
+}
+
+func TestLoadHandlers_MissingDirectory(t *testing.T) {
+	handlers, err := LoadHandlers(filepath.Join(t.TempDir(), "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, handlers)
+}
+
+func TestLoadHandlers_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "Unknown field", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumn: []\n", wantErr: "field column not found"},
+		{name: "Missing type", content: "displayName: Web Apps\ncolumns: [{name: Name, path: name}]\n", wantErr: "resourceType"},
+		{name: "No columns", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\n", wantErr: "at least one column"},
+		{name: "Invalid path", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: 'properties..x'}]\n", wantErr: "empty key"},
+		{name: "Invalid align", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name, align: top}]\n", wantErr: "align"},
+		{name: "Reserved key", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: D, label: Do, command: [echo]}]\n", wantErr: "reserved"},
+		{name: "Reserved tags key", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: g, label: Go, command: [echo]}]\n", wantErr: "reserved"},
+		{name: "Reserved table key", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: S, label: Scale, command: [echo]}]\n", wantErr: "reserved"},
+		{name: "Key not a letter", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: '/', label: Browse, command: [open]}]\n", wantErr: "single letter or digit"},
+		{name: "Duplicate key", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: b, label: A, command: [echo]}, {key: b, label: B, command: [echo]}]\n", wantErr: "used twice"},
+		{name: "No command", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: b, label: Browse}]\n", wantErr: "command is required"},
+		{name: "Invalid placeholder", content: "resourceType: Microsoft.Web/sites\ndisplayName: Web Apps\ncolumns: [{name: Name, path: name}]\nactions: [{key: b, label: Browse, command: [open, '{tags[}']}]\n", wantErr: "Browse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeHandler(t, dir, "handler.yml", tt.content)
+			_, err := LoadHandlers(dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Contains(t, err.Error(), "handler.yml")
+		})
+	}
+}
+
+func TestYAMLHandler_Actions(t *testing.T) {
+	dir := t.TempDir()
+	writeHandler(t, dir, "webapps.yaml", testHandlerYAML)
+	handlers, err := LoadHandlers(dir)
+	require.NoError(t, err)
+
+	actions := handlers[0].GetActions()
+	require.Len(t, actions, 3)
+	assert.Equal(t, 'd', actions[0].Key)
+	assert.Equal(t, 'b', actions[1].Key)
+	assert.Equal(t, "Browse", actions[1].Label)
+
+	host := &mockHost{}
+	context := &ActionContext{
+		SubscriptionID: "sub-123",
+		Host:           host,
+	}
+	resource := testPathResource()
+	assert.True(t, actions[1].Callback(resource, context))
+	assert.True(t, actions[2].Callback(resource, context))
+	assert.Equal(t, [][]string{
+		{"open", "https://app-1.azurewebsites.net"},
+		{"az", "webapp", "log", "tail", "--subscription", "sub-123", "-g", "my-rg", "-n", "app-1"},
+	}, host.commands)
+	assert.Equal(t, []bool{false, true}, host.waits)
+
+	// Without a way to run commands the action is not handled
+	assert.False(t, actions[1].Callback(resource, &ActionContext{}))
+}
+
+func TestRegistry_LoadHandlers(t *testing.T) {
+	dir := t.TempDir()
+	writeHandler(t, dir, "webapps.yaml", testHandlerYAML)
+
+	registry := NewRegistry()
+	registry.RegisterHandler(NewDefaultHandler())
+	handlers, err := registry.LoadHandlers(dir)
+	require.NoError(t, err)
+	require.Len(t, handlers, 1)
+	assert.Same(t, handlers[0], registry.GetHandlerOrDefault("Microsoft.Web/sites"))
+	assert.Contains(t, registry.GetSupportedResourceTypes(), "Microsoft.Web/sites")
+
+	t.Run("Types with a handler cannot be redefined", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.RegisterHandler(NewStorageHandler(nil, nil))
+		dir := t.TempDir()
+		writeHandler(t, dir, "storage.yaml", "resourceType: Microsoft.Storage/storageAccounts\ndisplayName: Storage\ncolumns: [{name: Name, path: name}]\n")
+
+		_, err := registry.LoadHandlers(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already has a handler")
+		assert.IsType(t, &StorageHandler{}, registry.GetHandlerOrDefault("Microsoft.Storage/storageAccounts"))
+	})
+
+	t.Run("Duplicate definitions", func(t *testing.T) {
+		dir := t.TempDir()
+		writeHandler(t, dir, "a.yaml", testHandlerYAML)
+		writeHandler(t, dir, "b.yaml", testHandlerYAML)
+
+		_, err := NewRegistry().LoadHandlers(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "b.yaml")
+	})
+}